@@ -0,0 +1,82 @@
+/*
+ * Copyright 2010 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aztec
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/aztec/decoder"
+	"github.com/discesoft/zxing-go/core/aztec/detector"
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+// AztecReader can detect and decode Aztec codes in an image.
+type AztecReader struct{}
+
+func NewAztecReader() *AztecReader {
+	return &AztecReader{}
+}
+
+// Decode locates and decodes an Aztec code in an image, trying it as
+// mirrored if it cannot be read as is. An Aztec Rune is told apart by its
+// symbology identifier "]zC", its text being the value as three digits.
+// It returns the decoded Result, core.ErrNotFound if an Aztec code cannot
+// be found, or core.ErrFormat if it cannot be decoded.
+func (r *AztecReader) Decode(image *common.BitMatrix, hints map[core.DecodeHintType]interface{}) (*core.Result, error) {
+	det := detector.NewDetector(image)
+	detectorResult, decoderResult, err := detectAndDecode(det, false)
+	if err != nil {
+		var mirrorErr error
+		detectorResult, decoderResult, mirrorErr = detectAndDecode(det, true)
+		if mirrorErr != nil {
+			return nil, err
+		}
+	}
+
+	result := core.NewResultWithNumBits(decoderResult.GetText(), decoderResult.GetRawBytes(), decoderResult.GetNumBits(), detectorResult.GetPoints(), core.Aztec, time.Now().UnixNano()/int64(time.Millisecond))
+	if byteSegments := decoderResult.GetByteSegments(); byteSegments != nil {
+		result.PutMetadata(core.ResultMetadataByteSegments, byteSegments)
+	}
+	if ecLevel := decoderResult.GetECLevel(); ecLevel != "" {
+		result.PutMetadata(core.ResultMetadataErrorCorrectionLevel, ecLevel)
+	}
+	result.PutMetadata(core.ResultMetadataErrorsCorrected, decoderResult.GetErrorsCorrected())
+	if detectorResult.IsRune() {
+		result.PutMetadata(core.ResultMetadataSymbologyIdentifier, "]zC")
+	} else {
+		result.PutMetadata(core.ResultMetadataSymbologyIdentifier, "]z"+strconv.Itoa(decoderResult.GetSymbologyModifier()))
+	}
+	return result, nil
+}
+
+func detectAndDecode(det *detector.Detector, isMirror bool) (*detector.AztecDetectorResult, *common.DecoderResult, error) {
+	detectorResult, err := det.DetectMirror(isMirror)
+	if err != nil {
+		return nil, nil, err
+	}
+	decoderResult, err := decoder.NewDecoder().Decode(detectorResult)
+	if err != nil {
+		return nil, nil, err
+	}
+	return detectorResult, decoderResult, nil
+}
+
+func (r *AztecReader) Reset() {
+	// do nothing
+}
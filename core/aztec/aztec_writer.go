@@ -0,0 +1,117 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aztec
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/aztec/encoder"
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+// AztecWriter renders an Aztec code as a BitMatrix.
+type AztecWriter struct{}
+
+func NewAztecWriter() *AztecWriter {
+	return &AztecWriter{}
+}
+
+// Encode encodes contents as an Aztec code, scaled by a whole number of
+// pixels per module to fit within width by height pixels where possible.
+// The core.EncodeHintCharacterSet hint selects the character set, which
+// is then designated by an ECI; the core.EncodeHintErrorCorrection hint
+// gives the minimal percentage of error correction words, and the
+// core.EncodeHintAztecLayers hint the number of layers.
+// It returns an error if contents cannot be encoded.
+func (w *AztecWriter) Encode(contents string, format core.BarcodeFormat, width, height int, hints map[core.EncodeHintType]interface{}) (*common.BitMatrix, error) {
+	if format != core.Aztec {
+		return nil, errors.New("Can only encode AZTEC, but got " + strconv.Itoa(int(format)))
+	}
+
+	var charset *common.CharacterSetECI // Do not add any ECI code by default
+	eccPercent := encoder.DefaultECPercent
+	layers := encoder.DefaultAztecLayers
+	if name, ok := hints[core.EncodeHintCharacterSet].(string); ok {
+		charset = common.GetCharacterSetECIByName(name)
+		if charset == nil {
+			return nil, errors.New("Unsupported character set " + name)
+		}
+	}
+	if percent, ok := hints[core.EncodeHintErrorCorrection].(int); ok {
+		eccPercent = percent
+	}
+	if requestedLayers, ok := hints[core.EncodeHintAztecLayers].(int); ok {
+		layers = requestedLayers
+	}
+
+	aztec, err := encoder.EncodeWithParameters(contents, eccPercent, layers, charset)
+	if err != nil {
+		return nil, err
+	}
+	return renderResult(aztec, width, height)
+}
+
+// EncodeRune encodes value, from 0 to 255, as an Aztec Rune, scaled as
+// by Encode.
+// It returns an error if value is out of range.
+func (w *AztecWriter) EncodeRune(value, width, height int) (*common.BitMatrix, error) {
+	aztec, err := encoder.EncodeRune(value)
+	if err != nil {
+		return nil, err
+	}
+	return renderResult(aztec, width, height)
+}
+
+func renderResult(code *encoder.AztecCode, width, height int) (*common.BitMatrix, error) {
+	input := code.GetMatrix()
+	inputWidth := int(input.GetWidth())
+	inputHeight := int(input.GetHeight())
+	outputWidth := width
+	if inputWidth > outputWidth {
+		outputWidth = inputWidth
+	}
+	outputHeight := height
+	if inputHeight > outputHeight {
+		outputHeight = inputHeight
+	}
+
+	multiple := outputWidth / inputWidth
+	if m := outputHeight / inputHeight; m < multiple {
+		multiple = m
+	}
+	leftPadding := (outputWidth - (inputWidth * multiple)) / 2
+	topPadding := (outputHeight - (inputHeight * multiple)) / 2
+
+	output, err := common.NewBitMatrix(uint32(outputWidth), uint32(outputHeight))
+	if err != nil {
+		return nil, err
+	}
+
+	for inputY, outputY := 0, topPadding; inputY < inputHeight; inputY, outputY = inputY+1, outputY+multiple {
+		// Write the contents of this row of the barcode
+		for inputX, outputX := 0, leftPadding; inputX < inputWidth; inputX, outputX = inputX+1, outputX+multiple {
+			if input.Get(uint32(inputX), uint32(inputY)) {
+				if err := output.SetRegion(uint32(outputX), uint32(outputY), uint32(multiple), uint32(multiple)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return output, nil
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aztec_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/aztec"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+func encodeAndRead(t *testing.T, contents string, hints map[core.EncodeHintType]interface{}) *core.Result {
+	matrix, err := aztec.NewAztecWriter().Encode(contents, core.Aztec, 0, 0, hints)
+	internal.AssertSuccess(t, err)
+	result := read(t, matrix)
+	internal.AssertEquals(t, contents, result.GetText(), "decoded "+strconv.Quote(result.GetText())+" instead of "+strconv.Quote(contents))
+	return result
+}
+
+func read(t *testing.T, matrix *common.BitMatrix) *core.Result {
+	// Scale the symbol up and give it a quiet zone, as if it were scanned
+	size := matrix.GetWidth()
+	image, err := common.NewBitMatrixFromDimension(4*size + 16)
+	internal.AssertSuccess(t, err)
+	for y := uint32(0); y < size; y++ {
+		for x := uint32(0); x < size; x++ {
+			if matrix.Get(x, y) {
+				internal.AssertSuccess(t, image.SetRegion(4*x+8, 4*y+8, 4, 4))
+			}
+		}
+	}
+
+	result, err := aztec.NewAztecReader().Decode(image, nil)
+	internal.AssertSuccess(t, err)
+	return result
+}
+
+func TestAztecWriter_RoundTrip(t *testing.T) {
+	for _, contents := range []string{
+		"A",
+		"Hello, World!",
+		"M1DOE/JOHN            E1A2B3C JFKLHRBA 0117 234Y012A0001 100",
+		"http://www.example.com/tickets?id=0123456789&seat=12A",
+	} {
+		result := encodeAndRead(t, contents, nil)
+		internal.AssertEquals(t, "]z0", result.GetResultMetadata()[core.ResultMetadataSymbologyIdentifier], "symbology identifier of "+strconv.Quote(contents))
+	}
+}
+
+func TestAztecWriter_Hints(t *testing.T) {
+	for _, layers := range []int{-1, -3, 1, 4, 6} {
+		hints := map[core.EncodeHintType]interface{}{core.EncodeHintAztecLayers: layers}
+		encodeAndRead(t, "Ticket 42", hints)
+	}
+
+	ecLevel := func(result *core.Result) int {
+		level := result.GetResultMetadata()[core.ResultMetadataErrorCorrectionLevel].(string)
+		percent, err := strconv.Atoi(level[:len(level)-1])
+		internal.AssertSuccess(t, err)
+		return percent
+	}
+	defaultLevel := ecLevel(encodeAndRead(t, "Ticket 42", nil))
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintErrorCorrection: 80}
+	highLevel := ecLevel(encodeAndRead(t, "Ticket 42", hints))
+	internal.AssertTrue(t, highLevel > defaultLevel, "error correction level "+strconv.Itoa(highLevel)+"% is not above the default "+strconv.Itoa(defaultLevel)+"%")
+
+	hints = map[core.EncodeHintType]interface{}{core.EncodeHintCharacterSet: "UTF-8"}
+	encodeAndRead(t, "Zürich → Genève", hints)
+}
+
+func TestAztecWriter_Runes(t *testing.T) {
+	writer := aztec.NewAztecWriter()
+	for _, test := range []struct {
+		value    int
+		expected string
+	}{
+		{0, "000"}, {7, "007"}, {25, "025"}, {128, "128"}, {170, "170"}, {255, "255"},
+	} {
+		matrix, err := writer.EncodeRune(test.value, 0, 0)
+		internal.AssertSuccess(t, err)
+		internal.AssertEquals(t, uint32(11), matrix.GetWidth(), "runes are 11x11")
+		result := read(t, matrix)
+		internal.AssertEquals(t, test.expected, result.GetText(), "decoded rune "+strconv.Itoa(test.value)+" as "+result.GetText())
+		internal.AssertEquals(t, "]zC", result.GetResultMetadata()[core.ResultMetadataSymbologyIdentifier], "symbology identifier of rune "+strconv.Itoa(test.value))
+	}
+
+	// A compact symbol is not mistaken for a rune
+	result := encodeAndRead(t, "A", map[core.EncodeHintType]interface{}{core.EncodeHintAztecLayers: -1})
+	internal.AssertEquals(t, "]z0", result.GetResultMetadata()[core.ResultMetadataSymbologyIdentifier], "symbology identifier of a compact symbol")
+
+	for _, value := range []int{-1, 256} {
+		_, err := writer.EncodeRune(value, 0, 0)
+		internal.AssertFailure(t, err, "encoded rune "+strconv.Itoa(value))
+	}
+}
+
+func TestAztecWriter_Scaling(t *testing.T) {
+	matrix, err := aztec.NewAztecWriter().Encode("A", core.Aztec, 100, 80, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, uint32(100), matrix.GetWidth(), "wrong scaled width")
+	internal.AssertEquals(t, uint32(80), matrix.GetHeight(), "wrong scaled height")
+}
+
+func TestAztecWriter_Errors(t *testing.T) {
+	writer := aztec.NewAztecWriter()
+	_, err := writer.Encode("ABC", core.QRCode, 0, 0, nil)
+	internal.AssertFailure(t, err, "encoded a QR code")
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintCharacterSet: "no-such-charset"}
+	_, err = writer.Encode("ABC", core.Aztec, 0, 0, hints)
+	internal.AssertFailure(t, err, "encoded in an unknown character set")
+	hints = map[core.EncodeHintType]interface{}{core.EncodeHintAztecLayers: 40}
+	_, err = writer.Encode("ABC", core.Aztec, 0, 0, hints)
+	internal.AssertFailure(t, err, "encoded with 40 layers")
+}
@@ -0,0 +1,472 @@
+/*
+ * Copyright 2010 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/aztec/detector"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/common/reedsolomon"
+)
+
+type table int
+
+const (
+	upperTable table = iota
+	lowerTable
+	mixedTable
+	digitTable
+	punctTable
+	binaryTable
+)
+
+var upperTableChars = []string{
+	"CTRL_PS", " ", "A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P",
+	"Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z", "CTRL_LL", "CTRL_ML", "CTRL_DL", "CTRL_BS",
+}
+
+var lowerTableChars = []string{
+	"CTRL_PS", " ", "a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p",
+	"q", "r", "s", "t", "u", "v", "w", "x", "y", "z", "CTRL_US", "CTRL_ML", "CTRL_DL", "CTRL_BS",
+}
+
+var mixedTableChars = []string{
+	"CTRL_PS", " ", "\x01", "\x02", "\x03", "\x04", "\x05", "\x06", "\x07", "\b", "\t", "\n",
+	"\x0b", "\f", "\r", "\x1b", "\x1c", "\x1d", "\x1e", "\x1f", "@", "\\", "^", "_",
+	"`", "|", "~", "\x7f", "CTRL_LL", "CTRL_UL", "CTRL_PL", "CTRL_BS",
+}
+
+var punctTableChars = []string{
+	"FLG(n)", "\r", "\r\n", ". ", ", ", ": ", "!", "\"", "#", "$", "%", "&", "'", "(", ")",
+	"*", "+", ",", "-", ".", "/", ":", ";", "<", "=", ">", "?", "[", "]", "{", "}", "CTRL_UL",
+}
+
+var digitTableChars = []string{
+	"CTRL_PS", " ", "0", "1", "2", "3", "4", "5", "6", "7", "8", "9", ",", ".", "CTRL_UL", "CTRL_US",
+}
+
+// Decoder is the main type which implements Aztec Code decoding -- as
+// opposed to locating and extracting the Aztec Code from an image.
+type Decoder struct {
+	ddata *detector.AztecDetectorResult
+}
+
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// correctedBitsResult holds the data bits of a symbol after error
+// correction and the removal of stuffed bits.
+type correctedBitsResult struct {
+	correctBits     []bool
+	errorsCorrected int
+	ecLevel         int
+}
+
+// Decode decodes the Aztec Code located by a detector. The value of an
+// Aztec Rune is decoded as three decimal digits.
+// It returns the text and bytes encoded within the Aztec Code, or
+// core.ErrFormat if the symbol cannot be decoded.
+func (d *Decoder) Decode(detectorResult *detector.AztecDetectorResult) (*common.DecoderResult, error) {
+	if detectorResult.IsRune() {
+		return decodeRune(detectorResult.GetRuneValue()), nil
+	}
+	d.ddata = detectorResult
+	matrix := detectorResult.GetBits()
+	rawbits := d.extractBits(matrix)
+	correctedBits, err := d.correctBits(rawbits)
+	if err != nil {
+		return nil, err
+	}
+	rawBytes := convertBoolArrayToByteArray(correctedBits.correctBits)
+	text, symbologyModifier, err := getEncodedData(correctedBits.correctBits)
+	if err != nil {
+		return nil, err
+	}
+	decoderResult := common.NewDecoderResultWithModifier(rawBytes, text, nil, strconv.Itoa(correctedBits.ecLevel)+"%", symbologyModifier)
+	decoderResult.SetNumBits(len(correctedBits.correctBits))
+	decoderResult.SetErrorsCorrected(correctedBits.errorsCorrected)
+	return decoderResult, nil
+}
+
+// decodeRune reports the value of an Aztec Rune as three decimal digits,
+// as ISO/IEC 24778:2008 Annex A specifies for transmission.
+func decodeRune(value int) *common.DecoderResult {
+	text := strconv.Itoa(value)
+	for len(text) < 3 {
+		text = "0" + text
+	}
+	decoderResult := common.NewDecoderResult([]uint8{uint8(value)}, text, nil, "")
+	decoderResult.SetNumBits(8)
+	return decoderResult
+}
+
+// HighLevelDecode decodes the data bits of a symbol, after error
+// correction and the removal of stuffed bits. This function is used for
+// testing the high-level encoder.
+// It returns the decoded text, or core.ErrFormat if the bits are not a
+// valid encodation.
+func HighLevelDecode(correctedBits []bool) (string, error) {
+	text, _, err := getEncodedData(correctedBits)
+	return text, err
+}
+
+// getEncodedData gets the string encoded in the aztec code bits.
+// It returns the decoded text and the symbology identifier modifier, see
+// ISO/IEC 24778:2008 Annex G, or core.ErrFormat if the bits are not a
+// valid encodation.
+func getEncodedData(correctedBits []bool) (string, int, error) {
+	endIndex := len(correctedBits)
+	latchTable := upperTable // table most recently latched to
+	shiftTable := upperTable // table to use for the next read
+
+	// Intermediary buffer of decoded bytes, which is decoded into a string
+	// when the character encoding changes (ECI) or input ends.
+	result := common.NewECIStringBuilder()
+	fnc1First := false
+	fnc1Second := false
+	isECIEncoded := false
+
+	index := 0
+	for index < endIndex {
+		if shiftTable == binaryTable {
+			if endIndex-index < 5 {
+				break
+			}
+			length := readCode(correctedBits, index, 5)
+			index += 5
+			if length == 0 {
+				if endIndex-index < 11 {
+					break
+				}
+				length = readCode(correctedBits, index, 11) + 31
+				index += 11
+			}
+			for charCount := 0; charCount < length; charCount++ {
+				if endIndex-index < 8 {
+					index = endIndex // Force outer loop to exit
+					break
+				}
+				code := readCode(correctedBits, index, 8)
+				result.AppendByte(uint8(code))
+				index += 8
+			}
+			// Go back to whatever mode we had been in
+			shiftTable = latchTable
+			continue
+		}
+
+		size := 5
+		if shiftTable == digitTable {
+			size = 4
+		}
+		if endIndex-index < size {
+			break
+		}
+		code := readCode(correctedBits, index, size)
+		index += size
+		str := getCharacter(shiftTable, code)
+		if str == "FLG(n)" {
+			if endIndex-index < 3 {
+				break
+			}
+			n := readCode(correctedBits, index, 3)
+			index += 3
+			switch n {
+			case 0:
+				text := result.String()
+				if len(text) == 0 {
+					fnc1First = true
+				} else if isFNC1SecondPrefix(text) {
+					fnc1Second = true
+				}
+				result.AppendByte(29) // translate FNC1 as ASCII 29
+			case 7:
+				return "", 0, core.ErrFormat // FLG(7) is reserved and illegal
+			default:
+				// ECI is decimal integer encoded as 1-6 codes in DIGIT mode
+				if endIndex-index < 4*n {
+					index = endIndex
+					break
+				}
+				eci := 0
+				for ; n > 0; n-- {
+					nextDigit := readCode(correctedBits, index, 4)
+					index += 4
+					if nextDigit < 2 || nextDigit > 11 {
+						return "", 0, core.ErrFormat // Not a decimal digit
+					}
+					eci = eci*10 + (nextDigit - 2)
+				}
+				if err := result.AppendECI(eci); err != nil {
+					return "", 0, core.ErrFormat
+				}
+				isECIEncoded = true
+			}
+			// Go back to whatever mode we had been in
+			shiftTable = latchTable
+		} else if strings.HasPrefix(str, "CTRL_") {
+			// Table changes
+			// ISO/IEC 24778:2008 prescribes ending a shift sequence in the
+			// mode from which it was invoked. That's including when that
+			// mode is a shift.
+			latchTable = shiftTable // Latch the current mode, so as to return to Upper after U/S B/S
+			shiftTable = getTable(str[5])
+			if str[6] == 'L' {
+				latchTable = shiftTable
+			}
+		} else {
+			// Though stored as a table of strings for convenience, codes
+			// actually represent 1 or 2 *bytes*.
+			result.AppendString(str)
+			// Go back to whatever mode we had been in
+			shiftTable = latchTable
+		}
+	}
+
+	symbologyModifier := 0
+	if fnc1First {
+		symbologyModifier = 1
+	} else if fnc1Second {
+		symbologyModifier = 2
+	}
+	if isECIEncoded {
+		symbologyModifier += 3
+	}
+	return result.String(), symbologyModifier, nil
+}
+
+// isFNC1SecondPrefix reports whether text is a single letter or two
+// digits, the prefixes after which FNC1 marks an AIM application.
+func isFNC1SecondPrefix(text string) bool {
+	if len(text) == 1 {
+		c := text[0]
+		return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+	}
+	return len(text) == 2 && text[0] >= '0' && text[0] <= '9' && text[1] >= '0' && text[1] <= '9'
+}
+
+// getTable gets the table corresponding to the char passed.
+func getTable(t uint8) table {
+	switch t {
+	case 'L':
+		return lowerTable
+	case 'P':
+		return punctTable
+	case 'M':
+		return mixedTable
+	case 'D':
+		return digitTable
+	case 'B':
+		return binaryTable
+	}
+	return upperTable
+}
+
+// getCharacter gets the character (or string) corresponding to the
+// passed code in the given table.
+func getCharacter(t table, code int) string {
+	switch t {
+	case lowerTable:
+		return lowerTableChars[code]
+	case mixedTable:
+		return mixedTableChars[code]
+	case punctTable:
+		return punctTableChars[code]
+	case digitTable:
+		return digitTableChars[code]
+	}
+	return upperTableChars[code]
+}
+
+// correctBits performs RS error correction on the raw bits of the symbol
+// and removes the stuffed bits.
+// It returns the corrected data bits, or core.ErrFormat if there are too
+// many errors or a codeword is invalid.
+func (d *Decoder) correctBits(rawbits []bool) (*correctedBitsResult, error) {
+	var gf *reedsolomon.GenericGF
+	var codewordSize int
+
+	if d.ddata.GetNbLayers() <= 2 {
+		codewordSize = 6
+		gf = reedsolomon.AztecData6
+	} else if d.ddata.GetNbLayers() <= 8 {
+		codewordSize = 8
+		gf = reedsolomon.AztecData8
+	} else if d.ddata.GetNbLayers() <= 22 {
+		codewordSize = 10
+		gf = reedsolomon.AztecData10
+	} else {
+		codewordSize = 12
+		gf = reedsolomon.AztecData12
+	}
+
+	numDataCodewords := d.ddata.GetNbDatablocks()
+	numCodewords := len(rawbits) / codewordSize
+	if numCodewords < numDataCodewords {
+		return nil, core.ErrFormat
+	}
+	offset := len(rawbits) % codewordSize
+
+	dataWords := make([]int, numCodewords)
+	for i := 0; i < numCodewords; i++ {
+		dataWords[i] = readCode(rawbits, offset, codewordSize)
+		offset += codewordSize
+	}
+
+	rsDecoder := reedsolomon.NewReedSolomonDecoder(gf)
+	errorsCorrected, err := rsDecoder.Decode(dataWords, numCodewords-numDataCodewords)
+	if err != nil {
+		return nil, core.ErrFormat
+	}
+
+	// Now perform the unstuffing operation.
+	// First, count how many bits are going to be thrown out as stuffing
+	mask := (1 << uint(codewordSize)) - 1
+	stuffedBits := 0
+	for i := 0; i < numDataCodewords; i++ {
+		dataWord := dataWords[i]
+		if dataWord == 0 || dataWord == mask {
+			return nil, core.ErrFormat
+		} else if dataWord == 1 || dataWord == mask-1 {
+			stuffedBits++
+		}
+	}
+	// Now, actually unpack the bits and remove the stuffing
+	correctedBits := make([]bool, numDataCodewords*codewordSize-stuffedBits)
+	index := 0
+	for i := 0; i < numDataCodewords; i++ {
+		dataWord := dataWords[i]
+		if dataWord == 1 || dataWord == mask-1 {
+			// next codewordSize-1 bits are all zeros or all ones
+			for j := 0; j < codewordSize-1; j++ {
+				correctedBits[index+j] = dataWord > 1
+			}
+			index += codewordSize - 1
+		} else {
+			for bit := codewordSize - 1; bit >= 0; bit-- {
+				correctedBits[index] = (dataWord & (1 << uint(bit))) != 0
+				index++
+			}
+		}
+	}
+
+	ecLevel := 100 * (numCodewords - numDataCodewords) / numCodewords
+	return &correctedBitsResult{correctedBits, errorsCorrected, ecLevel}, nil
+}
+
+// extractBits gets the array of bits from an Aztec Code matrix.
+func (d *Decoder) extractBits(matrix *common.BitMatrix) []bool {
+	compact := d.ddata.IsCompact()
+	layers := d.ddata.GetNbLayers()
+	baseMatrixSize := layers * 4 // not including alignment lines
+	if compact {
+		baseMatrixSize += 11
+	} else {
+		baseMatrixSize += 14
+	}
+	alignmentMap := make([]int, baseMatrixSize)
+	rawbits := make([]bool, totalBitsInLayer(layers, compact))
+
+	if compact {
+		for i := range alignmentMap {
+			alignmentMap[i] = i
+		}
+	} else {
+		matrixSize := baseMatrixSize + 1 + 2*((baseMatrixSize/2-1)/15)
+		origCenter := baseMatrixSize / 2
+		center := matrixSize / 2
+		for i := 0; i < origCenter; i++ {
+			newOffset := i + i/15
+			alignmentMap[origCenter-i-1] = center - newOffset - 1
+			alignmentMap[origCenter+i] = center + newOffset + 1
+		}
+	}
+	get := func(x, y int) bool {
+		return matrix.Get(uint32(x), uint32(y))
+	}
+	rowOffset := 0
+	for i := 0; i < layers; i++ {
+		rowSize := (layers-i)*4 + 12
+		if compact {
+			rowSize = (layers-i)*4 + 9
+		}
+		// The top-left most point of this layer is <low, low> (not including alignment lines)
+		low := i * 2
+		// The bottom-right most point of this layer is <high, high> (not including alignment lines)
+		high := baseMatrixSize - 1 - low
+		// We pull bits from the two 2 x rowSize columns and two rowSize x 2 rows
+		for j := 0; j < rowSize; j++ {
+			columnOffset := j * 2
+			for k := 0; k < 2; k++ {
+				// left column
+				rawbits[rowOffset+columnOffset+k] = get(alignmentMap[low+k], alignmentMap[low+j])
+				// bottom row
+				rawbits[rowOffset+2*rowSize+columnOffset+k] = get(alignmentMap[low+j], alignmentMap[high-k])
+				// right column
+				rawbits[rowOffset+4*rowSize+columnOffset+k] = get(alignmentMap[high-k], alignmentMap[high-j])
+				// top row
+				rawbits[rowOffset+6*rowSize+columnOffset+k] = get(alignmentMap[high-j], alignmentMap[low+k])
+			}
+		}
+		rowOffset += rowSize * 8
+	}
+	return rawbits
+}
+
+// readCode reads a code of given length and at given index in an array
+// of bits.
+func readCode(rawbits []bool, startIndex, length int) int {
+	res := 0
+	for i := startIndex; i < startIndex+length; i++ {
+		res <<= 1
+		if rawbits[i] {
+			res |= 0x01
+		}
+	}
+	return res
+}
+
+// readByte reads a code of length 8 in an array of bits, padding with
+// zeros.
+func readByte(rawbits []bool, startIndex int) uint8 {
+	n := len(rawbits) - startIndex
+	if n >= 8 {
+		return uint8(readCode(rawbits, startIndex, 8))
+	}
+	return uint8(readCode(rawbits, startIndex, n) << uint(8-n))
+}
+
+// convertBoolArrayToByteArray packs an array of bits into bytes, most
+// significant bit first.
+func convertBoolArrayToByteArray(boolArr []bool) []uint8 {
+	byteArr := make([]uint8, (len(boolArr)+7)/8)
+	for i := range byteArr {
+		byteArr[i] = readByte(boolArr, 8*i)
+	}
+	return byteArr
+}
+
+func totalBitsInLayer(layers int, compact bool) int {
+	if compact {
+		return (88 + 16*layers) * layers
+	}
+	return (112 + 16*layers) * layers
+}
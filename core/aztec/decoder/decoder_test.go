@@ -0,0 +1,73 @@
+/*
+ * Copyright 2010 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/aztec/decoder"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+func toBooleanArray(bits string) []bool {
+	bits = strings.Replace(bits, " ", "", -1)
+	result := make([]bool, len(bits))
+	for i, c := range bits {
+		result[i] = c == 'X'
+	}
+	return result
+}
+
+func assertHighLevelDecodes(t *testing.T, expected, bits string) {
+	text, err := decoder.HighLevelDecode(toBooleanArray(bits))
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, expected, text, "decoded "+strconv.Quote(text)+" instead of "+strconv.Quote(expected))
+}
+
+func TestDecoder_HighLevelDecode(t *testing.T) {
+	// no ECI codes
+	assertHighLevelDecodes(t, "A. b.",
+		// 'A'  P/S   '. ' L/L    b    D/L    '.'
+		"...X. ..... ...XX XXX.. ...XX XXXX. XX.X")
+	// initial ECI code 26 (switch to UTF-8)
+	assertHighLevelDecodes(t, "Ça",
+		// P/S FLG(n) 2 '2'  '6'  B/S   2     0xc3     0x87     L/L   'a'
+		"..... ..... .X. .X.. X... XXXXX ...X. XX....XX X....XXX XXX.. ...X.")
+	// initial character in the default character set (ISO-8859-1), then ECI 26
+	assertHighLevelDecodes(t, "Ça",
+		// B/S   1     0xc7     P/S FLG(n) 2 '2'  '6'  L/L   'a'
+		"XXXXX ....X XX...XXX ..... ..... .X. .X.. X... XXX.. ...X.")
+	// FNC1
+	assertHighLevelDecodes(t, "\x1dA",
+		// P/S FLG(n) 0   'A'
+		"..... ..... ... ...X.")
+	// U/S from lower returns to lower, M/L and P/S from mixed
+	assertHighLevelDecodes(t, "aB@!c",
+		// L/L   'a'  U/S   'B'  M/L   '@'  P/S   '!'  L/L   'c'
+		"XXX.. ...X. XXX.. ...XX XXX.X X.X.. ..... ..XX. XXX.. ..X..")
+}
+
+func TestDecoder_HighLevelDecodeInvalid(t *testing.T) {
+	// FLG(7) is reserved
+	_, err := decoder.HighLevelDecode(toBooleanArray("..... ..... XXX"))
+	internal.AssertFailure(t, err, "accepted FLG(7)")
+	// ECI digits must be decimal
+	_, err = decoder.HighLevelDecode(toBooleanArray("..... ..... ..X XXXX"))
+	internal.AssertFailure(t, err, "accepted a non-decimal ECI digit")
+}
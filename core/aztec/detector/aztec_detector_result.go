@@ -0,0 +1,67 @@
+/*
+ * Copyright 2010 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector
+
+import (
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/common/detector"
+)
+
+// AztecDetectorResult extends DetectorResult with the parameters of an
+// Aztec code read from its mode message, which the decoder needs to
+// locate the data within the symbol.
+//
+// An Aztec Rune is reported as a compact symbol with no layers, whose
+// number of data codewords is the value of the rune.
+type AztecDetectorResult struct {
+	*detector.DetectorResult
+	compact      bool
+	nbDatablocks int
+	nbLayers     int
+}
+
+func NewAztecDetectorResult(bits *common.BitMatrix, points []*core.ResultPoint, compact bool, nbDatablocks, nbLayers int) *AztecDetectorResult {
+	return &AztecDetectorResult{detector.NewDetectorResult(bits, points), compact, nbDatablocks, nbLayers}
+}
+
+// GetNbLayers returns the number of data layers around the bull's eye.
+func (r *AztecDetectorResult) GetNbLayers() int {
+	return r.nbLayers
+}
+
+// GetNbDatablocks returns the number of data codewords, the rest of the
+// codewords being error correction.
+func (r *AztecDetectorResult) GetNbDatablocks() int {
+	return r.nbDatablocks
+}
+
+// IsCompact reports whether the symbol is a compact Aztec code.
+func (r *AztecDetectorResult) IsCompact() bool {
+	return r.compact
+}
+
+// IsRune reports whether the symbol is an Aztec Rune, an 11x11 symbol
+// holding a single value in its mode message.
+func (r *AztecDetectorResult) IsRune() bool {
+	return r.compact && r.nbLayers == 0
+}
+
+// GetRuneValue returns the value of an Aztec Rune, from 0 to 255.
+func (r *AztecDetectorResult) GetRuneValue() int {
+	return r.nbDatablocks
+}
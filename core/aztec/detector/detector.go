@@ -0,0 +1,546 @@
+/*
+ * Copyright 2010 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector
+
+import (
+	"math"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/common/detector"
+)
+
+var expectedCornerBits = []int{
+	0xee0, // 07340  XXX .XX X.. ...
+	0x1dc, // 00734  ... XXX .XX X..
+	0x83b, // 04073  X.. ... XXX .XX
+	0x707, // 03407 .XX X.. ... XXX
+}
+
+// Detector encapsulates logic that can detect an Aztec Code in an image,
+// even if the Aztec Code is rotated or skewed, or partially obscured.
+type Detector struct {
+	image          *common.BitMatrix
+	compact        bool
+	nbLayers       int
+	nbDataBlocks   int
+	nbCenterLayers int
+	shift          int
+}
+
+func NewDetector(image *common.BitMatrix) *Detector {
+	return &Detector{image: image}
+}
+
+// Detect detects an Aztec Code in an image.
+// It returns the AztecDetectorResult encapsulating results of detecting
+// an Aztec Code, or core.ErrNotFound if no Aztec Code can be found.
+func (d *Detector) Detect() (*AztecDetectorResult, error) {
+	return d.DetectMirror(false)
+}
+
+// DetectMirror detects an Aztec Code in an image, reading it as mirrored
+// if isMirror is set.
+// It returns the AztecDetectorResult encapsulating results of detecting
+// an Aztec Code, or core.ErrNotFound if no Aztec Code can be found.
+func (d *Detector) DetectMirror(isMirror bool) (*AztecDetectorResult, error) {
+	// 1. Get the center of the aztec matrix
+	pCenter := d.getMatrixCenter()
+
+	// 2. Get the center points of the four diagonal points just outside the bull's eye
+	//  [topRight, bottomRight, bottomLeft, topLeft]
+	bullsEyeCorners, err := d.getBullsEyeCorners(pCenter)
+	if err != nil {
+		return nil, err
+	}
+
+	if isMirror {
+		bullsEyeCorners[0], bullsEyeCorners[2] = bullsEyeCorners[2], bullsEyeCorners[0]
+	}
+
+	// 3. Get the size of the matrix and other parameters from the bull's eye
+	if err := d.extractParameters(bullsEyeCorners); err != nil {
+		return nil, err
+	}
+
+	// 4. Sample the grid
+	bits, err := d.sampleGrid(d.image,
+		bullsEyeCorners[d.shift%4],
+		bullsEyeCorners[(d.shift+1)%4],
+		bullsEyeCorners[(d.shift+2)%4],
+		bullsEyeCorners[(d.shift+3)%4])
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Get the corners of the matrix.
+	corners := d.getMatrixCornerPoints(bullsEyeCorners)
+
+	return NewAztecDetectorResult(bits, corners, d.compact, d.nbDataBlocks, d.nbLayers), nil
+}
+
+// extractParameters extracts the number of data layers and data blocks
+// from the layer around the bull's eye.
+// It returns core.ErrNotFound if the parameters cannot be read.
+func (d *Detector) extractParameters(bullsEyeCorners []*core.ResultPoint) error {
+	if !d.isValidPoint(bullsEyeCorners[0]) || !d.isValidPoint(bullsEyeCorners[1]) ||
+		!d.isValidPoint(bullsEyeCorners[2]) || !d.isValidPoint(bullsEyeCorners[3]) {
+		return core.ErrNotFound
+	}
+	length := 2 * d.nbCenterLayers
+	// Get the bits around the bull's eye
+	sides := []int{
+		d.sampleLine(bullsEyeCorners[0], bullsEyeCorners[1], length), // Right side
+		d.sampleLine(bullsEyeCorners[1], bullsEyeCorners[2], length), // Bottom
+		d.sampleLine(bullsEyeCorners[2], bullsEyeCorners[3], length), // Left side
+		d.sampleLine(bullsEyeCorners[3], bullsEyeCorners[0], length), // Top
+	}
+
+	// bullsEyeCorners[shift] is the corner of the bulls'eye that has three
+	// orientation marks.
+	// sides[shift] is the row/column that goes from the corner with three
+	// orientation marks to the corner with two.
+	shift, err := getRotation(sides, length)
+	if err != nil {
+		return err
+	}
+	d.shift = shift
+
+	// Flatten the parameter bits into a single 28- or 40-bit long
+	var parameterData int64
+	for i := 0; i < 4; i++ {
+		side := sides[(d.shift+i)%4]
+		if d.compact {
+			// Each side of the form ..XXXXXXX. where Xs are parameter data
+			parameterData <<= 7
+			parameterData += int64((side >> 1) & 0x7F)
+		} else {
+			// Each side of the form ..XXXXX.XXXXX. where Xs are parameter data
+			parameterData <<= 10
+			parameterData += int64(((side >> 2) & (0x1f << 5)) + ((side >> 1) & 0x1F))
+		}
+	}
+
+	// Corrects parameter data using RS.  Returns just the data portion
+	// without the error correction.
+	d.nbLayers, d.nbDataBlocks, err = DecodeModeMessage(parameterData, d.compact)
+	if err != nil && d.compact {
+		// An Aztec Rune has no data layers, its value is in the mode message
+		value, runeErr := DecodeRuneMessage(parameterData)
+		if runeErr == nil {
+			d.nbLayers = 0
+			d.nbDataBlocks = value
+			return nil
+		}
+	}
+	return err
+}
+
+// getRotation determines which corner of the bull's eye holds the three
+// orientation marks.
+// It returns the index of that corner, or core.ErrNotFound if the
+// orientation marks cannot be matched.
+func getRotation(sides []int, length int) (int, error) {
+	// In a normal pattern, we expect to See
+	//   **    .*             D       A
+	//   *      *
+	//
+	//   .      *
+	//   ..    ..             C       B
+	//
+	// Grab the 3 bits from each of the sides the form the locator pattern and concatenate
+	// into a 12-bit integer.  Start with the bit at A
+	cornerBits := 0
+	for _, side := range sides {
+		// XX......X where X's are orientation marks
+		t := ((side >> uint(length-2)) << 1) + (side & 1)
+		cornerBits = (cornerBits << 3) + t
+	}
+	// Move the bottom bit to the top, so that the three bits of the locator pattern at A are
+	// together.  cornerBits is now:
+	//  3 orientation bits at A || 3 orientation bits at B || ... || 3 orientation bits at D
+	cornerBits = ((cornerBits & 1) << 11) + (cornerBits >> 1)
+	// The result shift indicates which element of BullsEyeCorners[] goes into the top-left
+	// corner. Since the four rotation values have a Hamming distance of 8, we
+	// can easily tolerate two errors.
+	for shift := 0; shift < 4; shift++ {
+		if bitCount(cornerBits^expectedCornerBits[shift]) <= 2 {
+			return shift, nil
+		}
+	}
+	return 0, core.ErrNotFound
+}
+
+func bitCount(i int) int {
+	count := 0
+	for ; i != 0; i &= i - 1 {
+		count++
+	}
+	return count
+}
+
+// getBullsEyeCorners finds the corners of a bull-eye centered on the
+// passed point. This returns the centers of the diagonal points just
+// outside the bull's eye.
+// It returns the corners [topRight, bottomRight, bottomLeft, topLeft], or
+// core.ErrNotFound if no valid bull-eye can be found.
+func (d *Detector) getBullsEyeCorners(pCenter point) ([]*core.ResultPoint, error) {
+	pina := pCenter
+	pinb := pCenter
+	pinc := pCenter
+	pind := pCenter
+
+	color := true
+
+	for d.nbCenterLayers = 1; d.nbCenterLayers < 9; d.nbCenterLayers++ {
+		pouta := d.getFirstDifferent(pina, color, 1, -1)
+		poutb := d.getFirstDifferent(pinb, color, 1, 1)
+		poutc := d.getFirstDifferent(pinc, color, -1, 1)
+		poutd := d.getFirstDifferent(pind, color, -1, -1)
+
+		//d      a
+		//
+		//c      b
+
+		if d.nbCenterLayers > 2 {
+			q := distancePoints(poutd, pouta) * float32(d.nbCenterLayers) / (distancePoints(pind, pina) * float32(d.nbCenterLayers+2))
+			if q < 0.75 || q > 1.25 || !d.isWhiteOrBlackRectangle(pouta, poutb, poutc, poutd) {
+				break
+			}
+		}
+
+		pina = pouta
+		pinb = poutb
+		pinc = poutc
+		pind = poutd
+
+		color = !color
+	}
+
+	if d.nbCenterLayers != 5 && d.nbCenterLayers != 7 {
+		return nil, core.ErrNotFound
+	}
+
+	d.compact = d.nbCenterLayers == 5
+
+	// Expand the square by .5 pixel in each direction so that we're on the border
+	// between the white square and the black square
+	pinax := core.NewResultPoint(float32(pina.x)+0.5, float32(pina.y)-0.5)
+	pinbx := core.NewResultPoint(float32(pinb.x)+0.5, float32(pinb.y)+0.5)
+	pincx := core.NewResultPoint(float32(pinc.x)-0.5, float32(pinc.y)+0.5)
+	pindx := core.NewResultPoint(float32(pind.x)-0.5, float32(pind.y)-0.5)
+
+	// Expand the square so that its corners are the centers of the points
+	// just outside the bull's eye.
+	return expandSquare([]*core.ResultPoint{pinax, pinbx, pincx, pindx},
+		2*d.nbCenterLayers-3,
+		2*d.nbCenterLayers), nil
+}
+
+// getMatrixCenter finds a candidate center point of an Aztec code from
+// an image.
+func (d *Detector) getMatrixCenter() point {
+	var pointA, pointB, pointC, pointD *core.ResultPoint
+
+	//Get a white rectangle that can be the border of the matrix in center bull's eye or
+	cornerPoints, err := detectWhiteRectangle(d.image, nil)
+	if err == nil {
+		pointA, pointB, pointC, pointD = cornerPoints[0], cornerPoints[1], cornerPoints[2], cornerPoints[3]
+	} else {
+		// This exception can be in case the initial rectangle is white
+		// In that case, surely in the bull's eye, we try to expand the rectangle.
+		cx := int(d.image.GetWidth() / 2)
+		cy := int(d.image.GetHeight() / 2)
+		pointA = d.getFirstDifferent(point{cx + 7, cy - 7}, false, 1, -1).toResultPoint()
+		pointB = d.getFirstDifferent(point{cx + 7, cy + 7}, false, 1, 1).toResultPoint()
+		pointC = d.getFirstDifferent(point{cx - 7, cy + 7}, false, -1, 1).toResultPoint()
+		pointD = d.getFirstDifferent(point{cx - 7, cy - 7}, false, -1, -1).toResultPoint()
+	}
+
+	//Compute the center of the rectangle
+	cx := detector.Round((pointA.GetX() + pointD.GetX() + pointB.GetX() + pointC.GetX()) / 4.0)
+	cy := detector.Round((pointA.GetY() + pointD.GetY() + pointB.GetY() + pointC.GetY()) / 4.0)
+
+	// Redetermine the white rectangle starting from previously computed center.
+	// This will ensure that we end up with a white rectangle in center bull's eye
+	// in order to compute a more accurate center.
+	cornerPoints, err = detectWhiteRectangle(d.image, &point{cx, cy})
+	if err == nil {
+		pointA, pointB, pointC, pointD = cornerPoints[0], cornerPoints[1], cornerPoints[2], cornerPoints[3]
+	} else {
+		// This exception can be in case the initial rectangle is white
+		// In that case we try to expand the rectangle.
+		pointA = d.getFirstDifferent(point{cx + 7, cy - 7}, false, 1, -1).toResultPoint()
+		pointB = d.getFirstDifferent(point{cx + 7, cy + 7}, false, 1, 1).toResultPoint()
+		pointC = d.getFirstDifferent(point{cx - 7, cy + 7}, false, -1, 1).toResultPoint()
+		pointD = d.getFirstDifferent(point{cx - 7, cy - 7}, false, -1, -1).toResultPoint()
+	}
+
+	// Recompute the center of the rectangle
+	cx = detector.Round((pointA.GetX() + pointD.GetX() + pointB.GetX() + pointC.GetX()) / 4.0)
+	cy = detector.Round((pointA.GetY() + pointD.GetY() + pointB.GetY() + pointC.GetY()) / 4.0)
+
+	return point{cx, cy}
+}
+
+// detectWhiteRectangle runs a WhiteRectangleDetector over image, from its
+// center or, if center is not nil, from there with an initial size of 15.
+func detectWhiteRectangle(image *common.BitMatrix, center *point) ([]*core.ResultPoint, error) {
+	var rectangleDetector *detector.WhiteRectangleDetector
+	var err error
+	if center == nil {
+		rectangleDetector, err = detector.NewWhiteRectangleDetector(image)
+	} else {
+		rectangleDetector, err = detector.NewWhiteRectangleDetectorWithInit(image, 15, center.x, center.y)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rectangleDetector.Detect()
+}
+
+// getMatrixCornerPoints gets the Aztec code corners from the bull's eye
+// corners and the parameters.
+func (d *Detector) getMatrixCornerPoints(bullsEyeCorners []*core.ResultPoint) []*core.ResultPoint {
+	return expandSquare(bullsEyeCorners, 2*d.nbCenterLayers, d.getDimension())
+}
+
+// sampleGrid creates a BitMatrix by sampling the provided image.
+// topLeft, topRight, bottomRight, and bottomLeft are the centers of the
+// squares on the diagonal just outside the bull's eye.
+func (d *Detector) sampleGrid(image *common.BitMatrix, topLeft, topRight, bottomRight, bottomLeft *core.ResultPoint) (*common.BitMatrix, error) {
+	sampler := common.GetGridSamplerInstance()
+	dimension := d.getDimension()
+
+	low := float32(dimension)/2.0 - float32(d.nbCenterLayers)
+	high := float32(dimension)/2.0 + float32(d.nbCenterLayers)
+
+	bits, err := sampler.SampleGrid(image,
+		dimension,
+		dimension,
+		low, low, // topleft
+		high, low, // topright
+		high, high, // bottomright
+		low, high, // bottomleft
+		topLeft.GetX(), topLeft.GetY(),
+		topRight.GetX(), topRight.GetY(),
+		bottomRight.GetX(), bottomRight.GetY(),
+		bottomLeft.GetX(), bottomLeft.GetY())
+	if err != nil {
+		return nil, core.ErrNotFound
+	}
+	return bits, nil
+}
+
+// sampleLine samples a line, returning the bits read as an integer, most
+// significant bit first. p1 is the start point (inclusive), p2 the end
+// point (exclusive) and size the number of bits to read.
+func (d *Detector) sampleLine(p1, p2 *core.ResultPoint, size int) int {
+	result := 0
+
+	dist := distanceResultPoints(p1, p2)
+	moduleSize := dist / float32(size)
+	px := p1.GetX()
+	py := p1.GetY()
+	dx := moduleSize * (p2.GetX() - p1.GetX()) / dist
+	dy := moduleSize * (p2.GetY() - p1.GetY()) / dist
+	for i := 0; i < size; i++ {
+		if d.get(detector.Round(px+float32(i)*dx), detector.Round(py+float32(i)*dy)) {
+			result |= 1 << uint(size-i-1)
+		}
+	}
+	return result
+}
+
+// isWhiteOrBlackRectangle reports whether the border of the rectangle
+// passed in parameter is either entirely white or entirely black.
+func (d *Detector) isWhiteOrBlackRectangle(p1, p2, p3, p4 point) bool {
+	corr := 3
+	width := int(d.image.GetWidth())
+	height := int(d.image.GetHeight())
+
+	p1 = point{maxInt(0, p1.x-corr), minInt(height-1, p1.y+corr)}
+	p2 = point{maxInt(0, p2.x-corr), maxInt(0, p2.y-corr)}
+	p3 = point{minInt(width-1, p3.x+corr), maxInt(0, minInt(height-1, p3.y-corr))}
+	p4 = point{minInt(width-1, p4.x+corr), minInt(height-1, p4.y+corr)}
+
+	cInit := d.getColor(p4, p1)
+
+	if cInit == 0 {
+		return false
+	}
+
+	c := d.getColor(p1, p2)
+
+	if c != cInit {
+		return false
+	}
+
+	c = d.getColor(p2, p3)
+
+	if c != cInit {
+		return false
+	}
+
+	c = d.getColor(p3, p4)
+
+	return c == cInit
+}
+
+// getColor gets the color of a segment.
+// It returns 1 if the segment is more than 90% black, -1 if it is more
+// than 90% white, and 0 otherwise.
+func (d *Detector) getColor(p1, p2 point) int {
+	dist := distancePoints(p1, p2)
+	if dist == 0.0 {
+		return 0
+	}
+	dx := float32(p2.x-p1.x) / dist
+	dy := float32(p2.y-p1.y) / dist
+	errorCount := 0
+
+	px := float32(p1.x)
+	py := float32(p1.y)
+
+	colorModel := d.get(p1.x, p1.y)
+
+	iMax := int(math.Floor(float64(dist)))
+	for i := 0; i < iMax; i++ {
+		if d.get(detector.Round(px), detector.Round(py)) != colorModel {
+			errorCount++
+		}
+		px += dx
+		py += dy
+	}
+
+	errRatio := float32(errorCount) / dist
+
+	if errRatio > 0.1 && errRatio < 0.9 {
+		return 0
+	}
+
+	if (errRatio <= 0.1) == colorModel {
+		return 1
+	}
+	return -1
+}
+
+// getFirstDifferent gets the coordinate of the first point with a
+// different color in the given direction.
+func (d *Detector) getFirstDifferent(init point, color bool, dx, dy int) point {
+	x := init.x + dx
+	y := init.y + dy
+
+	for d.isValid(x, y) && d.get(x, y) == color {
+		x += dx
+		y += dy
+	}
+
+	x -= dx
+	y -= dy
+
+	for d.isValid(x, y) && d.get(x, y) == color {
+		x += dx
+	}
+	x -= dx
+
+	for d.isValid(x, y) && d.get(x, y) == color {
+		y += dy
+	}
+	y -= dy
+
+	return point{x, y}
+}
+
+// expandSquare expands the square represented by the corner points by
+// pushing out equally in all directions.
+// cornerPoints are the corners of the square, which has the bull's eye at
+// its center, oldSide is the original length of the side of the square in
+// the target bit matrix and newSide the new length of the side of the
+// square in the target bit matrix.
+func expandSquare(cornerPoints []*core.ResultPoint, oldSide, newSide int) []*core.ResultPoint {
+	ratio := float32(newSide) / (2.0 * float32(oldSide))
+	dx := cornerPoints[0].GetX() - cornerPoints[2].GetX()
+	dy := cornerPoints[0].GetY() - cornerPoints[2].GetY()
+	centerx := (cornerPoints[0].GetX() + cornerPoints[2].GetX()) / 2.0
+	centery := (cornerPoints[0].GetY() + cornerPoints[2].GetY()) / 2.0
+
+	result0 := core.NewResultPoint(centerx+ratio*dx, centery+ratio*dy)
+	result2 := core.NewResultPoint(centerx-ratio*dx, centery-ratio*dy)
+
+	dx = cornerPoints[1].GetX() - cornerPoints[3].GetX()
+	dy = cornerPoints[1].GetY() - cornerPoints[3].GetY()
+	centerx = (cornerPoints[1].GetX() + cornerPoints[3].GetX()) / 2.0
+	centery = (cornerPoints[1].GetY() + cornerPoints[3].GetY()) / 2.0
+	result1 := core.NewResultPoint(centerx+ratio*dx, centery+ratio*dy)
+	result3 := core.NewResultPoint(centerx-ratio*dx, centery-ratio*dy)
+
+	return []*core.ResultPoint{result0, result1, result2, result3}
+}
+
+func (d *Detector) isValid(x, y int) bool {
+	return x >= 0 && x < int(d.image.GetWidth()) && y >= 0 && y < int(d.image.GetHeight())
+}
+
+func (d *Detector) isValidPoint(p *core.ResultPoint) bool {
+	x := detector.Round(p.GetX())
+	y := detector.Round(p.GetY())
+	return d.isValid(x, y)
+}
+
+// get returns the color of the pixel at x, y, treating pixels outside of
+// the image as white.
+func (d *Detector) get(x, y int) bool {
+	return d.isValid(x, y) && d.image.Get(uint32(x), uint32(y))
+}
+
+func distancePoints(a, b point) float32 {
+	return detector.DistanceInt(a.x, a.y, b.x, b.y)
+}
+
+func distanceResultPoints(a, b *core.ResultPoint) float32 {
+	return detector.Distance(a.GetX(), a.GetY(), b.GetX(), b.GetY())
+}
+
+func (d *Detector) getDimension() int {
+	if d.compact {
+		return 4*d.nbLayers + 11
+	}
+	return 4*d.nbLayers + 2*((2*d.nbLayers+6)/15) + 15
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+type point struct {
+	x, y int
+}
+
+func (p point) toResultPoint() *core.ResultPoint {
+	return core.NewResultPoint(float32(p.x), float32(p.y))
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright 2010 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector_test
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/aztec/detector"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/common/reedsolomon"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+// buildSymbol lays out the bull's eye, orientation marks and mode message
+// of an Aztec code around random data modules.
+func buildSymbol(t *testing.T, compact bool, layers, dataBlocks int, random *rand.Rand) *common.BitMatrix {
+	var size, eyeSize, modeData, numDataWords int
+	var modeWords []int
+	if compact {
+		size = 4*layers + 11
+		eyeSize = 5
+		modeData = (layers-1)<<6 | (dataBlocks - 1)
+		modeWords = make([]int, 7)
+		numDataWords = 2
+	} else {
+		size = 4*layers + 2*((2*layers+6)/15) + 15
+		eyeSize = 7
+		modeData = (layers-1)<<11 | (dataBlocks - 1)
+		modeWords = make([]int, 10)
+		numDataWords = 4
+	}
+	for i := numDataWords - 1; i >= 0; i-- {
+		modeWords[i] = modeData & 0xF
+		modeData >>= 4
+	}
+	internal.AssertSuccess(t, reedsolomon.NewReedSolomonEncoder(reedsolomon.AztecParam).Encode(modeWords, len(modeWords)-numDataWords))
+	modeBit := func(i int) bool {
+		return modeWords[i/4]&(8>>uint(i%4)) != 0
+	}
+
+	symbol, err := common.NewBitMatrixFromDimension(uint32(size))
+	internal.AssertSuccess(t, err)
+	center := size / 2
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			ring := int(math.Max(math.Abs(float64(x-center)), math.Abs(float64(y-center))))
+			var black bool
+			switch {
+			case ring < eyeSize:
+				black = ring%2 == 0
+			case ring == eyeSize:
+				continue
+			default:
+				black = random.Intn(2) == 1
+			}
+			setIf(symbol, black, x, y)
+		}
+	}
+	// Orientation marks
+	for _, mark := range [][2]int{
+		{-eyeSize, -eyeSize}, {-eyeSize + 1, -eyeSize}, {-eyeSize, -eyeSize + 1},
+		{eyeSize, -eyeSize}, {eyeSize, -eyeSize + 1}, {eyeSize, eyeSize - 1},
+	} {
+		symbol.Set(uint32(center+mark[0]), uint32(center+mark[1]))
+	}
+	// Mode message
+	if compact {
+		for i := 0; i < 7; i++ {
+			offset := center - 3 + i
+			setIf(symbol, modeBit(i), offset, center-5)
+			setIf(symbol, modeBit(i+7), center+5, offset)
+			setIf(symbol, modeBit(20-i), offset, center+5)
+			setIf(symbol, modeBit(27-i), center-5, offset)
+		}
+	} else {
+		for i := 0; i < 10; i++ {
+			offset := center - 5 + i + i/5
+			setIf(symbol, modeBit(i), offset, center-7)
+			setIf(symbol, modeBit(i+10), center+7, offset)
+			setIf(symbol, modeBit(29-i), offset, center+7)
+			setIf(symbol, modeBit(39-i), center-7, offset)
+		}
+	}
+	return symbol
+}
+
+func setIf(symbol *common.BitMatrix, black bool, x, y int) {
+	if black {
+		symbol.Set(uint32(x), uint32(y))
+	}
+}
+
+// renderRotated draws symbol with the given module size, rotated by angle
+// radians about the center of an image of imageSize pixels square.
+func renderRotated(t *testing.T, symbol *common.BitMatrix, moduleSize float64, angle float64, imageSize int) *common.BitMatrix {
+	size := float64(symbol.GetWidth())
+	center := float64(imageSize) / 2
+	corner := func(x, y float64) (float32, float32) {
+		x = (x - size/2) * moduleSize
+		y = (y - size/2) * moduleSize
+		return float32(center + x*math.Cos(angle) - y*math.Sin(angle)), float32(center + x*math.Sin(angle) + y*math.Cos(angle))
+	}
+	x0, y0 := corner(0, 0)
+	x1, y1 := corner(size, 0)
+	x2, y2 := corner(size, size)
+	x3, y3 := corner(0, size)
+	transform := common.QuadrilateralToQuadrilateral(
+		x0, y0, x1, y1, x2, y2, x3, y3,
+		0, 0, float32(size), 0, float32(size), float32(size), 0, float32(size))
+
+	image, err := common.NewBitMatrixFromDimension(uint32(imageSize))
+	internal.AssertSuccess(t, err)
+	point := make([]float32, 2)
+	for y := 0; y < imageSize; y++ {
+		for x := 0; x < imageSize; x++ {
+			point[0] = float32(x) + 0.5
+			point[1] = float32(y) + 0.5
+			transform.TransformPoints(point)
+			if point[0] < 0 || point[1] < 0 || point[0] >= float32(size) || point[1] >= float32(size) {
+				continue
+			}
+			if symbol.Get(uint32(point[0]), uint32(point[1])) {
+				image.Set(uint32(x), uint32(y))
+			}
+		}
+	}
+	return image
+}
+
+func assertDetects(t *testing.T, compact bool, layers, dataBlocks int, degrees float64) {
+	symbol := buildSymbol(t, compact, layers, dataBlocks, rand.New(rand.NewSource(int64(layers*1000)+int64(degrees))))
+	size := int(symbol.GetWidth())
+	image := renderRotated(t, symbol, 6, degrees*math.Pi/180, size*9+40)
+
+	description := strconv.Itoa(layers) + " layers at " + strconv.Itoa(int(degrees)) + " degrees"
+	result, err := detector.NewDetector(image).Detect()
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, compact, result.IsCompact(), "wrong compactness for "+description)
+	internal.AssertEquals(t, layers, result.GetNbLayers(), "wrong number of layers for "+description)
+	internal.AssertEquals(t, dataBlocks, result.GetNbDatablocks(), "wrong number of data blocks for "+description)
+	internal.AssertEquals(t, 4, len(result.GetPoints()), "expected four corner points")
+	internal.AssertTrue(t, symbol.Equals(result.GetBits()), "sampled bits differ from symbol for "+description+":\n"+result.GetBits().String())
+}
+
+// The bull's eye is traced along its diagonals, which tolerates rotations
+// of a few degrees either side of a quarter turn.
+var rotations = []float64{0, 90, 180, 270, 5, 95, 185, 275, 355}
+
+func TestDetector_Compact(t *testing.T) {
+	for _, degrees := range rotations {
+		assertDetects(t, true, 1, 9, degrees)
+		assertDetects(t, true, 4, 64, degrees)
+	}
+}
+
+func TestDetector_Full(t *testing.T) {
+	for _, degrees := range rotations {
+		assertDetects(t, false, 3, 30, degrees)
+		assertDetects(t, false, 7, 150, degrees)
+	}
+}
+
+func TestDetector_Mirror(t *testing.T) {
+	symbol := buildSymbol(t, true, 2, 20, rand.New(rand.NewSource(3)))
+	size := int(symbol.GetWidth())
+	mirrored, err := common.NewBitMatrixFromDimension(uint32(size))
+	internal.AssertSuccess(t, err)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if symbol.Get(uint32(x), uint32(y)) {
+				mirrored.Set(uint32(size-1-x), uint32(y))
+			}
+		}
+	}
+	image := renderRotated(t, mirrored, 4, 0, size*6+40)
+
+	_, err = detector.NewDetector(image).Detect()
+	internal.AssertFailure(t, err, "read the orientation of a mirrored symbol")
+	result, err := detector.NewDetector(image).DetectMirror(true)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 2, result.GetNbLayers(), "wrong number of layers")
+	internal.AssertEquals(t, 20, result.GetNbDatablocks(), "wrong number of data blocks")
+	internal.AssertTrue(t, symbol.Equals(result.GetBits()), "sampled bits differ from the unmirrored symbol:\n"+result.GetBits().String())
+}
+
+func TestDetector_EmptyImage(t *testing.T) {
+	image, err := common.NewBitMatrixFromDimension(50)
+	internal.AssertSuccess(t, err)
+	_, err = detector.NewDetector(image).Detect()
+	internal.AssertFailure(t, err, "detected a symbol in an empty image")
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2010 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector
+
+import (
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common/reedsolomon"
+)
+
+// runeMask is XORed with the mode message of an Aztec Rune, which tells
+// it apart from the mode message of a compact symbol.
+const runeMask = 0xAAAAAAA
+
+// DecodeModeMessage decodes the mode message of an Aztec code, which
+// gives the number of layers and data codewords of the symbol.
+// parameterData holds the mode message bits read clockwise from the
+// top-left corner of the bull's eye: 28 bits for a compact symbol and 40
+// bits for a full-range one, alignment modules excluded.
+// It returns the number of layers and data codewords, or core.ErrNotFound
+// if the mode message cannot be corrected.
+func DecodeModeMessage(parameterData int64, compact bool) (nbLayers, nbDataBlocks int, err error) {
+	correctedData, err := getCorrectedParameterData(parameterData, compact)
+	if err != nil {
+		return 0, 0, err
+	}
+	if compact {
+		// 8 bits:  2 bits layers and 6 bits data blocks
+		nbLayers = (correctedData >> 6) + 1
+		nbDataBlocks = (correctedData & 0x3F) + 1
+	} else {
+		// 16 bits:  5 bits layers and 11 bits data blocks
+		nbLayers = (correctedData >> 11) + 1
+		nbDataBlocks = (correctedData & 0x7FF) + 1
+	}
+	return nbLayers, nbDataBlocks, nil
+}
+
+// DecodeRuneMessage decodes the mode message of an Aztec Rune, which holds
+// the value of the rune in place of the symbol parameters. parameterData
+// is read as for a compact symbol.
+// It returns the value of the rune, from 0 to 255, or core.ErrNotFound if
+// the mode message cannot be corrected.
+func DecodeRuneMessage(parameterData int64) (int, error) {
+	return getCorrectedParameterData(parameterData^runeMask, true)
+}
+
+// getCorrectedParameterData corrects the parameter bits using
+// Reed-Solomon error correction over GF(16).
+// It returns the data bits, without the error correction, or
+// core.ErrNotFound if there are too many errors.
+func getCorrectedParameterData(parameterData int64, compact bool) (int, error) {
+	var numCodewords, numDataCodewords int
+	if compact {
+		numCodewords = 7
+		numDataCodewords = 2
+	} else {
+		numCodewords = 10
+		numDataCodewords = 4
+	}
+
+	numECCodewords := numCodewords - numDataCodewords
+	parameterWords := make([]int, numCodewords)
+	for i := numCodewords - 1; i >= 0; i-- {
+		parameterWords[i] = int(parameterData & 0xF)
+		parameterData >>= 4
+	}
+	rsDecoder := reedsolomon.NewReedSolomonDecoder(reedsolomon.AztecParam)
+	if _, err := rsDecoder.Decode(parameterWords, numECCodewords); err != nil {
+		return 0, core.ErrNotFound
+	}
+	// Toss the error correction.  Just return the data as an integer
+	result := 0
+	for i := 0; i < numDataCodewords; i++ {
+		result = (result << 4) + parameterWords[i]
+	}
+	return result, nil
+}
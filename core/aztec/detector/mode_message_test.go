@@ -0,0 +1,83 @@
+/*
+ * Copyright 2010 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/aztec/detector"
+	"github.com/discesoft/zxing-go/core/common/reedsolomon"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+func encodeModeMessage(t *testing.T, data, numDataWords, numCodewords int) int64 {
+	words := make([]int, numCodewords)
+	for i := numDataWords - 1; i >= 0; i-- {
+		words[i] = data & 0xF
+		data >>= 4
+	}
+	internal.AssertSuccess(t, reedsolomon.NewReedSolomonEncoder(reedsolomon.AztecParam).Encode(words, numCodewords-numDataWords))
+	var result int64
+	for _, word := range words {
+		result = result<<4 | int64(word)
+	}
+	return result
+}
+
+func TestDecodeModeMessage_Compact(t *testing.T) {
+	// 3 layers, 40 data codewords
+	modeMessage := encodeModeMessage(t, 2<<6|39, 2, 7)
+	nbLayers, nbDataBlocks, err := detector.DecodeModeMessage(modeMessage, true)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 3, nbLayers, "wrong number of layers")
+	internal.AssertEquals(t, 40, nbDataBlocks, "wrong number of data codewords")
+
+	// One corrupted codeword can be corrected
+	nbLayers, nbDataBlocks, err = detector.DecodeModeMessage(modeMessage^0xF00, true)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 3, nbLayers, "wrong number of layers after correction")
+	internal.AssertEquals(t, 40, nbDataBlocks, "wrong number of data codewords after correction")
+}
+
+func TestDecodeModeMessage_Full(t *testing.T) {
+	// 32 layers, 1437 data codewords
+	modeMessage := encodeModeMessage(t, 31<<11|1436, 4, 10)
+	nbLayers, nbDataBlocks, err := detector.DecodeModeMessage(modeMessage, false)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 32, nbLayers, "wrong number of layers")
+	internal.AssertEquals(t, 1437, nbDataBlocks, "wrong number of data codewords")
+
+	_, _, err = detector.DecodeModeMessage(modeMessage^0x5A5A5A5A5A, false)
+	internal.AssertFailure(t, err, "decoded a corrupted mode message")
+}
+
+func TestDecodeRuneMessage(t *testing.T) {
+	for value := 0; value < 256; value++ {
+		modeMessage := encodeModeMessage(t, value, 2, 7) ^ 0xAAAAAAA
+		decoded, err := detector.DecodeRuneMessage(modeMessage)
+		internal.AssertSuccess(t, err)
+		internal.AssertEquals(t, value, decoded, "wrong rune value")
+		decoded, err = detector.DecodeRuneMessage(modeMessage ^ 0x0F00000)
+		internal.AssertSuccess(t, err)
+		internal.AssertEquals(t, value, decoded, "wrong rune value after correction")
+
+		// The mask keeps runes from passing as compact symbols
+		_, _, err = detector.DecodeModeMessage(modeMessage, true)
+		internal.AssertFailure(t, err, "decoded rune "+strconv.Itoa(value)+" as a compact mode message")
+	}
+}
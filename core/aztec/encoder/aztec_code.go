@@ -0,0 +1,54 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import "github.com/discesoft/zxing-go/core/common"
+
+// AztecCode is an encoded Aztec code, along with the parameters chosen for
+// it. An Aztec Rune has no layers and no data codewords.
+type AztecCode struct {
+	compact   bool
+	size      int
+	layers    int
+	codeWords int
+	matrix    *common.BitMatrix
+}
+
+// IsCompact returns true if this is a compact Aztec code.
+func (c *AztecCode) IsCompact() bool {
+	return c.compact
+}
+
+// GetSize returns the size in pixels (width and height).
+func (c *AztecCode) GetSize() int {
+	return c.size
+}
+
+// GetLayers returns the number of levels.
+func (c *AztecCode) GetLayers() int {
+	return c.layers
+}
+
+// GetCodeWords returns the number of data codewords.
+func (c *AztecCode) GetCodeWords() int {
+	return c.codeWords
+}
+
+// GetMatrix returns the symbol image.
+func (c *AztecCode) GetMatrix() *common.BitMatrix {
+	return c.matrix
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+type binaryShiftToken struct {
+	previous             token
+	binaryShiftStart     int
+	binaryShiftByteCount int
+}
+
+func (t *binaryShiftToken) getPrevious() token {
+	return t.previous
+}
+
+func (t *binaryShiftToken) appendTo(bitArray *common.BitArray, text []uint8) {
+	bsbc := t.binaryShiftByteCount
+	for i := 0; i < bsbc; i++ {
+		if i == 0 || (i == 31 && bsbc <= 62) {
+			// We need a header before the first character, and before
+			// character 31 when the total byte code is <= 62
+			bitArray.AppendBits(31, 5) // BINARY_SHIFT
+			if bsbc > 62 {
+				bitArray.AppendBits(uint32(bsbc-31), 16)
+			} else if i == 0 {
+				// 1 <= binaryShiftByteCode <= 62
+				if bsbc < 31 {
+					bitArray.AppendBits(uint32(bsbc), 5)
+				} else {
+					bitArray.AppendBits(31, 5)
+				}
+			} else {
+				// 32 <= binaryShiftCount <= 62 and i == 31
+				bitArray.AppendBits(uint32(bsbc-31), 5)
+			}
+		}
+		bitArray.AppendBits(uint32(text[t.binaryShiftStart+i]), 8)
+	}
+}
+
+func (t *binaryShiftToken) String() string {
+	return "<" + strconv.Itoa(t.binaryShiftStart) + "::" + strconv.Itoa(t.binaryShiftStart+t.binaryShiftByteCount-1) + ">"
+}
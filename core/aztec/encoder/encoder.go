@@ -0,0 +1,436 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/common/reedsolomon"
+)
+
+const (
+	// DefaultECPercent is the default minimal percentage of error check
+	// words.
+	DefaultECPercent = 33
+	// DefaultAztecLayers asks for the smallest symbol the data fits in.
+	DefaultAztecLayers = 0
+
+	maxNbBits        = 32
+	maxNbBitsCompact = 4
+)
+
+var wordSize = []int{
+	4, 6, 6, 8, 8, 8, 8, 8, 8, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10,
+	12, 12, 12, 12, 12, 12, 12, 12, 12, 12,
+}
+
+// Encode encodes data in ISO-8859-1 as an Aztec code, with the default
+// error correction and the smallest symbol size that fits.
+// It returns an error if data cannot be encoded.
+func Encode(data string) (*AztecCode, error) {
+	return EncodeWithParameters(data, DefaultECPercent, DefaultAztecLayers, nil)
+}
+
+// EncodeWithParameters encodes data as an Aztec code.
+//
+// minECCPercent is the minimal percentage of error check words (according
+// to ISO/IEC 24778:2008, a minimum of 23% + 3 words is recommended).
+// userSpecifiedLayers, if non-zero, is the required number of layers: a
+// negative number specifies a compact symbol. If charset is not nil, data
+// is transcoded to it and the symbol starts with its ECI; otherwise data
+// is encoded in ISO-8859-1 without an ECI.
+// It returns an error if data cannot be represented in the character set,
+// or does not fit in the requested symbol.
+func EncodeWithParameters(data string, minECCPercent, userSpecifiedLayers int, charset *common.CharacterSetECI) (*AztecCode, error) {
+	encoding := charset
+	if encoding == nil {
+		encoding = common.ISO8859_1
+	}
+	bytes, ok := encoding.Encode(data)
+	if !ok {
+		return nil, errors.New("Cannot encode contents in " + encoding.GetName())
+	}
+	return EncodeBytes(bytes, minECCPercent, userSpecifiedLayers, charset)
+}
+
+// EncodeBytes encodes data, which holds characters in charset, as an Aztec
+// code. Unless charset is nil, the symbol starts with the ECI designating
+// it. The other parameters are as for EncodeWithParameters.
+// It returns an error if data does not fit in the requested symbol.
+func EncodeBytes(data []uint8, minECCPercent, userSpecifiedLayers int, charset *common.CharacterSetECI) (*AztecCode, error) {
+	// High-level encode
+	bits, err := NewHighLevelEncoderWithCharset(data, charset).Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	// stuff bits and choose symbol size
+	eccBits := int(bits.GetSize())*minECCPercent/100 + 11
+	totalSizeBits := int(bits.GetSize()) + eccBits
+	var compact bool
+	var layers int
+	var totalBits int
+	var wordSizeInBits int
+	var stuffedBits *common.BitArray
+	if userSpecifiedLayers != DefaultAztecLayers {
+		compact = userSpecifiedLayers < 0
+		layers = userSpecifiedLayers
+		if layers < 0 {
+			layers = -layers
+		}
+		maxLayers := maxNbBits
+		if compact {
+			maxLayers = maxNbBitsCompact
+		}
+		if layers > maxLayers {
+			return nil, errors.New("Illegal value " + strconv.Itoa(userSpecifiedLayers) + " for layers")
+		}
+		totalBits = totalBitsInLayer(layers, compact)
+		wordSizeInBits = wordSize[layers]
+		usableBitsInLayers := totalBits - (totalBits % wordSizeInBits)
+		stuffedBits = stuffBits(bits, wordSizeInBits)
+		if int(stuffedBits.GetSize())+eccBits > usableBitsInLayers {
+			return nil, errors.New("Data too large for user specified layer")
+		}
+		if compact && int(stuffedBits.GetSize()) > wordSizeInBits*64 {
+			// Compact format only allows 64 data words, though C4 can hold more words than that
+			return nil, errors.New("Data too large for user specified layer")
+		}
+	} else {
+		// We look at the possible table sizes in the order Compact1, Compact2, Compact3,
+		// Compact4, Normal4,...  Normal(i) for i < 4 isn't typically used since Compact(i+1)
+		// is the same size, but has more data.
+		for i := 0; ; i++ {
+			if i > maxNbBits {
+				return nil, errors.New("Data too large for an Aztec code")
+			}
+			compact = i <= 3
+			layers = i
+			if compact {
+				layers = i + 1
+			}
+			totalBits = totalBitsInLayer(layers, compact)
+			if totalSizeBits > totalBits {
+				continue
+			}
+			// [Re]stuff the bits if this is the first opportunity, or if the
+			// wordSize has changed
+			if stuffedBits == nil || wordSizeInBits != wordSize[layers] {
+				wordSizeInBits = wordSize[layers]
+				stuffedBits = stuffBits(bits, wordSizeInBits)
+			}
+			usableBitsInLayers := totalBits - (totalBits % wordSizeInBits)
+			if compact && int(stuffedBits.GetSize()) > wordSizeInBits*64 {
+				// Compact format only allows 64 data words, though C4 can hold more words than that
+				continue
+			}
+			if int(stuffedBits.GetSize())+eccBits <= usableBitsInLayers {
+				break
+			}
+		}
+	}
+	messageBits, err := generateCheckWords(stuffedBits, totalBits, wordSizeInBits)
+	if err != nil {
+		return nil, err
+	}
+
+	// generate mode message
+	messageSizeInWords := int(stuffedBits.GetSize()) / wordSizeInBits
+	modeMessage, err := generateModeMessage(compact, layers, messageSizeInWords)
+	if err != nil {
+		return nil, err
+	}
+
+	// allocate symbol
+	baseMatrixSize := 14 + layers*4 // not including alignment lines
+	if compact {
+		baseMatrixSize = 11 + layers*4
+	}
+	alignmentMap := make([]int, baseMatrixSize)
+	var matrixSize int
+	if compact {
+		// no alignment marks in compact mode, alignmentMap is a no-op
+		matrixSize = baseMatrixSize
+		for i := range alignmentMap {
+			alignmentMap[i] = i
+		}
+	} else {
+		matrixSize = baseMatrixSize + 1 + 2*((baseMatrixSize/2-1)/15)
+		origCenter := baseMatrixSize / 2
+		center := matrixSize / 2
+		for i := 0; i < origCenter; i++ {
+			newOffset := i + i/15
+			alignmentMap[origCenter-i-1] = center - newOffset - 1
+			alignmentMap[origCenter+i] = center + newOffset + 1
+		}
+	}
+	matrix, err := common.NewBitMatrixFromDimension(uint32(matrixSize))
+	if err != nil {
+		return nil, err
+	}
+	set := func(x, y int) {
+		matrix.Set(uint32(x), uint32(y))
+	}
+	get := func(i int) bool {
+		return messageBits.Get(uint32(i))
+	}
+
+	// draw data bits
+	for i, rowOffset := 0, 0; i < layers; i++ {
+		rowSize := (layers-i)*4 + 12
+		if compact {
+			rowSize = (layers-i)*4 + 9
+		}
+		for j := 0; j < rowSize; j++ {
+			columnOffset := j * 2
+			for k := 0; k < 2; k++ {
+				if get(rowOffset + columnOffset + k) {
+					set(alignmentMap[i*2+k], alignmentMap[i*2+j])
+				}
+				if get(rowOffset + rowSize*2 + columnOffset + k) {
+					set(alignmentMap[i*2+j], alignmentMap[baseMatrixSize-1-i*2-k])
+				}
+				if get(rowOffset + rowSize*4 + columnOffset + k) {
+					set(alignmentMap[baseMatrixSize-1-i*2-k], alignmentMap[baseMatrixSize-1-i*2-j])
+				}
+				if get(rowOffset + rowSize*6 + columnOffset + k) {
+					set(alignmentMap[baseMatrixSize-1-i*2-j], alignmentMap[i*2+k])
+				}
+			}
+		}
+		rowOffset += rowSize * 8
+	}
+
+	// draw mode message
+	drawModeMessage(matrix, compact, matrixSize, modeMessage)
+
+	// draw alignment marks
+	if compact {
+		drawBullsEye(matrix, matrixSize/2, 5)
+	} else {
+		drawBullsEye(matrix, matrixSize/2, 7)
+		for i, j := 0, 0; i < baseMatrixSize/2-1; i, j = i+15, j+16 {
+			for k := (matrixSize / 2) & 1; k < matrixSize; k += 2 {
+				set(matrixSize/2-j, k)
+				set(matrixSize/2+j, k)
+				set(k, matrixSize/2-j)
+				set(k, matrixSize/2+j)
+			}
+		}
+	}
+
+	return &AztecCode{
+		compact:   compact,
+		size:      matrixSize,
+		layers:    layers,
+		codeWords: messageSizeInWords,
+		matrix:    matrix,
+	}, nil
+}
+
+// EncodeRune encodes value, from 0 to 255, as an Aztec Rune: an 11x11
+// symbol made of just the compact bull's eye and a mode message holding
+// the value.
+// It returns an error if value is out of range.
+func EncodeRune(value int) (*AztecCode, error) {
+	if value < 0 || value > 255 {
+		return nil, errors.New("Illegal value " + strconv.Itoa(value) + " for an Aztec Rune")
+	}
+	message := common.NewEmptyBitArray()
+	message.AppendBits(uint32(value), 8)
+	modeMessage, err := generateCheckWords(message, 28, 4)
+	if err != nil {
+		return nil, err
+	}
+	// Invert every other bit, starting with the first, which tells a rune
+	// apart from a compact symbol
+	for i := uint32(0); i < 28; i += 2 {
+		modeMessage.Flip(i)
+	}
+
+	matrix, err := common.NewBitMatrixFromDimension(11)
+	if err != nil {
+		return nil, err
+	}
+	drawModeMessage(matrix, true, 11, modeMessage)
+	drawBullsEye(matrix, 5, 5)
+	return &AztecCode{
+		compact: true,
+		size:    11,
+		matrix:  matrix,
+	}, nil
+}
+
+func drawBullsEye(matrix *common.BitMatrix, center, size int) {
+	set := func(x, y int) {
+		matrix.Set(uint32(x), uint32(y))
+	}
+	for i := 0; i < size; i += 2 {
+		for j := center - i; j <= center+i; j++ {
+			set(j, center-i)
+			set(j, center+i)
+			set(center-i, j)
+			set(center+i, j)
+		}
+	}
+	set(center-size, center-size)
+	set(center-size+1, center-size)
+	set(center-size, center-size+1)
+	set(center+size, center-size)
+	set(center+size, center-size+1)
+	set(center+size, center+size-1)
+}
+
+func generateModeMessage(compact bool, layers, messageSizeInWords int) (*common.BitArray, error) {
+	modeMessage := common.NewEmptyBitArray()
+	if compact {
+		modeMessage.AppendBits(uint32(layers-1), 2)
+		modeMessage.AppendBits(uint32(messageSizeInWords-1), 6)
+		return generateCheckWords(modeMessage, 28, 4)
+	}
+	modeMessage.AppendBits(uint32(layers-1), 5)
+	modeMessage.AppendBits(uint32(messageSizeInWords-1), 11)
+	return generateCheckWords(modeMessage, 40, 4)
+}
+
+func drawModeMessage(matrix *common.BitMatrix, compact bool, matrixSize int, modeMessage *common.BitArray) {
+	set := func(x, y int) {
+		matrix.Set(uint32(x), uint32(y))
+	}
+	center := matrixSize / 2
+	if compact {
+		for i := 0; i < 7; i++ {
+			offset := center - 3 + i
+			if modeMessage.Get(uint32(i)) {
+				set(offset, center-5)
+			}
+			if modeMessage.Get(uint32(i + 7)) {
+				set(center+5, offset)
+			}
+			if modeMessage.Get(uint32(20 - i)) {
+				set(offset, center+5)
+			}
+			if modeMessage.Get(uint32(27 - i)) {
+				set(center-5, offset)
+			}
+		}
+	} else {
+		for i := 0; i < 10; i++ {
+			offset := center - 5 + i + i/5
+			if modeMessage.Get(uint32(i)) {
+				set(offset, center-7)
+			}
+			if modeMessage.Get(uint32(i + 10)) {
+				set(center+7, offset)
+			}
+			if modeMessage.Get(uint32(29 - i)) {
+				set(offset, center+7)
+			}
+			if modeMessage.Get(uint32(39 - i)) {
+				set(center-7, offset)
+			}
+		}
+	}
+}
+
+func generateCheckWords(bitArray *common.BitArray, totalBits, wordSize int) (*common.BitArray, error) {
+	// bitArray is guaranteed to be a multiple of the wordSize, so no padding needed
+	messageSizeInWords := int(bitArray.GetSize()) / wordSize
+	gf, err := getGF(wordSize)
+	if err != nil {
+		return nil, err
+	}
+	rs := reedsolomon.NewReedSolomonEncoder(gf)
+	totalWords := totalBits / wordSize
+	messageWords := bitsToWords(bitArray, wordSize, totalWords)
+	if err := rs.Encode(messageWords, totalWords-messageSizeInWords); err != nil {
+		return nil, err
+	}
+	startPad := totalBits % wordSize
+	messageBits := common.NewEmptyBitArray()
+	messageBits.AppendBits(0, uint32(startPad))
+	for _, messageWord := range messageWords {
+		messageBits.AppendBits(uint32(messageWord), uint32(wordSize))
+	}
+	return messageBits, nil
+}
+
+func bitsToWords(stuffedBits *common.BitArray, wordSize, totalWords int) []int {
+	message := make([]int, totalWords)
+	for i, n := 0, int(stuffedBits.GetSize())/wordSize; i < n; i++ {
+		value := 0
+		for j := 0; j < wordSize; j++ {
+			if stuffedBits.Get(uint32(i*wordSize + j)) {
+				value |= 1 << uint(wordSize-j-1)
+			}
+		}
+		message[i] = value
+	}
+	return message
+}
+
+func getGF(wordSize int) (*reedsolomon.GenericGF, error) {
+	switch wordSize {
+	case 4:
+		return reedsolomon.AztecParam, nil
+	case 6:
+		return reedsolomon.AztecData6, nil
+	case 8:
+		return reedsolomon.AztecData8, nil
+	case 10:
+		return reedsolomon.AztecData10, nil
+	case 12:
+		return reedsolomon.AztecData12, nil
+	}
+	return nil, errors.New("Unsupported word size " + strconv.Itoa(wordSize))
+}
+
+// stuffBits splits bits into words of wordSize bits, inserting a stuffed
+// bit into any word that would otherwise be all zeros or all ones.
+func stuffBits(bits *common.BitArray, wordSize int) *common.BitArray {
+	out := common.NewEmptyBitArray()
+
+	n := int(bits.GetSize())
+	mask := (1 << uint(wordSize)) - 2
+	for i := 0; i < n; i += wordSize {
+		word := 0
+		for j := 0; j < wordSize; j++ {
+			if i+j >= n || bits.Get(uint32(i+j)) {
+				word |= 1 << uint(wordSize-1-j)
+			}
+		}
+		if (word & mask) == mask {
+			out.AppendBits(uint32(word&mask), uint32(wordSize))
+			i--
+		} else if (word & mask) == 0 {
+			out.AppendBits(uint32(word|1), uint32(wordSize))
+			i--
+		} else {
+			out.AppendBits(uint32(word), uint32(wordSize))
+		}
+	}
+	return out
+}
+
+func totalBitsInLayer(layers int, compact bool) int {
+	if compact {
+		return (88 + 16*layers) * layers
+	}
+	return (112 + 16*layers) * layers
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder_test
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/aztec/decoder"
+	"github.com/discesoft/zxing-go/core/aztec/detector"
+	"github.com/discesoft/zxing-go/core/aztec/encoder"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+func encodeAndDecode(t *testing.T, data string, minECCPercent, layers int, charset *common.CharacterSetECI) *encoder.AztecCode {
+	aztec, err := encoder.EncodeWithParameters(data, minECCPercent, layers, charset)
+	internal.AssertSuccess(t, err)
+	matrix := aztec.GetMatrix()
+	internal.AssertEquals(t, uint32(aztec.GetSize()), matrix.GetWidth(), "matrix width differs from the symbol size")
+
+	detectorResult := detector.NewAztecDetectorResult(matrix, nil, aztec.IsCompact(), aztec.GetCodeWords(), aztec.GetLayers())
+	result, err := decoder.NewDecoder().Decode(detectorResult)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, data, result.GetText(), "decoded "+strconv.Quote(result.GetText())+" instead of "+strconv.Quote(data))
+	return aztec
+}
+
+func TestEncoder_EncodeDecode(t *testing.T) {
+	for _, test := range []struct {
+		data    string
+		compact bool
+		layers  int
+	}{
+		{"Abc123!", true, 1},
+		{"Lorem ipsum. http://test/", true, 2},
+		{"AAAANAAAANAAAANAAAANAAAANAAAANAAAANAAAANAAAANAAAAN", true, 3},
+		{"http://test/~!@#*^%&)__ ;:'\"[]{}\\|-+-=`1029384", true, 4},
+		{"http://test/~!@#*^%&)__ ;:'\"[]{}\\|-+-=`1029384756<>/?abc" +
+			"Four score and seven our forefathers brought forth", false, 5},
+	} {
+		aztec := encodeAndDecode(t, test.data, encoder.DefaultECPercent, encoder.DefaultAztecLayers, nil)
+		internal.AssertEquals(t, test.compact, aztec.IsCompact(), "wrong symbol type for "+strconv.Quote(test.data))
+		internal.AssertEquals(t, test.layers, aztec.GetLayers(), "wrong number of layers for "+strconv.Quote(test.data))
+	}
+}
+
+func TestEncoder_UserSpecifiedLayers(t *testing.T) {
+	for layers := -4; layers <= 32; layers++ {
+		if layers == 0 {
+			continue
+		}
+		aztec := encodeAndDecode(t, "Aztec", 25, layers, nil)
+		expectedSize := 11 + 4*-layers
+		if layers > 0 {
+			baseMatrixSize := 14 + 4*layers
+			expectedSize = baseMatrixSize + 1 + 2*((baseMatrixSize/2-1)/15)
+		}
+		internal.AssertEquals(t, layers < 0, aztec.IsCompact(), "wrong symbol type for "+strconv.Itoa(layers)+" layers")
+		internal.AssertEquals(t, expectedSize, aztec.GetSize(), "wrong symbol size for "+strconv.Itoa(layers)+" layers")
+	}
+
+	for _, layers := range []int{-5, 33} {
+		_, err := encoder.EncodeWithParameters("Aztec Code", 25, layers, nil)
+		internal.AssertFailure(t, err, "encoded with "+strconv.Itoa(layers)+" layers")
+	}
+	_, err := encoder.EncodeWithParameters(string(make([]byte, 100)), 25, -1, nil)
+	internal.AssertFailure(t, err, "encoded too much data for a compact 1 layer symbol")
+}
+
+func TestEncoder_ErrorCorrection(t *testing.T) {
+	data := "The quick brown fox jumps over the lazy dog"
+	previousSize := 0
+	for _, percent := range []int{5, 25, 50, 75, 90} {
+		aztec := encodeAndDecode(t, data, percent, encoder.DefaultAztecLayers, nil)
+		internal.AssertTrue(t, aztec.GetSize() >= previousSize, "more error correction gave a smaller symbol")
+		previousSize = aztec.GetSize()
+	}
+}
+
+func TestEncoder_ECI(t *testing.T) {
+	encodeAndDecode(t, "Ça va? Γειά σου κόσμε €", encoder.DefaultECPercent, encoder.DefaultAztecLayers, common.UTF8)
+	encodeAndDecode(t, "Français", encoder.DefaultECPercent, encoder.DefaultAztecLayers, common.ISO8859_1)
+
+	_, err := encoder.EncodeWithParameters("€", encoder.DefaultECPercent, encoder.DefaultAztecLayers, nil)
+	internal.AssertFailure(t, err, "encoded a character outside of ISO-8859-1 without a character set")
+}
+
+func TestEncoder_LargeData(t *testing.T) {
+	var data bytes.Buffer
+	for i := 0; i < 1500; i++ {
+		data.WriteByte(byte('A' + i%26))
+	}
+	aztec := encodeAndDecode(t, data.String(), encoder.DefaultECPercent, encoder.DefaultAztecLayers, nil)
+	internal.AssertFalse(t, aztec.IsCompact(), "expected a full range symbol")
+
+	_, err := encoder.Encode(string(make([]byte, 4000)))
+	internal.AssertFailure(t, err, "encoded data larger than any symbol")
+}
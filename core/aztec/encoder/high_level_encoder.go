@@ -0,0 +1,321 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import "github.com/discesoft/zxing-go/core/common"
+
+var modeNames = []string{"UPPER", "LOWER", "DIGIT", "MIXED", "PUNCT"}
+
+const (
+	modeUpper = 0 // 5 bits
+	modeLower = 1 // 5 bits
+	modeDigit = 2 // 4 bits
+	modeMixed = 3 // 5 bits
+	modePunct = 4 // 5 bits
+)
+
+// The Latch Table shows, for each pair of Modes, the optimal method for
+// getting from one mode to another.  In the worst possible case, this can
+// be up to 14 bits.  In the best possible case, we are already there!
+// The high half-word of each entry gives the number of bits.
+// The low half-word of each entry are the actual bits necessary to change
+var latchTable = [][]int{
+	{
+		0,
+		(5 << 16) + 28,              // UPPER -> LOWER
+		(5 << 16) + 30,              // UPPER -> DIGIT
+		(5 << 16) + 29,              // UPPER -> MIXED
+		(10 << 16) + (29 << 5) + 30, // UPPER -> MIXED -> PUNCT
+	},
+	{
+		(9 << 16) + (30 << 4) + 14, // LOWER -> DIGIT -> UPPER
+		0,
+		(5 << 16) + 30,              // LOWER -> DIGIT
+		(5 << 16) + 29,              // LOWER -> MIXED
+		(10 << 16) + (29 << 5) + 30, // LOWER -> MIXED -> PUNCT
+	},
+	{
+		(4 << 16) + 14,             // DIGIT -> UPPER
+		(9 << 16) + (14 << 5) + 28, // DIGIT -> UPPER -> LOWER
+		0,
+		(9 << 16) + (14 << 5) + 29, // DIGIT -> UPPER -> MIXED
+		(14 << 16) + (14 << 10) + (29 << 5) + 30,
+		// DIGIT -> UPPER -> MIXED -> PUNCT
+	},
+	{
+		(5 << 16) + 29,              // MIXED -> UPPER
+		(5 << 16) + 28,              // MIXED -> LOWER
+		(10 << 16) + (29 << 5) + 30, // MIXED -> UPPER -> DIGIT
+		0,
+		(5 << 16) + 30, // MIXED -> PUNCT
+	},
+	{
+		(5 << 16) + 31,              // PUNCT -> UPPER
+		(10 << 16) + (31 << 5) + 28, // PUNCT -> UPPER -> LOWER
+		(10 << 16) + (31 << 5) + 30, // PUNCT -> UPPER -> DIGIT
+		(10 << 16) + (31 << 5) + 29, // PUNCT -> UPPER -> MIXED
+		0,
+	},
+}
+
+// A reverse mapping from [mode][char] to the encoding for that character
+// in that mode.  An entry of 0 indicates no mapping exists.
+var charMap = func() [][]int {
+	charMap := make([][]int, 5)
+	for i := range charMap {
+		charMap[i] = make([]int, 256)
+	}
+	charMap[modeUpper][' '] = 1
+	for c := 'A'; c <= 'Z'; c++ {
+		charMap[modeUpper][c] = int(c-'A') + 2
+	}
+	charMap[modeLower][' '] = 1
+	for c := 'a'; c <= 'z'; c++ {
+		charMap[modeLower][c] = int(c-'a') + 2
+	}
+	charMap[modeDigit][' '] = 1
+	for c := '0'; c <= '9'; c++ {
+		charMap[modeDigit][c] = int(c-'0') + 2
+	}
+	charMap[modeDigit][','] = 12
+	charMap[modeDigit]['.'] = 13
+	mixedTable := []int{
+		'\x00', ' ', '\x01', '\x02', '\x03', '\x04', '\x05', '\x06', '\a', '\b', '\t', '\n',
+		'\v', '\f', '\r', '\x1b', '\x1c', '\x1d', '\x1e', '\x1f', '@', '\\', '^',
+		'_', '`', '|', '~', '\x7f',
+	}
+	for i, c := range mixedTable {
+		charMap[modeMixed][c] = i
+	}
+	punctTable := []int{
+		'\x00', '\r', '\x00', '\x00', '\x00', '\x00', '!', '"', '#', '$', '%', '&', '\'',
+		'(', ')', '*', '+', ',', '-', '.', '/', ':', ';', '<', '=', '>', '?',
+		'[', ']', '{', '}',
+	}
+	for i, c := range punctTable {
+		if c > 0 {
+			charMap[modePunct][c] = i
+		}
+	}
+	return charMap
+}()
+
+// A map showing the available shift codes.  (The shifts to BINARY are not
+// shown)
+var shiftTable = func() [][]int {
+	shiftTable := make([][]int, 6) // mode shift codes, per table
+	for i := range shiftTable {
+		shiftTable[i] = []int{-1, -1, -1, -1, -1, -1}
+	}
+	shiftTable[modeUpper][modePunct] = 0
+
+	shiftTable[modeLower][modePunct] = 0
+	shiftTable[modeLower][modeUpper] = 28
+
+	shiftTable[modeMixed][modePunct] = 0
+
+	shiftTable[modeDigit][modePunct] = 0
+	shiftTable[modeDigit][modeUpper] = 15
+	return shiftTable
+}()
+
+// HighLevelEncoder produces a near optimal bit representation of text for
+// an Aztec code.
+//
+// It uses a dynamic algorithm: for each prefix of the string, it keeps the
+// set of shortest sequences of codes ending in each mode, shift or Binary
+// Shift, and discards sequences which can never beat another one.
+type HighLevelEncoder struct {
+	text    []uint8
+	charset *common.CharacterSetECI
+}
+
+// NewHighLevelEncoder creates an encoder for text, which is encoded
+// without an ECI.
+func NewHighLevelEncoder(text []uint8) *HighLevelEncoder {
+	return &HighLevelEncoder{text, nil}
+}
+
+// NewHighLevelEncoderWithCharset creates an encoder for text, which holds
+// characters in charset. Unless charset is nil, the encoding is prefixed
+// with the ECI designating it.
+func NewHighLevelEncoderWithCharset(text []uint8, charset *common.CharacterSetECI) *HighLevelEncoder {
+	return &HighLevelEncoder{text, charset}
+}
+
+// Encode returns the text represented by this encoder encoded as a
+// BitArray.
+func (e *HighLevelEncoder) Encode() (*common.BitArray, error) {
+	states := []*state{initialState}
+	if e.charset != nil {
+		eciState, err := initialState.appendFLGn(e.charset.GetValue())
+		if err != nil {
+			return nil, err
+		}
+		states[0] = eciState
+	}
+	for index := 0; index < len(e.text); index++ {
+		var pairCode int
+		var nextChar uint8
+		if index+1 < len(e.text) {
+			nextChar = e.text[index+1]
+		}
+		switch e.text[index] {
+		case '\r':
+			if nextChar == '\n' {
+				pairCode = 2
+			}
+		case '.':
+			if nextChar == ' ' {
+				pairCode = 3
+			}
+		case ',':
+			if nextChar == ' ' {
+				pairCode = 4
+			}
+		case ':':
+			if nextChar == ' ' {
+				pairCode = 5
+			}
+		}
+		if pairCode > 0 {
+			// We have one of the four special PUNCT pairs.  Treat them specially.
+			// Get a new set of states for the two new characters.
+			states = updateStateListForPair(states, index, pairCode)
+			index++
+		} else {
+			// Get a new set of states for the new character.
+			states = e.updateStateListForChar(states, index)
+		}
+	}
+	// We are left with a set of states.  Find the shortest one.
+	minState := states[0]
+	for _, s := range states[1:] {
+		if s.bitCount < minState.bitCount {
+			minState = s
+		}
+	}
+	// Convert it to a bit array, and return.
+	return minState.toBitArray(e.text), nil
+}
+
+// updateStateListForChar updates a set of states for a new character by
+// updating each state for the new character, merging the results, and then
+// removing the non-optimal states.
+func (e *HighLevelEncoder) updateStateListForChar(states []*state, index int) []*state {
+	var result []*state
+	for _, s := range states {
+		result = e.updateStateForChar(s, index, result)
+	}
+	return simplifyStates(result)
+}
+
+// updateStateForChar appends to result the states that represent the
+// possible ways of updating s for the next character.
+func (e *HighLevelEncoder) updateStateForChar(s *state, index int, result []*state) []*state {
+	ch := e.text[index]
+	charInCurrentTable := charMap[s.mode][ch] > 0
+	var stateNoBinary *state
+	for mode := 0; mode <= modePunct; mode++ {
+		charInMode := charMap[mode][ch]
+		if charInMode > 0 {
+			if stateNoBinary == nil {
+				// Only create stateNoBinary the first time it's required.
+				stateNoBinary = s.endBinaryShift(index)
+			}
+			// Try generating the character by latching to its mode
+			if !charInCurrentTable || mode == s.mode || mode == modeDigit {
+				// If the character is in the current table, we don't want to latch to
+				// any other mode except possibly digit (which uses only 4 bits).  Any
+				// other latch would be equally successful *after* this character, and
+				// so wouldn't save any bits.
+				result = append(result, stateNoBinary.latchAndAppend(mode, charInMode))
+			}
+			// Try generating the character by switching to its mode.
+			if !charInCurrentTable && shiftTable[s.mode][mode] >= 0 {
+				// It never makes sense to temporarily shift to another mode if the
+				// character exists in the current mode.  That can never save bits.
+				result = append(result, stateNoBinary.shiftAndAppend(mode, charInMode))
+			}
+		}
+	}
+	if s.binaryShiftByteCount > 0 || charMap[s.mode][ch] == 0 {
+		// It's never worthwhile to go into binary shift mode if you're not already
+		// in binary shift mode, and the character exists in your current mode.
+		// That can never save bits over just outputting the char in the current mode.
+		result = append(result, s.addBinaryShiftChar(index))
+	}
+	return result
+}
+
+func updateStateListForPair(states []*state, index, pairCode int) []*state {
+	var result []*state
+	for _, s := range states {
+		result = updateStateForPair(s, index, pairCode, result)
+	}
+	return simplifyStates(result)
+}
+
+func updateStateForPair(s *state, index, pairCode int, result []*state) []*state {
+	stateNoBinary := s.endBinaryShift(index)
+	// Possibility 1.  Latch to MODE_PUNCT, and then append this code
+	result = append(result, stateNoBinary.latchAndAppend(modePunct, pairCode))
+	if s.mode != modePunct {
+		// Possibility 2.  Shift to MODE_PUNCT, and then append this code.
+		// Every state except MODE_PUNCT (handled above) can shift
+		result = append(result, stateNoBinary.shiftAndAppend(modePunct, pairCode))
+	}
+	if pairCode == 3 || pairCode == 4 {
+		// both characters are in DIGITS.  Sometimes better to just add two digits
+		digitState := stateNoBinary.
+			latchAndAppend(modeDigit, 16-pairCode). // period or comma in DIGIT
+			latchAndAppend(modeDigit, 1)            // space in DIGIT
+		result = append(result, digitState)
+	}
+	if s.binaryShiftByteCount > 0 {
+		// It only makes sense to do the characters as binary if we're already
+		// in binary mode.
+		binaryState := s.addBinaryShiftChar(index).addBinaryShiftChar(index + 1)
+		result = append(result, binaryState)
+	}
+	return result
+}
+
+// simplifyStates removes the states which are never better than another
+// one. Surviving states are kept most recently added first.
+func simplifyStates(states []*state) []*state {
+	var result []*state
+	for _, newState := range states {
+		add := true
+		kept := result[:0]
+		for i, oldState := range result {
+			if oldState.isBetterThanOrEqualTo(newState) {
+				add = false
+				kept = append(kept, result[i:]...)
+				break
+			}
+			if !newState.isBetterThanOrEqualTo(oldState) {
+				kept = append(kept, oldState)
+			}
+		}
+		result = kept
+		if add {
+			result = append([]*state{newState}, result...)
+		}
+	}
+	return result
+}
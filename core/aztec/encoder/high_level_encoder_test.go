@@ -0,0 +1,132 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/aztec/decoder"
+	"github.com/discesoft/zxing-go/core/aztec/encoder"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+func toBooleanArray(bits *common.BitArray) []bool {
+	result := make([]bool, bits.GetSize())
+	for i := range result {
+		result[i] = bits.Get(uint32(i))
+	}
+	return result
+}
+
+func visualize(bits *common.BitArray) string {
+	var result bytes.Buffer
+	for i := uint32(0); i < bits.GetSize(); i++ {
+		if bits.Get(i) {
+			result.WriteByte('X')
+		} else {
+			result.WriteByte('.')
+		}
+	}
+	return result.String()
+}
+
+func highLevelEncode(t *testing.T, text []uint8, charset *common.CharacterSetECI) *common.BitArray {
+	bits, err := encoder.NewHighLevelEncoderWithCharset(text, charset).Encode()
+	internal.AssertSuccess(t, err)
+	return bits
+}
+
+func assertHighLevelEncodes(t *testing.T, text string, charset *common.CharacterSetECI, expected string) {
+	encoding := charset
+	if encoding == nil {
+		encoding = common.ISO8859_1
+	}
+	data, ok := encoding.Encode(text)
+	internal.AssertTrue(t, ok, "cannot encode "+strconv.Quote(text))
+	bits := visualize(highLevelEncode(t, data, charset))
+	expected = strings.Replace(expected, " ", "", -1)
+	internal.AssertEquals(t, expected, bits, "encoded "+strconv.Quote(text)+" as "+bits+" instead of "+expected)
+}
+
+func assertHighLevelRoundTrips(t *testing.T, text string) {
+	data, ok := common.ISO8859_1.Encode(text)
+	internal.AssertTrue(t, ok, "cannot encode "+strconv.Quote(text))
+	bits := highLevelEncode(t, data, nil)
+	decoded, err := decoder.HighLevelDecode(toBooleanArray(bits))
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, text, decoded, "decoded "+strconv.Quote(decoded)+" instead of "+strconv.Quote(text))
+}
+
+func TestHighLevelEncoder_Encode(t *testing.T) {
+	assertHighLevelEncodes(t, "A. b.", nil,
+		// 'A'  P/S   '. ' L/L    b    D/L    '.'
+		"...X. ..... ...XX XXX.. ...XX XXXX. XX.X")
+	assertHighLevelEncodes(t, "0123", nil,
+		// D/L   '0'  '1'  '2'  '3'
+		"XXXX. ..X. ..XX .X.. .X.X")
+	assertHighLevelEncodes(t, "Ça", common.UTF8,
+		// P/S FLG(n) 2 '2'  '6'  B/S   3     0xc3     0x87     0x61
+		"..... ..... .X. .X.. X... XXXXX ...XX XX....XX X....XXX .XX....X")
+}
+
+func TestHighLevelEncoder_RoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"Lorem ipsum dolor sit amet, consectetur adipiscing elit.",
+		"The quick brown fox jumps over the lazy dog. 1234567890",
+		"a, b: c. d\r\ne",
+		"ABCdefGHIjkl mNoPqR ~@^|\\_`",
+		"!\"#$%&'()*+,-./:;<=>?[]{}",
+		"0.5, 1.25 and 3,75",
+		"\x00\x01\x7f\u0080\u00ff binary\x1b bytes",
+		"09 UAG    ^160MEUCIQC0sYS/HpKxnBELR1uB85R20OoqqwFGa0q2uEi" +
+			"Ygh6utAIgLl1aBVM4EOTQtMQQYH9M2Z3Dp4qnA/fwWuQ+M8L3V8U=",
+	} {
+		assertHighLevelRoundTrips(t, text)
+	}
+}
+
+func TestHighLevelEncoder_Binary(t *testing.T) {
+	// Characters which are in none of the modes are always Binary Shifted
+	for _, length := range []int{1, 2, 31, 32, 62, 63, 100, 2078, 2079, 2100} {
+		data := make([]uint8, length)
+		for i := range data {
+			data[i] = uint8(0x80 + i%0x80)
+		}
+		var expectedLength int
+		switch {
+		case length <= 31:
+			expectedLength = 10 + length*8
+		case length <= 62:
+			expectedLength = 20 + length*8
+		case length <= 2078:
+			expectedLength = 21 + length*8
+		default:
+			// A single Binary Shift holds at most 2078 bytes
+			expectedLength = 31 + length*8
+		}
+		bits := highLevelEncode(t, data, nil)
+		internal.AssertEquals(t, expectedLength, int(bits.GetSize()), "wrong length for "+strconv.Itoa(length)+" binary bytes")
+		decoded, err := decoder.HighLevelDecode(toBooleanArray(bits))
+		internal.AssertSuccess(t, err)
+		internal.AssertEquals(t, common.DecodeISO8859_1(data), decoded, "binary bytes did not round trip")
+	}
+}
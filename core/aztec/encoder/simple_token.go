@@ -0,0 +1,44 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+type simpleToken struct {
+	previous token
+	// For normal words, indicates value and bitCount
+	value    int
+	bitCount int
+}
+
+func (t *simpleToken) getPrevious() token {
+	return t.previous
+}
+
+func (t *simpleToken) appendTo(bitArray *common.BitArray, text []uint8) {
+	bitArray.AppendBits(uint32(t.value), uint32(t.bitCount))
+}
+
+func (t *simpleToken) String() string {
+	value := t.value & ((1 << uint(t.bitCount)) - 1)
+	value |= 1 << uint(t.bitCount)
+	return "<" + strconv.FormatInt(int64(value), 2)[1:] + ">"
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+// state represents all information about a sequence necessary to generate
+// the current output. Note that a state is immutable.
+type state struct {
+	// The current mode of the encoding (or the mode to which we'll return if
+	// we're in Binary Shift mode.
+	mode int
+	// The list of tokens that we output. If we are in Binary Shift mode, this
+	// token list does *not* yet included the token for those bytes
+	token token
+	// If non-zero, the number of most recent bytes that should be output
+	// in Binary Shift mode.
+	binaryShiftByteCount int
+	// The total number of bits generated (including Binary Shift).
+	bitCount        int
+	binaryShiftCost int
+}
+
+var initialState = newState(emptyToken, modeUpper, 0, 0)
+
+func newState(tok token, mode, binaryBytes, bitCount int) *state {
+	return &state{
+		mode:                 mode,
+		token:                tok,
+		binaryShiftByteCount: binaryBytes,
+		bitCount:             bitCount,
+		binaryShiftCost:      calculateBinaryShiftCost(binaryBytes),
+	}
+}
+
+// appendFLGn creates a new state with an FLG(n) code for the ECI eci,
+// or for FNC1 if eci is negative.
+func (s *state) appendFLGn(eci int) (*state, error) {
+	result := s.shiftAndAppend(modePunct, 0) // 0: FLG(n)
+	tok := result.token
+	bitsAdded := 3
+	if eci < 0 {
+		tok = addToken(tok, 0, 3) // 0: FNC1
+	} else if eci > 999999 {
+		return nil, errors.New("ECI code must be between 0 and 999999")
+	} else {
+		eciDigits := strconv.Itoa(eci)
+		tok = addToken(tok, len(eciDigits), 3) // 1-6: number of ECI digits
+		for i := 0; i < len(eciDigits); i++ {
+			tok = addToken(tok, int(eciDigits[i]-'0'+2), 4)
+		}
+		bitsAdded += len(eciDigits) * 4
+	}
+	return newState(tok, s.mode, 0, result.bitCount+bitsAdded), nil
+}
+
+// latchAndAppend creates a new state representing this state with a latch
+// to a (not necessary different) mode, and then a code.
+func (s *state) latchAndAppend(mode, value int) *state {
+	bitCount := s.bitCount
+	tok := s.token
+	if mode != s.mode {
+		latch := latchTable[s.mode][mode]
+		tok = addToken(tok, latch&0xFFFF, latch>>16)
+		bitCount += latch >> 16
+	}
+	latchModeBitCount := 5
+	if mode == modeDigit {
+		latchModeBitCount = 4
+	}
+	tok = addToken(tok, value, latchModeBitCount)
+	return newState(tok, mode, 0, bitCount+latchModeBitCount)
+}
+
+// shiftAndAppend creates a new state representing this state, with a
+// temporary shift to a different mode to output a single value.
+func (s *state) shiftAndAppend(mode, value int) *state {
+	tok := s.token
+	thisModeBitCount := 5
+	if s.mode == modeDigit {
+		thisModeBitCount = 4
+	}
+	// Shifts exist only to UPPER and PUNCT, both with tokens size 5.
+	tok = addToken(tok, shiftTable[s.mode][mode], thisModeBitCount)
+	tok = addToken(tok, value, 5)
+	return newState(tok, s.mode, 0, s.bitCount+thisModeBitCount+5)
+}
+
+// addBinaryShiftChar creates a new state representing this state, but an
+// additional character output in Binary Shift mode.
+func (s *state) addBinaryShiftChar(index int) *state {
+	tok := s.token
+	mode := s.mode
+	bitCount := s.bitCount
+	if s.mode == modePunct || s.mode == modeDigit {
+		latch := latchTable[mode][modeUpper]
+		tok = addToken(tok, latch&0xFFFF, latch>>16)
+		bitCount += latch >> 16
+		mode = modeUpper
+	}
+	deltaBitCount := 8
+	if s.binaryShiftByteCount == 0 || s.binaryShiftByteCount == 31 {
+		deltaBitCount = 18
+	} else if s.binaryShiftByteCount == 62 {
+		deltaBitCount = 9
+	}
+	result := newState(tok, mode, s.binaryShiftByteCount+1, bitCount+deltaBitCount)
+	if result.binaryShiftByteCount == 2047+31 {
+		// The string is as long as it's allowed to be.  We should end it.
+		result = result.endBinaryShift(index + 1)
+	}
+	return result
+}
+
+// endBinaryShift creates the state identical to this one, but we are no
+// longer in Binary Shift mode.
+func (s *state) endBinaryShift(index int) *state {
+	if s.binaryShiftByteCount == 0 {
+		return s
+	}
+	tok := addBinaryShiftToken(s.token, index-s.binaryShiftByteCount, s.binaryShiftByteCount)
+	return newState(tok, s.mode, 0, s.bitCount)
+}
+
+// isBetterThanOrEqualTo returns true if this state is better (or equal)
+// to be in than other under all possible circumstances.
+func (s *state) isBetterThanOrEqualTo(other *state) bool {
+	newModeBitCount := s.bitCount + (latchTable[s.mode][other.mode] >> 16)
+	if s.binaryShiftByteCount < other.binaryShiftByteCount {
+		// add additional B/S encoding cost of other, if any
+		newModeBitCount += other.binaryShiftCost - s.binaryShiftCost
+	} else if s.binaryShiftByteCount > other.binaryShiftByteCount && other.binaryShiftByteCount > 0 {
+		// maximum possible additional cost (we end up exceeding the 31 byte boundary and other state can stay beneath it)
+		newModeBitCount += 10
+	}
+	return newModeBitCount <= other.bitCount
+}
+
+func (s *state) toBitArray(text []uint8) *common.BitArray {
+	var symbols []token
+	for tok := s.endBinaryShift(len(text)).token; tok != nil; tok = tok.getPrevious() {
+		symbols = append(symbols, tok)
+	}
+	bitArray := common.NewEmptyBitArray()
+	// Add each token to the result in forward order
+	for i := len(symbols) - 1; i >= 0; i-- {
+		symbols[i].appendTo(bitArray, text)
+	}
+	return bitArray
+}
+
+func (s *state) String() string {
+	return modeNames[s.mode] + " bits=" + strconv.Itoa(s.bitCount) + " bytes=" + strconv.Itoa(s.binaryShiftByteCount)
+}
+
+func calculateBinaryShiftCost(binaryShiftByteCount int) int {
+	if binaryShiftByteCount > 62 {
+		return 21 // B/S with extended length
+	}
+	if binaryShiftByteCount > 31 {
+		return 20 // two B/S
+	}
+	if binaryShiftByteCount > 0 {
+		return 10 // one B/S
+	}
+	return 0
+}
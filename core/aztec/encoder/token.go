@@ -0,0 +1,36 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import "github.com/discesoft/zxing-go/core/common"
+
+// token is one link of the chain of output produced while encoding,
+// read back to front.
+type token interface {
+	getPrevious() token
+	appendTo(bitArray *common.BitArray, text []uint8)
+}
+
+var emptyToken token = &simpleToken{nil, 0, 0}
+
+func addToken(previous token, value, bitCount int) token {
+	return &simpleToken{previous, value, bitCount}
+}
+
+func addBinaryShiftToken(previous token, start, byteCount int) token {
+	return &binaryShiftToken{previous, start, byteCount}
+}
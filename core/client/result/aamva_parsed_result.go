@@ -0,0 +1,179 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package result
+
+import "time"
+
+// AAMVAParsedResult holds the contents of a driver's licence or
+// identification card encoded according to the AAMVA DL/ID Card Design
+// Standard, as found in the PDF417 symbol on the back of US and Canadian
+// licences.
+// Text fields are empty and dates zero if the card doesn't carry them.
+type AAMVAParsedResult struct {
+	issuerIdentificationNumber string
+	version                    int
+	jurisdictionVersion        int
+	documentType               string
+	customerID                 string
+	familyName                 string
+	firstName                  string
+	middleName                 string
+	dateOfBirth                time.Time
+	issueDate                  time.Time
+	expirationDate             time.Time
+	sex                        string
+	eyeColor                   string
+	height                     string
+	street                     string
+	street2                    string
+	city                       string
+	jurisdiction               string
+	postalCode                 string
+	country                    string
+	vehicleClass               string
+	restrictions               string
+	endorsements               string
+	documentDiscriminator      string
+	elements                   map[string]string
+}
+
+// GetIssuerIdentificationNumber returns the 6 digit IIN identifying the
+// issuing jurisdiction.
+func (r *AAMVAParsedResult) GetIssuerIdentificationNumber() string {
+	return r.issuerIdentificationNumber
+}
+
+// GetVersion returns the AAMVA version number of the standard the card
+// follows, 1 for the 2000 edition and increasing since.
+func (r *AAMVAParsedResult) GetVersion() int {
+	return r.version
+}
+
+// GetJurisdictionVersion returns the version of the jurisdiction's own
+// fields, or 0 before AAMVA version 2.
+func (r *AAMVAParsedResult) GetJurisdictionVersion() int {
+	return r.jurisdictionVersion
+}
+
+// GetDocumentType returns "DL" for a driver's licence or "ID" for an
+// identification card.
+func (r *AAMVAParsedResult) GetDocumentType() string {
+	return r.documentType
+}
+
+// GetCustomerID returns the licence or ID number (DAQ).
+func (r *AAMVAParsedResult) GetCustomerID() string {
+	return r.customerID
+}
+
+func (r *AAMVAParsedResult) GetFamilyName() string {
+	return r.familyName
+}
+
+func (r *AAMVAParsedResult) GetFirstName() string {
+	return r.firstName
+}
+
+func (r *AAMVAParsedResult) GetMiddleName() string {
+	return r.middleName
+}
+
+func (r *AAMVAParsedResult) GetDateOfBirth() time.Time {
+	return r.dateOfBirth
+}
+
+func (r *AAMVAParsedResult) GetIssueDate() time.Time {
+	return r.issueDate
+}
+
+func (r *AAMVAParsedResult) GetExpirationDate() time.Time {
+	return r.expirationDate
+}
+
+// GetSex returns "M" for male, "F" for female, or "" if not specified.
+func (r *AAMVAParsedResult) GetSex() string {
+	return r.sex
+}
+
+// GetEyeColor returns the ANSI D-20 eye color code, such as "BLU".
+func (r *AAMVAParsedResult) GetEyeColor() string {
+	return r.eyeColor
+}
+
+// GetHeight returns the height as written on the card, such as "069 in"
+// or "175 cm".
+func (r *AAMVAParsedResult) GetHeight() string {
+	return r.height
+}
+
+func (r *AAMVAParsedResult) GetStreet() string {
+	return r.street
+}
+
+func (r *AAMVAParsedResult) GetStreet2() string {
+	return r.street2
+}
+
+func (r *AAMVAParsedResult) GetCity() string {
+	return r.city
+}
+
+// GetJurisdiction returns the state or province code of the address, such
+// as "VA" or "ON".
+func (r *AAMVAParsedResult) GetJurisdiction() string {
+	return r.jurisdiction
+}
+
+func (r *AAMVAParsedResult) GetPostalCode() string {
+	return r.postalCode
+}
+
+// GetCountry returns "USA" or "CAN", or "" before AAMVA version 2.
+func (r *AAMVAParsedResult) GetCountry() string {
+	return r.country
+}
+
+func (r *AAMVAParsedResult) GetVehicleClass() string {
+	return r.vehicleClass
+}
+
+func (r *AAMVAParsedResult) GetRestrictions() string {
+	return r.restrictions
+}
+
+func (r *AAMVAParsedResult) GetEndorsements() string {
+	return r.endorsements
+}
+
+// GetDocumentDiscriminator returns the number identifying this particular
+// card (DCF).
+func (r *AAMVAParsedResult) GetDocumentDiscriminator() string {
+	return r.documentDiscriminator
+}
+
+// GetElement returns the value of any data element by its three letter
+// ID, such as "DCS" or a jurisdiction-specific "ZVA", and whether the
+// card carries it.
+func (r *AAMVAParsedResult) GetElement(id string) (string, bool) {
+	value, ok := r.elements[id]
+	return value, ok
+}
+
+// GetElements returns all data elements of the card, by ID.
+func (r *AAMVAParsedResult) GetElements() map[string]string {
+	return r.elements
+}
@@ -0,0 +1,279 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package result
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/discesoft/zxing-go/core"
+)
+
+const (
+	complianceIndicator = '@'
+
+	// The header is followed by one subfile designator per entry: the
+	// subfile type, its offset and its length.
+	subfileDesignatorLength = 10
+)
+
+// ParseAAMVA parses the text of result, normally decoded from a PDF417
+// symbol, as an AAMVA driver's licence or identification card.
+// The header and subfile directory are validated, and the elements of the
+// DL or ID subfile, along with those of any jurisdiction-specific
+// subfile, are read. Names, dates and licence details are then mapped
+// according to the AAMVA version of the card. Dates which cannot be
+// parsed are left zero.
+// It returns an error if the text is not an AAMVA file.
+func ParseAAMVA(result *core.Result) (*AAMVAParsedResult, error) {
+	text := result.GetText()
+	if len(text) < 21 || text[0] != complianceIndicator {
+		return nil, errors.New("Not an AAMVA file: no compliance indicator")
+	}
+	// The header declares the separators used by the rest of the file
+	elementSeparator := text[1]
+	segmentTerminator := text[3]
+	fileType := text[4:9]
+	if fileType != "ANSI " && fileType != "AAMVA" {
+		return nil, errors.New("Not an AAMVA file: unknown file type " + strconv.Quote(fileType))
+	}
+
+	parsed := &AAMVAParsedResult{elements: map[string]string{}}
+	parsed.issuerIdentificationNumber = text[9:15]
+	if !isDigits(parsed.issuerIdentificationNumber) {
+		return nil, errors.New("Invalid issuer identification number " + strconv.Quote(parsed.issuerIdentificationNumber))
+	}
+	var err error
+	if parsed.version, err = parseDigits(text[15:17]); err != nil {
+		return nil, err
+	}
+	position := 17
+	// The jurisdiction version only exists from version 2 on
+	if parsed.version >= 2 {
+		if parsed.jurisdictionVersion, err = parseDigits(text[17:19]); err != nil {
+			return nil, err
+		}
+		position = 19
+	}
+	entries, err := parseDigits(text[position : position+2])
+	if err != nil {
+		return nil, err
+	}
+	position += 2
+	directoryEnd := position + entries*subfileDesignatorLength
+	if entries == 0 || directoryEnd > len(text) {
+		return nil, errors.New("Invalid AAMVA subfile directory")
+	}
+
+	for i := 0; i < entries; i++ {
+		designator := text[position : position+subfileDesignatorLength]
+		position += subfileDesignatorLength
+		subfileType := designator[:2]
+		offset, err := parseDigits(designator[2:6])
+		if err != nil {
+			return nil, err
+		}
+		length, err := parseDigits(designator[6:10])
+		if err != nil {
+			return nil, err
+		}
+		subfile, err := findSubfile(text, directoryEnd, subfileType, offset, length)
+		if err != nil {
+			return nil, err
+		}
+		if subfileType == "DL" || subfileType == "ID" {
+			if parsed.documentType == "" {
+				parsed.documentType = subfileType
+			}
+		} else if subfileType[0] != 'Z' {
+			return nil, errors.New("Unknown AAMVA subfile type " + strconv.Quote(subfileType))
+		}
+		parseElements(subfile[2:], elementSeparator, segmentTerminator, parsed.elements)
+	}
+	if parsed.documentType == "" {
+		return nil, errors.New("AAMVA file has no DL or ID subfile")
+	}
+
+	mapElements(parsed)
+	return parsed, nil
+}
+
+// findSubfile returns the subfile of subfileType at offset in text, which
+// starts with its type. Some jurisdictions get the offsets in the
+// directory wrong, so failing that the subfile is looked for by its type
+// after the directory, which ends at directoryEnd.
+// It returns an error if the subfile cannot be found.
+func findSubfile(text string, directoryEnd int, subfileType string, offset, length int) (string, error) {
+	if offset < directoryEnd || offset+2 > len(text) || text[offset:offset+2] != subfileType {
+		i := strings.Index(text[directoryEnd:], subfileType)
+		if i < 0 {
+			return "", errors.New("AAMVA subfile " + subfileType + " not found")
+		}
+		offset = directoryEnd + i
+	}
+	end := offset + length
+	if end > len(text) || end <= offset+2 {
+		end = len(text)
+	}
+	return text[offset:end], nil
+}
+
+// parseElements reads the data elements of a subfile, each a three
+// letter ID followed by its value, into elements.
+func parseElements(subfile string, elementSeparator, segmentTerminator byte, elements map[string]string) {
+	if i := strings.IndexByte(subfile, segmentTerminator); i >= 0 {
+		subfile = subfile[:i]
+	}
+	for _, element := range strings.Split(subfile, string(elementSeparator)) {
+		element = strings.TrimRight(element, " \r\n")
+		if len(element) < 3 {
+			continue
+		}
+		id := element[:3]
+		if _, ok := elements[id]; !ok {
+			elements[id] = strings.TrimSpace(element[3:])
+		}
+	}
+}
+
+// mapElements fills in the typed fields of parsed from its elements, which
+// were renamed and reorganized between versions of the standard.
+func mapElements(parsed *AAMVAParsedResult) {
+	element := func(id string) string {
+		return parsed.elements[id]
+	}
+
+	parsed.customerID = element("DAQ")
+	parsed.eyeColor = element("DAY")
+	parsed.height = element("DAU")
+	parsed.street = element("DAG")
+	parsed.street2 = element("DAH")
+	parsed.city = element("DAI")
+	parsed.jurisdiction = element("DAJ")
+	parsed.postalCode = element("DAK")
+	parsed.country = element("DCG")
+	parsed.documentDiscriminator = element("DCF")
+	parsed.sex = parseSex(element("DBC"))
+
+	switch {
+	case parsed.version <= 1:
+		parsed.familyName = element("DAB")
+		parsed.firstName = element("DAC")
+		parsed.middleName = element("DAD")
+		if parsed.familyName == "" {
+			// The full name is family, first and middle name
+			parsed.familyName, parsed.firstName, parsed.middleName = splitName(element("DAA"))
+		}
+		parsed.vehicleClass = element("DAR")
+		parsed.restrictions = element("DAS")
+		parsed.endorsements = element("DAT")
+	case parsed.version <= 3:
+		parsed.familyName = element("DCS")
+		// The given names are first and middle name
+		_, parsed.firstName, parsed.middleName = splitName("," + element("DCT"))
+		parsed.vehicleClass = element("DCA")
+		parsed.restrictions = element("DCB")
+		parsed.endorsements = element("DCD")
+	default:
+		parsed.familyName = element("DCS")
+		parsed.firstName = element("DAC")
+		parsed.middleName = element("DAD")
+		parsed.vehicleClass = element("DCA")
+		parsed.restrictions = element("DCB")
+		parsed.endorsements = element("DCD")
+	}
+
+	// Dates are CCYYMMDD in version 1 and on Canadian cards, and MMDDCCYY
+	// on US cards since
+	yearFirst := parsed.version <= 1 || parsed.country == "CAN"
+	parsed.dateOfBirth = parseDate(element("DBB"), yearFirst)
+	parsed.issueDate = parseDate(element("DBD"), yearFirst)
+	parsed.expirationDate = parseDate(element("DBA"), yearFirst)
+}
+
+// splitName splits a name of up to three comma separated parts. Without
+// commas, the parts after the first may be separated by spaces.
+func splitName(name string) (family, first, middle string) {
+	parts := strings.SplitN(name, ",", 3)
+	if len(parts) == 2 {
+		parts = append(parts[:1], strings.SplitN(strings.TrimSpace(parts[1]), " ", 2)...)
+	}
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		switch i {
+		case 0:
+			family = part
+		case 1:
+			first = part
+		case 2:
+			middle = part
+		}
+	}
+	return family, first, middle
+}
+
+func parseSex(sex string) string {
+	switch sex {
+	case "1", "M":
+		return "M"
+	case "2", "F":
+		return "F"
+	}
+	return ""
+}
+
+// parseDate parses an 8 digit date, either CCYYMMDD if yearFirst is set or
+// MMDDCCYY.
+// It returns the zero time if date is no valid date.
+func parseDate(date string, yearFirst bool) time.Time {
+	if len(date) != 8 || !isDigits(date) {
+		return time.Time{}
+	}
+	yearPart, monthPart, dayPart := date[4:8], date[0:2], date[2:4]
+	if yearFirst {
+		yearPart, monthPart, dayPart = date[0:4], date[4:6], date[6:8]
+	}
+	year, _ := strconv.Atoi(yearPart)
+	month, _ := strconv.Atoi(monthPart)
+	day, _ := strconv.Atoi(dayPart)
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	// time.Date normalizes days and months out of range
+	if t.Month() != time.Month(month) || t.Day() != day {
+		return time.Time{}
+	}
+	return t
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseDigits parses a field of the header made of digits only.
+// It returns an error if s holds anything else.
+func parseDigits(s string) (int, error) {
+	if s == "" || !isDigits(s) {
+		return 0, errors.New("Invalid AAMVA header field " + strconv.Quote(s))
+	}
+	return strconv.Atoi(s)
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package result_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/client/result"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+// buildAAMVA assembles an AAMVA file from its header fields and subfiles,
+// each of which starts with its type, filling in the directory.
+func buildAAMVA(fileType, iin, versions string, subfiles ...string) string {
+	header := "@\n\x1e\r" + fileType + iin + versions
+	entries := strconv.Itoa(len(subfiles))
+	if len(entries) < 2 {
+		entries = "0" + entries
+	}
+	header += entries
+	offset := len(header) + 10*len(subfiles)
+	body := ""
+	for _, subfile := range subfiles {
+		header += subfile[:2] + fourDigits(offset) + fourDigits(len(subfile))
+		offset += len(subfile)
+		body += subfile
+	}
+	return header + body
+}
+
+func fourDigits(value int) string {
+	s := strconv.Itoa(value)
+	for len(s) < 4 {
+		s = "0" + s
+	}
+	return s
+}
+
+func parse(t *testing.T, text string) *result.AAMVAParsedResult {
+	parsed, err := result.ParseAAMVA(core.NewResult(text, nil, nil, core.PDF417))
+	internal.AssertSuccess(t, err)
+	return parsed
+}
+
+func assertDate(t *testing.T, year int, month time.Month, day int, date time.Time, msg string) {
+	internal.AssertTrue(t, date.Equal(time.Date(year, month, day, 0, 0, 0, 0, time.UTC)), msg+": got "+date.String())
+}
+
+func TestParseAAMVA_Version8(t *testing.T) {
+	// A sample in the format of version 08, the 2013 edition of the standard
+	text := "@\n\x1e\rANSI 636000080002DL00410278ZV03190008DLDAQT64235789\nDCSSAMPLE\nDDEN\nDACMICHAEL\nDDFN\nDADJOHN\n" +
+		"DDGN\nDCUJR\nDCAD\nDCBK\nDCDPH\nDBD06062019\nDBB06061986\nDBA12102024\nDBC1\nDAU068 in\nDAYBRO\n" +
+		"DAG2300 WEST BROAD STREET\nDAIRICHMOND\nDAJVA\nDAK232690000  \nDCF2424244747474786102204\nDCGUSA\n" +
+		"DCK123456789\nDDAF\nDDB06062018\nDDC06062020\nDDD1\rZVZVA01\r"
+	parsed := parse(t, text)
+	internal.AssertEquals(t, "636000", parsed.GetIssuerIdentificationNumber(), "IIN")
+	internal.AssertEquals(t, 8, parsed.GetVersion(), "version")
+	internal.AssertEquals(t, 0, parsed.GetJurisdictionVersion(), "jurisdiction version")
+	internal.AssertEquals(t, "DL", parsed.GetDocumentType(), "document type")
+	internal.AssertEquals(t, "T64235789", parsed.GetCustomerID(), "customer ID")
+	internal.AssertEquals(t, "SAMPLE", parsed.GetFamilyName(), "family name")
+	internal.AssertEquals(t, "MICHAEL", parsed.GetFirstName(), "first name")
+	internal.AssertEquals(t, "JOHN", parsed.GetMiddleName(), "middle name")
+	assertDate(t, 1986, time.June, 6, parsed.GetDateOfBirth(), "date of birth")
+	assertDate(t, 2019, time.June, 6, parsed.GetIssueDate(), "issue date")
+	assertDate(t, 2024, time.December, 10, parsed.GetExpirationDate(), "expiration date")
+	internal.AssertEquals(t, "M", parsed.GetSex(), "sex")
+	internal.AssertEquals(t, "BRO", parsed.GetEyeColor(), "eye color")
+	internal.AssertEquals(t, "068 in", parsed.GetHeight(), "height")
+	internal.AssertEquals(t, "2300 WEST BROAD STREET", parsed.GetStreet(), "street")
+	internal.AssertEquals(t, "RICHMOND", parsed.GetCity(), "city")
+	internal.AssertEquals(t, "VA", parsed.GetJurisdiction(), "jurisdiction")
+	internal.AssertEquals(t, "232690000", parsed.GetPostalCode(), "postal code")
+	internal.AssertEquals(t, "USA", parsed.GetCountry(), "country")
+	internal.AssertEquals(t, "D", parsed.GetVehicleClass(), "vehicle class")
+	internal.AssertEquals(t, "K", parsed.GetRestrictions(), "restrictions")
+	internal.AssertEquals(t, "PH", parsed.GetEndorsements(), "endorsements")
+	internal.AssertEquals(t, "2424244747474786102204", parsed.GetDocumentDiscriminator(), "document discriminator")
+
+	value, ok := parsed.GetElement("ZVA")
+	internal.AssertTrue(t, ok, "jurisdiction-specific element")
+	internal.AssertEquals(t, "01", value, "ZVA")
+	_, ok = parsed.GetElement("DAW")
+	internal.AssertFalse(t, ok, "missing element")
+	internal.AssertEquals(t, "1", parsed.GetElements()["DDD"], "DDD")
+}
+
+func TestParseAAMVA_Version1(t *testing.T) {
+	text := buildAAMVA("AAMVA", "636012", "01",
+		"DLDAQ123456789\nDAAPUBLIC,JOHN,Q\nDAG123 MAIN ST\nDAITORONTO\nDAJON\nDAR G\nDASA\nDATM\n"+
+			"DBB19700131\nDBA20250131\nDBD20200131\nDBCF\r")
+	parsed := parse(t, text)
+	internal.AssertEquals(t, 1, parsed.GetVersion(), "version")
+	internal.AssertEquals(t, "PUBLIC", parsed.GetFamilyName(), "family name")
+	internal.AssertEquals(t, "JOHN", parsed.GetFirstName(), "first name")
+	internal.AssertEquals(t, "Q", parsed.GetMiddleName(), "middle name")
+	internal.AssertEquals(t, "G", parsed.GetVehicleClass(), "vehicle class")
+	internal.AssertEquals(t, "A", parsed.GetRestrictions(), "restrictions")
+	internal.AssertEquals(t, "M", parsed.GetEndorsements(), "endorsements")
+	internal.AssertEquals(t, "F", parsed.GetSex(), "sex")
+	assertDate(t, 1970, time.January, 31, parsed.GetDateOfBirth(), "date of birth")
+	assertDate(t, 2025, time.January, 31, parsed.GetExpirationDate(), "expiration date")
+}
+
+func TestParseAAMVA_Version3(t *testing.T) {
+	text := buildAAMVA("ANSI ", "636015", "0300",
+		"IDDAQ0001\nDCSDOE\nDCTJANE ANN\nDBB02291992\nDBC2\nDCGUSA\r")
+	parsed := parse(t, text)
+	internal.AssertEquals(t, "ID", parsed.GetDocumentType(), "document type")
+	internal.AssertEquals(t, "DOE", parsed.GetFamilyName(), "family name")
+	internal.AssertEquals(t, "JANE", parsed.GetFirstName(), "first name")
+	internal.AssertEquals(t, "ANN", parsed.GetMiddleName(), "middle name")
+	internal.AssertEquals(t, "F", parsed.GetSex(), "sex")
+	assertDate(t, 1992, time.February, 29, parsed.GetDateOfBirth(), "leap day")
+
+	text = buildAAMVA("ANSI ", "636015", "0300", "DLDCSDOE\nDCTJANE,ANN\r")
+	parsed = parse(t, text)
+	internal.AssertEquals(t, "JANE", parsed.GetFirstName(), "comma separated first name")
+	internal.AssertEquals(t, "ANN", parsed.GetMiddleName(), "comma separated middle name")
+}
+
+func TestParseAAMVA_Canada(t *testing.T) {
+	text := buildAAMVA("ANSI ", "636028", "1001",
+		"DLDAQ1234\nDCSTREMBLAY\nDACMARIE\nDBB19851224\nDBA20301224\nDCGCAN\nDBC9\r")
+	parsed := parse(t, text)
+	internal.AssertEquals(t, 10, parsed.GetVersion(), "version")
+	internal.AssertEquals(t, 1, parsed.GetJurisdictionVersion(), "jurisdiction version")
+	assertDate(t, 1985, time.December, 24, parsed.GetDateOfBirth(), "date of birth")
+	assertDate(t, 2030, time.December, 24, parsed.GetExpirationDate(), "expiration date")
+	internal.AssertEquals(t, "", parsed.GetSex(), "unspecified sex")
+	internal.AssertTrue(t, parsed.GetIssueDate().IsZero(), "missing issue date")
+}
+
+func TestParseAAMVA_Lenient(t *testing.T) {
+	// A directory with a wrong offset, and an invalid date
+	text := "@\n\x1e\rANSI 636000090001DL00500020DLDAQ42\nDCSROE\nDBB13452000\r"
+	parsed := parse(t, text)
+	internal.AssertEquals(t, "42", parsed.GetCustomerID(), "customer ID")
+	internal.AssertEquals(t, "ROE", parsed.GetFamilyName(), "family name")
+	internal.AssertTrue(t, parsed.GetDateOfBirth().IsZero(), "invalid date")
+}
+
+func TestParseAAMVA_Invalid(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"Hello, World!",
+		"@\n\x1e\rXXXXX636000080001DL00310010DLDAQ1\r",
+		"@\n\x1e\rANSI 63600A080001DL00310010DLDAQ1\r",
+		"@\n\x1e\rANSI 636000080000",
+		"@\n\x1e\rANSI 636000080002DL00410010",
+		"@\n\x1e\rANSI 636000080001ZV00310008ZVZVA01\r",
+		"@\n\x1e\rANSI 636000080001XX00310008XXDAQ01\r",
+		"@\n\x1e\rANSI 636000080001DL00310008ZVZVA01\r",
+	} {
+		_, err := result.ParseAAMVA(core.NewResult(text, nil, nil, core.PDF417))
+		internal.AssertFailure(t, err, strconv.Quote(text)+" was accepted")
+	}
+}
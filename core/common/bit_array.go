@@ -107,7 +107,7 @@ func (ba *BitArray) GetNextUnset(from uint32) uint32 {
 
 	bitsOffset := (from / 32)
 	currentBits := ^(ba.bits[bitsOffset])
-	currentBits &= ^((1 << (from & 0x3F)) - 1)
+	currentBits &= ^((1 << (from & 0x1F)) - 1)
 	for {
 		if currentBits != 0 {
 			break
@@ -119,6 +119,25 @@ func TestBitArray_GetNextSet5(t *testing.T) {
 	}
 }
 
+func TestBitArray_GetNextUnset(t *testing.T) {
+	array := common.NewBitArray(100)
+	array.SetRange(0, 100)
+	array.Flip(40)
+	array.Flip(90)
+	for i := uint32(0); i < array.GetSize(); i++ {
+		var expected uint32
+		switch {
+		case i <= 40:
+			expected = 40
+		case i <= 90:
+			expected = 90
+		default:
+			expected = 100
+		}
+		internal.AssertEquals(t, expected, array.GetNextUnset(i), strconv.Itoa(int(i)))
+	}
+}
+
 func TestBitArray_SetBulk(t *testing.T) {
 	array := common.NewBitArray(64)
 	array.SetBulk(32, 0xFFFF0000)
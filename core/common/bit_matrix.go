@@ -288,6 +288,49 @@ func (bm *BitMatrix) Rotate180() error {
 	return nil
 }
 
+// Rotate90 rotates the current BitMatrix 90 degrees counterclockwise,
+// swapping its width and height.
+func (bm *BitMatrix) Rotate90() {
+	newWidth := bm.height
+	newHeight := bm.width
+	newRowSize := (newWidth + 31) / 32
+	newBits := make([][]uint32, newHeight)
+	for i := range newBits {
+		newBits[i] = make([]uint32, newRowSize)
+	}
+	for y := uint32(0); y < bm.height; y++ {
+		for x := uint32(0); x < bm.width; x++ {
+			if bm.Get(x, y) {
+				newBits[newHeight-1-x][y/32] |= 1 << (y & 0x1f)
+			}
+		}
+	}
+	bm.width = newWidth
+	bm.height = newHeight
+	bm.rowSize = newRowSize
+	bm.bits = newBits
+}
+
+// Rotate rotates the current BitMatrix counterclockwise by degrees, which
+// must be a multiple of 90.
+// It returns an error if degrees is not a multiple of 90, or if there's a
+// problem with the rotation.
+func (bm *BitMatrix) Rotate(degrees int) error {
+	switch ((degrees % 360) + 360) % 360 {
+	case 0:
+		return nil
+	case 90:
+		bm.Rotate90()
+		return nil
+	case 180:
+		return bm.Rotate180()
+	case 270:
+		bm.Rotate90()
+		return bm.Rotate180()
+	}
+	return errors.New("degrees must be a multiple of 90")
+}
+
 // GetEnclosingRectangle calculates the set bounds of a BitMatrix.
 // It returns a four-element []uint32 formatted as such:
 //     []uint32{left, top, width, height}
@@ -345,35 +388,20 @@ func (bm *BitMatrix) GetEnclosingRectangle() []uint32 {
 // If the BitMatrix is completely unset, or white, the result will
 // be `nil`.
 func (bm *BitMatrix) GetTopLeftOnBit() []uint32 {
-	yOffset := uint32(0)
-	xOffset := uint32(0)
-	for yOffset < bm.height-1 {
-		for (xOffset < bm.rowSize-1) && (bm.bits[yOffset][xOffset] == 0) {
-			xOffset++
-		}
-		if bm.bits[yOffset][xOffset] == 0 {
-			yOffset++
-		} else {
-			break
+	for y := uint32(0); y < bm.height; y++ {
+		for x := uint32(0); x < bm.rowSize; x++ {
+			theBits := bm.bits[y][x]
+			if theBits == 0 {
+				continue
+			}
+			bit := uint32(0)
+			for theBits<<(31-bit) == 0 {
+				bit++
+			}
+			return []uint32{x*32 + bit, y}
 		}
 	}
-
-	if (yOffset == bm.height-1) && (xOffset == bm.rowSize-1) {
-		return nil
-	}
-
-	xBitOffset := xOffset * 32
-
-	theBits := bm.bits[yOffset][xOffset]
-	bit := uint32(0)
-
-	for theBits<<(31-bit) == 0 {
-		bit++
-	}
-
-	xBitOffset += bit
-
-	return []uint32{xBitOffset, yOffset}
+	return nil
 }
 
 // GetBottomRightOnBit obtains the bottom-right corner set bit, i.e.
@@ -383,41 +411,20 @@ func (bm *BitMatrix) GetTopLeftOnBit() []uint32 {
 // If the BitMatrix is completely unset, or white, the result will
 // be `nil`.
 func (bm *BitMatrix) GetBottomRightOnBit() []uint32 {
-	yOffset := bm.height - 1
-	xOffset := bm.rowSize - 1
-	for yOffset >= 0 {
-		for (xOffset >= 0) && (bm.bits[yOffset][xOffset] == 0) {
-			if xOffset == 0 {
-				break
+	for y := int(bm.height) - 1; y >= 0; y-- {
+		for x := int(bm.rowSize) - 1; x >= 0; x-- {
+			theBits := bm.bits[y][x]
+			if theBits == 0 {
+				continue
 			}
-			xOffset--
-		}
-		if bm.bits[yOffset][xOffset] == 0 {
-			if yOffset == 0 {
-				break
+			bit := uint32(31)
+			for (theBits >> bit) == 0 {
+				bit--
 			}
-			yOffset--
-		} else {
-			break
+			return []uint32{uint32(x)*32 + bit, uint32(y)}
 		}
 	}
-
-	if (yOffset == 0) && (xOffset == 0) {
-		return nil
-	}
-
-	xBitOffset := xOffset * 32
-
-	theBits := bm.bits[yOffset][xOffset]
-	bit := uint32(31)
-
-	for (theBits >> bit) == 0 {
-		bit--
-	}
-
-	xBitOffset += bit
-
-	return []uint32{xBitOffset, yOffset}
+	return nil
 }
 
 func (bm *BitMatrix) GetWidth() uint32 {
@@ -92,6 +92,19 @@ func TestBitMatrix_OnBit(t *testing.T) {
 
 }
 
+func TestBitMatrix_OnBitWideMatrix(t *testing.T) {
+	matrix, err := common.NewBitMatrix(100, 4)
+	internal.AssertSuccess(t, err)
+	matrix.Set(70, 1)
+	matrix.Set(40, 2)
+	internal.AssertSlicesEqualU32(t, []uint32{70, 1}, matrix.GetTopLeftOnBit(), "top left on bit not {70,1}")
+	internal.AssertSlicesEqualU32(t, []uint32{40, 2}, matrix.GetBottomRightOnBit(), "bottom right on bit not {40,2}")
+	matrix.Set(5, 1)
+	matrix.Set(90, 2)
+	internal.AssertSlicesEqualU32(t, []uint32{5, 1}, matrix.GetTopLeftOnBit(), "top left on bit not {5,1}")
+	internal.AssertSlicesEqualU32(t, []uint32{90, 2}, matrix.GetBottomRightOnBit(), "bottom right on bit not {90,2}")
+}
+
 func TestBitMatrix_RectangularMatrix(t *testing.T) {
 	matrix, err := common.NewBitMatrix(75, 20)
 	internal.AssertSuccess(t, err)
@@ -182,6 +195,37 @@ func TestBitMatrix_Rotate180(t *testing.T) {
 	testRotate180(t, 8, 5)
 }
 
+func TestBitMatrix_Rotate90Simple(t *testing.T) {
+	matrix, err := common.NewBitMatrix(3, 2)
+	internal.AssertSuccess(t, err)
+	matrix.Set(0, 0)
+	matrix.Set(2, 0)
+	matrix.Set(1, 1)
+
+	matrix.Rotate90()
+
+	internal.AssertEquals(t, uint32(2), matrix.GetWidth(), "rotate: width was not swapped")
+	internal.AssertEquals(t, uint32(3), matrix.GetHeight(), "rotate: height was not swapped")
+	internal.AssertTrue(t, matrix.Get(0, 2), "rotate: {0,2} was false")
+	internal.AssertTrue(t, matrix.Get(0, 0), "rotate: {0,0} was false")
+	internal.AssertTrue(t, matrix.Get(1, 1), "rotate: {1,1} was false")
+	internal.AssertFalse(t, matrix.Get(1, 0), "rotate: {1,0} was true")
+}
+
+func TestBitMatrix_Rotate(t *testing.T) {
+	matrix, err := common.NewBitMatrix(40, 3)
+	internal.AssertSuccess(t, err)
+	matrix.Set(35, 1)
+	original := matrix.Clone()
+
+	for _, degrees := range []int{90, 180, 270} {
+		internal.AssertSuccess(t, matrix.Rotate(degrees))
+		internal.AssertSuccess(t, matrix.Rotate(360-degrees))
+		internal.AssertTrue(t, matrix.Equals(original), "rotate: not restored after "+strconv.Itoa(degrees))
+	}
+	internal.AssertFailure(t, matrix.Rotate(45), "rotate: accepted 45 degrees")
+}
+
 func TestBitMatrix_Parse(t *testing.T) {
 	var fullMatrix, centerMatrix, emptyMatrix24 *common.BitMatrix
 
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import "errors"
+
+// BitSource provides an easy abstraction to read bits at a time from a
+// sequence of bytes, where the number of bits read is not often a multiple
+// of 8.
+type BitSource struct {
+	bytes      []uint8
+	byteOffset int
+	bitOffset  int
+}
+
+// NewBitSource constructs a BitSource reading from bytes, starting at
+// the most significant bit of the first byte.
+func NewBitSource(bytes []uint8) *BitSource {
+	return &BitSource{bytes, 0, 0}
+}
+
+// GetBitOffset returns the index of the next bit to be read within the
+// current byte.
+func (bs *BitSource) GetBitOffset() int {
+	return bs.bitOffset
+}
+
+// GetByteOffset returns the index of the byte from which the next bits
+// will be read.
+func (bs *BitSource) GetByteOffset() int {
+	return bs.byteOffset
+}
+
+// ReadBits reads numBits bits from the source, which must be between 1 and
+// 32 inclusive, and no more than the number of bits available.
+// It returns the bits as the least-significant bits of an int, or an error
+// if numBits was out of range.
+func (bs *BitSource) ReadBits(numBits int) (int, error) {
+	if numBits < 1 || numBits > 32 || numBits > bs.Available() {
+		return 0, errors.New("invalid number of bits requested from BitSource")
+	}
+
+	result := 0
+
+	// First, read remainder from current byte
+	if bs.bitOffset > 0 {
+		bitsLeft := 8 - bs.bitOffset
+		toRead := numBits
+		if bitsLeft < toRead {
+			toRead = bitsLeft
+		}
+		bitsToNotRead := uint(bitsLeft - toRead)
+		mask := (0xFF >> uint(8-toRead)) << bitsToNotRead
+		result = (int(bs.bytes[bs.byteOffset]) & mask) >> bitsToNotRead
+		numBits -= toRead
+		bs.bitOffset += toRead
+		if bs.bitOffset == 8 {
+			bs.bitOffset = 0
+			bs.byteOffset++
+		}
+	}
+
+	// Next read whole bytes
+	if numBits > 0 {
+		for numBits >= 8 {
+			result = (result << 8) | int(bs.bytes[bs.byteOffset])
+			bs.byteOffset++
+			numBits -= 8
+		}
+
+		// Finally read a partial byte
+		if numBits > 0 {
+			bitsToNotRead := uint(8 - numBits)
+			mask := (0xFF >> bitsToNotRead) << bitsToNotRead
+			result = (result << uint(numBits)) | ((int(bs.bytes[bs.byteOffset]) & mask) >> bitsToNotRead)
+			bs.bitOffset += numBits
+		}
+	}
+
+	return result, nil
+}
+
+// Available returns the number of bits that can still be read.
+func (bs *BitSource) Available() int {
+	return 8*(len(bs.bytes)-bs.byteOffset) - bs.bitOffset
+}
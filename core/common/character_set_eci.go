@@ -0,0 +1,184 @@
+/*
+ * Copyright 2008 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// CharacterSetECI encapsulates a Character Set ECI, according to "Extended
+// Channel Interpretations" 5.3.1.1 of ISO 18004.
+//
+// Only the character sets which the standard library can represent
+// (ISO-8859-1, US-ASCII, UTF-8 and UTF-16BE) are actually transcoded; text
+// in any other character set is treated as ISO-8859-1.
+type CharacterSetECI struct {
+	values             []int
+	name               string
+	otherEncodingNames []string
+}
+
+// Enum values mirroring the CharacterSetECI enum of the Java library.
+var (
+	Cp437              = &CharacterSetECI{[]int{0, 2}, "Cp437", nil}
+	ISO8859_1          = &CharacterSetECI{[]int{1, 3}, "ISO8859_1", []string{"ISO-8859-1"}}
+	ISO8859_2          = &CharacterSetECI{[]int{4}, "ISO8859_2", []string{"ISO-8859-2"}}
+	ISO8859_3          = &CharacterSetECI{[]int{5}, "ISO8859_3", []string{"ISO-8859-3"}}
+	ISO8859_4          = &CharacterSetECI{[]int{6}, "ISO8859_4", []string{"ISO-8859-4"}}
+	ISO8859_5          = &CharacterSetECI{[]int{7}, "ISO8859_5", []string{"ISO-8859-5"}}
+	ISO8859_6          = &CharacterSetECI{[]int{8}, "ISO8859_6", []string{"ISO-8859-6"}}
+	ISO8859_7          = &CharacterSetECI{[]int{9}, "ISO8859_7", []string{"ISO-8859-7"}}
+	ISO8859_8          = &CharacterSetECI{[]int{10}, "ISO8859_8", []string{"ISO-8859-8"}}
+	ISO8859_9          = &CharacterSetECI{[]int{11}, "ISO8859_9", []string{"ISO-8859-9"}}
+	ISO8859_10         = &CharacterSetECI{[]int{12}, "ISO8859_10", []string{"ISO-8859-10"}}
+	ISO8859_11         = &CharacterSetECI{[]int{13}, "ISO8859_11", []string{"ISO-8859-11"}}
+	ISO8859_13         = &CharacterSetECI{[]int{15}, "ISO8859_13", []string{"ISO-8859-13"}}
+	ISO8859_14         = &CharacterSetECI{[]int{16}, "ISO8859_14", []string{"ISO-8859-14"}}
+	ISO8859_15         = &CharacterSetECI{[]int{17}, "ISO8859_15", []string{"ISO-8859-15"}}
+	ISO8859_16         = &CharacterSetECI{[]int{18}, "ISO8859_16", []string{"ISO-8859-16"}}
+	SJIS               = &CharacterSetECI{[]int{20}, "SJIS", []string{"Shift_JIS"}}
+	Cp1250             = &CharacterSetECI{[]int{21}, "Cp1250", []string{"windows-1250"}}
+	Cp1251             = &CharacterSetECI{[]int{22}, "Cp1251", []string{"windows-1251"}}
+	Cp1252             = &CharacterSetECI{[]int{23}, "Cp1252", []string{"windows-1252"}}
+	Cp1256             = &CharacterSetECI{[]int{24}, "Cp1256", []string{"windows-1256"}}
+	UnicodeBigUnmarked = &CharacterSetECI{[]int{25}, "UnicodeBigUnmarked", []string{"UTF-16BE", "UnicodeBig"}}
+	UTF8               = &CharacterSetECI{[]int{26}, "UTF8", []string{"UTF-8"}}
+	ASCII              = &CharacterSetECI{[]int{27, 170}, "ASCII", []string{"US-ASCII"}}
+	Big5               = &CharacterSetECI{[]int{28}, "Big5", nil}
+	GB18030            = &CharacterSetECI{[]int{29}, "GB18030", []string{"GB2312", "EUC_CN", "GBK"}}
+	EUC_KR             = &CharacterSetECI{[]int{30}, "EUC_KR", []string{"EUC-KR"}}
+)
+
+var characterSetECIs = []*CharacterSetECI{
+	Cp437, ISO8859_1, ISO8859_2, ISO8859_3, ISO8859_4, ISO8859_5, ISO8859_6,
+	ISO8859_7, ISO8859_8, ISO8859_9, ISO8859_10, ISO8859_11, ISO8859_13,
+	ISO8859_14, ISO8859_15, ISO8859_16, SJIS, Cp1250, Cp1251, Cp1252, Cp1256,
+	UnicodeBigUnmarked, UTF8, ASCII, Big5, GB18030, EUC_KR,
+}
+
+// GetCharacterSetECIByValue looks up the character set for an ECI value.
+// It returns an error if value is outside of the valid ECI range, and nil
+// if value is valid but doesn't name a supported character set.
+func GetCharacterSetECIByValue(value int) (*CharacterSetECI, error) {
+	if value < 0 || value >= 900 {
+		return nil, errors.New("ECI value out of range")
+	}
+
+	for _, eci := range characterSetECIs {
+		for _, v := range eci.values {
+			if v == value {
+				return eci, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// GetCharacterSetECIByName looks up a character set by either its Java
+// enum name or one of its common encoding names, ignoring case.
+// It returns nil if no character set has that name.
+func GetCharacterSetECIByName(name string) *CharacterSetECI {
+	for _, eci := range characterSetECIs {
+		if strings.EqualFold(eci.name, name) {
+			return eci
+		}
+		for _, other := range eci.otherEncodingNames {
+			if strings.EqualFold(other, name) {
+				return eci
+			}
+		}
+	}
+	return nil
+}
+
+// GetValue returns the primary ECI value of the character set.
+func (c *CharacterSetECI) GetValue() int {
+	return c.values[0]
+}
+
+// GetName returns the name of the character set.
+func (c *CharacterSetECI) GetName() string {
+	return c.name
+}
+
+// Decode converts bytes in this character set into a string.
+func (c *CharacterSetECI) Decode(b []uint8) string {
+	switch c {
+	case UTF8:
+		return string(b)
+	case UnicodeBigUnmarked:
+		units := make([]uint16, len(b)/2)
+		for i := range units {
+			units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+		}
+		return string(utf16.Decode(units))
+	}
+
+	var result bytes.Buffer
+	for _, value := range b {
+		result.WriteRune(rune(value))
+	}
+	return result.String()
+}
+
+// CanEncode reports whether every character of s is representable in this
+// character set.
+func (c *CharacterSetECI) CanEncode(s string) bool {
+	_, ok := c.Encode(s)
+	return ok
+}
+
+// Encode converts s into bytes in this character set.
+// It returns false as its second value if any character of s cannot be
+// represented, or if the character set cannot be transcoded.
+func (c *CharacterSetECI) Encode(s string) ([]uint8, bool) {
+	switch c {
+	case UTF8:
+		return []uint8(s), utf8.ValidString(s)
+	case UnicodeBigUnmarked:
+		units := utf16.Encode([]rune(s))
+		result := make([]uint8, 0, len(units)*2)
+		for _, unit := range units {
+			result = append(result, uint8(unit>>8), uint8(unit))
+		}
+		return result, true
+	case ISO8859_1, ASCII:
+		limit := rune(0xFF)
+		if c == ASCII {
+			limit = 0x7F
+		}
+		result := make([]uint8, 0, len(s))
+		for _, r := range s {
+			if r > limit {
+				return nil, false
+			}
+			result = append(result, uint8(r))
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+// DecodeISO8859_1 converts bytes into a string, treating each byte as the
+// code point of the same value.
+func DecodeISO8859_1(b []uint8) string {
+	return ISO8859_1.Decode(b)
+}
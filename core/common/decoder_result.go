@@ -0,0 +1,139 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+// DecoderResult encapsulates the result of decoding a matrix of bits. This
+// typically applies to 2D barcode formats, and holds the raw codewords
+// alongside the text they decoded to.
+type DecoderResult struct {
+	rawBytes                       []uint8
+	numBits                        int
+	text                           string
+	byteSegments                   [][]uint8
+	ecLevel                        string
+	errorsCorrected                int
+	erasures                       int
+	other                          interface{}
+	structuredAppendParity         int
+	structuredAppendSequenceNumber int
+	symbologyModifier              int
+}
+
+// NewDecoderResult constructs a DecoderResult without structured append
+// information or a symbology modifier.
+func NewDecoderResult(rawBytes []uint8, text string, byteSegments [][]uint8, ecLevel string) *DecoderResult {
+	return NewDecoderResultWithStructuredAppend(rawBytes, text, byteSegments, ecLevel, -1, -1, 0)
+}
+
+// NewDecoderResultWithModifier constructs a DecoderResult carrying the
+// given symbology identifier modifier.
+func NewDecoderResultWithModifier(rawBytes []uint8, text string, byteSegments [][]uint8, ecLevel string, symbologyModifier int) *DecoderResult {
+	return NewDecoderResultWithStructuredAppend(rawBytes, text, byteSegments, ecLevel, -1, -1, symbologyModifier)
+}
+
+// NewDecoderResultWithStructuredAppend constructs a DecoderResult which is
+// one part of a structured append sequence. saSequence and saParity should
+// both be -1 if the symbol was not part of such a sequence.
+func NewDecoderResultWithStructuredAppend(rawBytes []uint8, text string, byteSegments [][]uint8, ecLevel string, saSequence, saParity, symbologyModifier int) *DecoderResult {
+	return &DecoderResult{
+		rawBytes:                       rawBytes,
+		numBits:                        len(rawBytes) * 8,
+		text:                           text,
+		byteSegments:                   byteSegments,
+		ecLevel:                        ecLevel,
+		structuredAppendParity:         saParity,
+		structuredAppendSequenceNumber: saSequence,
+		symbologyModifier:              symbologyModifier,
+	}
+}
+
+// GetRawBytes returns the raw codewords the result was decoded from.
+func (dr *DecoderResult) GetRawBytes() []uint8 {
+	return dr.rawBytes
+}
+
+// GetNumBits returns how many bits of GetRawBytes are valid, which is
+// typically 8 times its length.
+func (dr *DecoderResult) GetNumBits() int {
+	return dr.numBits
+}
+
+func (dr *DecoderResult) SetNumBits(numBits int) {
+	dr.numBits = numBits
+}
+
+// GetText returns the decoded text.
+func (dr *DecoderResult) GetText() string {
+	return dr.text
+}
+
+// GetByteSegments returns any runs of binary data found while decoding,
+// or nil if there were none.
+func (dr *DecoderResult) GetByteSegments() [][]uint8 {
+	return dr.byteSegments
+}
+
+// GetECLevel returns the name of the error correction level used, or an
+// empty string if the format has none.
+func (dr *DecoderResult) GetECLevel() string {
+	return dr.ecLevel
+}
+
+func (dr *DecoderResult) GetErrorsCorrected() int {
+	return dr.errorsCorrected
+}
+
+func (dr *DecoderResult) SetErrorsCorrected(errorsCorrected int) {
+	dr.errorsCorrected = errorsCorrected
+}
+
+func (dr *DecoderResult) GetErasures() int {
+	return dr.erasures
+}
+
+func (dr *DecoderResult) SetErasures(erasures int) {
+	dr.erasures = erasures
+}
+
+// GetOther returns any format-specific data attached to the result.
+func (dr *DecoderResult) GetOther() interface{} {
+	return dr.other
+}
+
+func (dr *DecoderResult) SetOther(other interface{}) {
+	dr.other = other
+}
+
+// HasStructuredAppend reports whether the symbol was part of a structured
+// append sequence.
+func (dr *DecoderResult) HasStructuredAppend() bool {
+	return dr.structuredAppendParity >= 0 && dr.structuredAppendSequenceNumber >= 0
+}
+
+func (dr *DecoderResult) GetStructuredAppendParity() int {
+	return dr.structuredAppendParity
+}
+
+func (dr *DecoderResult) GetStructuredAppendSequenceNumber() int {
+	return dr.structuredAppendSequenceNumber
+}
+
+// GetSymbologyModifier returns the modifier of the symbology identifier,
+// i.e. the digit following the "]d" of a Data Matrix identifier.
+func (dr *DecoderResult) GetSymbologyModifier() int {
+	return dr.symbologyModifier
+}
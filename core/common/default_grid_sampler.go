@@ -0,0 +1,75 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+// DefaultGridSampler is the GridSampler used unless another is installed
+// with SetGridSampler.
+type DefaultGridSampler struct{}
+
+func (s DefaultGridSampler) SampleGrid(image *BitMatrix, dimensionX, dimensionY int,
+	p1ToX, p1ToY, p2ToX, p2ToY, p3ToX, p3ToY, p4ToX, p4ToY,
+	p1FromX, p1FromY, p2FromX, p2FromY, p3FromX, p3FromY, p4FromX, p4FromY float32) (*BitMatrix, error) {
+
+	transform := QuadrilateralToQuadrilateral(
+		p1ToX, p1ToY, p2ToX, p2ToY, p3ToX, p3ToY, p4ToX, p4ToY,
+		p1FromX, p1FromY, p2FromX, p2FromY, p3FromX, p3FromY, p4FromX, p4FromY)
+
+	return s.SampleGridWithTransform(image, dimensionX, dimensionY, transform)
+}
+
+func (s DefaultGridSampler) SampleGridWithTransform(image *BitMatrix, dimensionX, dimensionY int, transform *PerspectiveTransform) (*BitMatrix, error) {
+	if dimensionX <= 0 || dimensionY <= 0 {
+		return nil, ErrPointsOutsideImage
+	}
+	bits, err := NewBitMatrix(uint32(dimensionX), uint32(dimensionY))
+	if err != nil {
+		return nil, err
+	}
+	width := int(image.GetWidth())
+	height := int(image.GetHeight())
+	points := make([]float32, 2*dimensionX)
+	for y := 0; y < dimensionY; y++ {
+		max := len(points)
+		iValue := float32(y) + 0.5
+		for x := 0; x < max; x += 2 {
+			points[x] = float32(x/2) + 0.5
+			points[x+1] = iValue
+		}
+		transform.TransformPoints(points)
+		// Quick check to see if points transformed to something inside the image;
+		// sufficient to check the endpoints
+		if err := CheckAndNudgePoints(image, points); err != nil {
+			return nil, err
+		}
+		for x := 0; x < max; x += 2 {
+			px := int(points[x])
+			py := int(points[x+1])
+			if px < 0 || px >= width || py < 0 || py >= height {
+				// This feels wrong, but, sometimes if the finder patterns are misidentified, the resulting
+				// transform gets "twisted" such that it maps a straight line of points to a set of points
+				// whose endpoints are in bounds, but others are not. There is probably some mathematical
+				// way to detect this about the transformation that I don't know yet.
+				return nil, ErrPointsOutsideImage
+			}
+			if image.Get(uint32(px), uint32(py)) {
+				// Black(-ish) pixel
+				bits.Set(uint32(x/2), uint32(y))
+			}
+		}
+	}
+	return bits, nil
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2012 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector
+
+import "math"
+
+// Round rounds d to the nearest int, with halves rounding up, much faster
+// than going through math.Round.
+func Round(d float32) int {
+	if d < 0.0 {
+		return int(d - 0.5)
+	}
+	return int(d + 0.5)
+}
+
+// Distance returns the Euclidean distance between (aX, aY) and (bX, bY).
+func Distance(aX, aY, bX, bY float32) float32 {
+	xDiff := float64(aX - bX)
+	yDiff := float64(aY - bY)
+	return float32(math.Sqrt(xDiff*xDiff + yDiff*yDiff))
+}
+
+// DistanceInt returns the Euclidean distance between (aX, aY) and
+// (bX, bY).
+func DistanceInt(aX, aY, bX, bY int) float32 {
+	xDiff := float64(aX - bX)
+	yDiff := float64(aY - bY)
+	return float32(math.Sqrt(xDiff*xDiff + yDiff*yDiff))
+}
+
+// Sum returns the sum of the values of array.
+func Sum(array []int) int {
+	count := 0
+	for _, a := range array {
+		count += a
+	}
+	return count
+}
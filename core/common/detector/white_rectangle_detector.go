@@ -0,0 +1,287 @@
+/*
+ * Copyright 2010 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector
+
+import (
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+const (
+	initSize = 10
+	corr     = 1
+)
+
+// WhiteRectangleDetector detects a candidate barcode-like rectangular
+// region within an image. It starts around the center of the image,
+// increases the size of the candidate region until it finds a white
+// rectangular region. By keeping track of the last black points it
+// encountered, it determines the corners of the barcode.
+type WhiteRectangleDetector struct {
+	image     *common.BitMatrix
+	height    int
+	width     int
+	leftInit  int
+	rightInit int
+	downInit  int
+	upInit    int
+}
+
+// NewWhiteRectangleDetector constructs a detector which starts searching
+// from the center of image.
+// It returns core.ErrNotFound if image is too small to contain a barcode.
+func NewWhiteRectangleDetector(image *common.BitMatrix) (*WhiteRectangleDetector, error) {
+	return NewWhiteRectangleDetectorWithInit(image, initSize, int(image.GetWidth())/2, int(image.GetHeight())/2)
+}
+
+// NewWhiteRectangleDetectorWithInit constructs a detector which starts
+// searching with a region of size initSize centered on (x, y).
+// It returns core.ErrNotFound if that region does not fit within image.
+func NewWhiteRectangleDetectorWithInit(image *common.BitMatrix, initSize, x, y int) (*WhiteRectangleDetector, error) {
+	wrd := &WhiteRectangleDetector{
+		image:  image,
+		height: int(image.GetHeight()),
+		width:  int(image.GetWidth()),
+	}
+	halfsize := initSize / 2
+	wrd.leftInit = x - halfsize
+	wrd.rightInit = x + halfsize
+	wrd.upInit = y - halfsize
+	wrd.downInit = y + halfsize
+	if wrd.upInit < 0 || wrd.leftInit < 0 || wrd.downInit >= wrd.height || wrd.rightInit >= wrd.width {
+		return nil, core.ErrNotFound
+	}
+	return wrd, nil
+}
+
+// Detect detects a candidate barcode-like rectangular region within the
+// image.
+// It returns the four corners of the region, ordered top-most, left-most,
+// right-most then bottom-most, or core.ErrNotFound if no region was found.
+func (wrd *WhiteRectangleDetector) Detect() ([]*core.ResultPoint, error) {
+	left := wrd.leftInit
+	right := wrd.rightInit
+	up := wrd.upInit
+	down := wrd.downInit
+	sizeExceeded := false
+	aBlackPointFoundOnBorder := true
+
+	atLeastOneBlackPointFoundOnRight := false
+	atLeastOneBlackPointFoundOnBottom := false
+	atLeastOneBlackPointFoundOnLeft := false
+	atLeastOneBlackPointFoundOnTop := false
+
+	for aBlackPointFoundOnBorder {
+		aBlackPointFoundOnBorder = false
+
+		// .....
+		// .   |
+		// .....
+		rightBorderNotWhite := true
+		for (rightBorderNotWhite || !atLeastOneBlackPointFoundOnRight) && right < wrd.width {
+			rightBorderNotWhite = wrd.containsBlackPoint(up, down, right, false)
+			if rightBorderNotWhite {
+				right++
+				aBlackPointFoundOnBorder = true
+				atLeastOneBlackPointFoundOnRight = true
+			} else if !atLeastOneBlackPointFoundOnRight {
+				right++
+			}
+		}
+
+		if right >= wrd.width {
+			sizeExceeded = true
+			break
+		}
+
+		// .....
+		// .   .
+		// .___.
+		bottomBorderNotWhite := true
+		for (bottomBorderNotWhite || !atLeastOneBlackPointFoundOnBottom) && down < wrd.height {
+			bottomBorderNotWhite = wrd.containsBlackPoint(left, right, down, true)
+			if bottomBorderNotWhite {
+				down++
+				aBlackPointFoundOnBorder = true
+				atLeastOneBlackPointFoundOnBottom = true
+			} else if !atLeastOneBlackPointFoundOnBottom {
+				down++
+			}
+		}
+
+		if down >= wrd.height {
+			sizeExceeded = true
+			break
+		}
+
+		// .....
+		// |   .
+		// .....
+		leftBorderNotWhite := true
+		for (leftBorderNotWhite || !atLeastOneBlackPointFoundOnLeft) && left >= 0 {
+			leftBorderNotWhite = wrd.containsBlackPoint(up, down, left, false)
+			if leftBorderNotWhite {
+				left--
+				aBlackPointFoundOnBorder = true
+				atLeastOneBlackPointFoundOnLeft = true
+			} else if !atLeastOneBlackPointFoundOnLeft {
+				left--
+			}
+		}
+
+		if left < 0 {
+			sizeExceeded = true
+			break
+		}
+
+		// .___.
+		// .   .
+		// .....
+		topBorderNotWhite := true
+		for (topBorderNotWhite || !atLeastOneBlackPointFoundOnTop) && up >= 0 {
+			topBorderNotWhite = wrd.containsBlackPoint(left, right, up, true)
+			if topBorderNotWhite {
+				up--
+				aBlackPointFoundOnBorder = true
+				atLeastOneBlackPointFoundOnTop = true
+			} else if !atLeastOneBlackPointFoundOnTop {
+				up--
+			}
+		}
+
+		if up < 0 {
+			sizeExceeded = true
+			break
+		}
+	}
+
+	if sizeExceeded {
+		return nil, core.ErrNotFound
+	}
+
+	maxSize := right - left
+
+	var z *core.ResultPoint
+	for i := 1; z == nil && i < maxSize; i++ {
+		z = wrd.getBlackPointOnSegment(float32(left), float32(down-i), float32(left+i), float32(down))
+	}
+	if z == nil {
+		return nil, core.ErrNotFound
+	}
+
+	var t *core.ResultPoint
+	// go down right
+	for i := 1; t == nil && i < maxSize; i++ {
+		t = wrd.getBlackPointOnSegment(float32(left), float32(up+i), float32(left+i), float32(up))
+	}
+	if t == nil {
+		return nil, core.ErrNotFound
+	}
+
+	var x *core.ResultPoint
+	// go down left
+	for i := 1; x == nil && i < maxSize; i++ {
+		x = wrd.getBlackPointOnSegment(float32(right), float32(up+i), float32(right-i), float32(up))
+	}
+	if x == nil {
+		return nil, core.ErrNotFound
+	}
+
+	var y *core.ResultPoint
+	// go up left
+	for i := 1; y == nil && i < maxSize; i++ {
+		y = wrd.getBlackPointOnSegment(float32(right), float32(down-i), float32(right-i), float32(down))
+	}
+	if y == nil {
+		return nil, core.ErrNotFound
+	}
+
+	return wrd.centerEdges(y, z, x, t), nil
+}
+
+func (wrd *WhiteRectangleDetector) getBlackPointOnSegment(aX, aY, bX, bY float32) *core.ResultPoint {
+	dist := Round(Distance(aX, aY, bX, bY))
+	xStep := (bX - aX) / float32(dist)
+	yStep := (bY - aY) / float32(dist)
+
+	for i := 0; i < dist; i++ {
+		x := Round(aX + float32(i)*xStep)
+		y := Round(aY + float32(i)*yStep)
+		if wrd.image.Get(uint32(x), uint32(y)) {
+			return core.NewResultPoint(float32(x), float32(y))
+		}
+	}
+	return nil
+}
+
+// centerEdges recenters the points of a constant distance towards the
+// center. y is the bottom-most point, z the left-most, x the right-most
+// and t the top-most.
+// It returns the recentered points ordered top-most, left-most,
+// right-most then bottom-most.
+func (wrd *WhiteRectangleDetector) centerEdges(y, z, x, t *core.ResultPoint) []*core.ResultPoint {
+	//
+	//       t            t
+	//  z                      x
+	//        x    OR    z
+	//   y                    y
+	//
+
+	yi := y.GetX()
+	yj := y.GetY()
+	zi := z.GetX()
+	zj := z.GetY()
+	xi := x.GetX()
+	xj := x.GetY()
+	ti := t.GetX()
+	tj := t.GetY()
+
+	if yi < float32(wrd.width)/2.0 {
+		return []*core.ResultPoint{
+			core.NewResultPoint(ti-corr, tj+corr),
+			core.NewResultPoint(zi+corr, zj+corr),
+			core.NewResultPoint(xi-corr, xj-corr),
+			core.NewResultPoint(yi+corr, yj-corr),
+		}
+	}
+	return []*core.ResultPoint{
+		core.NewResultPoint(ti+corr, tj+corr),
+		core.NewResultPoint(zi+corr, zj-corr),
+		core.NewResultPoint(xi-corr, xj+corr),
+		core.NewResultPoint(yi-corr, yj-corr),
+	}
+}
+
+// containsBlackPoint determines whether a segment contains a black point.
+// a and b are the start and end of the segment along the axis, and fixed
+// is the value of the other coordinate.
+func (wrd *WhiteRectangleDetector) containsBlackPoint(a, b, fixed int, horizontal bool) bool {
+	if horizontal {
+		for x := a; x <= b; x++ {
+			if wrd.image.Get(uint32(x), uint32(fixed)) {
+				return true
+			}
+		}
+	} else {
+		for y := a; y <= b; y++ {
+			if wrd.image.Get(uint32(fixed), uint32(y)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2022 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+)
+
+// ECIStringBuilder accumulates bytes and characters in the manner of a
+// string builder, while also honouring ECI designators. Bytes appended
+// after an ECI are decoded in the character set it names, and bytes
+// appended before any ECI are decoded as ISO-8859-1.
+type ECIStringBuilder struct {
+	currentBytes   bytes.Buffer
+	result         bytes.Buffer
+	currentCharset *CharacterSetECI
+}
+
+// NewECIStringBuilder constructs an empty ECIStringBuilder.
+func NewECIStringBuilder() *ECIStringBuilder {
+	return &ECIStringBuilder{currentCharset: ISO8859_1}
+}
+
+// AppendByte appends a single byte, in the current character set.
+func (sb *ECIStringBuilder) AppendByte(value uint8) {
+	sb.currentBytes.WriteByte(value)
+}
+
+// AppendBytes appends several bytes, in the current character set.
+func (sb *ECIStringBuilder) AppendBytes(value []uint8) {
+	sb.currentBytes.Write(value)
+}
+
+// AppendString appends the characters of value, each of which is truncated
+// to a single byte in the current character set.
+func (sb *ECIStringBuilder) AppendString(value string) {
+	for _, r := range value {
+		sb.currentBytes.WriteByte(uint8(r))
+	}
+}
+
+// AppendInt appends the decimal representation of value.
+func (sb *ECIStringBuilder) AppendInt(value int) {
+	sb.AppendString(strconv.Itoa(value))
+}
+
+// AppendCharacters appends already-decoded text, bypassing the current
+// character set entirely.
+func (sb *ECIStringBuilder) AppendCharacters(value string) {
+	sb.encodeCurrentBytesIfAny()
+	sb.result.WriteString(value)
+}
+
+// AppendECI switches the character set used for subsequently appended
+// bytes to the one designated by the ECI value.
+// It returns an error if value does not designate a supported character
+// set.
+func (sb *ECIStringBuilder) AppendECI(value int) error {
+	sb.encodeCurrentBytesIfAny()
+	characterSetECI, err := GetCharacterSetECIByValue(value)
+	if err != nil {
+		return err
+	}
+	if characterSetECI == nil {
+		return errors.New("unsupported ECI value " + strconv.Itoa(value))
+	}
+	sb.currentCharset = characterSetECI
+	return nil
+}
+
+func (sb *ECIStringBuilder) encodeCurrentBytesIfAny() {
+	if sb.currentBytes.Len() > 0 {
+		sb.result.WriteString(sb.currentCharset.Decode(sb.currentBytes.Bytes()))
+		sb.currentBytes.Reset()
+	}
+}
+
+// Len returns the number of characters appended so far.
+func (sb *ECIStringBuilder) Len() int {
+	return len([]rune(sb.String()))
+}
+
+// IsEmpty reports whether nothing has been appended yet.
+func (sb *ECIStringBuilder) IsEmpty() bool {
+	return sb.currentBytes.Len() == 0 && sb.result.Len() == 0
+}
+
+func (sb *ECIStringBuilder) String() string {
+	sb.encodeCurrentBytesIfAny()
+	return sb.result.String()
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import "errors"
+
+// ErrPointsOutsideImage is returned by a GridSampler when the transform
+// maps the grid to points well outside of the image.
+var ErrPointsOutsideImage = errors.New("transformed points lie outside of the image")
+
+// GridSampler samples an image for a rectangular matrix of bits of a given
+// dimension. This is used to extract the black/white modules of a 2D
+// barcode like a QR Code found in an image. Because this barcode may be
+// rotated or perspective-distorted, the caller supplies four points in the
+// source image that define known points in the barcode, so that the image
+// may be sampled appropriately.
+//
+// The last argument to SampleGridWithTransform is essentially the core of
+// the GridSampler. Implementations may for example use native code to
+// speed up sampling, and may be installed with SetGridSampler.
+type GridSampler interface {
+	// SampleGrid samples an image for a square matrix of bits of the
+	// given dimension, mapping the four "to" points, in the coordinates
+	// of the sampled grid, onto the four "from" points in the image.
+	SampleGrid(image *BitMatrix, dimensionX, dimensionY int,
+		p1ToX, p1ToY, p2ToX, p2ToY, p3ToX, p3ToY, p4ToX, p4ToY,
+		p1FromX, p1FromY, p2FromX, p2FromY, p3FromX, p3FromY, p4FromX, p4FromY float32) (*BitMatrix, error)
+
+	// SampleGridWithTransform samples an image for a matrix of bits of
+	// the given dimensions, using transform to map the grid into the
+	// image.
+	SampleGridWithTransform(image *BitMatrix, dimensionX, dimensionY int, transform *PerspectiveTransform) (*BitMatrix, error)
+}
+
+var gridSampler GridSampler = DefaultGridSampler{}
+
+// SetGridSampler sets the implementation of GridSampler used by the
+// library. One global instance is stored, which may sound problematic. But,
+// the implementation provided ought to be appropriate for the entire
+// platform, and all uses of this package within the process.
+func SetGridSampler(newGridSampler GridSampler) {
+	gridSampler = newGridSampler
+}
+
+// GetGridSamplerInstance returns the current GridSampler.
+func GetGridSamplerInstance() GridSampler {
+	return gridSampler
+}
+
+// CheckAndNudgePoints checks a set of points that have been transformed to
+// sample points on an image against the image's dimensions to see if the
+// point are even within the image.
+//
+// This method will actually "nudge" the endpoints back onto the image if
+// they are found to be barely (less than 1 pixel) off the image. This
+// accounts for imperfect detection of finder patterns in an image where
+// the QR Code runs all the way to the image border.
+//
+// For efficiency, the method will check points from either end of the
+// line until one is found to be within the image. Because the set of
+// points are assumed to be linear, this is valid.
+// It returns ErrPointsOutsideImage if an endpoint lies outside the image
+// boundaries.
+func CheckAndNudgePoints(image *BitMatrix, points []float32) error {
+	width := int(image.GetWidth())
+	height := int(image.GetHeight())
+	// Check and nudge points from start until we see some that are OK:
+	nudged := true
+	maxOffset := len(points) - 1 // points.length must be even
+	for offset := 0; offset < maxOffset && nudged; offset += 2 {
+		x := int(points[offset])
+		y := int(points[offset+1])
+		if x < -1 || x > width || y < -1 || y > height {
+			return ErrPointsOutsideImage
+		}
+		nudged = false
+		if x == -1 {
+			points[offset] = 0.0
+			nudged = true
+		} else if x == width {
+			points[offset] = float32(width - 1)
+			nudged = true
+		}
+		if y == -1 {
+			points[offset+1] = 0.0
+			nudged = true
+		} else if y == height {
+			points[offset+1] = float32(height - 1)
+			nudged = true
+		}
+	}
+	// Check and nudge points from end:
+	nudged = true
+	for offset := len(points) - 2; offset >= 0 && nudged; offset -= 2 {
+		x := int(points[offset])
+		y := int(points[offset+1])
+		if x < -1 || x > width || y < -1 || y > height {
+			return ErrPointsOutsideImage
+		}
+		nudged = false
+		if x == -1 {
+			points[offset] = 0.0
+			nudged = true
+		} else if x == width {
+			points[offset] = float32(width - 1)
+			nudged = true
+		}
+		if y == -1 {
+			points[offset+1] = 0.0
+			nudged = true
+		} else if y == height {
+			points[offset+1] = float32(height - 1)
+			nudged = true
+		}
+	}
+	return nil
+}
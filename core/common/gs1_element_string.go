@@ -0,0 +1,442 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// GS1GroupSeparator is the character separating a variable-length GS1
+// element from the next in an element string. Barcodes encode it as FNC1.
+const GS1GroupSeparator = 0x1D
+
+// gs1Component describes one part of the data field of an application
+// identifier, e.g. "N13" or "X..20" in the GS1 General Specifications.
+type gs1Component struct {
+	numeric    bool
+	minLength  int
+	maxLength  int
+	checkDigit bool
+	date       bool
+}
+
+// gs1ApplicationIdentifier describes the application identifiers starting
+// with prefix. Where aiLength is longer than prefix, the remaining digits
+// of the identifier are free, such as the decimal point position of the
+// 310n net weight.
+type gs1ApplicationIdentifier struct {
+	prefix     string
+	aiLength   int
+	components []gs1Component
+}
+
+// gs1Specs maps application identifier prefixes to the format of their
+// data field, in the notation of the GS1 General Specifications section
+// 3.2, with "csum" marking a check digit and "date" a YYMMDD date.
+// Components are separated by '|'.
+var gs1Specs = []struct {
+	prefix   string
+	aiLength int
+	spec     string
+}{
+	{"00", 2, "N18 csum"},
+	{"01", 2, "N14 csum"},
+	{"02", 2, "N14 csum"},
+	{"10", 2, "X..20"},
+	{"11", 2, "N6 date"},
+	{"12", 2, "N6 date"},
+	{"13", 2, "N6 date"},
+	{"15", 2, "N6 date"},
+	{"16", 2, "N6 date"},
+	{"17", 2, "N6 date"},
+	{"20", 2, "N2"},
+	{"21", 2, "X..20"},
+	{"22", 2, "X..20"},
+	{"235", 3, "X..28"},
+	{"240", 3, "X..30"},
+	{"241", 3, "X..30"},
+	{"242", 3, "N..6"},
+	{"243", 3, "X..20"},
+	{"250", 3, "X..30"},
+	{"251", 3, "X..30"},
+	{"253", 3, "N13 csum|X..17"},
+	{"254", 3, "X..20"},
+	{"255", 3, "N13 csum|N..12"},
+	{"30", 2, "N..8"},
+	{"31", 4, "N6"},
+	{"32", 4, "N6"},
+	{"33", 4, "N6"},
+	{"34", 4, "N6"},
+	{"35", 4, "N6"},
+	{"36", 4, "N6"},
+	{"37", 2, "N..8"},
+	{"390", 4, "N..15"},
+	{"391", 4, "N3|N..15"},
+	{"392", 4, "N..15"},
+	{"393", 4, "N3|N..15"},
+	{"394", 4, "N4"},
+	{"395", 4, "N6"},
+	{"400", 3, "X..30"},
+	{"401", 3, "X..30"},
+	{"402", 3, "N17 csum"},
+	{"403", 3, "X..30"},
+	{"410", 3, "N13 csum"},
+	{"411", 3, "N13 csum"},
+	{"412", 3, "N13 csum"},
+	{"413", 3, "N13 csum"},
+	{"414", 3, "N13 csum"},
+	{"415", 3, "N13 csum"},
+	{"416", 3, "N13 csum"},
+	{"417", 3, "N13 csum"},
+	{"420", 3, "X..20"},
+	{"421", 3, "N3|X..9"},
+	{"422", 3, "N3"},
+	{"423", 3, "N3|N..12"},
+	{"424", 3, "N3"},
+	{"425", 3, "N3|N..12"},
+	{"426", 3, "N3"},
+	{"427", 3, "X..3"},
+	{"7001", 4, "N13"},
+	{"7002", 4, "X..30"},
+	{"7003", 4, "N10"},
+	{"7004", 4, "N..4"},
+	{"7005", 4, "X..12"},
+	{"7006", 4, "N6 date"},
+	{"7007", 4, "N6..12"},
+	{"7008", 4, "X..3"},
+	{"7009", 4, "X..10"},
+	{"7010", 4, "X..2"},
+	{"7020", 4, "X..20"},
+	{"7021", 4, "X..20"},
+	{"7022", 4, "X..20"},
+	{"7023", 4, "X..30"},
+	{"703", 4, "N3|X..27"},
+	{"710", 3, "X..20"},
+	{"711", 3, "X..20"},
+	{"712", 3, "X..20"},
+	{"713", 3, "X..20"},
+	{"714", 3, "X..20"},
+	{"715", 3, "X..20"},
+	{"8001", 4, "N14"},
+	{"8002", 4, "X..20"},
+	{"8003", 4, "N14 csum|X..16"},
+	{"8004", 4, "X..30"},
+	{"8005", 4, "N6"},
+	{"8006", 4, "N14 csum|N4"},
+	{"8007", 4, "X..34"},
+	{"8008", 4, "N8|N..4"},
+	{"8009", 4, "X..50"},
+	{"8010", 4, "X..30"},
+	{"8011", 4, "N..12"},
+	{"8012", 4, "X..20"},
+	{"8013", 4, "X..25"},
+	{"8017", 4, "N18 csum"},
+	{"8018", 4, "N18 csum"},
+	{"8019", 4, "N..10"},
+	{"8020", 4, "X..25"},
+	{"8026", 4, "N14 csum|N4"},
+	{"8110", 4, "X..70"},
+	{"8111", 4, "N4"},
+	{"8112", 4, "X..70"},
+	{"8200", 4, "X..70"},
+	{"90", 2, "X..30"},
+	{"91", 2, "X..90"},
+	{"92", 2, "X..90"},
+	{"93", 2, "X..90"},
+	{"94", 2, "X..90"},
+	{"95", 2, "X..90"},
+	{"96", 2, "X..90"},
+	{"97", 2, "X..90"},
+	{"98", 2, "X..90"},
+	{"99", 2, "X..90"},
+}
+
+var gs1ApplicationIdentifiers = parseGS1Specs()
+
+// gs1PredefinedLengths holds the total length, identifier included, of
+// the elements whose identifiers start with the given two digits, as
+// listed in GS1 General Specifications figure 7.8.5-2. Only these
+// elements are never followed by a separator.
+var gs1PredefinedLengths = map[string]int{
+	"00": 20, "01": 16, "02": 16, "03": 16, "04": 18,
+	"11": 8, "12": 8, "13": 8, "14": 8, "15": 8, "16": 8, "17": 8, "18": 8, "19": 8,
+	"20": 4,
+	"31": 10, "32": 10, "33": 10, "34": 10, "35": 10, "36": 10,
+	"41": 16,
+}
+
+func parseGS1Specs() []*gs1ApplicationIdentifier {
+	result := make([]*gs1ApplicationIdentifier, 0, len(gs1Specs))
+	for _, s := range gs1Specs {
+		ai := &gs1ApplicationIdentifier{prefix: s.prefix, aiLength: s.aiLength}
+		for _, part := range strings.Split(s.spec, "|") {
+			words := strings.Fields(part)
+			c := gs1Component{numeric: words[0][0] == 'N'}
+			lengths := words[0][1:]
+			if i := strings.Index(lengths, ".."); i >= 0 {
+				c.minLength = 1
+				if i > 0 {
+					c.minLength, _ = strconv.Atoi(lengths[:i])
+				}
+				c.maxLength, _ = strconv.Atoi(lengths[i+2:])
+			} else {
+				c.minLength, _ = strconv.Atoi(lengths)
+				c.maxLength = c.minLength
+			}
+			for _, flag := range words[1:] {
+				c.checkDigit = c.checkDigit || flag == "csum"
+				c.date = c.date || flag == "date"
+			}
+			ai.components = append(ai.components, c)
+		}
+		result = append(result, ai)
+	}
+	return result
+}
+
+// GS1Element is one application identifier and its data field.
+type GS1Element struct {
+	AI    string
+	Value string
+}
+
+// ParseGS1ElementString parses GS1 data given either in human readable
+// form, with each application identifier in parentheses, such as
+// "(01)09506000134352(17)201225(10)ABC", or as a raw element string in
+// which variable-length elements are terminated by GS1GroupSeparator.
+// Every element is validated against the format of its application
+// identifier, including check digits and dates.
+// It returns the elements in order, or an error describing the first
+// element that is malformed.
+func ParseGS1ElementString(data string) ([]GS1Element, error) {
+	var elements []GS1Element
+	var err error
+	if strings.HasPrefix(data, "(") {
+		elements, err = parseGS1HumanReadable(data)
+	} else {
+		elements, err = parseGS1Raw(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) == 0 {
+		return nil, errors.New("GS1 data holds no elements")
+	}
+	for _, element := range elements {
+		if err := validateGS1Element(element); err != nil {
+			return nil, err
+		}
+	}
+	return elements, nil
+}
+
+// FormatGS1ElementString concatenates elements into a raw element string,
+// inserting GS1GroupSeparator after each element which is not of a
+// predefined length, except for the last.
+func FormatGS1ElementString(elements []GS1Element) string {
+	var result bytes.Buffer
+	for i, element := range elements {
+		result.WriteString(element.AI)
+		result.WriteString(element.Value)
+		if i < len(elements)-1 {
+			if _, ok := gs1PredefinedLengths[element.AI[:2]]; !ok {
+				result.WriteByte(GS1GroupSeparator)
+			}
+		}
+	}
+	return result.String()
+}
+
+func parseGS1HumanReadable(data string) ([]GS1Element, error) {
+	var elements []GS1Element
+	pos := 0
+	for pos < len(data) {
+		if data[pos] != '(' {
+			return nil, errors.New("expected '(' at offset " + strconv.Itoa(pos) + " of GS1 data")
+		}
+		end := strings.IndexByte(data[pos:], ')')
+		if end < 0 {
+			return nil, errors.New("unterminated application identifier in GS1 data")
+		}
+		ai := data[pos+1 : pos+end]
+		pos += end + 1
+		valueEnd := nextGS1HumanReadableAI(data, pos)
+		elements = append(elements, GS1Element{ai, data[pos:valueEnd]})
+		pos = valueEnd
+	}
+	return elements, nil
+}
+
+// nextGS1HumanReadableAI returns the offset of the next parenthesized
+// application identifier in data at or after pos, or the length of data
+// if there is none.
+func nextGS1HumanReadableAI(data string, pos int) int {
+	for i := pos; i < len(data); i++ {
+		if data[i] != '(' {
+			continue
+		}
+		j := i + 1
+		for j < len(data) && data[j] >= '0' && data[j] <= '9' {
+			j++
+		}
+		if digits := j - i - 1; digits >= 2 && digits <= 4 && j < len(data) && data[j] == ')' {
+			return i
+		}
+	}
+	return len(data)
+}
+
+func parseGS1Raw(data string) ([]GS1Element, error) {
+	var elements []GS1Element
+	pos := 0
+	for pos < len(data) {
+		ai := findGS1ApplicationIdentifier(data[pos:])
+		if ai == nil {
+			return nil, errors.New("unknown GS1 application identifier at offset " + strconv.Itoa(pos))
+		}
+		aiEnd := pos + ai.aiLength
+		var valueEnd, next int
+		if length, ok := gs1PredefinedLengths[ai.prefix[:2]]; ok {
+			valueEnd = pos + length
+			if valueEnd > len(data) {
+				return nil, errors.New("GS1 element " + data[pos:aiEnd] + " is truncated")
+			}
+			next = valueEnd
+			// Tolerate a redundant separator
+			if next < len(data) && data[next] == GS1GroupSeparator {
+				next++
+			}
+		} else {
+			valueEnd = strings.IndexByte(data[aiEnd:], GS1GroupSeparator)
+			if valueEnd < 0 {
+				valueEnd = len(data)
+				next = valueEnd
+			} else {
+				valueEnd += aiEnd
+				next = valueEnd + 1
+			}
+		}
+		elements = append(elements, GS1Element{data[pos:aiEnd], data[aiEnd:valueEnd]})
+		pos = next
+	}
+	return elements, nil
+}
+
+// findGS1ApplicationIdentifier finds the application identifier data
+// starts with, or nil if it doesn't start with a known one.
+func findGS1ApplicationIdentifier(data string) *gs1ApplicationIdentifier {
+	for _, ai := range gs1ApplicationIdentifiers {
+		if len(data) < ai.aiLength || !strings.HasPrefix(data, ai.prefix) {
+			continue
+		}
+		for _, c := range data[:ai.aiLength] {
+			if c < '0' || c > '9' {
+				return nil
+			}
+		}
+		return ai
+	}
+	return nil
+}
+
+func validateGS1Element(element GS1Element) error {
+	ai := findGS1ApplicationIdentifier(element.AI)
+	if ai == nil || ai.aiLength != len(element.AI) {
+		return errors.New("unknown GS1 application identifier (" + element.AI + ")")
+	}
+	value := element.Value
+	for i, c := range ai.components {
+		length := c.maxLength
+		if i == len(ai.components)-1 || length > len(value) {
+			length = len(value)
+		}
+		if length < c.minLength || length > c.maxLength {
+			return errors.New("GS1 element (" + element.AI + ") has an invalid length")
+		}
+		field := value[:length]
+		value = value[length:]
+		if !isGS1Encodable(field, c.numeric) {
+			return errors.New("GS1 element (" + element.AI + ") contains invalid characters")
+		}
+		if c.checkDigit && !isGS1CheckDigitValid(field) {
+			return errors.New("GS1 element (" + element.AI + ") has an invalid check digit")
+		}
+		if c.date && !isGS1DateValid(field) {
+			return errors.New("GS1 element (" + element.AI + ") has an invalid date")
+		}
+	}
+	if len(value) > 0 {
+		return errors.New("GS1 element (" + element.AI + ") is too long")
+	}
+	return nil
+}
+
+// isGS1Encodable reports whether field holds only digits, if numeric is
+// set, or otherwise only characters of GS1 AI encodable character set 82.
+func isGS1Encodable(field string, numeric bool) bool {
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+		switch {
+		case c >= '0' && c <= '9':
+		case numeric:
+			return false
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		case strings.IndexByte("!\"%&'()*+,-./:;<=>?_", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isGS1CheckDigitValid verifies the GS1 modulo 10 check digit, which is
+// the last digit of digits.
+func isGS1CheckDigitValid(digits string) bool {
+	sum := 0
+	for i := len(digits) - 2; i >= 0; i-- {
+		digit := int(digits[i] - '0')
+		if (len(digits)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return int(digits[len(digits)-1]-'0') == (10-sum%10)%10
+}
+
+// daysInMonth holds the length of each month of a year that is not a leap
+// year.
+var daysInMonth = []int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// isGS1DateValid verifies a YYMMDD date, where a day of 00 stands for the
+// last day of the month. Years divisible by 4 are taken as leap years,
+// which holds for every year from 1901 to 2099.
+func isGS1DateValid(date string) bool {
+	year, _ := strconv.Atoi(date[0:2])
+	month, _ := strconv.Atoi(date[2:4])
+	day, _ := strconv.Atoi(date[4:6])
+	if month < 1 || month > 12 {
+		return false
+	}
+	days := daysInMonth[month-1]
+	if month == 2 && year%4 == 0 {
+		days++
+	}
+	return day <= days
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common_test
+
+import (
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+func assertGS1Parses(t *testing.T, data, expected string) {
+	elements, err := common.ParseGS1ElementString(data)
+	internal.AssertSuccess(t, err)
+	actual := common.FormatGS1ElementString(elements)
+	internal.AssertEquals(t, expected, actual, "formatted "+data+" as "+actual)
+}
+
+func TestParseGS1ElementString_HumanReadable(t *testing.T) {
+	assertGS1Parses(t, "(01)09506000134352(17)201225(10)ABC", "01095060001343521720122510ABC")
+	assertGS1Parses(t, "(10)ABC(01)09506000134352", "10ABC\x1d0109506000134352")
+	assertGS1Parses(t, "(3103)001250(21)12", "31030012502112")
+	assertGS1Parses(t, "(8200)http://example.com/(90)X", "8200http://example.com/\x1d90X")
+}
+
+func TestParseGS1ElementString_Dates(t *testing.T) {
+	assertGS1Parses(t, "(17)240229", "17240229")
+	assertGS1Parses(t, "(17)210200", "17210200")
+	assertGS1Parses(t, "(17)211231", "17211231")
+}
+
+func TestParseGS1ElementString_Raw(t *testing.T) {
+	assertGS1Parses(t, "01095060001343521720122510ABC", "01095060001343521720122510ABC")
+	assertGS1Parses(t, "10ABC\x1d0109506000134352", "10ABC\x1d0109506000134352")
+	// A separator after a predefined length element is redundant
+	assertGS1Parses(t, "0109506000134352\x1d10ABC", "010950600013435210ABC")
+}
+
+func TestParseGS1ElementString_Invalid(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"(01)09506000134353",
+		"(01)0950600013435",
+		"(17)201325",
+		"(17)210231",
+		"(17)210229",
+		"(17)210431",
+		"(17)211232",
+		"(10)",
+		"(10)ABCDEFGHIJKLMNOPQRSTU",
+		"(10)AB^C",
+		"(3103)0012AB",
+		"(05)123",
+		"(99)x|y",
+		"0509506000134352",
+		"01095060",
+	} {
+		_, err := common.ParseGS1ElementString(data)
+		internal.AssertFailure(t, err, "expected "+data+" to be rejected")
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+// PerspectiveTransform implements a perspective transform in two
+// dimensions. Given four source and four destination points, it will
+// compute the transformation implied between them. The code is based
+// directly upon section 3.4.2 of George Wolberg's "Digital Image Warping";
+// see pages 54-56.
+type PerspectiveTransform struct {
+	a11, a12, a13, a21, a22, a23, a31, a32, a33 float32
+}
+
+func newPerspectiveTransform(a11, a21, a31, a12, a22, a32, a13, a23, a33 float32) *PerspectiveTransform {
+	return &PerspectiveTransform{
+		a11: a11, a12: a12, a13: a13,
+		a21: a21, a22: a22, a23: a23,
+		a31: a31, a32: a32, a33: a33,
+	}
+}
+
+// QuadrilateralToQuadrilateral computes the transform mapping the
+// quadrilateral (x0,y0)-(x3,y3) onto the quadrilateral (x0p,y0p)-(x3p,y3p).
+func QuadrilateralToQuadrilateral(x0, y0, x1, y1, x2, y2, x3, y3, x0p, y0p, x1p, y1p, x2p, y2p, x3p, y3p float32) *PerspectiveTransform {
+	qToS := QuadrilateralToSquare(x0, y0, x1, y1, x2, y2, x3, y3)
+	sToQ := SquareToQuadrilateral(x0p, y0p, x1p, y1p, x2p, y2p, x3p, y3p)
+	return sToQ.times(qToS)
+}
+
+// TransformPoints transforms, in place, a slice of points laid out as
+// alternating x and y coordinates.
+func (pt *PerspectiveTransform) TransformPoints(points []float32) {
+	maxI := len(points) - 1 // points.length must be even
+	for i := 0; i < maxI; i += 2 {
+		x := points[i]
+		y := points[i+1]
+		denominator := pt.a13*x + pt.a23*y + pt.a33
+		points[i] = (pt.a11*x + pt.a21*y + pt.a31) / denominator
+		points[i+1] = (pt.a12*x + pt.a22*y + pt.a32) / denominator
+	}
+}
+
+// TransformPointsXY transforms, in place, points whose x and y coordinates
+// are held in separate slices of the same length.
+func (pt *PerspectiveTransform) TransformPointsXY(xValues, yValues []float32) {
+	for i := range xValues {
+		x := xValues[i]
+		y := yValues[i]
+		denominator := pt.a13*x + pt.a23*y + pt.a33
+		xValues[i] = (pt.a11*x + pt.a21*y + pt.a31) / denominator
+		yValues[i] = (pt.a12*x + pt.a22*y + pt.a32) / denominator
+	}
+}
+
+// SquareToQuadrilateral computes the transform mapping the unit square
+// onto the quadrilateral (x0,y0)-(x3,y3).
+func SquareToQuadrilateral(x0, y0, x1, y1, x2, y2, x3, y3 float32) *PerspectiveTransform {
+	dx3 := x0 - x1 + x2 - x3
+	dy3 := y0 - y1 + y2 - y3
+	if dx3 == 0.0 && dy3 == 0.0 {
+		// Affine
+		return newPerspectiveTransform(x1-x0, x2-x1, x0, y1-y0, y2-y1, y0, 0.0, 0.0, 1.0)
+	}
+	dx1 := x1 - x2
+	dx2 := x3 - x2
+	dy1 := y1 - y2
+	dy2 := y3 - y2
+	denominator := dx1*dy2 - dx2*dy1
+	a13 := (dx3*dy2 - dx2*dy3) / denominator
+	a23 := (dx1*dy3 - dx3*dy1) / denominator
+	return newPerspectiveTransform(
+		x1-x0+a13*x1, x3-x0+a23*x3, x0,
+		y1-y0+a13*y1, y3-y0+a23*y3, y0,
+		a13, a23, 1.0)
+}
+
+// QuadrilateralToSquare computes the transform mapping the quadrilateral
+// (x0,y0)-(x3,y3) onto the unit square.
+func QuadrilateralToSquare(x0, y0, x1, y1, x2, y2, x3, y3 float32) *PerspectiveTransform {
+	// Here, the adjoint serves as the inverse
+	return SquareToQuadrilateral(x0, y0, x1, y1, x2, y2, x3, y3).buildAdjoint()
+}
+
+func (pt *PerspectiveTransform) buildAdjoint() *PerspectiveTransform {
+	// Adjoint is the transpose of the cofactor matrix:
+	return newPerspectiveTransform(
+		pt.a22*pt.a33-pt.a23*pt.a32,
+		pt.a23*pt.a31-pt.a21*pt.a33,
+		pt.a21*pt.a32-pt.a22*pt.a31,
+		pt.a13*pt.a32-pt.a12*pt.a33,
+		pt.a11*pt.a33-pt.a13*pt.a31,
+		pt.a12*pt.a31-pt.a11*pt.a32,
+		pt.a12*pt.a23-pt.a13*pt.a22,
+		pt.a13*pt.a21-pt.a11*pt.a23,
+		pt.a11*pt.a22-pt.a12*pt.a21)
+}
+
+func (pt *PerspectiveTransform) times(other *PerspectiveTransform) *PerspectiveTransform {
+	return newPerspectiveTransform(
+		pt.a11*other.a11+pt.a21*other.a12+pt.a31*other.a13,
+		pt.a11*other.a21+pt.a21*other.a22+pt.a31*other.a23,
+		pt.a11*other.a31+pt.a21*other.a32+pt.a31*other.a33,
+		pt.a12*other.a11+pt.a22*other.a12+pt.a32*other.a13,
+		pt.a12*other.a21+pt.a22*other.a22+pt.a32*other.a23,
+		pt.a12*other.a31+pt.a22*other.a32+pt.a32*other.a33,
+		pt.a13*other.a11+pt.a23*other.a12+pt.a33*other.a13,
+		pt.a13*other.a21+pt.a23*other.a22+pt.a33*other.a23,
+		pt.a13*other.a31+pt.a23*other.a32+pt.a33*other.a33)
+}
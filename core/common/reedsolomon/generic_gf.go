@@ -0,0 +1,150 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reedsolomon
+
+import "strconv"
+
+// GenericGF is a utility class for performing Galois field operations,
+// over a field of a size which is a power of 2, with a primitive element
+// (alpha) of 2.
+//
+// Throughout this package, elements of the field are represented as ints
+// for convenience and speed, at the cost of memory.
+type GenericGF struct {
+	expTable      []int
+	logTable      []int
+	zero          *genericGFPoly
+	one           *genericGFPoly
+	size          int
+	primitive     int
+	generatorBase int
+}
+
+// The fields used by the various barcode formats.
+var (
+	AztecData12        = NewGenericGF(0x1069, 4096, 1) // x^12 + x^6 + x^5 + x^3 + 1
+	AztecData10        = NewGenericGF(0x409, 1024, 1)  // x^10 + x^3 + 1
+	AztecData6         = NewGenericGF(0x43, 64, 1)     // x^6 + x + 1
+	AztecParam         = NewGenericGF(0x13, 16, 1)     // x^4 + x + 1
+	QRCodeField256     = NewGenericGF(0x011D, 256, 0)  // x^8 + x^4 + x^3 + x^2 + 1
+	DataMatrixField256 = NewGenericGF(0x012D, 256, 1)  // x^8 + x^5 + x^3 + x^2 + 1
+	AztecData8         = DataMatrixField256
+	MaxiCodeField64    = AztecData6
+)
+
+// NewGenericGF creates a representation of GF(size) using the given
+// primitive polynomial.
+// primitive is the irreducible polynomial whose coefficients are
+// represented by the bits of an int, where the least-significant bit
+// represents the constant coefficient. size is the size of the field, and
+// b is the factor b in the generator polynomial, which can be 0- or
+// 1-based (g(x) = (x+a^b)(x+a^(b+1))...(x+a^(b+2t-1))). In most cases it
+// should be 1, but for QR code it is 0.
+func NewGenericGF(primitive, size, b int) *GenericGF {
+	field := &GenericGF{
+		expTable:      make([]int, size),
+		logTable:      make([]int, size),
+		size:          size,
+		primitive:     primitive,
+		generatorBase: b,
+	}
+
+	x := 1
+	for i := 0; i < size; i++ {
+		field.expTable[i] = x
+		x *= 2 // we're assuming the generator alpha is 2
+		if x >= size {
+			x ^= primitive
+			x &= size - 1
+		}
+	}
+	for i := 0; i < size-1; i++ {
+		field.logTable[field.expTable[i]] = i
+	}
+	// logTable[0] == 0 but this should never be used
+	field.zero = newGenericGFPoly(field, []int{0})
+	field.one = newGenericGFPoly(field, []int{1})
+	return field
+}
+
+func (gf *GenericGF) getZero() *genericGFPoly {
+	return gf.zero
+}
+
+func (gf *GenericGF) getOne() *genericGFPoly {
+	return gf.one
+}
+
+// buildMonomial returns the monomial representing coefficient * x^degree.
+func (gf *GenericGF) buildMonomial(degree, coefficient int) *genericGFPoly {
+	if degree < 0 {
+		panic("reedsolomon: negative monomial degree")
+	}
+	if coefficient == 0 {
+		return gf.zero
+	}
+	coefficients := make([]int, degree+1)
+	coefficients[0] = coefficient
+	return newGenericGFPoly(gf, coefficients)
+}
+
+// addOrSubtract implements both addition and subtraction; they are the
+// same in GF(size).
+func addOrSubtract(a, b int) int {
+	return a ^ b
+}
+
+// Exp returns 2 to the power of a in GF(size).
+func (gf *GenericGF) Exp(a int) int {
+	return gf.expTable[a]
+}
+
+// Log returns the base 2 log of a in GF(size). a must not be 0.
+func (gf *GenericGF) Log(a int) int {
+	if a == 0 {
+		panic("reedsolomon: log of 0")
+	}
+	return gf.logTable[a]
+}
+
+// Inverse returns the multiplicative inverse of a. a must not be 0.
+func (gf *GenericGF) Inverse(a int) int {
+	if a == 0 {
+		panic("reedsolomon: inverse of 0")
+	}
+	return gf.expTable[gf.size-gf.logTable[a]-1]
+}
+
+// Multiply returns the product of a and b in GF(size).
+func (gf *GenericGF) Multiply(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gf.expTable[(gf.logTable[a]+gf.logTable[b])%(gf.size-1)]
+}
+
+func (gf *GenericGF) GetSize() int {
+	return gf.size
+}
+
+func (gf *GenericGF) GetGeneratorBase() int {
+	return gf.generatorBase
+}
+
+func (gf *GenericGF) String() string {
+	return "GF(0x" + strconv.FormatInt(int64(gf.primitive), 16) + "," + strconv.Itoa(gf.size) + ")"
+}
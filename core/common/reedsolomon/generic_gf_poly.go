@@ -0,0 +1,240 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reedsolomon
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// genericGFPoly represents a polynomial whose coefficients are elements of
+// a GF. Instances of this type are immutable.
+type genericGFPoly struct {
+	field        *GenericGF
+	coefficients []int
+}
+
+// newGenericGFPoly constructs a polynomial over field. coefficients are
+// given from most significant (highest-power term) to least significant,
+// and any leading zeros are stripped.
+func newGenericGFPoly(field *GenericGF, coefficients []int) *genericGFPoly {
+	if len(coefficients) == 0 {
+		panic("reedsolomon: polynomial needs at least one coefficient")
+	}
+	coefficientsLength := len(coefficients)
+	if coefficientsLength > 1 && coefficients[0] == 0 {
+		// Leading term must be non-zero for anything except the constant polynomial "0"
+		firstNonZero := 1
+		for firstNonZero < coefficientsLength && coefficients[firstNonZero] == 0 {
+			firstNonZero++
+		}
+		if firstNonZero == coefficientsLength {
+			coefficients = []int{0}
+		} else {
+			stripped := make([]int, coefficientsLength-firstNonZero)
+			copy(stripped, coefficients[firstNonZero:])
+			coefficients = stripped
+		}
+	}
+	return &genericGFPoly{field, coefficients}
+}
+
+func (p *genericGFPoly) getCoefficients() []int {
+	return p.coefficients
+}
+
+// getDegree returns the degree of this polynomial.
+func (p *genericGFPoly) getDegree() int {
+	return len(p.coefficients) - 1
+}
+
+// isZero reports whether this polynomial is the monomial "0".
+func (p *genericGFPoly) isZero() bool {
+	return p.coefficients[0] == 0
+}
+
+// getCoefficient returns the coefficient of the x^degree term.
+func (p *genericGFPoly) getCoefficient(degree int) int {
+	return p.coefficients[len(p.coefficients)-1-degree]
+}
+
+// evaluateAt returns the value of this polynomial at a.
+func (p *genericGFPoly) evaluateAt(a int) int {
+	if a == 0 {
+		// Just return the x^0 coefficient
+		return p.getCoefficient(0)
+	}
+	if a == 1 {
+		// Just the sum of the coefficients
+		result := 0
+		for _, coefficient := range p.coefficients {
+			result = addOrSubtract(result, coefficient)
+		}
+		return result
+	}
+	result := p.coefficients[0]
+	for i := 1; i < len(p.coefficients); i++ {
+		result = addOrSubtract(p.field.Multiply(a, result), p.coefficients[i])
+	}
+	return result
+}
+
+func (p *genericGFPoly) addOrSubtract(other *genericGFPoly) *genericGFPoly {
+	if p.field != other.field {
+		panic("reedsolomon: GenericGFPolys do not have same GenericGF field")
+	}
+	if p.isZero() {
+		return other
+	}
+	if other.isZero() {
+		return p
+	}
+
+	smallerCoefficients := p.coefficients
+	largerCoefficients := other.coefficients
+	if len(smallerCoefficients) > len(largerCoefficients) {
+		smallerCoefficients, largerCoefficients = largerCoefficients, smallerCoefficients
+	}
+	sumDiff := make([]int, len(largerCoefficients))
+	lengthDiff := len(largerCoefficients) - len(smallerCoefficients)
+	// Copy high-order terms only found in higher-degree polynomial's coefficients
+	copy(sumDiff, largerCoefficients[:lengthDiff])
+
+	for i := lengthDiff; i < len(largerCoefficients); i++ {
+		sumDiff[i] = addOrSubtract(smallerCoefficients[i-lengthDiff], largerCoefficients[i])
+	}
+
+	return newGenericGFPoly(p.field, sumDiff)
+}
+
+func (p *genericGFPoly) multiply(other *genericGFPoly) *genericGFPoly {
+	if p.field != other.field {
+		panic("reedsolomon: GenericGFPolys do not have same GenericGF field")
+	}
+	if p.isZero() || other.isZero() {
+		return p.field.getZero()
+	}
+	aCoefficients := p.coefficients
+	bCoefficients := other.coefficients
+	product := make([]int, len(aCoefficients)+len(bCoefficients)-1)
+	for i, aCoeff := range aCoefficients {
+		for j, bCoeff := range bCoefficients {
+			product[i+j] = addOrSubtract(product[i+j], p.field.Multiply(aCoeff, bCoeff))
+		}
+	}
+	return newGenericGFPoly(p.field, product)
+}
+
+func (p *genericGFPoly) multiplyScalar(scalar int) *genericGFPoly {
+	if scalar == 0 {
+		return p.field.getZero()
+	}
+	if scalar == 1 {
+		return p
+	}
+	product := make([]int, len(p.coefficients))
+	for i, coefficient := range p.coefficients {
+		product[i] = p.field.Multiply(coefficient, scalar)
+	}
+	return newGenericGFPoly(p.field, product)
+}
+
+func (p *genericGFPoly) multiplyByMonomial(degree, coefficient int) *genericGFPoly {
+	if degree < 0 {
+		panic("reedsolomon: negative monomial degree")
+	}
+	if coefficient == 0 {
+		return p.field.getZero()
+	}
+	product := make([]int, len(p.coefficients)+degree)
+	for i, c := range p.coefficients {
+		product[i] = p.field.Multiply(c, coefficient)
+	}
+	return newGenericGFPoly(p.field, product)
+}
+
+// divide returns the quotient and remainder of dividing this polynomial
+// by other.
+func (p *genericGFPoly) divide(other *genericGFPoly) (*genericGFPoly, *genericGFPoly) {
+	if p.field != other.field {
+		panic("reedsolomon: GenericGFPolys do not have same GenericGF field")
+	}
+	if other.isZero() {
+		panic("reedsolomon: divide by 0")
+	}
+
+	quotient := p.field.getZero()
+	remainder := p
+
+	denominatorLeadingTerm := other.getCoefficient(other.getDegree())
+	inverseDenominatorLeadingTerm := p.field.Inverse(denominatorLeadingTerm)
+
+	for remainder.getDegree() >= other.getDegree() && !remainder.isZero() {
+		degreeDifference := remainder.getDegree() - other.getDegree()
+		scale := p.field.Multiply(remainder.getCoefficient(remainder.getDegree()), inverseDenominatorLeadingTerm)
+		term := other.multiplyByMonomial(degreeDifference, scale)
+		iterationQuotient := p.field.buildMonomial(degreeDifference, scale)
+		quotient = quotient.addOrSubtract(iterationQuotient)
+		remainder = remainder.addOrSubtract(term)
+	}
+
+	return quotient, remainder
+}
+
+func (p *genericGFPoly) String() string {
+	if p.isZero() {
+		return "0"
+	}
+	var result bytes.Buffer
+	for degree := p.getDegree(); degree >= 0; degree-- {
+		coefficient := p.getCoefficient(degree)
+		if coefficient != 0 {
+			if coefficient < 0 {
+				if degree == p.getDegree() {
+					result.WriteString("-")
+				} else {
+					result.WriteString(" - ")
+				}
+				coefficient = -coefficient
+			} else {
+				if result.Len() > 0 {
+					result.WriteString(" + ")
+				}
+			}
+			if degree == 0 || coefficient != 1 {
+				alphaPower := p.field.Log(coefficient)
+				if alphaPower == 0 {
+					result.WriteString("1")
+				} else if alphaPower == 1 {
+					result.WriteString("a")
+				} else {
+					result.WriteString("a^")
+					result.WriteString(strconv.Itoa(alphaPower))
+				}
+			}
+			if degree != 0 {
+				if degree == 1 {
+					result.WriteString("x")
+				} else {
+					result.WriteString("x^")
+					result.WriteString(strconv.Itoa(degree))
+				}
+			}
+		}
+	}
+	return result.String()
+}
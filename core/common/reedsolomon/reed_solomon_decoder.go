@@ -0,0 +1,177 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reedsolomon
+
+import "errors"
+
+// ReedSolomonDecoder implements Reed-Solomon decoding, as the name implies.
+//
+// The algorithm will not be explained here, but the following references
+// were helpful in creating this implementation:
+//
+//	Bruce Maggs. "Decoding Reed-Solomon Codes" (see discussion of Forney's Formula)
+//	J.I. Hall. "Chapter 5. Generalized Reed-Solomon Codes" (see discussion of Euclidean algorithm)
+//
+// Much credit is due to William Rucklidge since portions of this code are
+// an indirect port of his C++ Reed-Solomon implementation.
+type ReedSolomonDecoder struct {
+	field *GenericGF
+}
+
+// NewReedSolomonDecoder constructs a decoder operating over field.
+func NewReedSolomonDecoder(field *GenericGF) *ReedSolomonDecoder {
+	return &ReedSolomonDecoder{field}
+}
+
+// Decode decodes given set of received codewords, which include both data
+// and error-correction codewords. Really, this means it uses Reed-Solomon
+// to detect and correct errors, in-place, in the input.
+// twoS is the number of error-correction codewords available.
+// It returns the number of errors corrected, or an error if the codewords
+// could not be decoded due to too many errors.
+func (rsd *ReedSolomonDecoder) Decode(received []int, twoS int) (int, error) {
+	poly := newGenericGFPoly(rsd.field, received)
+	syndromeCoefficients := make([]int, twoS)
+	noError := true
+	for i := 0; i < twoS; i++ {
+		eval := poly.evaluateAt(rsd.field.Exp(i + rsd.field.generatorBase))
+		syndromeCoefficients[len(syndromeCoefficients)-1-i] = eval
+		if eval != 0 {
+			noError = false
+		}
+	}
+	if noError {
+		return 0, nil
+	}
+
+	syndrome := newGenericGFPoly(rsd.field, syndromeCoefficients)
+	sigma, omega, err := rsd.runEuclideanAlgorithm(rsd.field.buildMonomial(twoS, 1), syndrome, twoS)
+	if err != nil {
+		return 0, err
+	}
+	errorLocations, err := rsd.findErrorLocations(sigma)
+	if err != nil {
+		return 0, err
+	}
+	errorMagnitudes, err := rsd.findErrorMagnitudes(omega, errorLocations)
+	if err != nil {
+		return 0, err
+	}
+	for i := range errorLocations {
+		position := len(received) - 1 - rsd.field.Log(errorLocations[i])
+		if position < 0 {
+			return 0, errors.New("bad error location")
+		}
+		received[position] = addOrSubtract(received[position], errorMagnitudes[i])
+	}
+	return len(errorLocations), nil
+}
+
+func (rsd *ReedSolomonDecoder) runEuclideanAlgorithm(a, b *genericGFPoly, R int) (*genericGFPoly, *genericGFPoly, error) {
+	// Assume a's degree is >= b's
+	if a.getDegree() < b.getDegree() {
+		a, b = b, a
+	}
+
+	rLast := a
+	r := b
+	tLast := rsd.field.getZero()
+	t := rsd.field.getOne()
+
+	// Run Euclidean algorithm until r's degree is less than R/2
+	for 2*r.getDegree() >= R {
+		rLastLast := rLast
+		tLastLast := tLast
+		rLast = r
+		tLast = t
+
+		// Divide rLastLast by rLast, with quotient in q and remainder in r
+		if rLast.isZero() {
+			// Oops, Euclidean algorithm already terminated?
+			return nil, nil, errors.New("r_{i-1} was zero")
+		}
+		r = rLastLast
+		q := rsd.field.getZero()
+		denominatorLeadingTerm := rLast.getCoefficient(rLast.getDegree())
+		dltInverse := rsd.field.Inverse(denominatorLeadingTerm)
+		for r.getDegree() >= rLast.getDegree() && !r.isZero() {
+			degreeDiff := r.getDegree() - rLast.getDegree()
+			scale := rsd.field.Multiply(r.getCoefficient(r.getDegree()), dltInverse)
+			q = q.addOrSubtract(rsd.field.buildMonomial(degreeDiff, scale))
+			r = r.addOrSubtract(rLast.multiplyByMonomial(degreeDiff, scale))
+		}
+
+		t = q.multiply(tLast).addOrSubtract(tLastLast)
+
+		if r.getDegree() >= rLast.getDegree() {
+			return nil, nil, errors.New("division algorithm failed to reduce polynomial")
+		}
+	}
+
+	sigmaTildeAtZero := t.getCoefficient(0)
+	if sigmaTildeAtZero == 0 {
+		return nil, nil, errors.New("sigmaTilde(0) was zero")
+	}
+
+	inverse := rsd.field.Inverse(sigmaTildeAtZero)
+	sigma := t.multiplyScalar(inverse)
+	omega := r.multiplyScalar(inverse)
+	return sigma, omega, nil
+}
+
+func (rsd *ReedSolomonDecoder) findErrorLocations(errorLocator *genericGFPoly) ([]int, error) {
+	// This is a direct application of Chien's search
+	numErrors := errorLocator.getDegree()
+	if numErrors == 1 { // shortcut
+		return []int{errorLocator.getCoefficient(1)}, nil
+	}
+	result := make([]int, numErrors)
+	e := 0
+	for i := 1; i < rsd.field.GetSize() && e < numErrors; i++ {
+		if errorLocator.evaluateAt(i) == 0 {
+			result[e] = rsd.field.Inverse(i)
+			e++
+		}
+	}
+	if e != numErrors {
+		return nil, errors.New("error locator degree does not match number of roots")
+	}
+	return result, nil
+}
+
+func (rsd *ReedSolomonDecoder) findErrorMagnitudes(errorEvaluator *genericGFPoly, errorLocations []int) ([]int, error) {
+	// This is directly applying Forney's Formula
+	s := len(errorLocations)
+	result := make([]int, s)
+	for i := 0; i < s; i++ {
+		xiInverse := rsd.field.Inverse(errorLocations[i])
+		denominator := 1
+		for j := 0; j < s; j++ {
+			if i != j {
+				denominator = rsd.field.Multiply(denominator, addOrSubtract(1, rsd.field.Multiply(errorLocations[j], xiInverse)))
+			}
+		}
+		if denominator == 0 {
+			return nil, errors.New("error magnitude denominator was zero")
+		}
+		result[i] = rsd.field.Multiply(errorEvaluator.evaluateAt(xiInverse), rsd.field.Inverse(denominator))
+		if rsd.field.generatorBase != 0 {
+			result[i] = rsd.field.Multiply(result[i], xiInverse)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2008 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reedsolomon
+
+import "errors"
+
+// ReedSolomonEncoder implements Reed-Solomon encoding, as the name implies.
+type ReedSolomonEncoder struct {
+	field            *GenericGF
+	cachedGenerators []*genericGFPoly
+}
+
+// NewReedSolomonEncoder constructs an encoder operating over field.
+func NewReedSolomonEncoder(field *GenericGF) *ReedSolomonEncoder {
+	return &ReedSolomonEncoder{field, []*genericGFPoly{newGenericGFPoly(field, []int{1})}}
+}
+
+func (rse *ReedSolomonEncoder) buildGenerator(degree int) *genericGFPoly {
+	if degree >= len(rse.cachedGenerators) {
+		lastGenerator := rse.cachedGenerators[len(rse.cachedGenerators)-1]
+		for d := len(rse.cachedGenerators); d <= degree; d++ {
+			nextGenerator := lastGenerator.multiply(newGenericGFPoly(rse.field, []int{1, rse.field.Exp(d - 1 + rse.field.generatorBase)}))
+			rse.cachedGenerators = append(rse.cachedGenerators, nextGenerator)
+			lastGenerator = nextGenerator
+		}
+	}
+	return rse.cachedGenerators[degree]
+}
+
+// Encode computes ecBytes error-correction codewords for the data held in
+// the leading len(toEncode)-ecBytes elements of toEncode, and writes them
+// into its trailing ecBytes elements.
+// It returns an error if there are no data or no error-correction
+// codewords to work with.
+func (rse *ReedSolomonEncoder) Encode(toEncode []int, ecBytes int) error {
+	if ecBytes == 0 {
+		return errors.New("no error correction bytes")
+	}
+	dataBytes := len(toEncode) - ecBytes
+	if dataBytes <= 0 {
+		return errors.New("no data bytes provided")
+	}
+	generator := rse.buildGenerator(ecBytes)
+	infoCoefficients := make([]int, dataBytes)
+	copy(infoCoefficients, toEncode[:dataBytes])
+	info := newGenericGFPoly(rse.field, infoCoefficients)
+	info = info.multiplyByMonomial(ecBytes, 1)
+	_, remainder := info.divide(generator)
+	coefficients := remainder.getCoefficients()
+	numZeroCoefficients := ecBytes - len(coefficients)
+	for i := 0; i < numZeroCoefficients; i++ {
+		toEncode[dataBytes+i] = 0
+	}
+	copy(toEncode[dataBytes+numZeroCoefficients:], coefficients)
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reedsolomon_test
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/common/reedsolomon"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+func testEncodeDecodeRandom(t *testing.T, field *reedsolomon.GenericGF, dataSize, ecSize int) {
+	random := rand.New(rand.NewSource(0xDEADBEEF))
+	encoder := reedsolomon.NewReedSolomonEncoder(field)
+	decoder := reedsolomon.NewReedSolomonDecoder(field)
+
+	for iteration := 0; iteration < 10; iteration++ {
+		message := make([]int, dataSize+ecSize)
+		for i := 0; i < dataSize; i++ {
+			message[i] = random.Intn(field.GetSize())
+		}
+		internal.AssertSuccess(t, encoder.Encode(message, ecSize))
+
+		expected := make([]int, len(message))
+		copy(expected, message)
+
+		// Corrupt as many codewords as the error correction can repair
+		numErrors := ecSize / 2
+		for _, position := range random.Perm(len(message))[:numErrors] {
+			message[position] ^= 1 + random.Intn(field.GetSize()-1)
+		}
+
+		corrected, err := decoder.Decode(message, ecSize)
+		internal.AssertSuccess(t, err)
+		internal.AssertEquals(t, numErrors, corrected, field.String()+": corrected "+strconv.Itoa(corrected)+" errors instead of "+strconv.Itoa(numErrors))
+		for i := range expected {
+			internal.AssertEquals(t, expected[i], message[i], field.String()+": codeword "+strconv.Itoa(i)+" was not corrected")
+		}
+	}
+}
+
+func TestReedSolomon_DataMatrix(t *testing.T) {
+	testEncodeDecodeRandom(t, reedsolomon.DataMatrixField256, 3, 5)
+	testEncodeDecodeRandom(t, reedsolomon.DataMatrixField256, 156, 62)
+}
+
+func TestReedSolomon_QRCode(t *testing.T) {
+	testEncodeDecodeRandom(t, reedsolomon.QRCodeField256, 19, 7)
+	testEncodeDecodeRandom(t, reedsolomon.QRCodeField256, 118, 30)
+}
+
+func TestReedSolomon_Aztec(t *testing.T) {
+	testEncodeDecodeRandom(t, reedsolomon.AztecParam, 2, 5)
+	testEncodeDecodeRandom(t, reedsolomon.AztecData6, 9, 12)
+	testEncodeDecodeRandom(t, reedsolomon.AztecData10, 100, 80)
+	testEncodeDecodeRandom(t, reedsolomon.AztecData12, 500, 300)
+}
+
+func TestReedSolomon_NoErrors(t *testing.T) {
+	message := []int{142, 164, 186, 0, 0, 0, 0, 0}
+	internal.AssertSuccess(t, reedsolomon.NewReedSolomonEncoder(reedsolomon.DataMatrixField256).Encode(message, 5))
+	corrected, err := reedsolomon.NewReedSolomonDecoder(reedsolomon.DataMatrixField256).Decode(message, 5)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 0, corrected, "clean codewords reported corrections")
+}
+
+func TestReedSolomon_TooManyErrors(t *testing.T) {
+	message := []int{142, 164, 186, 0, 0, 0, 0, 0}
+	internal.AssertSuccess(t, reedsolomon.NewReedSolomonEncoder(reedsolomon.DataMatrixField256).Encode(message, 5))
+	for i := range message {
+		message[i] ^= 0x55
+	}
+	_, err := reedsolomon.NewReedSolomonDecoder(reedsolomon.DataMatrixField256).Decode(message, 5)
+	internal.AssertFailure(t, err, "fully corrupted codewords were decoded")
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datamatrix
+
+import (
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/datamatrix/decoder"
+	"github.com/discesoft/zxing-go/core/datamatrix/detector"
+)
+
+// DataMatrixReader can detect and decode Data Matrix codes in an image.
+type DataMatrixReader struct {
+	decoder *decoder.Decoder
+}
+
+func NewDataMatrixReader() *DataMatrixReader {
+	return &DataMatrixReader{decoder.NewDecoder()}
+}
+
+// Decode locates and decodes a Data Matrix code in an image.
+// It returns the decoded Result, core.ErrNotFound if a Data Matrix code
+// cannot be found, core.ErrFormat if it cannot be decoded, or
+// core.ErrChecksum if error correction fails.
+func (r *DataMatrixReader) Decode(image *common.BitMatrix, hints map[core.DecodeHintType]interface{}) (*core.Result, error) {
+	var decoderResult *common.DecoderResult
+	var points []*core.ResultPoint
+	if _, ok := hints[core.DecodeHintPureBarcode]; ok {
+		bits, err := extractPureBits(image)
+		if err != nil {
+			return nil, err
+		}
+		decoderResult, err = r.decoder.Decode(bits)
+		if err != nil {
+			return nil, err
+		}
+		points = []*core.ResultPoint{}
+	} else {
+		det, err := detector.NewDetector(image)
+		if err != nil {
+			return nil, err
+		}
+		detectorResult, err := det.Detect()
+		if err != nil {
+			return nil, err
+		}
+		decoderResult, err = r.decoder.Decode(detectorResult.GetBits())
+		if err != nil {
+			return nil, err
+		}
+		points = detectorResult.GetPoints()
+	}
+	result := core.NewResult(decoderResult.GetText(), decoderResult.GetRawBytes(), points, core.DataMatrix)
+	if byteSegments := decoderResult.GetByteSegments(); byteSegments != nil {
+		result.PutMetadata(core.ResultMetadataByteSegments, byteSegments)
+	}
+	if ecLevel := decoderResult.GetECLevel(); ecLevel != "" {
+		result.PutMetadata(core.ResultMetadataErrorCorrectionLevel, ecLevel)
+	}
+	result.PutMetadata(core.ResultMetadataErrorsCorrected, decoderResult.GetErrorsCorrected())
+	result.PutMetadata(core.ResultMetadataSymbologyIdentifier, "]d"+strconv.Itoa(decoderResult.GetSymbologyModifier()))
+	return result, nil
+}
+
+func (r *DataMatrixReader) Reset() {
+	// do nothing
+}
+
+// extractPureBits is a method that detects and decodes a Data Matrix Code
+// when it is known that the image is a pure barcode, i.e. it contains
+// only an unrotated, unskewed, image of a Data Matrix Code, with some
+// white border around it. This is a specialized method that works
+// exceptionally fast in this special case.
+func extractPureBits(image *common.BitMatrix) (*common.BitMatrix, error) {
+	leftTopBlack := image.GetTopLeftOnBit()
+	rightBottomBlack := image.GetBottomRightOnBit()
+	if leftTopBlack == nil || rightBottomBlack == nil {
+		return nil, core.ErrNotFound
+	}
+
+	moduleSize, err := moduleSize(leftTopBlack, image)
+	if err != nil {
+		return nil, err
+	}
+
+	top := int(leftTopBlack[1])
+	bottom := int(rightBottomBlack[1])
+	left := int(leftTopBlack[0])
+	right := int(rightBottomBlack[0])
+
+	matrixWidth := (right - left + 1) / moduleSize
+	matrixHeight := (bottom - top + 1) / moduleSize
+	if matrixWidth <= 0 || matrixHeight <= 0 {
+		return nil, core.ErrNotFound
+	}
+
+	// Push in the "border" by half the module width so that we start
+	// sampling in the middle of the module. Just in case the image is a
+	// little off, this will help recover.
+	nudge := moduleSize / 2
+	top += nudge
+	left += nudge
+
+	// Now just read off the bits
+	bits, err := common.NewBitMatrix(uint32(matrixWidth), uint32(matrixHeight))
+	if err != nil {
+		return nil, core.ErrNotFound
+	}
+	for y := 0; y < matrixHeight; y++ {
+		iOffset := top + y*moduleSize
+		for x := 0; x < matrixWidth; x++ {
+			if image.Get(uint32(left+x*moduleSize), uint32(iOffset)) {
+				bits.Set(uint32(x), uint32(y))
+			}
+		}
+	}
+	return bits, nil
+}
+
+func moduleSize(leftTopBlack []uint32, image *common.BitMatrix) (int, error) {
+	width := image.GetWidth()
+	x := leftTopBlack[0]
+	y := leftTopBlack[1]
+	for x < width && image.Get(x, y) {
+		x++
+	}
+	if x == width {
+		return 0, core.ErrNotFound
+	}
+
+	moduleSize := int(x - leftTopBlack[0])
+	if moduleSize == 0 {
+		return 0, core.ErrNotFound
+	}
+	return moduleSize, nil
+}
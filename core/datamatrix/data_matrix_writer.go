@@ -0,0 +1,232 @@
+/*
+ * Copyright 2008 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datamatrix
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/datamatrix/encoder"
+)
+
+// DataMatrixWriter renders a Data Matrix code as a BitMatrix.
+type DataMatrixWriter struct{}
+
+func NewDataMatrixWriter() *DataMatrixWriter {
+	return &DataMatrixWriter{}
+}
+
+// Encode encodes contents as a Data Matrix code, scaled by a whole number
+// of pixels per module to fit within width by height pixels where
+// possible. The core.EncodeHintDataMatrixShape, core.EncodeHintMinSize and
+// core.EncodeHintMaxSize hints constrain the symbol chosen. With the
+// core.EncodeHintGS1Format hint set, contents is taken as GS1 data, in
+// either human readable or raw form, and encoded with FNC1 in the first
+// position.
+// It returns an error if contents cannot be encoded.
+func (w *DataMatrixWriter) Encode(contents string, format core.BarcodeFormat, width, height int, hints map[core.EncodeHintType]interface{}) (*common.BitMatrix, error) {
+	if len(contents) == 0 {
+		return nil, errors.New("Found empty contents")
+	}
+
+	if format != core.DataMatrix {
+		return nil, errors.New("Can only encode DATA_MATRIX, but got " + strconv.Itoa(int(format)))
+	}
+
+	if width < 0 || height < 0 {
+		return nil, errors.New("Requested dimensions can't be negative: " + strconv.Itoa(width) + "x" + strconv.Itoa(height))
+	}
+
+	// Try to get force shape & min / max size
+	shape := encoder.ForceNone
+	var minSize, maxSize *core.Dimension
+	if requestedShape, ok := hints[core.EncodeHintDataMatrixShape].(encoder.SymbolShapeHint); ok {
+		shape = requestedShape
+	}
+	if requestedMinSize, ok := hints[core.EncodeHintMinSize].(*core.Dimension); ok {
+		minSize = requestedMinSize
+	}
+	if requestedMaxSize, ok := hints[core.EncodeHintMaxSize].(*core.Dimension); ok {
+		maxSize = requestedMaxSize
+	}
+
+	//1. step: Data encodation
+	var encoded []uint8
+	var err error
+	if gs1, ok := hints[core.EncodeHintGS1Format].(bool); ok && gs1 {
+		var elements []common.GS1Element
+		elements, err = common.ParseGS1ElementString(contents)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err = encoder.EncodeHighLevelGS1(common.FormatGS1ElementString(elements), shape, minSize, maxSize)
+	} else {
+		encoded, err = encoder.EncodeHighLevelWithConstraints(contents, shape, minSize, maxSize)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	symbolInfo, err := encoder.LookupSymbolInfo(len(encoded), shape, minSize, maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	//2. step: ECC generation
+	codewords, err := encoder.EncodeECC200(encoded, symbolInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	//3. step: Module placement in Matrix
+	symbolWidth, err := symbolInfo.GetSymbolDataWidth()
+	if err != nil {
+		return nil, err
+	}
+	symbolHeight, err := symbolInfo.GetSymbolDataHeight()
+	if err != nil {
+		return nil, err
+	}
+	placement := encoder.NewDefaultPlacement(codewords, symbolWidth, symbolHeight)
+	placement.Place()
+
+	//4. step: low-level encoding
+	return encodeLowLevel(placement, symbolInfo, width, height)
+}
+
+// encodeLowLevel surrounds each data region of the placement with its
+// finder and timing patterns, and scales the result.
+func encodeLowLevel(placement *encoder.DefaultPlacement, symbolInfo *encoder.SymbolInfo, width, height int) (*common.BitMatrix, error) {
+	symbolWidth, err := symbolInfo.GetSymbolDataWidth()
+	if err != nil {
+		return nil, err
+	}
+	symbolHeight, err := symbolInfo.GetSymbolDataHeight()
+	if err != nil {
+		return nil, err
+	}
+	fullWidth, err := symbolInfo.GetSymbolWidth()
+	if err != nil {
+		return nil, err
+	}
+	fullHeight, err := symbolInfo.GetSymbolHeight()
+	if err != nil {
+		return nil, err
+	}
+	matrixWidth := symbolInfo.GetMatrixWidth()
+	matrixHeight := symbolInfo.GetMatrixHeight()
+
+	matrix, err := common.NewBitMatrix(uint32(fullWidth), uint32(fullHeight))
+	if err != nil {
+		return nil, err
+	}
+	set := func(x, y int, value bool) {
+		if value {
+			matrix.Set(uint32(x), uint32(y))
+		}
+	}
+
+	matrixY := 0
+
+	for y := 0; y < symbolHeight; y++ {
+		// Fill the top edge with alternate 0 / 1
+		var matrixX int
+		if (y % matrixHeight) == 0 {
+			matrixX = 0
+			for x := 0; x < fullWidth; x++ {
+				set(matrixX, matrixY, (x%2) == 0)
+				matrixX++
+			}
+			matrixY++
+		}
+		matrixX = 0
+		for x := 0; x < symbolWidth; x++ {
+			// Fill the right edge with full 1
+			if (x % matrixWidth) == 0 {
+				set(matrixX, matrixY, true)
+				matrixX++
+			}
+			set(matrixX, matrixY, placement.GetBit(x, y))
+			matrixX++
+			// Fill the right edge with alternate 0 / 1
+			if (x % matrixWidth) == matrixWidth-1 {
+				set(matrixX, matrixY, (y%2) == 0)
+				matrixX++
+			}
+		}
+		matrixY++
+		// Fill the bottom edge with full 1
+		if (y % matrixHeight) == matrixHeight-1 {
+			matrixX = 0
+			for x := 0; x < fullWidth; x++ {
+				set(matrixX, matrixY, true)
+				matrixX++
+			}
+			matrixY++
+		}
+	}
+
+	return scaleMatrix(matrix, width, height)
+}
+
+// scaleMatrix scales matrix up by the largest whole factor that fits
+// within reqWidth by reqHeight, centering it. If the requested size is
+// smaller than matrix, matrix is returned unscaled without padding.
+func scaleMatrix(matrix *common.BitMatrix, reqWidth, reqHeight int) (*common.BitMatrix, error) {
+	matrixWidth := int(matrix.GetWidth())
+	matrixHeight := int(matrix.GetHeight())
+	outputWidth := reqWidth
+	if matrixWidth > outputWidth {
+		outputWidth = matrixWidth
+	}
+	outputHeight := reqHeight
+	if matrixHeight > outputHeight {
+		outputHeight = matrixHeight
+	}
+
+	multiple := outputWidth / matrixWidth
+	if m := outputHeight / matrixHeight; m < multiple {
+		multiple = m
+	}
+
+	leftPadding := (outputWidth - (matrixWidth * multiple)) / 2
+	topPadding := (outputHeight - (matrixHeight * multiple)) / 2
+
+	// remove padding if requested width and height are too small
+	if reqHeight < matrixHeight || reqWidth < matrixWidth {
+		return matrix, nil
+	}
+	output, err := common.NewBitMatrix(uint32(reqWidth), uint32(reqHeight))
+	if err != nil {
+		return nil, err
+	}
+
+	for inputY, outputY := 0, topPadding; inputY < matrixHeight; inputY, outputY = inputY+1, outputY+multiple {
+		// Write the contents of this row of the matrix
+		for inputX, outputX := 0, leftPadding; inputX < matrixWidth; inputX, outputX = inputX+1, outputX+multiple {
+			if matrix.Get(uint32(inputX), uint32(inputY)) {
+				if err := output.SetRegion(uint32(outputX), uint32(outputY), uint32(multiple), uint32(multiple)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return output, nil
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright 2008 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datamatrix_test
+
+import (
+	"bytes"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/datamatrix"
+	"github.com/discesoft/zxing-go/core/datamatrix/encoder"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+func encodeAndDecode(t *testing.T, contents string, hints map[core.EncodeHintType]interface{}, pure bool) *core.Result {
+	matrix, err := datamatrix.NewDataMatrixWriter().Encode(contents, core.DataMatrix, 0, 0, hints)
+	internal.AssertSuccess(t, err)
+
+	// Scale the symbol up and give it a quiet zone, as if it were scanned
+	width := matrix.GetWidth()
+	height := matrix.GetHeight()
+	image, err := common.NewBitMatrix(4*width+16, 4*height+16)
+	internal.AssertSuccess(t, err)
+	for y := uint32(0); y < height; y++ {
+		for x := uint32(0); x < width; x++ {
+			if matrix.Get(x, y) {
+				internal.AssertSuccess(t, image.SetRegion(4*x+8, 4*y+8, 4, 4))
+			}
+		}
+	}
+
+	decodeHints := map[core.DecodeHintType]interface{}{}
+	if pure {
+		decodeHints[core.DecodeHintPureBarcode] = true
+	}
+	result, err := datamatrix.NewDataMatrixReader().Decode(image, decodeHints)
+	internal.AssertSuccess(t, err)
+	return result
+}
+
+func assertRoundTrips(t *testing.T, contents string, hints map[core.EncodeHintType]interface{}, pure bool) {
+	result := encodeAndDecode(t, contents, hints, pure)
+	internal.AssertEquals(t, contents, result.GetText(), "decoded "+strconv.Quote(result.GetText())+" instead of "+strconv.Quote(contents))
+}
+
+func TestDataMatrixWriter_RoundTrip(t *testing.T) {
+	for _, contents := range []string{
+		"A",
+		"123456",
+		"Hello, World!",
+		"AIMAIMAIM",
+		"aimaimaim",
+		"ABC>ABC123>AB",
+		".A.C1.3.DATA.123DATA.123DATA",
+		"«äöüé»",
+		"[)>\u001E05\u001DPART1234\u001E\u0004",
+		"http://www.example.com/some/path?query=value&other=1",
+	} {
+		assertRoundTrips(t, contents, nil, true)
+		assertRoundTrips(t, contents, nil, false)
+	}
+}
+
+func TestDataMatrixWriter_GS1(t *testing.T) {
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintGS1Format: true}
+	for _, test := range []struct {
+		contents string
+		expected string
+	}{
+		{"(01)09506000134352(17)201225(10)ABC", "01095060001343521720122510ABC"},
+		{"(10)ABC123(21)xyz(17)201225", "10ABC123\x1d21xyz\x1d17201225"},
+		{"10ABC123\x1d21xyz\x1d17201225", "10ABC123\x1d21xyz\x1d17201225"},
+		{"(00)095060001343528884(3103)001250", "000950600013435288843103001250"},
+		{"(91)abcdefghijklmnopqrstuvwxyz0123456789(10)X", "91abcdefghijklmnopqrstuvwxyz0123456789\x1d10X"},
+	} {
+		for _, pure := range []bool{true, false} {
+			result := encodeAndDecode(t, test.contents, hints, pure)
+			// The leading FNC1 is reported as a group separator too
+			internal.AssertEquals(t, "\x1d"+test.expected, result.GetText(), "decoded "+strconv.Quote(result.GetText())+" for "+test.contents)
+			internal.AssertEquals(t, "]d2", result.GetResultMetadata()[core.ResultMetadataSymbologyIdentifier], "symbology identifier of "+test.contents)
+		}
+	}
+
+	for _, contents := range []string{
+		"(01)09506000134353",
+		"(17)201325",
+		"(99)",
+		"(10)ABC~",
+		"(05)123",
+	} {
+		_, err := datamatrix.NewDataMatrixWriter().Encode(contents, core.DataMatrix, 0, 0, hints)
+		internal.AssertFailure(t, err, "expected "+contents+" to be rejected")
+	}
+}
+
+func TestDataMatrixWriter_GS1Constraints(t *testing.T) {
+	maxSize, err := core.NewDimension(12, 12)
+	internal.AssertSuccess(t, err)
+	contents := "(01)09506000134352(17)201225(10)ABC123"
+	const arrangementError = "Can't find a symbol arrangement that matches the message. Data codewords: "
+
+	// The GS1 path reports the same constraint error as the plain one
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintMaxSize: maxSize}
+	_, err = datamatrix.NewDataMatrixWriter().Encode(contents, core.DataMatrix, 0, 0, hints)
+	internal.AssertFailure(t, err, "expected "+contents+" not to fit in 12x12")
+	internal.AssertTrue(t, strings.HasPrefix(err.Error(), arrangementError), "unexpected error "+err.Error())
+	hints[core.EncodeHintGS1Format] = true
+	_, err = datamatrix.NewDataMatrixWriter().Encode(contents, core.DataMatrix, 0, 0, hints)
+	internal.AssertFailure(t, err, "expected GS1 "+contents+" not to fit in 12x12")
+	internal.AssertTrue(t, strings.HasPrefix(err.Error(), arrangementError), "unexpected GS1 error "+err.Error())
+}
+
+func TestDataMatrixWriter_Shapes(t *testing.T) {
+	for _, shape := range []encoder.SymbolShapeHint{encoder.ForceNone, encoder.ForceSquare, encoder.ForceRectangle} {
+		hints := map[core.EncodeHintType]interface{}{core.EncodeHintDataMatrixShape: shape}
+		assertRoundTrips(t, "ABCDEF0123456789", hints, true)
+		assertRoundTrips(t, "Lorem ipsum dolor sit amet", hints, false)
+	}
+
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintDataMatrixShape: encoder.ForceRectangle}
+	matrix, err := datamatrix.NewDataMatrixWriter().Encode("ABC", core.DataMatrix, 0, 0, hints)
+	internal.AssertSuccess(t, err)
+	internal.AssertTrue(t, matrix.GetWidth() > matrix.GetHeight(), "rectangle hint produced a "+strconv.Itoa(int(matrix.GetWidth()))+"x"+strconv.Itoa(int(matrix.GetHeight()))+" symbol")
+}
+
+func TestDataMatrixWriter_DMRE(t *testing.T) {
+	for _, size := range [][2]int{
+		{48, 8}, {64, 8}, {80, 8}, {96, 8}, {120, 8}, {144, 8},
+		{64, 12}, {88, 12}, {64, 16},
+		{36, 20}, {44, 20}, {64, 20}, {48, 22}, {48, 24}, {64, 24},
+		{40, 26}, {48, 26}, {64, 26},
+	} {
+		dimension, err := core.NewDimension(size[0], size[1])
+		internal.AssertSuccess(t, err)
+		hints := map[core.EncodeHintType]interface{}{
+			core.EncodeHintDataMatrixShape: encoder.ForceRectangle,
+			core.EncodeHintMinSize:         dimension,
+			core.EncodeHintMaxSize:         dimension,
+		}
+		matrix, err := datamatrix.NewDataMatrixWriter().Encode("DMRE 12345", core.DataMatrix, 0, 0, hints)
+		internal.AssertSuccess(t, err)
+		internal.AssertEquals(t, uint32(size[0]), matrix.GetWidth(), "wrong width for "+dimension.String())
+		internal.AssertEquals(t, uint32(size[1]), matrix.GetHeight(), "wrong height for "+dimension.String())
+		assertRoundTrips(t, "DMRE 12345", hints, true)
+		assertRoundTrips(t, "DMRE 12345", hints, false)
+	}
+}
+
+func TestDataMatrixWriter_LargeSymbols(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, length := range []int{100, 500, 1000, 1500} {
+		var sb bytes.Buffer
+		for i := 0; i < length; i++ {
+			sb.WriteByte(byte(' ' + random.Intn(95)))
+		}
+		assertRoundTrips(t, sb.String(), nil, true)
+	}
+
+	// 144x144 interleaves blocks of differing lengths
+	contents := strings.Repeat("1a", 1000)
+	matrix, err := datamatrix.NewDataMatrixWriter().Encode(contents, core.DataMatrix, 0, 0, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, uint32(144), matrix.GetWidth(), "expected a 144x144 symbol")
+	assertRoundTrips(t, contents, nil, true)
+}
+
+func TestDataMatrixWriter_Errors(t *testing.T) {
+	writer := datamatrix.NewDataMatrixWriter()
+	_, err := writer.Encode("", core.DataMatrix, 0, 0, nil)
+	internal.AssertFailure(t, err, "encoded empty contents")
+	_, err = writer.Encode("ABC", core.QRCode, 0, 0, nil)
+	internal.AssertFailure(t, err, "encoded a QR code")
+	_, err = writer.Encode(strings.Repeat("x", 5000), core.DataMatrix, 0, 0, nil)
+	internal.AssertFailure(t, err, "encoded contents larger than any symbol")
+}
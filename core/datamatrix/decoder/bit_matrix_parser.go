@@ -0,0 +1,308 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+type bitMatrixParser struct {
+	mappingBitMatrix  *common.BitMatrix
+	readMappingMatrix *common.BitMatrix
+	version           *Version
+}
+
+// newBitMatrixParser prepares bitMatrix, which holds the modules of the
+// whole symbol, for reading codewords out of it.
+// It returns core.ErrFormat if the dimension of bitMatrix is not valid.
+func newBitMatrixParser(bitMatrix *common.BitMatrix) (*bitMatrixParser, error) {
+	dimension := bitMatrix.GetHeight()
+	if dimension < 8 || dimension > 144 || (dimension&0x01) != 0 {
+		return nil, core.ErrFormat
+	}
+
+	version, err := GetVersionForDimensions(int(bitMatrix.GetHeight()), int(bitMatrix.GetWidth()))
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &bitMatrixParser{version: version}
+	parser.mappingBitMatrix, err = parser.extractDataRegion(bitMatrix)
+	if err != nil {
+		return nil, err
+	}
+	parser.readMappingMatrix, err = common.NewBitMatrix(parser.mappingBitMatrix.GetWidth(), parser.mappingBitMatrix.GetHeight())
+	if err != nil {
+		return nil, err
+	}
+	return parser, nil
+}
+
+func (p *bitMatrixParser) getVersion() *Version {
+	return p.version
+}
+
+// readCodewords reads the bits in the mapping matrix, in the order
+// specified by ISO 16022:2006 Annex F.3.
+// It returns the codewords in the order in which they were placed, or
+// core.ErrFormat if they could not be read completely.
+func (p *bitMatrixParser) readCodewords() ([]uint8, error) {
+	result := make([]uint8, p.version.GetTotalCodewords())
+	resultOffset := 0
+
+	row := 4
+	column := 0
+
+	numRows := int(p.mappingBitMatrix.GetHeight())
+	numColumns := int(p.mappingBitMatrix.GetWidth())
+
+	corner1Read := false
+	corner2Read := false
+	corner3Read := false
+	corner4Read := false
+
+	// Read all of the codewords
+	for {
+		if resultOffset >= len(result) {
+			break
+		}
+
+		// Check the four corner cases
+		if (row == numRows) && (column == 0) && !corner1Read {
+			result[resultOffset] = p.readCorner1(numRows, numColumns)
+			resultOffset++
+			row -= 2
+			column += 2
+			corner1Read = true
+		} else if (row == numRows-2) && (column == 0) && ((numColumns & 0x03) != 0) && !corner2Read {
+			result[resultOffset] = p.readCorner2(numRows, numColumns)
+			resultOffset++
+			row -= 2
+			column += 2
+			corner2Read = true
+		} else if (row == numRows+4) && (column == 2) && ((numColumns & 0x07) == 0) && !corner3Read {
+			result[resultOffset] = p.readCorner3(numRows, numColumns)
+			resultOffset++
+			row -= 2
+			column += 2
+			corner3Read = true
+		} else if (row == numRows-2) && (column == 0) && ((numColumns & 0x07) == 4) && !corner4Read {
+			result[resultOffset] = p.readCorner4(numRows, numColumns)
+			resultOffset++
+			row -= 2
+			column += 2
+			corner4Read = true
+		} else {
+			// Sweep upward diagonally to the right
+			for {
+				if (row < numRows) && (column >= 0) && !p.readMappingMatrix.Get(uint32(column), uint32(row)) {
+					if resultOffset >= len(result) {
+						return nil, core.ErrFormat
+					}
+					result[resultOffset] = p.readUtah(row, column, numRows, numColumns)
+					resultOffset++
+				}
+				row -= 2
+				column += 2
+				if !((row >= 0) && (column < numColumns)) {
+					break
+				}
+			}
+			row++
+			column += 3
+
+			// Sweep downward diagonally to the left
+			for {
+				if (row >= 0) && (column < numColumns) && !p.readMappingMatrix.Get(uint32(column), uint32(row)) {
+					if resultOffset >= len(result) {
+						return nil, core.ErrFormat
+					}
+					result[resultOffset] = p.readUtah(row, column, numRows, numColumns)
+					resultOffset++
+				}
+				row += 2
+				column -= 2
+				if !((row < numRows) && (column >= 0)) {
+					break
+				}
+			}
+			row += 3
+			column++
+		}
+
+		if !((row < numRows) || (column < numColumns)) {
+			break
+		}
+	}
+
+	if resultOffset != p.version.GetTotalCodewords() {
+		return nil, core.ErrFormat
+	}
+	return result, nil
+}
+
+// readModule reads a module of the mapping matrix, wrapping row and column
+// around the edges of the matrix as required by the placement algorithm.
+// It returns true if the module is set.
+func (p *bitMatrixParser) readModule(row, column, numRows, numColumns int) bool {
+	// Adjust the row and column indices based on boundary wrapping
+	if row < 0 {
+		row += numRows
+		column += 4 - ((numRows + 4) & 0x07)
+	}
+	if column < 0 {
+		column += numColumns
+		row += 4 - ((numColumns + 4) & 0x07)
+	}
+	if row >= numRows {
+		row -= numRows
+	}
+	p.readMappingMatrix.Set(uint32(column), uint32(row))
+	return p.mappingBitMatrix.Get(uint32(column), uint32(row))
+}
+
+func (p *bitMatrixParser) readBits(positions [8][2]int, numRows, numColumns int) uint8 {
+	currentByte := uint8(0)
+	for _, position := range positions {
+		currentByte <<= 1
+		if p.readModule(position[0], position[1], numRows, numColumns) {
+			currentByte |= 1
+		}
+	}
+	return currentByte
+}
+
+// readUtah reads the 8 bits of the standard Utah-shaped pattern, see ISO
+// 16022:2006, 5.8.1 Figure 6.
+// row and column locate the lower-right module of the pattern.
+func (p *bitMatrixParser) readUtah(row, column, numRows, numColumns int) uint8 {
+	return p.readBits([8][2]int{
+		{row - 2, column - 2},
+		{row - 2, column - 1},
+		{row - 1, column - 2},
+		{row - 1, column - 1},
+		{row - 1, column},
+		{row, column - 2},
+		{row, column - 1},
+		{row, column},
+	}, numRows, numColumns)
+}
+
+// readCorner1 reads the 8 bits of the special corner condition 1, see ISO
+// 16022:2006, Figure F.3.
+func (p *bitMatrixParser) readCorner1(numRows, numColumns int) uint8 {
+	return p.readBits([8][2]int{
+		{numRows - 1, 0},
+		{numRows - 1, 1},
+		{numRows - 1, 2},
+		{0, numColumns - 2},
+		{0, numColumns - 1},
+		{1, numColumns - 1},
+		{2, numColumns - 1},
+		{3, numColumns - 1},
+	}, numRows, numColumns)
+}
+
+// readCorner2 reads the 8 bits of the special corner condition 2, see ISO
+// 16022:2006, Figure F.4.
+func (p *bitMatrixParser) readCorner2(numRows, numColumns int) uint8 {
+	return p.readBits([8][2]int{
+		{numRows - 3, 0},
+		{numRows - 2, 0},
+		{numRows - 1, 0},
+		{0, numColumns - 4},
+		{0, numColumns - 3},
+		{0, numColumns - 2},
+		{0, numColumns - 1},
+		{1, numColumns - 1},
+	}, numRows, numColumns)
+}
+
+// readCorner3 reads the 8 bits of the special corner condition 3, see ISO
+// 16022:2006, Figure F.5.
+func (p *bitMatrixParser) readCorner3(numRows, numColumns int) uint8 {
+	return p.readBits([8][2]int{
+		{numRows - 1, 0},
+		{numRows - 1, numColumns - 1},
+		{0, numColumns - 3},
+		{0, numColumns - 2},
+		{0, numColumns - 1},
+		{1, numColumns - 3},
+		{1, numColumns - 2},
+		{1, numColumns - 1},
+	}, numRows, numColumns)
+}
+
+// readCorner4 reads the 8 bits of the special corner condition 4, see ISO
+// 16022:2006, Figure F.6.
+func (p *bitMatrixParser) readCorner4(numRows, numColumns int) uint8 {
+	return p.readBits([8][2]int{
+		{numRows - 3, 0},
+		{numRows - 2, 0},
+		{numRows - 1, 0},
+		{0, numColumns - 2},
+		{0, numColumns - 1},
+		{1, numColumns - 1},
+		{2, numColumns - 1},
+		{3, numColumns - 1},
+	}, numRows, numColumns)
+}
+
+// extractDataRegion strips the finder patterns and alignment patterns out
+// of bitMatrix, leaving only the modules of its data regions pushed
+// together into a single matrix.
+func (p *bitMatrixParser) extractDataRegion(bitMatrix *common.BitMatrix) (*common.BitMatrix, error) {
+	symbolSizeRows := p.version.GetSymbolSizeRows()
+	symbolSizeColumns := p.version.GetSymbolSizeColumns()
+
+	if int(bitMatrix.GetHeight()) != symbolSizeRows {
+		return nil, core.ErrFormat
+	}
+
+	dataRegionSizeRows := p.version.GetDataRegionSizeRows()
+	dataRegionSizeColumns := p.version.GetDataRegionSizeColumns()
+
+	numDataRegionsRow := symbolSizeRows / dataRegionSizeRows
+	numDataRegionsColumn := symbolSizeColumns / dataRegionSizeColumns
+
+	sizeDataRegionRow := numDataRegionsRow * dataRegionSizeRows
+	sizeDataRegionColumn := numDataRegionsColumn * dataRegionSizeColumns
+
+	bitMatrixWithoutAlignment, err := common.NewBitMatrix(uint32(sizeDataRegionColumn), uint32(sizeDataRegionRow))
+	if err != nil {
+		return nil, err
+	}
+	for dataRegionRow := 0; dataRegionRow < numDataRegionsRow; dataRegionRow++ {
+		dataRegionRowOffset := dataRegionRow * dataRegionSizeRows
+		for dataRegionColumn := 0; dataRegionColumn < numDataRegionsColumn; dataRegionColumn++ {
+			dataRegionColumnOffset := dataRegionColumn * dataRegionSizeColumns
+			for i := 0; i < dataRegionSizeRows; i++ {
+				readRowOffset := dataRegionRow*(dataRegionSizeRows+2) + 1 + i
+				writeRowOffset := dataRegionRowOffset + i
+				for j := 0; j < dataRegionSizeColumns; j++ {
+					readColumnOffset := dataRegionColumn*(dataRegionSizeColumns+2) + 1 + j
+					if bitMatrix.Get(uint32(readColumnOffset), uint32(readRowOffset)) {
+						writeColumnOffset := dataRegionColumnOffset + j
+						bitMatrixWithoutAlignment.Set(uint32(writeColumnOffset), uint32(writeRowOffset))
+					}
+				}
+			}
+		}
+	}
+	return bitMatrixWithoutAlignment, nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import "github.com/discesoft/zxing-go/core"
+
+// dataBlock encapsulates a block of data within a Data Matrix Code. Data
+// Matrix Codes may split their data into multiple blocks, each of which is
+// a unit of data and error-correction codewords. Each is represented by
+// an instance of this type.
+type dataBlock struct {
+	numDataCodewords int
+	codewords        []uint8
+}
+
+// getDataBlocks separates the codewords read from a symbol into their data
+// blocks, undoing the interleaving applied when the symbol was encoded.
+// rawCodewords are the codewords as read directly from the Data Matrix
+// Code, and version is the version of the symbol.
+// It returns the data blocks, or core.ErrFormat if the number of codewords
+// doesn't match the version.
+func getDataBlocks(rawCodewords []uint8, version *Version) ([]*dataBlock, error) {
+	// Figure out the number and size of data blocks used by this version
+	ecBlocks := version.GetECBlocks()
+
+	// First count the total number of data blocks
+	totalBlocks := 0
+	ecBlockArray := ecBlocks.GetECBlocks()
+	for _, ecBlock := range ecBlockArray {
+		totalBlocks += ecBlock.GetCount()
+	}
+
+	// Now establish DataBlocks of the appropriate size and number of data codewords
+	result := make([]*dataBlock, 0, totalBlocks)
+	for _, ecBlock := range ecBlockArray {
+		for i := 0; i < ecBlock.GetCount(); i++ {
+			numDataCodewords := ecBlock.GetDataCodewords()
+			numBlockCodewords := ecBlocks.GetECCodewords() + numDataCodewords
+			result = append(result, &dataBlock{numDataCodewords, make([]uint8, numBlockCodewords)})
+		}
+	}
+	numResultBlocks := len(result)
+
+	// All blocks have the same amount of data, except that the last n
+	// (where n may be 0) have 1 less byte. Figure out where these start.
+	// There is only one case where there is a difference for Data Matrix:
+	// the 144x144 symbol.
+	longerBlocksTotalCodewords := len(result[0].codewords)
+
+	longerBlocksNumDataCodewords := longerBlocksTotalCodewords - ecBlocks.GetECCodewords()
+	shorterBlocksNumDataCodewords := longerBlocksNumDataCodewords - 1
+
+	// The last elements of result may be 1 element shorter for 144 matrix
+	// first fill out as many elements as all of them have minus 1
+	rawCodewordsOffset := 0
+	for i := 0; i < shorterBlocksNumDataCodewords; i++ {
+		for j := 0; j < numResultBlocks; j++ {
+			result[j].codewords[i] = rawCodewords[rawCodewordsOffset]
+			rawCodewordsOffset++
+		}
+	}
+
+	// Fill out the last data block in the longer ones
+	numLongerBlocks := 0
+	for _, block := range result {
+		if block.numDataCodewords == longerBlocksNumDataCodewords {
+			numLongerBlocks++
+		}
+	}
+	for j := 0; j < numLongerBlocks; j++ {
+		result[j].codewords[longerBlocksNumDataCodewords-1] = rawCodewords[rawCodewordsOffset]
+		rawCodewordsOffset++
+	}
+
+	// Now add in error correction blocks. The codewords of all blocks are
+	// interleaved as a whole, so where there are shorter blocks the
+	// interleaving of the error correction continues with the first of
+	// those, which has already run out of data.
+	max := len(result[0].codewords)
+	for i := longerBlocksNumDataCodewords; i < max; i++ {
+		for j := 0; j < numResultBlocks; j++ {
+			jOffset := (j + numLongerBlocks) % numResultBlocks
+			iOffset := i
+			if jOffset >= numLongerBlocks {
+				iOffset = i - 1
+			}
+			result[jOffset].codewords[iOffset] = rawCodewords[rawCodewordsOffset]
+			rawCodewordsOffset++
+		}
+	}
+
+	if rawCodewordsOffset != len(rawCodewords) {
+		return nil, core.ErrFormat
+	}
+
+	return result, nil
+}
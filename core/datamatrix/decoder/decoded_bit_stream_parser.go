@@ -0,0 +1,504 @@
+/*
+ * Copyright 2008 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+// The decoded bit stream parser takes the data codewords of a Data Matrix
+// Code and decodes them into text, according to ISO 16022:2006 5.2.
+
+type mode int
+
+const (
+	padEncode mode = iota // Not really a mode
+	asciiEncode
+	c40Encode
+	textEncode
+	ansiX12Encode
+	edifactEncode
+	base256Encode
+	eciEncode
+)
+
+// See ISO 16022:2006, Annex C Table C.1
+// The C40 Basic Character Set (*'s used for placeholders for the shift values)
+var c40BasicSetChars = []rune{
+	'*', '*', '*', ' ', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9',
+	'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N',
+	'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z',
+}
+
+var c40Shift2SetChars = []rune{
+	'!', '"', '#', '$', '%', '&', '\'', '(', ')', '*', '+', ',', '-', '.',
+	'/', ':', ';', '<', '=', '>', '?', '@', '[', '\\', ']', '^', '_',
+}
+
+// See ISO 16022:2006, Annex C Table C.2
+// The Text Basic Character Set (*'s used for placeholders for the shift values)
+var textBasicSetChars = []rune{
+	'*', '*', '*', ' ', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9',
+	'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n',
+	'o', 'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z',
+}
+
+// Shift 2 for Text is the same encoding as C40
+var textShift2SetChars = c40Shift2SetChars
+
+var textShift3SetChars = []rune{
+	'`', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N',
+	'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', '{', '|', '}', '~', 127,
+}
+
+// decodeBitStream decodes the data codewords of a symbol.
+// It returns the decoded result, or core.ErrFormat if the codewords are
+// not a valid encodation.
+func decodeBitStream(bytes []uint8) (*common.DecoderResult, error) {
+	bits := common.NewBitSource(bytes)
+	result := common.NewECIStringBuilder()
+	resultTrailer := ""
+	var byteSegments [][]uint8
+	currentMode := asciiEncode
+	// Could look directly at 'bytes', if we're sure of not having to account for multi byte values
+	fnc1Positions := map[int]bool{}
+	isECIEncoded := false
+
+	var err error
+	for {
+		if currentMode == asciiEncode {
+			currentMode, err = decodeAsciiSegment(bits, result, &resultTrailer, fnc1Positions)
+		} else {
+			switch currentMode {
+			case c40Encode:
+				err = decodeC40Segment(bits, result, fnc1Positions)
+			case textEncode:
+				err = decodeTextSegment(bits, result, fnc1Positions)
+			case ansiX12Encode:
+				err = decodeAnsiX12Segment(bits, result)
+			case edifactEncode:
+				err = decodeEdifactSegment(bits, result)
+			case base256Encode:
+				byteSegments, err = decodeBase256Segment(bits, result, byteSegments)
+			case eciEncode:
+				err = decodeECISegment(bits, result)
+				isECIEncoded = true // ECI detection only, atm continue decoding as ASCII
+			default:
+				err = core.ErrFormat
+			}
+			currentMode = asciiEncode
+		}
+		if err != nil {
+			return nil, err
+		}
+		if currentMode == padEncode || bits.Available() <= 0 {
+			break
+		}
+	}
+	if len(resultTrailer) > 0 {
+		result.AppendCharacters(resultTrailer)
+	}
+
+	// The symbology identifier modifier depends on whether FNC1 appears
+	// in the first or second position, and on whether ECIs were used.
+	// See ISO 16022:2006, Annex N.
+	symbologyModifier := 1
+	if fnc1Positions[0] || fnc1Positions[4] {
+		symbologyModifier = 2
+	} else if fnc1Positions[1] || fnc1Positions[5] {
+		symbologyModifier = 3
+	}
+	if isECIEncoded {
+		symbologyModifier += 3
+	}
+
+	return common.NewDecoderResultWithModifier(bytes, result.String(), byteSegments, "", symbologyModifier), nil
+}
+
+// decodeAsciiSegment decodes codewords in ASCII encodation, see ISO
+// 16022:2006, 5.2.3 and Annex C, Table C.2.
+// It returns the mode in which decoding should continue.
+func decodeAsciiSegment(bits *common.BitSource, result *common.ECIStringBuilder, resultTrailer *string, fnc1Positions map[int]bool) (mode, error) {
+	upperShift := false
+	for {
+		oneByte, err := bits.ReadBits(8)
+		if err != nil {
+			return padEncode, err
+		}
+		if oneByte == 0 {
+			return padEncode, core.ErrFormat
+		} else if oneByte <= 128 { // ASCII data (ASCII value + 1)
+			if upperShift {
+				oneByte += 128
+			}
+			result.AppendByte(uint8(oneByte - 1))
+			return asciiEncode, nil
+		} else if oneByte == 129 { // Pad
+			return padEncode, nil
+		} else if oneByte <= 229 { // 2-digit data 00-99 (Numeric Value + 130)
+			value := oneByte - 130
+			if value < 10 { // pad with '0' for single digit values
+				result.AppendByte('0')
+			}
+			result.AppendInt(value)
+		} else {
+			switch oneByte {
+			case 230: // Latch to C40 encodation
+				return c40Encode, nil
+			case 231: // Latch to Base 256 encodation
+				return base256Encode, nil
+			case 232: // FNC1
+				fnc1Positions[result.Len()] = true
+				result.AppendByte(29) // translate as ASCII 29
+			case 233, 234:
+				// Structured Append, Reader Programming
+				// Ignore these symbols for now
+			case 235: // Upper Shift (shift to Extended ASCII)
+				upperShift = true
+			case 236: // 05 Macro
+				result.AppendString("[)>\x1E05\x1D")
+				*resultTrailer = "\x1E\x04" + *resultTrailer
+			case 237: // 06 Macro
+				result.AppendString("[)>\x1E06\x1D")
+				*resultTrailer = "\x1E\x04" + *resultTrailer
+			case 238: // Latch to ANSI X12 encodation
+				return ansiX12Encode, nil
+			case 239: // Latch to Text encodation
+				return textEncode, nil
+			case 240: // Latch to EDIFACT encodation
+				return edifactEncode, nil
+			case 241: // ECI Character
+				return eciEncode, nil
+			default:
+				// Not to be used in ASCII encodation
+				// but work around encoders that end with 254, latch back to ASCII
+				if oneByte != 254 || bits.Available() != 0 {
+					return padEncode, core.ErrFormat
+				}
+			}
+		}
+		if bits.Available() <= 0 {
+			break
+		}
+	}
+	return asciiEncode, nil
+}
+
+// decodeC40Segment decodes codewords in C40 encodation, see ISO
+// 16022:2006, 5.2.5 and Annex C, Table C.1.
+func decodeC40Segment(bits *common.BitSource, result *common.ECIStringBuilder, fnc1Positions map[int]bool) error {
+	return decodeC40OrTextSegment(bits, result, fnc1Positions, c40BasicSetChars, c40Shift2SetChars, nil)
+}
+
+// decodeTextSegment decodes codewords in Text encodation, see ISO
+// 16022:2006, 5.2.6 and Annex C, Table C.2.
+func decodeTextSegment(bits *common.BitSource, result *common.ECIStringBuilder, fnc1Positions map[int]bool) error {
+	return decodeC40OrTextSegment(bits, result, fnc1Positions, textBasicSetChars, textShift2SetChars, textShift3SetChars)
+}
+
+// decodeC40OrTextSegment decodes either C40 or Text encodation, which
+// differ only in their basic and shift 3 sets. A nil shift3SetChars
+// selects the C40 behaviour for shift 3.
+func decodeC40OrTextSegment(bits *common.BitSource, result *common.ECIStringBuilder, fnc1Positions map[int]bool, basicSetChars, shift2SetChars, shift3SetChars []rune) error {
+	// Three C40 values are encoded in a 16-bit value as
+	// (1600 * C1) + (40 * C2) + C3 + 1
+	upperShift := false
+
+	cValues := make([]int, 3)
+	shift := 0
+
+	appendChar := func(value int) {
+		if upperShift {
+			value += 128
+			upperShift = false
+		}
+		result.AppendByte(uint8(value))
+	}
+
+	for {
+		// If there is only one byte left then it will be encoded as ASCII
+		if bits.Available() == 8 {
+			return nil
+		}
+		firstByte, err := bits.ReadBits(8)
+		if err != nil {
+			return err
+		}
+		if firstByte == 254 { // Unlatch codeword
+			return nil
+		}
+
+		secondByte, err := bits.ReadBits(8)
+		if err != nil {
+			return err
+		}
+		parseTwoBytes(firstByte, secondByte, cValues)
+
+		for _, cValue := range cValues {
+			switch shift {
+			case 0:
+				if cValue < 3 {
+					shift = cValue + 1
+				} else if cValue < len(basicSetChars) {
+					appendChar(int(basicSetChars[cValue]))
+				} else {
+					return core.ErrFormat
+				}
+			case 1:
+				appendChar(cValue)
+				shift = 0
+			case 2:
+				if cValue < len(shift2SetChars) {
+					appendChar(int(shift2SetChars[cValue]))
+				} else {
+					switch cValue {
+					case 27: // FNC1
+						fnc1Positions[result.Len()] = true
+						result.AppendByte(29) // translate as ASCII 29
+					case 30: // Upper Shift
+						upperShift = true
+					default:
+						return core.ErrFormat
+					}
+				}
+				shift = 0
+			case 3:
+				if shift3SetChars == nil {
+					appendChar(cValue + 96)
+				} else if cValue < len(shift3SetChars) {
+					appendChar(int(shift3SetChars[cValue]))
+				} else {
+					return core.ErrFormat
+				}
+				shift = 0
+			default:
+				return core.ErrFormat
+			}
+		}
+
+		if bits.Available() <= 0 {
+			return nil
+		}
+	}
+}
+
+// decodeAnsiX12Segment decodes codewords in ANSI X12 encodation, see ISO
+// 16022:2006, 5.2.7.
+func decodeAnsiX12Segment(bits *common.BitSource, result *common.ECIStringBuilder) error {
+	// Three ANSI X12 values are encoded in a 16-bit value as
+	// (1600 * C1) + (40 * C2) + C3 + 1
+
+	cValues := make([]int, 3)
+	for {
+		// If there is only one byte left then it will be encoded as ASCII
+		if bits.Available() == 8 {
+			return nil
+		}
+		firstByte, err := bits.ReadBits(8)
+		if err != nil {
+			return err
+		}
+		if firstByte == 254 { // Unlatch codeword
+			return nil
+		}
+
+		secondByte, err := bits.ReadBits(8)
+		if err != nil {
+			return err
+		}
+		parseTwoBytes(firstByte, secondByte, cValues)
+
+		for _, cValue := range cValues {
+			switch cValue {
+			case 0: // X12 segment terminator <CR>
+				result.AppendByte('\r')
+			case 1: // X12 segment separator *
+				result.AppendByte('*')
+			case 2: // X12 sub-element separator >
+				result.AppendByte('>')
+			case 3: // space
+				result.AppendByte(' ')
+			default:
+				if cValue < 14 { // 0 - 9
+					result.AppendByte(uint8(cValue + 44))
+				} else if cValue < 40 { // A - Z
+					result.AppendByte(uint8(cValue + 51))
+				} else {
+					return core.ErrFormat
+				}
+			}
+		}
+
+		if bits.Available() <= 0 {
+			return nil
+		}
+	}
+}
+
+func parseTwoBytes(firstByte, secondByte int, result []int) {
+	fullBitValue := (firstByte << 8) + secondByte - 1
+	temp := fullBitValue / 1600
+	result[0] = temp
+	fullBitValue -= temp * 1600
+	temp = fullBitValue / 40
+	result[1] = temp
+	result[2] = fullBitValue - temp*40
+}
+
+// decodeEdifactSegment decodes codewords in EDIFACT encodation, see ISO
+// 16022:2006, 5.2.8 and Annex C Table C.3.
+func decodeEdifactSegment(bits *common.BitSource, result *common.ECIStringBuilder) error {
+	for {
+		// If there is only two or less bytes left then it will be encoded as ASCII
+		if bits.Available() <= 16 {
+			return nil
+		}
+
+		for i := 0; i < 4; i++ {
+			edifactValue, err := bits.ReadBits(6)
+			if err != nil {
+				return err
+			}
+
+			// Check for the unlatch character
+			if edifactValue == 0x1F { // 011111
+				// Read rest of byte, which should be 0, and stop
+				bitsLeft := 8 - bits.GetBitOffset()
+				if bitsLeft != 8 {
+					if _, err := bits.ReadBits(bitsLeft); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			if (edifactValue & 0x20) == 0 { // no 1 in the leading (6th) bit
+				edifactValue |= 0x40 // Add a leading 01 to the 6 bit binary value
+			}
+			result.AppendByte(uint8(edifactValue))
+		}
+
+		if bits.Available() <= 0 {
+			return nil
+		}
+	}
+}
+
+// decodeBase256Segment decodes codewords in Base 256 encodation, see ISO
+// 16022:2006, 5.2.9 and Annex B, B.2.
+// It returns byteSegments with the decoded bytes appended.
+func decodeBase256Segment(bits *common.BitSource, result *common.ECIStringBuilder, byteSegments [][]uint8) ([][]uint8, error) {
+	// Figure out how long the Base 256 Segment is.
+	codewordPosition := 1 + bits.GetByteOffset() // position is 1-indexed
+	readUnrandomized := func() (int, error) {
+		value, err := bits.ReadBits(8)
+		if err != nil {
+			return 0, err
+		}
+		value = unrandomize255State(value, codewordPosition)
+		codewordPosition++
+		return value, nil
+	}
+
+	d1, err := readUnrandomized()
+	if err != nil {
+		return nil, err
+	}
+	var count int
+	if d1 == 0 { // Read the remainder of the symbol
+		count = bits.Available() / 8
+	} else if d1 < 250 {
+		count = d1
+	} else {
+		d2, err := readUnrandomized()
+		if err != nil {
+			return nil, err
+		}
+		count = 250*(d1-249) + d2
+	}
+
+	// We're seeing negative counts from some encoders
+	if count < 0 {
+		return nil, core.ErrFormat
+	}
+
+	bytes := make([]uint8, count)
+	for i := 0; i < count; i++ {
+		// Have seen this particular error in the wild, such as at
+		// http://www.bcgen.com/demo/IDAutomationStreamingDataMatrix.aspx?MODE=3&D=Fred&PFMT=3&PT=F&X=0.3&O=0&LM=0.2
+		if bits.Available() < 8 {
+			return nil, core.ErrFormat
+		}
+		value, err := readUnrandomized()
+		if err != nil {
+			return nil, err
+		}
+		bytes[i] = uint8(value)
+	}
+	result.AppendBytes(bytes)
+
+	return append(byteSegments, bytes), nil
+}
+
+// decodeECISegment decodes an ECI designator, see ISO 16022:2007, 5.4.1.
+func decodeECISegment(bits *common.BitSource, result *common.ECIStringBuilder) error {
+	if bits.Available() < 8 {
+		return core.ErrFormat
+	}
+	c1, err := bits.ReadBits(8)
+	if err != nil {
+		return err
+	}
+	value := c1 - 1
+	if c1 > 127 {
+		if bits.Available() < 8 {
+			return core.ErrFormat
+		}
+		c2, err := bits.ReadBits(8)
+		if err != nil {
+			return err
+		}
+		if c1 <= 191 {
+			value = (c1-128)*254 + (c2 - 1) + 127
+		} else {
+			if bits.Available() < 8 {
+				return core.ErrFormat
+			}
+			c3, err := bits.ReadBits(8)
+			if err != nil {
+				return err
+			}
+			value = (c1-192)*64516 + (c2-1)*254 + (c3 - 1) + 16383
+		}
+	}
+	if err := result.AppendECI(value); err != nil {
+		return core.ErrFormat
+	}
+	return nil
+}
+
+// unrandomize255State reverses the 255-state randomising algorithm, see
+// ISO 16022:2006, Annex B, B.2.
+func unrandomize255State(randomizedBase256Codeword, base256CodewordPosition int) int {
+	pseudoRandomNumber := ((149 * base256CodewordPosition) % 255) + 1
+	tempVariable := randomizedBase256Codeword - pseudoRandomNumber
+	if tempVariable >= 0 {
+		return tempVariable
+	}
+	return tempVariable + 256
+}
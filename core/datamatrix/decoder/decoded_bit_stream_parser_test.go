@@ -0,0 +1,89 @@
+/*
+ * Copyright 2008 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+func assertDecodes(t *testing.T, bytes []uint8, expected string) {
+	result, err := decodeBitStream(bytes)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, expected, result.GetText(), "decoded "+result.GetText()+" instead of "+expected)
+}
+
+func TestDecodedBitStreamParser_AsciiStandardDecode(t *testing.T) {
+	// ASCII characters 0-127 are encoded as the value + 1
+	assertDecodes(t, []uint8{'a' + 1, 'b' + 1, 'c' + 1, 'A' + 1, 'B' + 1, 'C' + 1}, "abcABC")
+}
+
+func TestDecodedBitStreamParser_AsciiDoubleDigitDecode(t *testing.T) {
+	// ASCII double digit (00 - 99) Numeric Value + 130
+	assertDecodes(t, []uint8{130, 130 + 1, 130 + 98, 130 + 99}, "00019899")
+}
+
+func TestDecodedBitStreamParser_UpperShift(t *testing.T) {
+	assertDecodes(t, []uint8{235, 0x69 + 1}, "é")
+}
+
+func TestDecodedBitStreamParser_C40(t *testing.T) {
+	// "AIM" is (1600 * 14) + (40 * 22) + 26 + 1 = 23307
+	assertDecodes(t, []uint8{230, 91, 11, 254, 'a' + 1}, "AIMa")
+}
+
+func TestDecodedBitStreamParser_AnsiX12(t *testing.T) {
+	// "ABC" is (1600 * 14) + (40 * 15) + 16 + 1 = 23017
+	assertDecodes(t, []uint8{238, 89, 233}, "ABC")
+}
+
+func TestDecodedBitStreamParser_Edifact(t *testing.T) {
+	// "ABCD" as four 6 bit values, followed by the unlatch value
+	assertDecodes(t, []uint8{240, 0x04, 0x20, 0xC4, 0x7C, 'a' + 1, 'b' + 1}, "ABCDab")
+}
+
+func TestDecodedBitStreamParser_Base256(t *testing.T) {
+	result, err := decodeBitStream([]uint8{231, 47, 34, 185, 79})
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "abc", result.GetText(), "base 256 segment decoded incorrectly")
+	internal.AssertEquals(t, 1, len(result.GetByteSegments()), "base 256 segment was not recorded")
+}
+
+func TestDecodedBitStreamParser_Macro05(t *testing.T) {
+	assertDecodes(t, []uint8{236, 'A' + 1}, "[)>\x1E05\x1DA\x1E\x04")
+}
+
+func TestDecodedBitStreamParser_FNC1(t *testing.T) {
+	result, err := decodeBitStream([]uint8{232, 130 + 1, 130 + 23})
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "\x1D0123", result.GetText(), "FNC1 was not translated to GS")
+	internal.AssertEquals(t, 2, result.GetSymbologyModifier(), "FNC1 in first position should give modifier 2")
+}
+
+func TestDecodedBitStreamParser_ECI(t *testing.T) {
+	// ECI 26 (UTF-8), followed by the two bytes of U+00E9 via upper shift
+	result, err := decodeBitStream([]uint8{241, 27, 235, 0x43 + 1, 235, 0x29 + 1})
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "é", result.GetText(), "UTF-8 ECI was not honoured")
+	internal.AssertEquals(t, 4, result.GetSymbologyModifier(), "ECI should give modifier 4")
+}
+
+func TestDecodedBitStreamParser_Invalid(t *testing.T) {
+	_, err := decodeBitStream([]uint8{0})
+	internal.AssertFailure(t, err, "codeword 0 should not decode")
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/common/reedsolomon"
+)
+
+// Decoder is the main type which implements Data Matrix Code decoding --
+// as opposed to locating and extracting the Data Matrix Code from an
+// image.
+type Decoder struct {
+	rsDecoder *reedsolomon.ReedSolomonDecoder
+}
+
+func NewDecoder() *Decoder {
+	return &Decoder{reedsolomon.NewReedSolomonDecoder(reedsolomon.DataMatrixField256)}
+}
+
+// DecodeBoolMatrix is a convenience method that can decode a Data Matrix
+// Code represented as a 2D slice of booleans, where true means a black
+// module.
+// It returns the text and bytes encoded within the Data Matrix Code, or
+// core.ErrFormat if the symbol cannot be decoded, or core.ErrChecksum if
+// error correction fails.
+func (d *Decoder) DecodeBoolMatrix(image [][]bool) (*common.DecoderResult, error) {
+	bits, err := common.ParseToBitMatrix(image)
+	if err != nil {
+		return nil, core.ErrFormat
+	}
+	return d.Decode(bits)
+}
+
+// Decode decodes a Data Matrix Code represented as a BitMatrix, where a
+// set bit is a black module. bits should contain exactly the modules of
+// the symbol, including its finder and timing patterns.
+// It returns the text and bytes encoded within the Data Matrix Code, or
+// core.ErrFormat if the symbol cannot be decoded, or core.ErrChecksum if
+// error correction fails.
+func (d *Decoder) Decode(bits *common.BitMatrix) (*common.DecoderResult, error) {
+	// Construct a parser and read version, error-correction level
+	parser, err := newBitMatrixParser(bits)
+	if err != nil {
+		return nil, err
+	}
+	version := parser.getVersion()
+
+	// Read the codewords
+	codewords, err := parser.readCodewords()
+	if err != nil {
+		return nil, err
+	}
+	// Separate into data blocks
+	dataBlocks, err := getDataBlocks(codewords, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Count total number of data bytes
+	totalBytes := 0
+	for _, db := range dataBlocks {
+		totalBytes += db.numDataCodewords
+	}
+	resultBytes := make([]uint8, totalBytes)
+
+	errorsCorrected := 0
+	dataBlocksCount := len(dataBlocks)
+	// Error-correct and copy data blocks together into a stream of bytes
+	for j, dataBlock := range dataBlocks {
+		codewordBytes := dataBlock.codewords
+		numDataCodewords := dataBlock.numDataCodewords
+		corrected, err := d.correctErrors(codewordBytes, numDataCodewords)
+		if err != nil {
+			return nil, err
+		}
+		errorsCorrected += corrected
+		for i := 0; i < numDataCodewords; i++ {
+			// De-interlace data blocks.
+			resultBytes[i*dataBlocksCount+j] = codewordBytes[i]
+		}
+	}
+
+	// Decode the contents of that stream of bytes
+	result, err := decodeBitStream(resultBytes)
+	if err != nil {
+		return nil, err
+	}
+	result.SetErrorsCorrected(errorsCorrected)
+	return result, nil
+}
+
+// correctErrors corrects errors in-place in codewordBytes, using the
+// Reed-Solomon error correction codewords which follow its first
+// numDataCodewords data codewords.
+// It returns the number of errors corrected, or core.ErrChecksum if error
+// correction fails.
+func (d *Decoder) correctErrors(codewordBytes []uint8, numDataCodewords int) (int, error) {
+	numCodewords := len(codewordBytes)
+	// First read into an array of ints
+	codewordsInts := make([]int, numCodewords)
+	for i := 0; i < numCodewords; i++ {
+		codewordsInts[i] = int(codewordBytes[i])
+	}
+	errorsCorrected, err := d.rsDecoder.Decode(codewordsInts, len(codewordBytes)-numDataCodewords)
+	if err != nil {
+		return 0, core.ErrChecksum
+	}
+	// Copy back into array of bytes -- only need to worry about the bytes that were data
+	// We don't care about errors in the error-correction codewords
+	for i := 0; i < numDataCodewords; i++ {
+		codewordBytes[i] = uint8(codewordsInts[i])
+	}
+	return errorsCorrected, nil
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core"
+)
+
+// Version encapsulates a set of error correction blocks in one symbol
+// version. Most versions will use blocks of differing sizes within one
+// version, so this encapsulates the parameters for each set of blocks. It
+// also holds the number of error-correction codewords per block since it
+// will be the same across all blocks within one version.
+//
+// The Version object encapsulates attributes about a particular size Data
+// Matrix Code.
+type Version struct {
+	versionNumber         int
+	symbolSizeRows        int
+	symbolSizeColumns     int
+	dataRegionSizeRows    int
+	dataRegionSizeColumns int
+	ecBlocks              *ECBlocks
+	totalCodewords        int
+}
+
+// ECBlocks holds the error correction parameters shared by all of the
+// blocks in a version.
+type ECBlocks struct {
+	ecCodewords int
+	ecBlocks    []ECB
+}
+
+// ECB describes a run of count blocks, each holding dataCodewords data
+// codewords.
+type ECB struct {
+	count         int
+	dataCodewords int
+}
+
+func newVersion(versionNumber, symbolSizeRows, symbolSizeColumns, dataRegionSizeRows, dataRegionSizeColumns int, ecBlocks *ECBlocks) *Version {
+	// Calculate the total number of codewords
+	total := 0
+	ecCodewords := ecBlocks.ecCodewords
+	for _, ecBlock := range ecBlocks.ecBlocks {
+		total += ecBlock.count * (ecBlock.dataCodewords + ecCodewords)
+	}
+	return &Version{
+		versionNumber,
+		symbolSizeRows,
+		symbolSizeColumns,
+		dataRegionSizeRows,
+		dataRegionSizeColumns,
+		ecBlocks,
+		total,
+	}
+}
+
+func (v *Version) GetVersionNumber() int {
+	return v.versionNumber
+}
+
+func (v *Version) GetSymbolSizeRows() int {
+	return v.symbolSizeRows
+}
+
+func (v *Version) GetSymbolSizeColumns() int {
+	return v.symbolSizeColumns
+}
+
+func (v *Version) GetDataRegionSizeRows() int {
+	return v.dataRegionSizeRows
+}
+
+func (v *Version) GetDataRegionSizeColumns() int {
+	return v.dataRegionSizeColumns
+}
+
+func (v *Version) GetTotalCodewords() int {
+	return v.totalCodewords
+}
+
+func (v *Version) GetECBlocks() *ECBlocks {
+	return v.ecBlocks
+}
+
+func (v *Version) String() string {
+	return strconv.Itoa(v.versionNumber)
+}
+
+func (ecb *ECBlocks) GetECCodewords() int {
+	return ecb.ecCodewords
+}
+
+func (ecb *ECBlocks) GetECBlocks() []ECB {
+	return ecb.ecBlocks
+}
+
+func (ecb ECB) GetCount() int {
+	return ecb.count
+}
+
+func (ecb ECB) GetDataCodewords() int {
+	return ecb.dataCodewords
+}
+
+// GetVersionForDimensions deduces the version of a Data Matrix Code from
+// its dimensions.
+// numRows and numColumns are the number of rows and columns in the
+// symbol, respectively.
+// It returns the matching Version, or core.ErrFormat if the dimensions
+// don't correspond to a valid Data Matrix size.
+func GetVersionForDimensions(numRows, numColumns int) (*Version, error) {
+	if (numRows&0x01) != 0 || (numColumns&0x01) != 0 {
+		return nil, core.ErrFormat
+	}
+
+	for _, version := range versions {
+		if version.symbolSizeRows == numRows && version.symbolSizeColumns == numColumns {
+			return version, nil
+		}
+	}
+
+	return nil, core.ErrFormat
+}
+
+// versions is the table of all ECC 200 symbol sizes, see ISO 16022:2006
+// 5.5.1 Table 7, followed by the rectangular extension (DMRE) sizes of
+// ISO/IEC 21471:2020 Table 7.
+var versions = []*Version{
+	newVersion(1, 10, 10, 8, 8, &ECBlocks{5, []ECB{{1, 3}}}),
+	newVersion(2, 12, 12, 10, 10, &ECBlocks{7, []ECB{{1, 5}}}),
+	newVersion(3, 14, 14, 12, 12, &ECBlocks{10, []ECB{{1, 8}}}),
+	newVersion(4, 16, 16, 14, 14, &ECBlocks{12, []ECB{{1, 12}}}),
+	newVersion(5, 18, 18, 16, 16, &ECBlocks{14, []ECB{{1, 18}}}),
+	newVersion(6, 20, 20, 18, 18, &ECBlocks{18, []ECB{{1, 22}}}),
+	newVersion(7, 22, 22, 20, 20, &ECBlocks{20, []ECB{{1, 30}}}),
+	newVersion(8, 24, 24, 22, 22, &ECBlocks{24, []ECB{{1, 36}}}),
+	newVersion(9, 26, 26, 24, 24, &ECBlocks{28, []ECB{{1, 44}}}),
+	newVersion(10, 32, 32, 14, 14, &ECBlocks{36, []ECB{{1, 62}}}),
+	newVersion(11, 36, 36, 16, 16, &ECBlocks{42, []ECB{{1, 86}}}),
+	newVersion(12, 40, 40, 18, 18, &ECBlocks{48, []ECB{{1, 114}}}),
+	newVersion(13, 44, 44, 20, 20, &ECBlocks{56, []ECB{{1, 144}}}),
+	newVersion(14, 48, 48, 22, 22, &ECBlocks{68, []ECB{{1, 174}}}),
+	newVersion(15, 52, 52, 24, 24, &ECBlocks{42, []ECB{{2, 102}}}),
+	newVersion(16, 64, 64, 14, 14, &ECBlocks{56, []ECB{{2, 140}}}),
+	newVersion(17, 72, 72, 16, 16, &ECBlocks{36, []ECB{{4, 92}}}),
+	newVersion(18, 80, 80, 18, 18, &ECBlocks{48, []ECB{{4, 114}}}),
+	newVersion(19, 88, 88, 20, 20, &ECBlocks{56, []ECB{{4, 144}}}),
+	newVersion(20, 96, 96, 22, 22, &ECBlocks{68, []ECB{{4, 174}}}),
+	newVersion(21, 104, 104, 24, 24, &ECBlocks{56, []ECB{{6, 136}}}),
+	newVersion(22, 120, 120, 18, 18, &ECBlocks{68, []ECB{{6, 175}}}),
+	newVersion(23, 132, 132, 20, 20, &ECBlocks{62, []ECB{{8, 163}}}),
+	newVersion(24, 144, 144, 22, 22, &ECBlocks{62, []ECB{{8, 156}, {2, 155}}}),
+	newVersion(25, 8, 18, 6, 16, &ECBlocks{7, []ECB{{1, 5}}}),
+	newVersion(26, 8, 32, 6, 14, &ECBlocks{11, []ECB{{1, 10}}}),
+	newVersion(27, 12, 26, 10, 24, &ECBlocks{14, []ECB{{1, 16}}}),
+	newVersion(28, 12, 36, 10, 16, &ECBlocks{18, []ECB{{1, 22}}}),
+	newVersion(29, 16, 36, 14, 16, &ECBlocks{24, []ECB{{1, 32}}}),
+	newVersion(30, 16, 48, 14, 22, &ECBlocks{28, []ECB{{1, 49}}}),
+
+	// DMRE
+	newVersion(31, 8, 48, 6, 22, &ECBlocks{15, []ECB{{1, 18}}}),
+	newVersion(32, 8, 64, 6, 14, &ECBlocks{18, []ECB{{1, 24}}}),
+	newVersion(33, 8, 80, 6, 18, &ECBlocks{22, []ECB{{1, 32}}}),
+	newVersion(34, 8, 96, 6, 22, &ECBlocks{28, []ECB{{1, 38}}}),
+	newVersion(35, 8, 120, 6, 18, &ECBlocks{32, []ECB{{1, 49}}}),
+	newVersion(36, 8, 144, 6, 22, &ECBlocks{36, []ECB{{1, 63}}}),
+	newVersion(37, 12, 64, 10, 14, &ECBlocks{27, []ECB{{1, 43}}}),
+	newVersion(38, 12, 88, 10, 20, &ECBlocks{36, []ECB{{1, 64}}}),
+	newVersion(39, 16, 64, 14, 14, &ECBlocks{36, []ECB{{1, 62}}}),
+	newVersion(40, 20, 36, 18, 16, &ECBlocks{28, []ECB{{1, 44}}}),
+	newVersion(41, 20, 44, 18, 20, &ECBlocks{34, []ECB{{1, 56}}}),
+	newVersion(42, 20, 64, 18, 14, &ECBlocks{42, []ECB{{1, 84}}}),
+	newVersion(43, 22, 48, 20, 22, &ECBlocks{38, []ECB{{1, 72}}}),
+	newVersion(44, 24, 48, 22, 22, &ECBlocks{41, []ECB{{1, 80}}}),
+	newVersion(45, 24, 64, 22, 14, &ECBlocks{46, []ECB{{1, 108}}}),
+	newVersion(46, 26, 40, 24, 18, &ECBlocks{38, []ECB{{1, 70}}}),
+	newVersion(47, 26, 48, 24, 22, &ECBlocks{42, []ECB{{1, 90}}}),
+	newVersion(48, 26, 64, 24, 14, &ECBlocks{50, []ECB{{1, 118}}}),
+}
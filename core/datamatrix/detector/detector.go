@@ -0,0 +1,375 @@
+/*
+ * Copyright 2008 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector
+
+import (
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/common/detector"
+)
+
+// Detector encapsulates logic that can detect a Data Matrix Code in an
+// image, even if the Data Matrix Code is rotated or skewed, or partially
+// obscured.
+type Detector struct {
+	image             *common.BitMatrix
+	rectangleDetector *detector.WhiteRectangleDetector
+}
+
+// NewDetector constructs a Detector searching image.
+// It returns core.ErrNotFound if image is too small to hold a symbol.
+func NewDetector(image *common.BitMatrix) (*Detector, error) {
+	rectangleDetector, err := detector.NewWhiteRectangleDetector(image)
+	if err != nil {
+		return nil, err
+	}
+	return &Detector{image, rectangleDetector}, nil
+}
+
+// Detect detects a Data Matrix Code in an image.
+// It returns the sampled bits along with the corners of the symbol,
+// ordered top-left, bottom-left, bottom-right then top-right, or
+// core.ErrNotFound if no Data Matrix Code can be found.
+func (d *Detector) Detect() (*detector.DetectorResult, error) {
+	cornerPoints, err := d.rectangleDetector.Detect()
+	if err != nil {
+		return nil, err
+	}
+
+	points := d.detectSolid1(cornerPoints)
+	points = d.detectSolid2(points)
+	points[3] = d.correctTopRight(points)
+	if points[3] == nil {
+		return nil, core.ErrNotFound
+	}
+	points = d.shiftToModuleCenter(points)
+
+	topLeft := points[0]
+	bottomLeft := points[1]
+	bottomRight := points[2]
+	topRight := points[3]
+
+	dimensionTop := d.transitionsBetween(topLeft, topRight) + 1
+	dimensionRight := d.transitionsBetween(bottomRight, topRight) + 1
+	if (dimensionTop & 0x01) == 1 {
+		dimensionTop++
+	}
+	if (dimensionRight & 0x01) == 1 {
+		dimensionRight++
+	}
+
+	if 4*dimensionTop < 6*dimensionRight && 4*dimensionRight < 6*dimensionTop {
+		// The matrix is square
+		if dimensionRight > dimensionTop {
+			dimensionTop = dimensionRight
+		}
+		dimensionRight = dimensionTop
+	}
+
+	bits, err := sampleGrid(d.image, topLeft, bottomLeft, bottomRight, topRight, dimensionTop, dimensionRight)
+	if err != nil {
+		return nil, err
+	}
+
+	return detector.NewDetectorResult(bits, []*core.ResultPoint{topLeft, bottomLeft, bottomRight, topRight}), nil
+}
+
+func shiftPoint(point, to *core.ResultPoint, div int) *core.ResultPoint {
+	x := (to.GetX() - point.GetX()) / float32(div+1)
+	y := (to.GetY() - point.GetY()) / float32(div+1)
+	return core.NewResultPoint(point.GetX()+x, point.GetY()+y)
+}
+
+func moveAway(point *core.ResultPoint, fromX, fromY float32) *core.ResultPoint {
+	x := point.GetX()
+	y := point.GetY()
+
+	if x < fromX {
+		x--
+	} else {
+		x++
+	}
+
+	if y < fromY {
+		y--
+	} else {
+		y++
+	}
+
+	return core.NewResultPoint(x, y)
+}
+
+// detectSolid1 detects a solid side which has minimum transition.
+func (d *Detector) detectSolid1(cornerPoints []*core.ResultPoint) []*core.ResultPoint {
+	// 0  2
+	// 1  3
+	pointA := cornerPoints[0]
+	pointB := cornerPoints[1]
+	pointC := cornerPoints[3]
+	pointD := cornerPoints[2]
+
+	trAB := d.transitionsBetween(pointA, pointB)
+	trBC := d.transitionsBetween(pointB, pointC)
+	trCD := d.transitionsBetween(pointC, pointD)
+	trDA := d.transitionsBetween(pointD, pointA)
+
+	// 0..3
+	// :  :
+	// 1--2
+	min := trAB
+	points := []*core.ResultPoint{pointD, pointA, pointB, pointC}
+	if min > trBC {
+		min = trBC
+		points = []*core.ResultPoint{pointA, pointB, pointC, pointD}
+	}
+	if min > trCD {
+		min = trCD
+		points = []*core.ResultPoint{pointB, pointC, pointD, pointA}
+	}
+	if min > trDA {
+		points = []*core.ResultPoint{pointC, pointD, pointA, pointB}
+	}
+
+	return points
+}
+
+// detectSolid2 detects a second solid side next to the first solid side.
+func (d *Detector) detectSolid2(points []*core.ResultPoint) []*core.ResultPoint {
+	// A..D
+	// :  :
+	// B--C
+	pointA := points[0]
+	pointB := points[1]
+	pointC := points[2]
+	pointD := points[3]
+
+	// Transition detection on the edge is not stable.
+	// To safely detect, shift the points to the module center.
+	tr := d.transitionsBetween(pointA, pointD)
+	pointBs := shiftPoint(pointB, pointC, (tr+1)*4)
+	pointCs := shiftPoint(pointC, pointB, (tr+1)*4)
+	trBA := d.transitionsBetween(pointBs, pointA)
+	trCD := d.transitionsBetween(pointCs, pointD)
+
+	// 0..3
+	// |  :
+	// 1--2
+	if trBA < trCD {
+		// solid sides: A-B-C
+		return []*core.ResultPoint{pointA, pointB, pointC, pointD}
+	}
+	// solid sides: B-C-D
+	return []*core.ResultPoint{pointB, pointC, pointD, pointA}
+}
+
+// correctTopRight calculates the corner position of the white top right
+// module.
+// It returns nil if neither candidate position lies within the image.
+func (d *Detector) correctTopRight(points []*core.ResultPoint) *core.ResultPoint {
+	// A..D
+	// |  :
+	// B--C
+	pointA := points[0]
+	pointB := points[1]
+	pointC := points[2]
+	pointD := points[3]
+
+	// shift points for safe transition detection.
+	trTop := d.transitionsBetween(pointA, pointD)
+	trRight := d.transitionsBetween(pointB, pointD)
+	pointAs := shiftPoint(pointA, pointB, (trRight+1)*4)
+	pointCs := shiftPoint(pointC, pointB, (trTop+1)*4)
+
+	trTop = d.transitionsBetween(pointAs, pointD)
+	trRight = d.transitionsBetween(pointCs, pointD)
+
+	candidate1 := core.NewResultPoint(
+		pointD.GetX()+(pointC.GetX()-pointB.GetX())/float32(trTop+1),
+		pointD.GetY()+(pointC.GetY()-pointB.GetY())/float32(trTop+1))
+	candidate2 := core.NewResultPoint(
+		pointD.GetX()+(pointA.GetX()-pointB.GetX())/float32(trRight+1),
+		pointD.GetY()+(pointA.GetY()-pointB.GetY())/float32(trRight+1))
+
+	if !d.isValid(candidate1) {
+		if d.isValid(candidate2) {
+			return candidate2
+		}
+		return nil
+	}
+	if !d.isValid(candidate2) {
+		return candidate1
+	}
+
+	sumc1 := d.transitionsBetween(pointAs, candidate1) + d.transitionsBetween(pointCs, candidate1)
+	sumc2 := d.transitionsBetween(pointAs, candidate2) + d.transitionsBetween(pointCs, candidate2)
+
+	if sumc1 > sumc2 {
+		return candidate1
+	}
+	return candidate2
+}
+
+// shiftToModuleCenter shifts the edge points to the module center.
+func (d *Detector) shiftToModuleCenter(points []*core.ResultPoint) []*core.ResultPoint {
+	// A..D
+	// |  :
+	// B--C
+	pointA := points[0]
+	pointB := points[1]
+	pointC := points[2]
+	pointD := points[3]
+
+	// calculate pseudo dimensions
+	dimH := d.transitionsBetween(pointA, pointD) + 1
+	dimV := d.transitionsBetween(pointC, pointD) + 1
+
+	// shift points for safe dimension detection
+	pointAs := shiftPoint(pointA, pointB, dimV*4)
+	pointCs := shiftPoint(pointC, pointB, dimH*4)
+
+	//  calculate more precise dimensions
+	dimH = d.transitionsBetween(pointAs, pointD) + 1
+	dimV = d.transitionsBetween(pointCs, pointD) + 1
+	if (dimH & 0x01) == 1 {
+		dimH++
+	}
+	if (dimV & 0x01) == 1 {
+		dimV++
+	}
+
+	// WhiteRectangleDetector returns points inside of the rectangle.
+	// I want points on the edges.
+	centerX := (pointA.GetX() + pointB.GetX() + pointC.GetX() + pointD.GetX()) / 4
+	centerY := (pointA.GetY() + pointB.GetY() + pointC.GetY() + pointD.GetY()) / 4
+	pointA = moveAway(pointA, centerX, centerY)
+	pointB = moveAway(pointB, centerX, centerY)
+	pointC = moveAway(pointC, centerX, centerY)
+	pointD = moveAway(pointD, centerX, centerY)
+
+	// shift points to the center of each modules
+	pointAs = shiftPoint(pointA, pointB, dimV*4)
+	pointAs = shiftPoint(pointAs, pointD, dimH*4)
+	pointBs := shiftPoint(pointB, pointA, dimV*4)
+	pointBs = shiftPoint(pointBs, pointC, dimH*4)
+	pointCs = shiftPoint(pointC, pointD, dimV*4)
+	pointCs = shiftPoint(pointCs, pointB, dimH*4)
+	pointDs := shiftPoint(pointD, pointC, dimV*4)
+	pointDs = shiftPoint(pointDs, pointA, dimH*4)
+
+	return []*core.ResultPoint{pointAs, pointBs, pointCs, pointDs}
+}
+
+func (d *Detector) isValid(p *core.ResultPoint) bool {
+	return p.GetX() >= 0 && p.GetX() <= float32(d.image.GetWidth()-1) &&
+		p.GetY() > 0 && p.GetY() <= float32(d.image.GetHeight()-1)
+}
+
+func sampleGrid(image *common.BitMatrix, topLeft, bottomLeft, bottomRight, topRight *core.ResultPoint, dimensionX, dimensionY int) (*common.BitMatrix, error) {
+	sampler := common.GetGridSamplerInstance()
+
+	bits, err := sampler.SampleGrid(image,
+		dimensionX,
+		dimensionY,
+		0.5,
+		0.5,
+		float32(dimensionX)-0.5,
+		0.5,
+		float32(dimensionX)-0.5,
+		float32(dimensionY)-0.5,
+		0.5,
+		float32(dimensionY)-0.5,
+		topLeft.GetX(),
+		topLeft.GetY(),
+		topRight.GetX(),
+		topRight.GetY(),
+		bottomRight.GetX(),
+		bottomRight.GetY(),
+		bottomLeft.GetX(),
+		bottomLeft.GetY())
+	if err != nil {
+		return nil, core.ErrNotFound
+	}
+	return bits, nil
+}
+
+// transitionsBetween counts the number of black/white transitions between
+// two points, using something like Bresenham's algorithm. Pixels outside
+// of the image are treated as white.
+func (d *Detector) transitionsBetween(from, to *core.ResultPoint) int {
+	// See QR Code Detector, sizeOfBlackWhiteBlackRun()
+	fromX := int(from.GetX())
+	fromY := int(from.GetY())
+	toX := int(to.GetX())
+	toY := int(to.GetY())
+	if maxY := int(d.image.GetHeight()) - 1; toY > maxY {
+		toY = maxY
+	}
+
+	steep := abs(toY-fromY) > abs(toX-fromX)
+	if steep {
+		fromX, fromY = fromY, fromX
+		toX, toY = toY, toX
+	}
+
+	dx := abs(toX - fromX)
+	dy := abs(toY - fromY)
+	errorTerm := -dx / 2
+	ystep := -1
+	if fromY < toY {
+		ystep = 1
+	}
+	xstep := -1
+	if fromX < toX {
+		xstep = 1
+	}
+	transitions := 0
+	inBlack := d.get(fromX, fromY, steep)
+	for x, y := fromX, fromY; x != toX; x += xstep {
+		isBlack := d.get(x, y, steep)
+		if isBlack != inBlack {
+			transitions++
+			inBlack = isBlack
+		}
+		errorTerm += dy
+		if errorTerm > 0 {
+			if y == toY {
+				break
+			}
+			y += ystep
+			errorTerm -= dx
+		}
+	}
+	return transitions
+}
+
+// get returns the pixel at (x, y), or at (y, x) if steep is set.
+func (d *Detector) get(x, y int, steep bool) bool {
+	if steep {
+		x, y = y, x
+	}
+	if x < 0 || y < 0 || x >= int(d.image.GetWidth()) || y >= int(d.image.GetHeight()) {
+		return false
+	}
+	return d.image.Get(uint32(x), uint32(y))
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
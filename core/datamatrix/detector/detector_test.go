@@ -0,0 +1,171 @@
+/*
+ * Copyright 2008 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/datamatrix/detector"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+// buildSymbol lays out a symbol with the Data Matrix finder and timing
+// patterns around a random data region.
+func buildSymbol(t *testing.T, width, height int, random *rand.Rand) *common.BitMatrix {
+	symbol, err := common.NewBitMatrix(uint32(width), uint32(height))
+	internal.AssertSuccess(t, err)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var black bool
+			switch {
+			case x == 0 || y == height-1:
+				black = true
+			case y == 0:
+				black = x%2 == 0
+			case x == width-1:
+				black = y%2 == 1
+			default:
+				black = random.Intn(2) == 1
+			}
+			if black {
+				symbol.Set(uint32(x), uint32(y))
+			}
+		}
+	}
+	return symbol
+}
+
+// render draws symbol with the given module size and quiet zone, rotated
+// clockwise by quarterTurns quarter turns.
+func render(t *testing.T, symbol *common.BitMatrix, moduleSize, quietZone, quarterTurns int) *common.BitMatrix {
+	width := int(symbol.GetWidth())
+	height := int(symbol.GetHeight())
+	rotatedWidth, rotatedHeight := width, height
+	if quarterTurns%2 == 1 {
+		rotatedWidth, rotatedHeight = height, width
+	}
+	image, err := common.NewBitMatrix(uint32(rotatedWidth*moduleSize+2*quietZone), uint32(rotatedHeight*moduleSize+2*quietZone))
+	internal.AssertSuccess(t, err)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if !symbol.Get(uint32(x), uint32(y)) {
+				continue
+			}
+			rx, ry := x, y
+			for i := 0; i < quarterTurns; i++ {
+				// (x, y) -> (h-1-y, x) within the current orientation
+				h := height
+				if i%2 == 1 {
+					h = width
+				}
+				rx, ry = h-1-ry, rx
+			}
+			err := image.SetRegion(uint32(quietZone+rx*moduleSize), uint32(quietZone+ry*moduleSize), uint32(moduleSize), uint32(moduleSize))
+			internal.AssertSuccess(t, err)
+		}
+	}
+	return image
+}
+
+// renderRotated draws symbol with the given module size, rotated by angle
+// radians about the center of an image of imageSize pixels square.
+func renderRotated(t *testing.T, symbol *common.BitMatrix, moduleSize float64, angle float64, imageSize int) *common.BitMatrix {
+	width := float64(symbol.GetWidth())
+	height := float64(symbol.GetHeight())
+	center := float64(imageSize) / 2
+	corner := func(x, y float64) (float32, float32) {
+		x = (x - width/2) * moduleSize
+		y = (y - height/2) * moduleSize
+		return float32(center + x*math.Cos(angle) - y*math.Sin(angle)), float32(center + x*math.Sin(angle) + y*math.Cos(angle))
+	}
+	x0, y0 := corner(0, 0)
+	x1, y1 := corner(width, 0)
+	x2, y2 := corner(width, height)
+	x3, y3 := corner(0, height)
+	transform := common.QuadrilateralToQuadrilateral(
+		x0, y0, x1, y1, x2, y2, x3, y3,
+		0, 0, float32(width), 0, float32(width), float32(height), 0, float32(height))
+
+	image, err := common.NewBitMatrixFromDimension(uint32(imageSize))
+	internal.AssertSuccess(t, err)
+	point := make([]float32, 2)
+	for y := 0; y < imageSize; y++ {
+		for x := 0; x < imageSize; x++ {
+			point[0] = float32(x) + 0.5
+			point[1] = float32(y) + 0.5
+			transform.TransformPoints(point)
+			if point[0] < 0 || point[1] < 0 || point[0] >= float32(width) || point[1] >= float32(height) {
+				continue
+			}
+			if symbol.Get(uint32(point[0]), uint32(point[1])) {
+				image.Set(uint32(x), uint32(y))
+			}
+		}
+	}
+	return image
+}
+
+func assertDetects(t *testing.T, width, height, moduleSize, quarterTurns int) {
+	symbol := buildSymbol(t, width, height, rand.New(rand.NewSource(int64(width*height+quarterTurns))))
+	image := render(t, symbol, moduleSize, 3*moduleSize, quarterTurns)
+
+	det, err := detector.NewDetector(image)
+	internal.AssertSuccess(t, err)
+	result, err := det.Detect()
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 4, len(result.GetPoints()), "expected four corner points")
+	internal.AssertTrue(t, symbol.Equals(result.GetBits()), "sampled bits differ from symbol:\n"+result.GetBits().String())
+}
+
+func TestDetector_Square(t *testing.T) {
+	for quarterTurns := 0; quarterTurns < 4; quarterTurns++ {
+		assertDetects(t, 14, 14, 4, quarterTurns)
+		assertDetects(t, 26, 26, 3, quarterTurns)
+	}
+}
+
+func TestDetector_Rectangular(t *testing.T) {
+	for quarterTurns := 0; quarterTurns < 4; quarterTurns++ {
+		assertDetects(t, 32, 8, 4, quarterTurns)
+		assertDetects(t, 36, 16, 3, quarterTurns)
+	}
+}
+
+func TestDetector_ArbitraryRotation(t *testing.T) {
+	for _, degrees := range []float64{10, 30, 80, 100, 150, 190, 250, 300} {
+		symbol := buildSymbol(t, 16, 16, rand.New(rand.NewSource(int64(degrees))))
+		image := renderRotated(t, symbol, 6, degrees*math.Pi/180, 200)
+
+		det, err := detector.NewDetector(image)
+		internal.AssertSuccess(t, err)
+		result, err := det.Detect()
+		internal.AssertSuccess(t, err)
+		internal.AssertTrue(t, symbol.Equals(result.GetBits()), "sampled bits differ from symbol:\n"+result.GetBits().String())
+	}
+}
+
+func TestDetector_EmptyImage(t *testing.T) {
+	image, err := common.NewBitMatrixFromDimension(50)
+	internal.AssertSuccess(t, err)
+	det, err := detector.NewDetector(image)
+	internal.AssertSuccess(t, err)
+	_, err = det.Detect()
+	internal.AssertFailure(t, err, "detected a symbol in an empty image")
+}
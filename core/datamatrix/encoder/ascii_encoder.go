@@ -0,0 +1,80 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import "errors"
+
+type asciiEncoder struct{}
+
+func (e asciiEncoder) getEncodingMode() int {
+	return asciiEncodation
+}
+
+func (e asciiEncoder) encode(context *encoderContext) error {
+	//step B
+	n := DetermineConsecutiveDigitCount(context.getMessage(), context.pos)
+	if n >= 2 {
+		codeword, err := encodeASCIIDigits(context.getMessage()[context.pos], context.getMessage()[context.pos+1])
+		if err != nil {
+			return err
+		}
+		context.writeCodeword(codeword)
+		context.pos += 2
+		return nil
+	}
+
+	c := context.getCurrentChar()
+	if context.isFNC1(c) {
+		context.writeCodeword(fnc1)
+		context.pos++
+		return nil
+	}
+	newMode := lookAheadTest(context.getMessage(), context.pos, e.getEncodingMode())
+	if newMode != e.getEncodingMode() {
+		switch newMode {
+		case base256Encodation:
+			context.writeCodeword(latchToBase256)
+		case c40Encodation:
+			context.writeCodeword(latchToC40)
+		case x12Encodation:
+			context.writeCodeword(latchToAnsiX12)
+		case textEncodation:
+			context.writeCodeword(latchToText)
+		case edifactEncodation:
+			context.writeCodeword(latchToEdifact)
+		default:
+			return errors.New("illegal mode")
+		}
+		context.signalEncoderChange(newMode)
+	} else if isExtendedASCII(c) {
+		context.writeCodeword(upperShift)
+		context.writeCodeword(c - 128 + 1)
+		context.pos++
+	} else {
+		context.writeCodeword(c + 1)
+		context.pos++
+	}
+	return nil
+}
+
+func encodeASCIIDigits(digit1, digit2 uint8) (uint8, error) {
+	if isDigit(digit1) && isDigit(digit2) {
+		num := int(digit1-48)*10 + int(digit2-48)
+		return uint8(num + 130), nil
+	}
+	return 0, errors.New("not digits: " + string([]byte{digit1, digit2}))
+}
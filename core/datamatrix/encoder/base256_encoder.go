@@ -0,0 +1,83 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+)
+
+type base256Encoder struct{}
+
+func (e base256Encoder) getEncodingMode() int {
+	return base256Encodation
+}
+
+func (e base256Encoder) encode(context *encoderContext) error {
+	var buffer bytes.Buffer
+	buffer.WriteByte(0) //Initialize length field
+	for context.hasMoreCharacters() {
+		c := context.getCurrentChar()
+		buffer.WriteByte(c)
+
+		context.pos++
+
+		if context.hasMoreCharacters() && context.isFNC1(context.getCurrentChar()) {
+			// FNC1 can't be expressed in Base 256, leave it to ASCII
+			context.signalEncoderChange(asciiEncodation)
+			break
+		}
+		newMode := lookAheadTest(context.getMessage(), context.pos, e.getEncodingMode())
+		if newMode != e.getEncodingMode() {
+			// Return to ASCII encodation, which will actually handle latch to new mode
+			context.signalEncoderChange(asciiEncodation)
+			break
+		}
+	}
+	data := buffer.Bytes()
+	dataCount := len(data) - 1
+	lengthFieldSize := 1
+	currentSize := context.getCodewordCount() + dataCount + lengthFieldSize
+	if err := context.updateSymbolInfoForLength(currentSize); err != nil {
+		return err
+	}
+	mustPad := (context.getSymbolInfo().GetDataCapacity() - currentSize) > 0
+	if context.hasMoreCharacters() || mustPad {
+		if dataCount <= 249 {
+			data[0] = uint8(dataCount)
+		} else if dataCount <= 1555 {
+			data[0] = uint8((dataCount / 250) + 249)
+			data = append([]uint8{data[0], uint8(dataCount % 250)}, data[1:]...)
+		} else {
+			return errors.New("Message length not in valid ranges: " + strconv.Itoa(dataCount))
+		}
+	}
+	for _, c := range data {
+		context.writeCodeword(randomize255State(c, context.getCodewordCount()+1))
+	}
+	return nil
+}
+
+func randomize255State(ch uint8, codewordPosition int) uint8 {
+	pseudoRandom := ((149 * codewordPosition) % 255) + 1
+	tempVariable := int(ch) + pseudoRandom
+	if tempVariable <= 255 {
+		return uint8(tempVariable)
+	}
+	return uint8(tempVariable - 256)
+}
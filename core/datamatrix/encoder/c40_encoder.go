@@ -0,0 +1,194 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"bytes"
+	"errors"
+)
+
+// c40Encoder implements the C40 encodation, and, with a different
+// character set, the Text encodation.
+type c40Encoder struct {
+	encodingMode int
+	encodeChar   func(c uint8, sb *bytes.Buffer) int
+}
+
+func newC40Encoder() *c40Encoder {
+	return &c40Encoder{c40Encodation, encodeC40Char}
+}
+
+func (e *c40Encoder) getEncodingMode() int {
+	return e.encodingMode
+}
+
+func (e *c40Encoder) encode(context *encoderContext) error {
+	//step C
+	var buffer bytes.Buffer
+	for context.hasMoreCharacters() {
+		c := context.getCurrentChar()
+		context.pos++
+
+		lastCharSize := e.encodeCharInContext(context, c, &buffer)
+
+		unwritten := (buffer.Len() / 3) * 2
+
+		curCodewordCount := context.getCodewordCount() + unwritten
+		if err := context.updateSymbolInfoForLength(curCodewordCount); err != nil {
+			return err
+		}
+		available := context.getSymbolInfo().GetDataCapacity() - curCodewordCount
+
+		if !context.hasMoreCharacters() {
+			//Avoid having a single C40 value in the last triplet
+			var removed bytes.Buffer
+			if (buffer.Len()%3) == 2 && available != 2 {
+				lastCharSize = e.backtrackOneCharacter(context, &buffer, &removed, lastCharSize)
+			}
+			for (buffer.Len()%3) == 1 && (lastCharSize > 3 || available != 1) {
+				lastCharSize = e.backtrackOneCharacter(context, &buffer, &removed, lastCharSize)
+			}
+			break
+		}
+
+		count := buffer.Len()
+		if (count % 3) == 0 {
+			newMode := lookAheadTest(context.getMessage(), context.pos, e.getEncodingMode())
+			if newMode != e.getEncodingMode() {
+				// Return to ASCII encodation, which will actually handle latch to new mode
+				context.signalEncoderChange(asciiEncodation)
+				break
+			}
+		}
+	}
+	return handleC40EOD(context, &buffer)
+}
+
+func (e *c40Encoder) backtrackOneCharacter(context *encoderContext, buffer, removed *bytes.Buffer, lastCharSize int) int {
+	count := buffer.Len()
+	buffer.Truncate(count - lastCharSize)
+	context.pos--
+	c := context.getCurrentChar()
+	lastCharSize = e.encodeCharInContext(context, c, removed)
+	context.resetSymbolInfo() //Deal with possible reduction in symbol size
+	return lastCharSize
+}
+
+// encodeCharInContext encodes c like encodeChar, except that FNC1 is
+// encoded in the Shift 2 set, which holds it for both C40 and Text.
+func (e *c40Encoder) encodeCharInContext(context *encoderContext, c uint8, sb *bytes.Buffer) int {
+	if context.isFNC1(c) {
+		sb.WriteByte(1) //Shift 2 Set
+		sb.WriteByte(27)
+		return 2
+	}
+	return e.encodeChar(c, sb)
+}
+
+func writeNextTriplet(context *encoderContext, buffer *bytes.Buffer) {
+	context.writeCodewords(encodeToC40Codewords(buffer.Bytes()))
+	buffer.Next(3)
+}
+
+// handleC40EOD handles "end of data" situations
+func handleC40EOD(context *encoderContext, buffer *bytes.Buffer) error {
+	unwritten := (buffer.Len() / 3) * 2
+	rest := buffer.Len() % 3
+
+	curCodewordCount := context.getCodewordCount() + unwritten
+	if err := context.updateSymbolInfoForLength(curCodewordCount); err != nil {
+		return err
+	}
+	available := context.getSymbolInfo().GetDataCapacity() - curCodewordCount
+
+	if rest == 2 {
+		buffer.WriteByte(0) //Shift 1
+		for buffer.Len() >= 3 {
+			writeNextTriplet(context, buffer)
+		}
+		if context.hasMoreCharacters() {
+			context.writeCodeword(c40Unlatch)
+		}
+	} else if available == 1 && rest == 1 {
+		for buffer.Len() >= 3 {
+			writeNextTriplet(context, buffer)
+		}
+		if context.hasMoreCharacters() {
+			context.writeCodeword(c40Unlatch)
+		}
+		// else no unlatch
+		context.pos--
+	} else if rest == 0 {
+		for buffer.Len() >= 3 {
+			writeNextTriplet(context, buffer)
+		}
+		if available > 0 || context.hasMoreCharacters() {
+			context.writeCodeword(c40Unlatch)
+		}
+	} else {
+		return errors.New("unexpected case in C40 end of data")
+	}
+	context.signalEncoderChange(asciiEncodation)
+	return nil
+}
+
+func encodeC40Char(c uint8, sb *bytes.Buffer) int {
+	if c == ' ' {
+		sb.WriteByte(3)
+		return 1
+	}
+	if c >= '0' && c <= '9' {
+		sb.WriteByte(c - 48 + 4)
+		return 1
+	}
+	if c >= 'A' && c <= 'Z' {
+		sb.WriteByte(c - 65 + 14)
+		return 1
+	}
+	if c < ' ' {
+		sb.WriteByte(0) //Shift 1 Set
+		sb.WriteByte(c)
+		return 2
+	}
+	if c <= '/' {
+		sb.WriteByte(1) //Shift 2 Set
+		sb.WriteByte(c - 33)
+		return 2
+	}
+	if c <= '@' {
+		sb.WriteByte(1) //Shift 2 Set
+		sb.WriteByte(c - 58 + 15)
+		return 2
+	}
+	if c <= '_' {
+		sb.WriteByte(1) //Shift 2 Set
+		sb.WriteByte(c - 91 + 22)
+		return 2
+	}
+	if c <= 127 {
+		sb.WriteByte(2) //Shift 3 Set
+		sb.WriteByte(c - 96)
+		return 2
+	}
+	sb.Write([]uint8{1, 0x1e}) //Shift 2, Upper Shift
+	return 2 + encodeC40Char(c-128, sb)
+}
+
+func encodeToC40Codewords(sb []uint8) []uint8 {
+	v := (1600 * int(sb[0])) + (40 * int(sb[1])) + int(sb[2]) + 1
+	return []uint8{uint8(v / 256), uint8(v % 256)}
+}
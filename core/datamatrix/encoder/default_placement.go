@@ -0,0 +1,205 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+// DefaultPlacement is the symbol character placement algorithm of ECC200
+// symbols, as described in ISO/IEC 16022:2000(E) Annex F.
+type DefaultPlacement struct {
+	codewords []uint8
+	numrows   int
+	numcols   int
+	bits      []int8
+}
+
+// NewDefaultPlacement constructs a placement of codewords into a matrix of
+// numcols by numrows modules, which excludes the finder and timing
+// patterns.
+func NewDefaultPlacement(codewords []uint8, numcols, numrows int) *DefaultPlacement {
+	bits := make([]int8, numcols*numrows)
+	for i := range bits {
+		bits[i] = -1 //Initialize with "not set" value
+	}
+	return &DefaultPlacement{codewords, numrows, numcols, bits}
+}
+
+func (dp *DefaultPlacement) GetNumrows() int {
+	return dp.numrows
+}
+
+func (dp *DefaultPlacement) GetNumcols() int {
+	return dp.numcols
+}
+
+// GetBit reports whether the module at col, row is black.
+func (dp *DefaultPlacement) GetBit(col, row int) bool {
+	return dp.bits[row*dp.numcols+col] == 1
+}
+
+func (dp *DefaultPlacement) setBit(col, row int, bit bool) {
+	if bit {
+		dp.bits[row*dp.numcols+col] = 1
+	} else {
+		dp.bits[row*dp.numcols+col] = 0
+	}
+}
+
+func (dp *DefaultPlacement) noBit(col, row int) bool {
+	return dp.bits[row*dp.numcols+col] < 0
+}
+
+// Place lays the codewords out into the matrix.
+func (dp *DefaultPlacement) Place() {
+	pos := 0
+	row := 4
+	col := 0
+
+	for {
+		// repeatedly first check for one of the special corner cases, then...
+		if (row == dp.numrows) && (col == 0) {
+			dp.corner1(pos)
+			pos++
+		}
+		if (row == dp.numrows-2) && (col == 0) && ((dp.numcols % 4) != 0) {
+			dp.corner2(pos)
+			pos++
+		}
+		if (row == dp.numrows-2) && (col == 0) && (dp.numcols%8 == 4) {
+			dp.corner3(pos)
+			pos++
+		}
+		if (row == dp.numrows+4) && (col == 2) && ((dp.numcols % 8) == 0) {
+			dp.corner4(pos)
+			pos++
+		}
+		// sweep upward diagonally, inserting successive characters...
+		for {
+			if (row < dp.numrows) && (col >= 0) && dp.noBit(col, row) {
+				dp.utah(row, col, pos)
+				pos++
+			}
+			row -= 2
+			col += 2
+			if !(row >= 0 && (col < dp.numcols)) {
+				break
+			}
+		}
+		row++
+		col += 3
+
+		// and then sweep downward diagonally, inserting successive characters, ...
+		for {
+			if (row >= 0) && (col < dp.numcols) && dp.noBit(col, row) {
+				dp.utah(row, col, pos)
+				pos++
+			}
+			row += 2
+			col -= 2
+			if !((row < dp.numrows) && (col >= 0)) {
+				break
+			}
+		}
+		row += 3
+		col++
+
+		// ...until the entire array is scanned
+		if !((row < dp.numrows) || (col < dp.numcols)) {
+			break
+		}
+	}
+
+	// Lastly, if the lower right-hand corner is untouched, fill in fixed pattern
+	if dp.noBit(dp.numcols-1, dp.numrows-1) {
+		dp.setBit(dp.numcols-1, dp.numrows-1, true)
+		dp.setBit(dp.numcols-2, dp.numrows-2, true)
+	}
+}
+
+func (dp *DefaultPlacement) module(row, col, pos, bit int) {
+	if row < 0 {
+		row += dp.numrows
+		col += 4 - ((dp.numrows + 4) % 8)
+	}
+	if col < 0 {
+		col += dp.numcols
+		row += 4 - ((dp.numcols + 4) % 8)
+	}
+	// The adjustment above can overshoot the bottom of the rectangular
+	// extension sizes, wrap around again
+	if row >= dp.numrows {
+		row -= dp.numrows
+	}
+	// Note the conversion:
+	v := int(dp.codewords[pos])
+	v &= 1 << uint(8-bit)
+	dp.setBit(col, row, v != 0)
+}
+
+// utah places the 8 bits of a utah-shaped symbol character in ECC200.
+func (dp *DefaultPlacement) utah(row, col, pos int) {
+	dp.module(row-2, col-2, pos, 1)
+	dp.module(row-2, col-1, pos, 2)
+	dp.module(row-1, col-2, pos, 3)
+	dp.module(row-1, col-1, pos, 4)
+	dp.module(row-1, col, pos, 5)
+	dp.module(row, col-2, pos, 6)
+	dp.module(row, col-1, pos, 7)
+	dp.module(row, col, pos, 8)
+}
+
+func (dp *DefaultPlacement) corner1(pos int) {
+	dp.module(dp.numrows-1, 0, pos, 1)
+	dp.module(dp.numrows-1, 1, pos, 2)
+	dp.module(dp.numrows-1, 2, pos, 3)
+	dp.module(0, dp.numcols-2, pos, 4)
+	dp.module(0, dp.numcols-1, pos, 5)
+	dp.module(1, dp.numcols-1, pos, 6)
+	dp.module(2, dp.numcols-1, pos, 7)
+	dp.module(3, dp.numcols-1, pos, 8)
+}
+
+func (dp *DefaultPlacement) corner2(pos int) {
+	dp.module(dp.numrows-3, 0, pos, 1)
+	dp.module(dp.numrows-2, 0, pos, 2)
+	dp.module(dp.numrows-1, 0, pos, 3)
+	dp.module(0, dp.numcols-4, pos, 4)
+	dp.module(0, dp.numcols-3, pos, 5)
+	dp.module(0, dp.numcols-2, pos, 6)
+	dp.module(0, dp.numcols-1, pos, 7)
+	dp.module(1, dp.numcols-1, pos, 8)
+}
+
+func (dp *DefaultPlacement) corner3(pos int) {
+	dp.module(dp.numrows-3, 0, pos, 1)
+	dp.module(dp.numrows-2, 0, pos, 2)
+	dp.module(dp.numrows-1, 0, pos, 3)
+	dp.module(0, dp.numcols-2, pos, 4)
+	dp.module(0, dp.numcols-1, pos, 5)
+	dp.module(1, dp.numcols-1, pos, 6)
+	dp.module(2, dp.numcols-1, pos, 7)
+	dp.module(3, dp.numcols-1, pos, 8)
+}
+
+func (dp *DefaultPlacement) corner4(pos int) {
+	dp.module(dp.numrows-1, 0, pos, 1)
+	dp.module(dp.numrows-1, dp.numcols-1, pos, 2)
+	dp.module(0, dp.numcols-3, pos, 3)
+	dp.module(0, dp.numcols-2, pos, 4)
+	dp.module(0, dp.numcols-1, pos, 5)
+	dp.module(1, dp.numcols-3, pos, 6)
+	dp.module(1, dp.numcols-2, pos, 7)
+	dp.module(1, dp.numcols-1, pos, 8)
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"bytes"
+	"errors"
+)
+
+type edifactEncoder struct{}
+
+func (e edifactEncoder) getEncodingMode() int {
+	return edifactEncodation
+}
+
+func (e edifactEncoder) encode(context *encoderContext) error {
+	//step F
+	var buffer bytes.Buffer
+	for context.hasMoreCharacters() {
+		c := context.getCurrentChar()
+		if err := encodeEdifactChar(c, &buffer); err != nil {
+			return err
+		}
+		context.pos++
+
+		count := buffer.Len()
+		if count >= 4 {
+			codewords, err := encodeToEdifactCodewords(buffer.Bytes())
+			if err != nil {
+				return err
+			}
+			context.writeCodewords(codewords)
+			buffer.Next(4)
+
+			newMode := lookAheadTest(context.getMessage(), context.pos, e.getEncodingMode())
+			if newMode != e.getEncodingMode() {
+				// Return to ASCII encodation, which will actually handle latch to new mode
+				context.signalEncoderChange(asciiEncodation)
+				break
+			}
+		}
+	}
+	buffer.WriteByte(31) //Unlatch
+	err := handleEdifactEOD(context, buffer.Bytes())
+	context.signalEncoderChange(asciiEncodation)
+	return err
+}
+
+// handleEdifactEOD handles "end of data" situations, where buffer holds
+// the unwritten characters followed by the unlatch.
+func handleEdifactEOD(context *encoderContext, buffer []uint8) error {
+	count := len(buffer)
+	if count == 0 {
+		return nil //Already finished
+	}
+	if count == 1 {
+		//Only an unlatch at the end
+		if err := context.updateSymbolInfo(); err != nil {
+			return err
+		}
+		available := context.getSymbolInfo().GetDataCapacity() - context.getCodewordCount()
+		remaining := context.getRemainingCharacters()
+		// The following two lines are a hack inspired by the 'fix' from https://sourceforge.net/p/barcode4j/svn/221/
+		if remaining > available {
+			if err := context.updateSymbolInfoForLength(context.getCodewordCount() + 1); err != nil {
+				return err
+			}
+			available = context.getSymbolInfo().GetDataCapacity() - context.getCodewordCount()
+		}
+		if remaining <= available && available <= 2 {
+			return nil //No unlatch
+		}
+	}
+
+	if count > 4 {
+		return errors.New("count must not exceed 4")
+	}
+	restChars := count - 1
+	encoded, err := encodeToEdifactCodewords(buffer)
+	if err != nil {
+		return err
+	}
+	endOfSymbolReached := !context.hasMoreCharacters()
+	restInAscii := endOfSymbolReached && restChars <= 2
+
+	if restChars <= 2 {
+		if err := context.updateSymbolInfoForLength(context.getCodewordCount() + restChars); err != nil {
+			return err
+		}
+		available := context.getSymbolInfo().GetDataCapacity() - context.getCodewordCount()
+		if available >= 3 {
+			restInAscii = false
+			if err := context.updateSymbolInfoForLength(context.getCodewordCount() + len(encoded)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if restInAscii {
+		context.resetSymbolInfo()
+		context.pos -= restChars
+	} else {
+		context.writeCodewords(encoded)
+	}
+	return nil
+}
+
+func encodeEdifactChar(c uint8, sb *bytes.Buffer) error {
+	if c >= ' ' && c <= '?' {
+		sb.WriteByte(c)
+	} else if c >= '@' && c <= '^' {
+		sb.WriteByte(c - 64)
+	} else {
+		return illegalCharacter(c)
+	}
+	return nil
+}
+
+func encodeToEdifactCodewords(sb []uint8) ([]uint8, error) {
+	length := len(sb)
+	if length == 0 {
+		return nil, errors.New("buffer must not be empty")
+	}
+	var c1, c2, c3, c4 int
+	c1 = int(sb[0])
+	if length >= 2 {
+		c2 = int(sb[1])
+	}
+	if length >= 3 {
+		c3 = int(sb[2])
+	}
+	if length >= 4 {
+		c4 = int(sb[3])
+	}
+
+	v := (c1 << 18) + (c2 << 12) + (c3 << 6) + c4
+	cw1 := uint8((v >> 16) & 255)
+	cw2 := uint8((v >> 8) & 255)
+	cw3 := uint8(v & 255)
+	res := []uint8{cw1}
+	if length >= 2 {
+		res = append(res, cw2)
+	}
+	if length >= 3 {
+		res = append(res, cw3)
+	}
+	return res, nil
+}
@@ -0,0 +1,27 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+// encoder is implemented by each of the Data Matrix encodation modes.
+type encoder interface {
+	getEncodingMode() int
+
+	// encode consumes characters of the message held by context and
+	// writes their codewords, until either the message ends or the
+	// encoder signals a change of mode.
+	encode(context *encoderContext) error
+}
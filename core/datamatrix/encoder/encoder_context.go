@@ -0,0 +1,148 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+// encoderContext holds the state shared by the encoders while a message
+// is encoded: the message itself, the position reached within it, the
+// codewords written so far and the symbol they are expected to fit in.
+type encoderContext struct {
+	msg         []uint8
+	shape       SymbolShapeHint
+	minSize     *core.Dimension
+	maxSize     *core.Dimension
+	codewords   bytes.Buffer
+	pos         int
+	newEncoding int
+	symbolInfo  *SymbolInfo
+	skipAtEnd   int
+	gs1         bool
+}
+
+// newEncoderContext constructs a context for encoding msg.
+// It returns an error if msg contains characters outside of ISO-8859-1.
+func newEncoderContext(msg string) (*encoderContext, error) {
+	//From this point on Strings are not Unicode anymore!
+	msgBinary, ok := common.ISO8859_1.Encode(msg)
+	if !ok {
+		return nil, errors.New("Message contains characters outside ISO-8859-1 encoding.")
+	}
+	return &encoderContext{msg: msgBinary, shape: ForceNone, newEncoding: -1}, nil
+}
+
+func (ec *encoderContext) setSymbolShape(shape SymbolShapeHint) {
+	ec.shape = shape
+}
+
+func (ec *encoderContext) setSizeConstraints(minSize, maxSize *core.Dimension) {
+	ec.minSize = minSize
+	ec.maxSize = maxSize
+}
+
+// isFNC1 reports whether c stands for FNC1, which is only the case for
+// the GS1 group separator in a GS1 message.
+func (ec *encoderContext) isFNC1(c uint8) bool {
+	return ec.gs1 && c == common.GS1GroupSeparator
+}
+
+func (ec *encoderContext) getMessage() []uint8 {
+	return ec.msg
+}
+
+func (ec *encoderContext) setSkipAtEnd(count int) {
+	ec.skipAtEnd = count
+}
+
+func (ec *encoderContext) getCurrentChar() uint8 {
+	return ec.msg[ec.pos]
+}
+
+func (ec *encoderContext) getCodewords() []uint8 {
+	return ec.codewords.Bytes()
+}
+
+func (ec *encoderContext) writeCodewords(codewords []uint8) {
+	ec.codewords.Write(codewords)
+}
+
+func (ec *encoderContext) writeCodeword(codeword uint8) {
+	ec.codewords.WriteByte(codeword)
+}
+
+func (ec *encoderContext) getCodewordCount() int {
+	return ec.codewords.Len()
+}
+
+func (ec *encoderContext) getNewEncoding() int {
+	return ec.newEncoding
+}
+
+func (ec *encoderContext) signalEncoderChange(encoding int) {
+	ec.newEncoding = encoding
+}
+
+func (ec *encoderContext) resetEncoderSignal() {
+	ec.newEncoding = -1
+}
+
+func (ec *encoderContext) hasMoreCharacters() bool {
+	return ec.pos < ec.getTotalMessageCharCount()
+}
+
+func (ec *encoderContext) getTotalMessageCharCount() int {
+	return len(ec.msg) - ec.skipAtEnd
+}
+
+func (ec *encoderContext) getRemainingCharacters() int {
+	return ec.getTotalMessageCharCount() - ec.pos
+}
+
+func (ec *encoderContext) getSymbolInfo() *SymbolInfo {
+	return ec.symbolInfo
+}
+
+// updateSymbolInfo selects a symbol large enough for the codewords
+// written so far.
+// It returns an error if no symbol is large enough.
+func (ec *encoderContext) updateSymbolInfo() error {
+	return ec.updateSymbolInfoForLength(ec.getCodewordCount())
+}
+
+// updateSymbolInfoForLength selects a symbol large enough for len
+// codewords, if the current one is too small.
+// It returns an error if no symbol is large enough.
+func (ec *encoderContext) updateSymbolInfoForLength(len int) error {
+	if ec.symbolInfo == nil || len > ec.symbolInfo.GetDataCapacity() {
+		symbolInfo, err := LookupSymbolInfo(len, ec.shape, ec.minSize, ec.maxSize)
+		if err != nil {
+			return err
+		}
+		ec.symbolInfo = symbolInfo
+	}
+	return nil
+}
+
+func (ec *encoderContext) resetSymbolInfo() {
+	ec.symbolInfo = nil
+}
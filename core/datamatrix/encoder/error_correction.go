@@ -0,0 +1,74 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"errors"
+
+	"github.com/discesoft/zxing-go/core/common/reedsolomon"
+)
+
+// EncodeECC200 creates the ECC200 error correction for an encoded message,
+// interleaving it across the Reed-Solomon blocks of symbolInfo.
+// codewords must hold exactly as many codewords as the symbol's data
+// capacity.
+// It returns the data codewords followed by the error correction
+// codewords, or an error if codewords doesn't fill the symbol.
+func EncodeECC200(codewords []uint8, symbolInfo *SymbolInfo) ([]uint8, error) {
+	if len(codewords) != symbolInfo.GetDataCapacity() {
+		return nil, errors.New("the number of codewords does not match the selected symbol")
+	}
+	result := make([]uint8, symbolInfo.GetDataCapacity()+symbolInfo.GetErrorCodewords())
+	copy(result, codewords)
+	blockCount := symbolInfo.GetInterleavedBlockCount()
+	for block := 0; block < blockCount; block++ {
+		dataSize := symbolInfo.GetDataLengthForInterleavedBlock(block + 1)
+		errorSize := symbolInfo.GetErrorLengthForInterleavedBlock(block + 1)
+		temp := make([]uint8, 0, dataSize)
+		for d := block; d < symbolInfo.GetDataCapacity(); d += blockCount {
+			temp = append(temp, codewords[d])
+		}
+		ecc, err := createECCBlock(temp, errorSize)
+		if err != nil {
+			return nil, err
+		}
+		// The codewords are interleaved as a whole, so the error
+		// correction of each block continues on from where its data left
+		// off. This only matters for 144x144, whose blocks differ in length.
+		first := (block - symbolInfo.GetDataCapacity()%blockCount + blockCount) % blockCount
+		for pos := range ecc {
+			result[symbolInfo.GetDataCapacity()+first+pos*blockCount] = ecc[pos]
+		}
+	}
+	return result, nil
+}
+
+func createECCBlock(codewords []uint8, numECWords int) ([]uint8, error) {
+	toEncode := make([]int, len(codewords)+numECWords)
+	for i, codeword := range codewords {
+		toEncode[i] = int(codeword)
+	}
+	rsEncoder := reedsolomon.NewReedSolomonEncoder(reedsolomon.DataMatrixField256)
+	if err := rsEncoder.Encode(toEncode, numECWords); err != nil {
+		return nil, err
+	}
+	ecc := make([]uint8, numECWords)
+	for i := range ecc {
+		ecc[i] = uint8(toEncode[len(codewords)+i])
+	}
+	return ecc, nil
+}
@@ -0,0 +1,454 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/discesoft/zxing-go/core"
+)
+
+// Codewords with special meaning in Data Matrix, and the encodation modes
+// they switch between. DataMatrix ECC 200 data encodation is described in
+// ISO/IEC 16022:2000(E) Annex P.
+const (
+	// Padding character
+	pad uint8 = 129
+	// mode latch to C40 encodation mode
+	latchToC40 uint8 = 230
+	// mode latch to Base 256 encodation mode
+	latchToBase256 uint8 = 231
+	// FNC1 Codeword
+	fnc1 uint8 = 232
+	// Structured Append Codeword
+	structuredAppend uint8 = 233
+	// Reader Programming
+	readerProgramming uint8 = 234
+	// Upper Shift
+	upperShift uint8 = 235
+	// 05 Macro
+	macro05 uint8 = 236
+	// 06 Macro
+	macro06 uint8 = 237
+	// mode latch to ANSI X.12 encodation mode
+	latchToAnsiX12 uint8 = 238
+	// mode latch to Text encodation mode
+	latchToText uint8 = 239
+	// mode latch to EDIFACT encodation mode
+	latchToEdifact uint8 = 240
+	// ECI character (Extended Channel Interpretation)
+	eci uint8 = 241
+
+	// Unlatch from C40 encodation
+	c40Unlatch uint8 = 254
+	// Unlatch from X12 encodation
+	x12Unlatch uint8 = 254
+
+	asciiEncodation   = 0
+	c40Encodation     = 1
+	textEncodation    = 2
+	x12Encodation     = 3
+	edifactEncodation = 4
+	base256Encodation = 5
+)
+
+const (
+	// 05 Macro header
+	macro05Header = "[)>\u001E05\u001D"
+	// 06 Macro header
+	macro06Header = "[)>\u001E06\u001D"
+	// Macro trailer
+	macroTrailer = "\u001E\u0004"
+)
+
+func randomize253State(codewordPosition int) uint8 {
+	pseudoRandom := ((149 * codewordPosition) % 253) + 1
+	tempVariable := int(pad) + pseudoRandom
+	if tempVariable <= 254 {
+		return uint8(tempVariable)
+	}
+	return uint8(tempVariable - 254)
+}
+
+// EncodeHighLevel performs message encoding of a Data Matrix message using
+// the algorithm described in annex P of ISO/IEC 16022:2000(E).
+// It returns the encoded message as codewords, or an error if msg cannot
+// be encoded.
+func EncodeHighLevel(msg string) ([]uint8, error) {
+	return EncodeHighLevelWithConstraints(msg, ForceNone, nil, nil)
+}
+
+// EncodeHighLevelWithConstraints performs message encoding of a Data
+// Matrix message using the algorithm described in annex P of ISO/IEC
+// 16022:2000(E), choosing the symbol it is padded to from those of the
+// given shape and, if they are not nil, no smaller than minSize and no
+// larger than maxSize.
+// It returns the encoded message as codewords, or an error if msg cannot
+// be encoded.
+func EncodeHighLevelWithConstraints(msg string, shape SymbolShapeHint, minSize, maxSize *core.Dimension) ([]uint8, error) {
+	return encodeHighLevel(msg, shape, minSize, maxSize, false)
+}
+
+// EncodeHighLevelGS1 performs message encoding of a GS1 Data Matrix
+// message, which is marked by FNC1 in the first position. msg is a raw
+// GS1 element string, such as returned by common.FormatGS1ElementString,
+// whose group separators are encoded as FNC1. The symbol is chosen as by
+// EncodeHighLevelWithConstraints.
+// It returns the encoded message as codewords, or an error if msg cannot
+// be encoded.
+func EncodeHighLevelGS1(msg string, shape SymbolShapeHint, minSize, maxSize *core.Dimension) ([]uint8, error) {
+	return encodeHighLevel(msg, shape, minSize, maxSize, true)
+}
+
+func encodeHighLevel(msg string, shape SymbolShapeHint, minSize, maxSize *core.Dimension, gs1 bool) ([]uint8, error) {
+	//the codewords 0..255 are encoded as Unicode characters
+	encoders := []encoder{
+		asciiEncoder{}, newC40Encoder(), newTextEncoder(),
+		x12Encoder{}, edifactEncoder{}, base256Encoder{},
+	}
+
+	context, err := newEncoderContext(msg)
+	if err != nil {
+		return nil, err
+	}
+	context.setSymbolShape(shape)
+	context.setSizeConstraints(minSize, maxSize)
+	context.gs1 = gs1
+
+	if gs1 {
+		context.writeCodeword(fnc1)
+	} else if strings.HasPrefix(msg, macro05Header) && strings.HasSuffix(msg, macroTrailer) {
+		context.writeCodeword(macro05)
+		context.setSkipAtEnd(2)
+		context.pos += len(macro05Header)
+	} else if strings.HasPrefix(msg, macro06Header) && strings.HasSuffix(msg, macroTrailer) {
+		context.writeCodeword(macro06)
+		context.setSkipAtEnd(2)
+		context.pos += len(macro06Header)
+	}
+
+	encodingMode := asciiEncodation //Default mode
+	for context.hasMoreCharacters() {
+		if err := encoders[encodingMode].encode(context); err != nil {
+			return nil, err
+		}
+		if context.getNewEncoding() >= 0 {
+			encodingMode = context.getNewEncoding()
+			context.resetEncoderSignal()
+		}
+	}
+	length := context.getCodewordCount()
+	if err := context.updateSymbolInfo(); err != nil {
+		return nil, err
+	}
+	capacity := context.getSymbolInfo().GetDataCapacity()
+	if length < capacity &&
+		encodingMode != asciiEncodation &&
+		encodingMode != base256Encodation &&
+		encodingMode != edifactEncodation {
+		context.writeCodeword(0xfe) //Unlatch (254)
+	}
+	//Padding
+	if context.getCodewordCount() < capacity {
+		context.writeCodeword(pad)
+	}
+	for context.getCodewordCount() < capacity {
+		context.writeCodeword(randomize253State(context.getCodewordCount() + 1))
+	}
+
+	return context.getCodewords(), nil
+}
+
+// lookAheadTest decides which encodation mode the characters of msg from
+// startpos onwards are best encoded in, given that they would otherwise
+// be encoded in currentMode.
+func lookAheadTest(msg []uint8, startpos, currentMode int) int {
+	newMode := lookAheadTestIntern(msg, startpos, currentMode)
+	if currentMode == x12Encodation && newMode == x12Encodation {
+		endpos := startpos + 3
+		if endpos > len(msg) {
+			endpos = len(msg)
+		}
+		for i := startpos; i < endpos; i++ {
+			if !isNativeX12(msg[i]) {
+				return asciiEncodation
+			}
+		}
+	} else if currentMode == edifactEncodation && newMode == edifactEncodation {
+		endpos := startpos + 4
+		if endpos > len(msg) {
+			endpos = len(msg)
+		}
+		for i := startpos; i < endpos; i++ {
+			if !isNativeEDIFACT(msg[i]) {
+				return asciiEncodation
+			}
+		}
+	}
+	return newMode
+}
+
+func lookAheadTestIntern(msg []uint8, startpos, currentMode int) int {
+	if startpos >= len(msg) {
+		return currentMode
+	}
+	var charCounts []float32
+	//step J
+	if currentMode == asciiEncodation {
+		charCounts = []float32{0, 1, 1, 1, 1, 1.25}
+	} else {
+		charCounts = []float32{1, 2, 2, 2, 2, 2.25}
+		charCounts[currentMode] = 0
+	}
+
+	charsProcessed := 0
+	mins := make([]int, 6)
+	intCharCounts := make([]int, 6)
+	for {
+		//step K
+		if (startpos + charsProcessed) == len(msg) {
+			min := findMinimums(charCounts, intCharCounts, math.MaxInt32, mins)
+			minCount := getMinimumCount(mins)
+
+			if intCharCounts[asciiEncodation] == min {
+				return asciiEncodation
+			}
+			if minCount == 1 {
+				if mins[base256Encodation] > 0 {
+					return base256Encodation
+				}
+				if mins[edifactEncodation] > 0 {
+					return edifactEncodation
+				}
+				if mins[textEncodation] > 0 {
+					return textEncodation
+				}
+				if mins[x12Encodation] > 0 {
+					return x12Encodation
+				}
+			}
+			return c40Encodation
+		}
+
+		c := msg[startpos+charsProcessed]
+		charsProcessed++
+
+		//step L
+		if isDigit(c) {
+			charCounts[asciiEncodation] += 0.5
+		} else if isExtendedASCII(c) {
+			charCounts[asciiEncodation] = ceil(charCounts[asciiEncodation])
+			charCounts[asciiEncodation] += 2.0
+		} else {
+			charCounts[asciiEncodation] = ceil(charCounts[asciiEncodation])
+			charCounts[asciiEncodation]++
+		}
+
+		//step M
+		if isNativeC40(c) {
+			charCounts[c40Encodation] += 2.0 / 3.0
+		} else if isExtendedASCII(c) {
+			charCounts[c40Encodation] += 8.0 / 3.0
+		} else {
+			charCounts[c40Encodation] += 4.0 / 3.0
+		}
+
+		//step N
+		if isNativeText(c) {
+			charCounts[textEncodation] += 2.0 / 3.0
+		} else if isExtendedASCII(c) {
+			charCounts[textEncodation] += 8.0 / 3.0
+		} else {
+			charCounts[textEncodation] += 4.0 / 3.0
+		}
+
+		//step O
+		if isNativeX12(c) {
+			charCounts[x12Encodation] += 2.0 / 3.0
+		} else if isExtendedASCII(c) {
+			charCounts[x12Encodation] += 13.0 / 3.0
+		} else {
+			charCounts[x12Encodation] += 10.0 / 3.0
+		}
+
+		//step P
+		if isNativeEDIFACT(c) {
+			charCounts[edifactEncodation] += 3.0 / 4.0
+		} else if isExtendedASCII(c) {
+			charCounts[edifactEncodation] += 17.0 / 4.0
+		} else {
+			charCounts[edifactEncodation] += 13.0 / 4.0
+		}
+
+		// step Q
+		charCounts[base256Encodation]++
+
+		//step R
+		if charsProcessed >= 4 {
+			findMinimums(charCounts, intCharCounts, math.MaxInt32, mins)
+
+			if intCharCounts[asciiEncodation] < min5(intCharCounts[base256Encodation],
+				intCharCounts[c40Encodation], intCharCounts[textEncodation], intCharCounts[x12Encodation],
+				intCharCounts[edifactEncodation]) {
+				return asciiEncodation
+			}
+			if intCharCounts[base256Encodation] < intCharCounts[asciiEncodation] ||
+				intCharCounts[base256Encodation]+1 < min4(intCharCounts[c40Encodation],
+					intCharCounts[textEncodation], intCharCounts[x12Encodation], intCharCounts[edifactEncodation]) {
+				return base256Encodation
+			}
+			if intCharCounts[edifactEncodation]+1 < min5(intCharCounts[base256Encodation],
+				intCharCounts[c40Encodation], intCharCounts[textEncodation], intCharCounts[x12Encodation],
+				intCharCounts[asciiEncodation]) {
+				return edifactEncodation
+			}
+			if intCharCounts[textEncodation]+1 < min5(intCharCounts[base256Encodation],
+				intCharCounts[c40Encodation], intCharCounts[edifactEncodation], intCharCounts[x12Encodation],
+				intCharCounts[asciiEncodation]) {
+				return textEncodation
+			}
+			if intCharCounts[x12Encodation]+1 < min5(intCharCounts[base256Encodation],
+				intCharCounts[c40Encodation], intCharCounts[edifactEncodation], intCharCounts[textEncodation],
+				intCharCounts[asciiEncodation]) {
+				return x12Encodation
+			}
+			if intCharCounts[c40Encodation]+1 < min4(intCharCounts[asciiEncodation],
+				intCharCounts[base256Encodation], intCharCounts[edifactEncodation], intCharCounts[textEncodation]) {
+				if intCharCounts[c40Encodation] < intCharCounts[x12Encodation] {
+					return c40Encodation
+				}
+				if intCharCounts[c40Encodation] == intCharCounts[x12Encodation] {
+					p := startpos + charsProcessed + 1
+					for p < len(msg) {
+						tc := msg[p]
+						if isX12TermSep(tc) {
+							return x12Encodation
+						}
+						if !isNativeX12(tc) {
+							break
+						}
+						p++
+					}
+					return c40Encodation
+				}
+			}
+		}
+	}
+}
+
+func ceil(f float32) float32 {
+	return float32(math.Ceil(float64(f)))
+}
+
+func min5(f1, f2, f3, f4, f5 int) int {
+	m := min4(f1, f2, f3, f4)
+	if f5 < m {
+		return f5
+	}
+	return m
+}
+
+func min4(f1, f2, f3, f4 int) int {
+	m := f1
+	for _, f := range []int{f2, f3, f4} {
+		if f < m {
+			m = f
+		}
+	}
+	return m
+}
+
+// findMinimums rounds each of charCounts up into intCharCounts, and
+// counts in mins which of them are the smallest.
+// It returns the smallest count.
+func findMinimums(charCounts []float32, intCharCounts []int, min int, mins []int) int {
+	for i := range mins {
+		mins[i] = 0
+	}
+	for i := 0; i < 6; i++ {
+		intCharCounts[i] = int(ceil(charCounts[i]))
+		current := intCharCounts[i]
+		if min > current {
+			min = current
+			for j := range mins {
+				mins[j] = 0
+			}
+		}
+		if min == current {
+			mins[i]++
+		}
+	}
+	return min
+}
+
+func getMinimumCount(mins []int) int {
+	minCount := 0
+	for i := 0; i < 6; i++ {
+		minCount += mins[i]
+	}
+	return minCount
+}
+
+func isDigit(ch uint8) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isExtendedASCII(ch uint8) bool {
+	return ch >= 128
+}
+
+func isNativeC40(ch uint8) bool {
+	return (ch == ' ') || (ch >= '0' && ch <= '9') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isNativeText(ch uint8) bool {
+	return (ch == ' ') || (ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'z')
+}
+
+func isNativeX12(ch uint8) bool {
+	return isX12TermSep(ch) || (ch == ' ') || (ch >= '0' && ch <= '9') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isX12TermSep(ch uint8) bool {
+	return (ch == '\r') || //CR
+		(ch == '*') ||
+		(ch == '>')
+}
+
+func isNativeEDIFACT(ch uint8) bool {
+	return ch >= ' ' && ch <= '^'
+}
+
+// DetermineConsecutiveDigitCount determines the number of consecutive
+// characters that are encodable using numeric compaction.
+// It returns the number of digits in msg starting at startpos.
+func DetermineConsecutiveDigitCount(msg []uint8, startpos int) int {
+	idx := startpos
+	for idx < len(msg) && isDigit(msg[idx]) {
+		idx++
+	}
+	return idx - startpos
+}
+
+func illegalCharacter(c uint8) error {
+	hex := strconv.FormatInt(int64(c), 16)
+	hex = "0000"[:4-len(hex)] + hex
+	return errors.New("Illegal character: " + string(rune(c)) + " (0x" + hex + ")")
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/datamatrix/encoder"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+func visualize(codewords []uint8) string {
+	values := make([]string, len(codewords))
+	for i, codeword := range codewords {
+		values[i] = strconv.Itoa(int(codeword))
+	}
+	return strings.Join(values, " ")
+}
+
+func assertEncodes(t *testing.T, msg, expected string) {
+	codewords, err := encoder.EncodeHighLevel(msg)
+	internal.AssertSuccess(t, err)
+	visualized := visualize(codewords)
+	internal.AssertEquals(t, expected, visualized, "encoded "+strconv.Quote(msg)+" as "+visualized+" instead of "+expected)
+}
+
+func TestHighLevelEncoder_ASCIIEncodation(t *testing.T) {
+	assertEncodes(t, "123456", "142 164 186")
+	assertEncodes(t, "123456£", "142 164 186 235 36")
+	assertEncodes(t, "30Q324343430794<OQQ", "160 82 162 173 173 173 137 224 61 80 82 82")
+}
+
+func TestHighLevelEncoder_C40EncodationBasic(t *testing.T) {
+	assertEncodes(t, "AIMAIMAIM", "230 91 11 91 11 91 11 254")
+	// "AIMAIAB" has a single trailing value, which is shifted into
+	// the last triplet with a Shift 1
+	assertEncodes(t, "AIMAIAB", "230 91 11 90 255 254 67 129")
+}
+
+func TestHighLevelEncoder_TextEncodation(t *testing.T) {
+	assertEncodes(t, "aimaimaim", "239 91 11 91 11 91 11 254")
+}
+
+func TestHighLevelEncoder_X12Encodation(t *testing.T) {
+	assertEncodes(t, "ABC>ABC123>AB", "238 89 233 14 192 100 207 44 31 67")
+}
+
+func TestHighLevelEncoder_Base256Encodation(t *testing.T) {
+	assertEncodes(t, "«äöüé»", "231 44 108 59 226 126 1 104")
+}
+
+func TestHighLevelEncoder_Padding(t *testing.T) {
+	// A single character is padded out to fill the smallest symbol
+	assertEncodes(t, "A", "66 129 70")
+}
+
+func TestHighLevelEncoder_NonLatin1(t *testing.T) {
+	_, err := encoder.EncodeHighLevel("€")
+	internal.AssertFailure(t, err, "encoded a character outside of ISO-8859-1")
+}
+
+func TestHighLevelEncoder_ShapeHint(t *testing.T) {
+	codewords, err := encoder.EncodeHighLevelWithConstraints("12345678901234567890", encoder.ForceRectangle, nil, nil)
+	internal.AssertSuccess(t, err)
+	symbolInfo, err := encoder.LookupSymbolInfo(len(codewords), encoder.ForceRectangle, nil, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertTrue(t, symbolInfo.IsRectangular(), "rectangle hint chose "+symbolInfo.String())
+	internal.AssertEquals(t, symbolInfo.GetDataCapacity(), len(codewords), "codewords not padded to the symbol capacity")
+}
+
+func TestHighLevelEncoder_GS1(t *testing.T) {
+	codewords, err := encoder.EncodeHighLevelGS1("10123\x1d2112", encoder.ForceNone, nil, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "232 140 142 52 232 151 142 129", visualize(codewords), "encoded GS1 data as "+visualize(codewords))
+
+	// Within C40, FNC1 is encoded in the Shift 2 set
+	codewords, err = encoder.EncodeHighLevelGS1("10ABCDEFGHIJ\x1d21KLMNOPQR", encoder.ForceNone, nil, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, uint8(232), codewords[0], "expected FNC1 in the first position")
+	internal.AssertEquals(t, uint8(230), codewords[2], "expected C40 encodation")
+}
@@ -0,0 +1,318 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core"
+)
+
+// SymbolInfo describes the size and layout of one Data Matrix symbol.
+type SymbolInfo struct {
+	rectangular    bool
+	dataCapacity   int
+	errorCodewords int
+	matrixWidth    int
+	matrixHeight   int
+	dataRegions    int
+	rsBlockData    int
+	rsBlockError   int
+	dmre           bool
+}
+
+func newSymbolInfo(rectangular bool, dataCapacity, errorCodewords, matrixWidth, matrixHeight, dataRegions int) *SymbolInfo {
+	return newInterleavedSymbolInfo(rectangular, dataCapacity, errorCodewords, matrixWidth, matrixHeight, dataRegions, dataCapacity, errorCodewords)
+}
+
+func newInterleavedSymbolInfo(rectangular bool, dataCapacity, errorCodewords, matrixWidth, matrixHeight, dataRegions, rsBlockData, rsBlockError int) *SymbolInfo {
+	return &SymbolInfo{
+		rectangular,
+		dataCapacity,
+		errorCodewords,
+		matrixWidth,
+		matrixHeight,
+		dataRegions,
+		rsBlockData,
+		rsBlockError,
+		false,
+	}
+}
+
+// newDMRESymbolInfo describes a rectangular extension symbol, whose data
+// regions are all side by side.
+func newDMRESymbolInfo(dataCapacity, errorCodewords, matrixWidth, matrixHeight, dataRegions int) *SymbolInfo {
+	symbol := newSymbolInfo(true, dataCapacity, errorCodewords, matrixWidth, matrixHeight, dataRegions)
+	symbol.dmre = true
+	return symbol
+}
+
+// prodSymbols is the table of all ECC 200 symbol sizes, in increasing
+// order of data capacity. The rectangular extension (DMRE) sizes of
+// ISO/IEC 21471 are only chosen when a rectangle is requested, as not all
+// readers support them.
+var prodSymbols = []*SymbolInfo{
+	newSymbolInfo(false, 3, 5, 8, 8, 1),
+	newSymbolInfo(false, 5, 7, 10, 10, 1),
+	/*rect*/ newSymbolInfo(true, 5, 7, 16, 6, 1),
+	newSymbolInfo(false, 8, 10, 12, 12, 1),
+	/*rect*/ newSymbolInfo(true, 10, 11, 14, 6, 2),
+	newSymbolInfo(false, 12, 12, 14, 14, 1),
+	/*rect*/ newSymbolInfo(true, 16, 14, 24, 10, 1),
+
+	newSymbolInfo(false, 18, 14, 16, 16, 1),
+	/*dmre*/ newDMRESymbolInfo(18, 15, 22, 6, 2),
+	newSymbolInfo(false, 22, 18, 18, 18, 1),
+	/*rect*/ newSymbolInfo(true, 22, 18, 16, 10, 2),
+	/*dmre*/ newDMRESymbolInfo(24, 18, 14, 6, 4),
+	newSymbolInfo(false, 30, 20, 20, 20, 1),
+	/*rect*/ newSymbolInfo(true, 32, 24, 16, 14, 2),
+	/*dmre*/ newDMRESymbolInfo(32, 22, 18, 6, 4),
+	newSymbolInfo(false, 36, 24, 22, 22, 1),
+	/*dmre*/ newDMRESymbolInfo(38, 28, 22, 6, 4),
+	/*dmre*/ newDMRESymbolInfo(43, 27, 14, 10, 4),
+	newSymbolInfo(false, 44, 28, 24, 24, 1),
+	/*dmre*/ newDMRESymbolInfo(44, 28, 16, 18, 2),
+	/*rect*/ newSymbolInfo(true, 49, 28, 22, 14, 2),
+	/*dmre*/ newDMRESymbolInfo(49, 32, 18, 6, 6),
+	/*dmre*/ newDMRESymbolInfo(56, 34, 20, 18, 2),
+
+	newSymbolInfo(false, 62, 36, 14, 14, 4),
+	/*dmre*/ newDMRESymbolInfo(62, 36, 14, 14, 4),
+	/*dmre*/ newDMRESymbolInfo(63, 36, 22, 6, 6),
+	/*dmre*/ newDMRESymbolInfo(64, 36, 20, 10, 4),
+	/*dmre*/ newDMRESymbolInfo(70, 38, 18, 24, 2),
+	/*dmre*/ newDMRESymbolInfo(72, 38, 22, 20, 2),
+	/*dmre*/ newDMRESymbolInfo(80, 41, 22, 22, 2),
+	/*dmre*/ newDMRESymbolInfo(84, 42, 14, 18, 4),
+	newSymbolInfo(false, 86, 42, 16, 16, 4),
+	/*dmre*/ newDMRESymbolInfo(90, 42, 22, 24, 2),
+	/*dmre*/ newDMRESymbolInfo(108, 46, 14, 22, 4),
+	newSymbolInfo(false, 114, 48, 18, 18, 4),
+	/*dmre*/ newDMRESymbolInfo(118, 50, 14, 24, 4),
+	newSymbolInfo(false, 144, 56, 20, 20, 4),
+	newSymbolInfo(false, 174, 68, 22, 22, 4),
+
+	newInterleavedSymbolInfo(false, 204, 84, 24, 24, 4, 102, 42),
+	newInterleavedSymbolInfo(false, 280, 112, 14, 14, 16, 140, 56),
+	newInterleavedSymbolInfo(false, 368, 144, 16, 16, 16, 92, 36),
+	newInterleavedSymbolInfo(false, 456, 192, 18, 18, 16, 114, 48),
+	newInterleavedSymbolInfo(false, 576, 224, 20, 20, 16, 144, 56),
+	newInterleavedSymbolInfo(false, 696, 272, 22, 22, 16, 174, 68),
+	newInterleavedSymbolInfo(false, 816, 336, 24, 24, 16, 136, 56),
+	newInterleavedSymbolInfo(false, 1050, 408, 18, 18, 36, 175, 68),
+	newInterleavedSymbolInfo(false, 1304, 496, 20, 20, 36, 163, 62),
+	// 144x144 interleaves eight blocks of 156 data codewords and two of
+	// 155
+	newInterleavedSymbolInfo(false, 1558, 620, 22, 22, 36, 156, 62),
+}
+
+var symbols = prodSymbols
+
+// OverrideSymbolSet overrides the symbol info set used by this package.
+// Used for testing purposes.
+func OverrideSymbolSet(override []*SymbolInfo) {
+	symbols = override
+}
+
+// LookupSymbolInfo finds the smallest symbol that can hold dataCodewords
+// codewords, has the requested shape and lies within the optional minSize
+// and maxSize, either of which may be nil.
+// It returns an error if no such symbol exists.
+func LookupSymbolInfo(dataCodewords int, shape SymbolShapeHint, minSize, maxSize *core.Dimension) (*SymbolInfo, error) {
+	for _, symbol := range symbols {
+		if shape == ForceSquare && symbol.rectangular {
+			continue
+		}
+		if shape == ForceRectangle && !symbol.rectangular {
+			continue
+		}
+		if shape != ForceRectangle && symbol.dmre {
+			continue
+		}
+		if minSize != nil || maxSize != nil {
+			width, err := symbol.GetSymbolWidth()
+			if err != nil {
+				return nil, err
+			}
+			height, err := symbol.GetSymbolHeight()
+			if err != nil {
+				return nil, err
+			}
+			if minSize != nil && (width < minSize.GetWidth() || height < minSize.GetHeight()) {
+				continue
+			}
+			if maxSize != nil && (width > maxSize.GetWidth() || height > maxSize.GetHeight()) {
+				continue
+			}
+		}
+		if dataCodewords <= symbol.dataCapacity {
+			return symbol, nil
+		}
+	}
+	return nil, errors.New("Can't find a symbol arrangement that matches the message. Data codewords: " + strconv.Itoa(dataCodewords))
+}
+
+// errDataRegions is returned for a symbol whose data regions cannot be
+// laid out in a square grid.
+var errDataRegions = errors.New("cannot handle this number of data regions")
+
+func (s *SymbolInfo) getHorizontalDataRegions() (int, error) {
+	if s.rectangular {
+		// Rectangular symbols are a single data region high
+		return s.dataRegions, nil
+	}
+	switch s.dataRegions {
+	case 1:
+		return 1, nil
+	case 4:
+		return 2, nil
+	case 16:
+		return 4, nil
+	case 36:
+		return 6, nil
+	}
+	return 0, errDataRegions
+}
+
+func (s *SymbolInfo) getVerticalDataRegions() (int, error) {
+	if s.rectangular {
+		return 1, nil
+	}
+	switch s.dataRegions {
+	case 1:
+		return 1, nil
+	case 4:
+		return 2, nil
+	case 16:
+		return 4, nil
+	case 36:
+		return 6, nil
+	}
+	return 0, errDataRegions
+}
+
+// IsRectangular reports whether the symbol is rectangular rather than
+// square.
+func (s *SymbolInfo) IsRectangular() bool {
+	return s.rectangular
+}
+
+// GetMatrixWidth returns the width of one data region, in modules.
+func (s *SymbolInfo) GetMatrixWidth() int {
+	return s.matrixWidth
+}
+
+// GetMatrixHeight returns the height of one data region, in modules.
+func (s *SymbolInfo) GetMatrixHeight() int {
+	return s.matrixHeight
+}
+
+// GetSymbolDataWidth returns the width of all data regions together,
+// excluding the finder and timing patterns.
+// It returns an error if the data regions cannot be laid out.
+func (s *SymbolInfo) GetSymbolDataWidth() (int, error) {
+	regions, err := s.getHorizontalDataRegions()
+	if err != nil {
+		return 0, err
+	}
+	return regions * s.matrixWidth, nil
+}
+
+// GetSymbolDataHeight returns the height of all data regions together,
+// excluding the finder and timing patterns.
+// It returns an error if the data regions cannot be laid out.
+func (s *SymbolInfo) GetSymbolDataHeight() (int, error) {
+	regions, err := s.getVerticalDataRegions()
+	if err != nil {
+		return 0, err
+	}
+	return regions * s.matrixHeight, nil
+}
+
+// GetSymbolWidth returns the width of the whole symbol, in modules.
+// It returns an error if the data regions cannot be laid out.
+func (s *SymbolInfo) GetSymbolWidth() (int, error) {
+	regions, err := s.getHorizontalDataRegions()
+	if err != nil {
+		return 0, err
+	}
+	return regions * (s.matrixWidth + 2), nil
+}
+
+// GetSymbolHeight returns the height of the whole symbol, in modules.
+// It returns an error if the data regions cannot be laid out.
+func (s *SymbolInfo) GetSymbolHeight() (int, error) {
+	regions, err := s.getVerticalDataRegions()
+	if err != nil {
+		return 0, err
+	}
+	return regions * (s.matrixHeight + 2), nil
+}
+
+func (s *SymbolInfo) GetCodewordCount() int {
+	return s.dataCapacity + s.errorCodewords
+}
+
+// GetInterleavedBlockCount returns the number of Reed-Solomon blocks the
+// codewords are interleaved across.
+func (s *SymbolInfo) GetInterleavedBlockCount() int {
+	return (s.dataCapacity + s.rsBlockData - 1) / s.rsBlockData
+}
+
+func (s *SymbolInfo) GetDataCapacity() int {
+	return s.dataCapacity
+}
+
+func (s *SymbolInfo) GetErrorCodewords() int {
+	return s.errorCodewords
+}
+
+// GetDataLengthForInterleavedBlock returns the number of data codewords
+// in the index'th interleaved block, counting from 1. Where the data
+// doesn't divide evenly, the leading blocks are one codeword longer.
+func (s *SymbolInfo) GetDataLengthForInterleavedBlock(index int) int {
+	numLongerBlocks := s.dataCapacity - s.GetInterleavedBlockCount()*(s.rsBlockData-1)
+	if index <= numLongerBlocks {
+		return s.rsBlockData
+	}
+	return s.rsBlockData - 1
+}
+
+// GetErrorLengthForInterleavedBlock returns the number of error
+// correction codewords in the index'th interleaved block, counting from 1.
+func (s *SymbolInfo) GetErrorLengthForInterleavedBlock(index int) int {
+	return s.rsBlockError
+}
+
+func (s *SymbolInfo) String() string {
+	shape := "Square Symbol:"
+	if s.rectangular {
+		shape = "Rectangular Symbol:"
+	}
+	description := shape + " data region " + strconv.Itoa(s.matrixWidth) + "x" + strconv.Itoa(s.matrixHeight)
+	horizontal, horizontalErr := s.getHorizontalDataRegions()
+	vertical, verticalErr := s.getVerticalDataRegions()
+	if horizontalErr != nil || verticalErr != nil {
+		return description + ", " + strconv.Itoa(s.dataRegions) + " data regions"
+	}
+	return description +
+		", symbol size " + strconv.Itoa(horizontal*(s.matrixWidth+2)) + "x" + strconv.Itoa(vertical*(s.matrixHeight+2)) +
+		", symbol data size " + strconv.Itoa(horizontal*s.matrixWidth) + "x" + strconv.Itoa(vertical*s.matrixHeight) +
+		", codewords " + strconv.Itoa(s.dataCapacity) + "+" + strconv.Itoa(s.errorCodewords)
+}
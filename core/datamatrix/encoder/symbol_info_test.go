@@ -0,0 +1,100 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder_test
+
+import (
+	"testing"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/datamatrix/encoder"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+func assertSymbolSize(t *testing.T, dataCodewords int, shape encoder.SymbolShapeHint, minSize, maxSize *core.Dimension, width, height int) {
+	info, err := encoder.LookupSymbolInfo(dataCodewords, shape, minSize, maxSize)
+	internal.AssertSuccess(t, err)
+	symbolWidth, err := info.GetSymbolWidth()
+	internal.AssertSuccess(t, err)
+	symbolHeight, err := info.GetSymbolHeight()
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, width, symbolWidth, "wrong symbol width for "+info.String())
+	internal.AssertEquals(t, height, symbolHeight, "wrong symbol height for "+info.String())
+}
+
+func TestSymbolInfo_Lookup(t *testing.T) {
+	assertSymbolSize(t, 3, encoder.ForceNone, nil, nil, 10, 10)
+	assertSymbolSize(t, 3, encoder.ForceRectangle, nil, nil, 18, 8)
+	assertSymbolSize(t, 9, encoder.ForceNone, nil, nil, 32, 8)
+	assertSymbolSize(t, 9, encoder.ForceSquare, nil, nil, 16, 16)
+	assertSymbolSize(t, 35, encoder.ForceRectangle, nil, nil, 96, 8)
+	assertSymbolSize(t, 35, encoder.ForceNone, nil, nil, 24, 24)
+	assertSymbolSize(t, 1558, encoder.ForceNone, nil, nil, 144, 144)
+
+	_, err := encoder.LookupSymbolInfo(119, encoder.ForceRectangle, nil, nil)
+	internal.AssertFailure(t, err, "found a rectangular symbol for 119 codewords")
+	_, err = encoder.LookupSymbolInfo(1559, encoder.ForceNone, nil, nil)
+	internal.AssertFailure(t, err, "found a symbol for 1559 codewords")
+}
+
+func TestSymbolInfo_DMRE(t *testing.T) {
+	// The rectangular extension sizes are only used for rectangles
+	assertSymbolSize(t, 17, encoder.ForceRectangle, nil, nil, 48, 8)
+	assertSymbolSize(t, 17, encoder.ForceNone, nil, nil, 18, 18)
+	assertSymbolSize(t, 35, encoder.ForceRectangle, nil, nil, 96, 8)
+	assertSymbolSize(t, 49, encoder.ForceRectangle, nil, nil, 48, 16)
+	assertSymbolSize(t, 50, encoder.ForceRectangle, nil, nil, 44, 20)
+	assertSymbolSize(t, 100, encoder.ForceRectangle, nil, nil, 64, 24)
+	assertSymbolSize(t, 118, encoder.ForceRectangle, nil, nil, 64, 26)
+
+	minSize, err := core.NewDimension(60, 1)
+	internal.AssertSuccess(t, err)
+	assertSymbolSize(t, 1, encoder.ForceRectangle, minSize, nil, 64, 8)
+	minSize, err = core.NewDimension(1, 12)
+	internal.AssertSuccess(t, err)
+	assertSymbolSize(t, 1, encoder.ForceRectangle, minSize, nil, 26, 12)
+}
+
+func TestSymbolInfo_SizeConstraints(t *testing.T) {
+	fixed, err := core.NewDimension(26, 26)
+	internal.AssertSuccess(t, err)
+	assertSymbolSize(t, 1, encoder.ForceNone, fixed, fixed, 26, 26)
+
+	_, err = encoder.LookupSymbolInfo(45, encoder.ForceNone, fixed, fixed)
+	internal.AssertFailure(t, err, "found a 26x26 symbol for 45 codewords")
+
+	minSize, err := core.NewDimension(20, 16)
+	internal.AssertSuccess(t, err)
+	maxSize, err := core.NewDimension(50, 16)
+	internal.AssertSuccess(t, err)
+	assertSymbolSize(t, 1, encoder.ForceNone, minSize, maxSize, 36, 16)
+}
+
+func TestSymbolInfo_InvalidDataRegions(t *testing.T) {
+	info := &encoder.SymbolInfo{}
+	_, err := info.GetSymbolWidth()
+	internal.AssertFailure(t, err, "expected a symbol without data regions to have no width")
+	_, err = info.GetSymbolDataHeight()
+	internal.AssertFailure(t, err, "expected a symbol without data regions to have no data height")
+}
+
+func TestSymbolInfo_InterleavedBlocks(t *testing.T) {
+	info, err := encoder.LookupSymbolInfo(1558, encoder.ForceNone, nil, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 10, info.GetInterleavedBlockCount(), "144x144 should interleave 10 blocks")
+	internal.AssertEquals(t, 156, info.GetDataLengthForInterleavedBlock(8), "block 8 should hold 156 codewords")
+	internal.AssertEquals(t, 155, info.GetDataLengthForInterleavedBlock(9), "block 9 should hold 155 codewords")
+}
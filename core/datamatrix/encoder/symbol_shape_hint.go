@@ -0,0 +1,30 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+// SymbolShapeHint enumerates the requested shape of a Data Matrix symbol.
+type SymbolShapeHint uint8
+
+const (
+	// ForceNone allows both square and rectangular symbols.
+	ForceNone SymbolShapeHint = iota
+	// ForceSquare only allows square symbols.
+	ForceSquare
+	// ForceRectangle only allows rectangular symbols, including the
+	// rectangular extension (DMRE) sizes.
+	ForceRectangle
+)
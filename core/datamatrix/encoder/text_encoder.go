@@ -0,0 +1,77 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import "bytes"
+
+// newTextEncoder constructs an encoder for the Text encodation, which
+// works like C40 but with the cases of the basic set swapped.
+func newTextEncoder() *c40Encoder {
+	return &c40Encoder{textEncodation, encodeTextChar}
+}
+
+func encodeTextChar(c uint8, sb *bytes.Buffer) int {
+	if c == ' ' {
+		sb.WriteByte(3)
+		return 1
+	}
+	if c >= '0' && c <= '9' {
+		sb.WriteByte(c - 48 + 4)
+		return 1
+	}
+	if c >= 'a' && c <= 'z' {
+		sb.WriteByte(c - 97 + 14)
+		return 1
+	}
+	if c < ' ' {
+		sb.WriteByte(0) //Shift 1 Set
+		sb.WriteByte(c)
+		return 2
+	}
+	if c <= '/' {
+		sb.WriteByte(1) //Shift 2 Set
+		sb.WriteByte(c - 33)
+		return 2
+	}
+	if c <= '@' {
+		sb.WriteByte(1) //Shift 2 Set
+		sb.WriteByte(c - 58 + 15)
+		return 2
+	}
+	if c >= '[' && c <= '_' {
+		sb.WriteByte(1) //Shift 2 Set
+		sb.WriteByte(c - 91 + 22)
+		return 2
+	}
+	if c == '`' {
+		sb.WriteByte(2) //Shift 3 Set
+		sb.WriteByte(0) // '`' - 96 == 0
+		return 2
+	}
+	if c <= 'Z' {
+		sb.WriteByte(2) //Shift 3 Set
+		sb.WriteByte(c - 65 + 1)
+		return 2
+	}
+	if c <= 127 {
+		sb.WriteByte(2) //Shift 3 Set
+		sb.WriteByte(c - 123 + 27)
+		return 2
+	}
+	sb.Write([]uint8{1, 0x1e}) //Shift 2, Upper Shift
+	return 2 + encodeTextChar(c-128, sb)
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import "bytes"
+
+type x12Encoder struct{}
+
+func (e x12Encoder) getEncodingMode() int {
+	return x12Encodation
+}
+
+func (e x12Encoder) encode(context *encoderContext) error {
+	//step C
+	var buffer bytes.Buffer
+	for context.hasMoreCharacters() {
+		c := context.getCurrentChar()
+		context.pos++
+
+		if err := encodeX12Char(c, &buffer); err != nil {
+			return err
+		}
+
+		count := buffer.Len()
+		if (count % 3) == 0 {
+			writeNextTriplet(context, &buffer)
+
+			newMode := lookAheadTest(context.getMessage(), context.pos, e.getEncodingMode())
+			if newMode != e.getEncodingMode() {
+				// Return to ASCII encodation, which will actually handle latch to new mode
+				context.signalEncoderChange(asciiEncodation)
+				break
+			}
+		}
+	}
+	return handleX12EOD(context, &buffer)
+}
+
+func encodeX12Char(c uint8, sb *bytes.Buffer) error {
+	switch {
+	case c == '\r':
+		sb.WriteByte(0)
+	case c == '*':
+		sb.WriteByte(1)
+	case c == '>':
+		sb.WriteByte(2)
+	case c == ' ':
+		sb.WriteByte(3)
+	case c >= '0' && c <= '9':
+		sb.WriteByte(c - 48 + 4)
+	case c >= 'A' && c <= 'Z':
+		sb.WriteByte(c - 65 + 14)
+	default:
+		return illegalCharacter(c)
+	}
+	return nil
+}
+
+func handleX12EOD(context *encoderContext, buffer *bytes.Buffer) error {
+	if err := context.updateSymbolInfo(); err != nil {
+		return err
+	}
+	available := context.getSymbolInfo().GetDataCapacity() - context.getCodewordCount()
+	count := buffer.Len()
+	context.pos -= count
+	if context.getRemainingCharacters() > 1 || available > 1 ||
+		context.getRemainingCharacters() != available {
+		context.writeCodeword(x12Unlatch)
+	}
+	if context.getNewEncoding() < 0 {
+		context.signalEncoderChange(asciiEncodation)
+	}
+	return nil
+}
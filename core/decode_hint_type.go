@@ -0,0 +1,76 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+// DecodeHintType encapsulates a type of hint that a caller may pass to a
+// barcode reader to help it more quickly or accurately decode it. It is up
+// to implementations to decide what, if anything, to do with the
+// information that is supplied.
+type DecodeHintType uint8
+
+const (
+	// DecodeHintOther is unspecified, application-specific hint. Maps to
+	// an unspecified value.
+	DecodeHintOther DecodeHintType = iota
+
+	// DecodeHintPureBarcode is an image which is a pure monochrome image
+	// of a barcode. Doesn't matter what it maps to.
+	DecodeHintPureBarcode
+
+	// DecodeHintPossibleFormats is an image which is known to be of one of
+	// a few possible formats. Maps to a []BarcodeFormat.
+	DecodeHintPossibleFormats
+
+	// DecodeHintTryHarder spends more time to try to find a barcode;
+	// optimize for accuracy, not speed. Doesn't matter what it maps to.
+	DecodeHintTryHarder
+
+	// DecodeHintCharacterSet specifies what character encoding to use
+	// when decoding, where applicable. Maps to a string.
+	DecodeHintCharacterSet
+
+	// DecodeHintAllowedLengths restricts the allowed lengths of encoded
+	// data. Maps to a []int.
+	DecodeHintAllowedLengths
+
+	// DecodeHintAssumeCode39CheckDigit assumes Code 39 codes employ a
+	// check digit. Doesn't matter what it maps to.
+	DecodeHintAssumeCode39CheckDigit
+
+	// DecodeHintAssumeGS1 assumes the barcode is being processed as a GS1
+	// barcode, and modifies behavior as needed. For example this affects
+	// FNC1 handling for Code 128 (aka GS1-128). Doesn't matter what it
+	// maps to.
+	DecodeHintAssumeGS1
+
+	// DecodeHintReturnCodabarStartEnd asks the Codabar reader to return
+	// the start and end digits. Doesn't matter what it maps to.
+	DecodeHintReturnCodabarStartEnd
+
+	// DecodeHintNeedResultPointCallback asks the caller to be notified of
+	// possible result points, as they're found. Maps to a
+	// ResultPointCallback.
+	DecodeHintNeedResultPointCallback
+
+	// DecodeHintAllowedEANExtensions allows EAN or UPC-A barcodes with an
+	// extension. Maps to a []int of the allowed extension lengths.
+	DecodeHintAllowedEANExtensions
+
+	// DecodeHintAlsoInverted also tries to decode the barcode after
+	// inverting the image. Doesn't matter what it maps to.
+	DecodeHintAlsoInverted
+)
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2012 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Dimension simply encapsulates a width and height.
+type Dimension struct {
+	width, height int
+}
+
+// NewDimension constructs a Dimension.
+// It returns an error if either width or height is negative.
+func NewDimension(width, height int) (*Dimension, error) {
+	if width < 0 || height < 0 {
+		return nil, errors.New("dimensions must not be negative")
+	}
+	return &Dimension{width, height}, nil
+}
+
+func (d *Dimension) GetWidth() int {
+	return d.width
+}
+
+func (d *Dimension) GetHeight() int {
+	return d.height
+}
+
+func (d *Dimension) Equals(other *Dimension) bool {
+	return d.width == other.width && d.height == other.height
+}
+
+func (d *Dimension) String() string {
+	return strconv.Itoa(d.width) + "x" + strconv.Itoa(d.height)
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2008 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+// EncodeHintType encapsulates a type of hint that a caller may pass to a
+// barcode writer.
+type EncodeHintType uint8
+
+const (
+	// EncodeHintErrorCorrection specifies what degree of error correction
+	// to use, for example in QR Codes. Type depends on the encoder. For
+	// example for PDF417 it is an int between 0 and 8, and for Aztec it
+	// is the minimal percentage of error correction words, as an int.
+	EncodeHintErrorCorrection EncodeHintType = iota
+
+	// EncodeHintCharacterSet specifies what character encoding to use
+	// where applicable, as a string.
+	EncodeHintCharacterSet
+
+	// EncodeHintDataMatrixShape specifies the matrix shape for Data
+	// Matrix, as an encoder.SymbolShapeHint.
+	EncodeHintDataMatrixShape
+
+	// EncodeHintDataMatrixCompact specifies whether to use compact mode
+	// for Data Matrix, as a bool.
+	EncodeHintDataMatrixCompact
+
+	// EncodeHintMinSize specifies a minimum barcode size, as a *Dimension.
+	// Only applicable to Data Matrix now.
+	EncodeHintMinSize
+
+	// EncodeHintMaxSize specifies a maximum barcode size, as a *Dimension.
+	// Only applicable to Data Matrix now.
+	EncodeHintMaxSize
+
+	// EncodeHintMargin specifies margin, in pixels, to use when generating
+	// the barcode, as an int. The meaning can vary by format; for example
+	// it controls margin before and after the barcode horizontally for
+	// most 1D formats.
+	EncodeHintMargin
+
+	// EncodeHintPDF417Compact specifies whether to use compact mode for
+	// PDF417, as a bool.
+	EncodeHintPDF417Compact
+
+	// EncodeHintPDF417Compaction specifies what compaction mode to use for
+	// PDF417, as an encoder.Compaction.
+	EncodeHintPDF417Compaction
+
+	// EncodeHintPDF417Dimensions specifies the minimum and maximum number
+	// of rows and columns for PDF417, as an *encoder.Dimensions.
+	EncodeHintPDF417Dimensions
+
+	// EncodeHintPDF417AutoECI specifies whether to automatically insert
+	// ECIs when encoding PDF417, as a bool.
+	EncodeHintPDF417AutoECI
+
+	// EncodeHintAztecLayers specifies the required number of layers for
+	// an Aztec code, as an int: a negative number (-1, -2, -3, -4)
+	// specifies a compact Aztec code, 0 indicates to use the minimum
+	// number of layers (the default), and a positive number (1, 2, .. 32)
+	// specifies a normal (non-compact) Aztec code.
+	EncodeHintAztecLayers
+
+	// EncodeHintGS1Format specifies whether the data should be encoded to
+	// the GS1 standard, as a bool.
+	EncodeHintGS1Format
+
+	// EncodeHintForceCodeSet forces which encoding will be used. Currently
+	// only used for Code 128 code sets, as a string of "A", "B" or "C".
+	EncodeHintForceCodeSet
+
+	// EncodeHintForceC40 forces C40 encoding for Data Matrix, as a bool.
+	EncodeHintForceC40
+
+	// EncodeHintCode128Compact specifies whether to use compact mode for
+	// Code 128, as a bool.
+	EncodeHintCode128Compact
+
+	// EncodeHintPDF417MacroMetadata specifies the Macro PDF417 control
+	// block of a PDF417 segment, as a *pdf417common.PDF417ResultMetadata
+	// from package pdf417/common.
+	EncodeHintPDF417MacroMetadata
+
+	// EncodeHintMaxiCodeMode specifies the mode of a MaxiCode, as an int
+	// from 2 to 6.
+	EncodeHintMaxiCodeMode
+
+	// EncodeHintPDF417RowHeight specifies the height of a PDF417 row as a
+	// multiple of the module width, as an int.
+	EncodeHintPDF417RowHeight
+
+	// EncodeHintPDF417AspectRatio specifies the width to height ratio,
+	// in modules and with rows of the PDF417 row height, that the number
+	// of PDF417 columns and rows is chosen to come closest to, as a
+	// float64.
+	EncodeHintPDF417AspectRatio
+)
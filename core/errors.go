@@ -0,0 +1,36 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import "errors"
+
+// These errors stand in for the ReaderException hierarchy of the Java
+// library, and are returned as-is by the readers and decoders so that
+// callers may compare against them.
+var (
+	// ErrNotFound is returned when a barcode could not be located within
+	// an image.
+	ErrNotFound = errors.New("barcode not found")
+
+	// ErrFormat is returned when a barcode was located, but its contents
+	// do not conform to the rules of its format.
+	ErrFormat = errors.New("barcode format is invalid")
+
+	// ErrChecksum is returned when a barcode was decoded, but failed its
+	// checksum or could not be repaired by error correction.
+	ErrChecksum = errors.New("barcode checksum failed")
+)
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2011 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	maxicodecommon "github.com/discesoft/zxing-go/core/maxicode/common"
+)
+
+type bitMatrixParser struct {
+	bitMatrix *common.BitMatrix
+}
+
+// newBitMatrixParser prepares bitMatrix, which holds the modules of a
+// MaxiCode, for reading codewords out of it.
+// It returns core.ErrFormat if bitMatrix is not maxicodecommon.MatrixWidth
+// by maxicodecommon.MatrixHeight modules.
+func newBitMatrixParser(bitMatrix *common.BitMatrix) (*bitMatrixParser, error) {
+	if bitMatrix.GetWidth() != maxicodecommon.MatrixWidth || bitMatrix.GetHeight() != maxicodecommon.MatrixHeight {
+		return nil, core.ErrFormat
+	}
+	return &bitMatrixParser{bitMatrix}, nil
+}
+
+// readCodewords assembles the codewords from the bits the modules hold,
+// as laid out by maxicodecommon.ModuleBits.
+func (p *bitMatrixParser) readCodewords() []uint8 {
+	result := make([]uint8, maxicodecommon.NumCodewords)
+	for y := 0; y < maxicodecommon.MatrixHeight; y++ {
+		bitnrRow := maxicodecommon.ModuleBits[y]
+		for x := 0; x < maxicodecommon.MatrixWidth; x++ {
+			bit := bitnrRow[x]
+			if bit >= 0 && p.bitMatrix.Get(uint32(x), uint32(y)) {
+				result[bit/6] |= 1 << uint(5-(bit%6))
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,268 @@
+/*
+ * Copyright 2011 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	maxicodecommon "github.com/discesoft/zxing-go/core/maxicode/common"
+)
+
+// MaxiCode is decoded according to ISO/IEC 16023:2000.
+
+// Code set characters that stand for control functions rather than
+// text.
+const (
+	shiftA      = '\uFFF0'
+	shiftB      = '\uFFF1'
+	shiftC      = '\uFFF2'
+	shiftD      = '\uFFF3'
+	shiftE      = '\uFFF4'
+	twoShiftA   = '\uFFF5'
+	threeShiftA = '\uFFF6'
+	latchA      = '\uFFF7'
+	latchB      = '\uFFF8'
+	lock        = '\uFFF9'
+	eci         = '\uFFFA'
+	ns          = '\uFFFB'
+	pad         = '\uFFFC'
+	fs          = '\u001C'
+	gs          = '\u001D'
+	rs          = '\u001E'
+)
+
+// sets holds the 64 characters of code sets A to E.
+var sets = [][]rune{
+	[]rune("\rABCDEFGHIJKLMNOPQRSTUVWXYZ" + string([]rune{eci, fs, gs, rs, ns}) + " " + string(pad) +
+		"\"#$%&'()*+,-./0123456789:" + string([]rune{shiftB, shiftC, shiftD, shiftE, latchB})),
+	[]rune("`abcdefghijklmnopqrstuvwxyz" + string([]rune{eci, fs, gs, rs, ns}) + "{" + string(pad) +
+		"}~\u007F;<=>?[\\]^_ ,./:@!|" + string([]rune{pad, twoShiftA, threeShiftA, pad, shiftA, shiftC, shiftD, shiftE, latchA})),
+	[]rune("ÀÁÂÃÄÅÆÇÈÉÊËÌÍÎÏÐÑÒÓÔÕÖ×ØÙÚ" +
+		string([]rune{eci, fs, gs, rs, ns}) +
+		"ÛÜÝÞßª¬±²³µ¹º¼½¾\u0080\u0081\u0082\u0083\u0084\u0085\u0086\u0087\u0088\u0089" +
+		string([]rune{latchA, ' ', lock, shiftD, shiftE, latchB})),
+	[]rune("àáâãäåæçèéêëìíîïðñòóôõö÷øùú" +
+		string([]rune{eci, fs, gs, rs, ns}) +
+		"ûüýþÿ¡¨«¯°´·¸»¿\u008A\u008B\u008C\u008D\u008E\u008F\u0090\u0091\u0092\u0093\u0094" +
+		string([]rune{latchA, ' ', shiftC, lock, shiftE, latchB})),
+	[]rune("\u0000\u0001\u0002\u0003\u0004\u0005\u0006\u0007\u0008\u0009\n\u000B\u000C\r\u000E\u000F\u0010\u0011\u0012\u0013\u0014\u0015\u0016\u0017\u0018\u0019\u001A" +
+		string([]rune{eci, pad, pad, '\u001B', ns, fs, gs, rs}) +
+		"\u001F\u009F\u00A0¢£¤¥¦§©\u00AD®¶\u0095\u0096\u0097\u0098\u0099\u009A\u009B\u009C\u009D\u009E" +
+		string([]rune{latchA, ' ', shiftC, shiftD, lock, latchB})),
+}
+
+// decodeBitStream decodes the data codewords of a symbol in mode, the
+// primary message's followed by the secondary message's.
+// It returns the decoded result, whose symbology modifier tells whether
+// the symbol has a structured carrier message and whether it uses ECIs,
+// or core.ErrFormat if the codewords are not a valid encodation.
+func decodeBitStream(bytes []uint8, mode int) (*common.DecoderResult, error) {
+	var result string
+	var hasECI bool
+	var err error
+	switch mode {
+	case 2, 3:
+		var postcode string
+		if mode == 2 {
+			pc := getInt(bytes, maxicodecommon.PostCode2Bits)
+			ps2Length := getInt(bytes, maxicodecommon.PostCode2LengthBits)
+			if ps2Length > 10 {
+				return nil, core.ErrFormat
+			}
+			postcode = padDigits(pc, ps2Length)
+		} else {
+			postcode = getPostCode3(bytes)
+		}
+		country := padDigits(getInt(bytes, maxicodecommon.CountryBits), 3)
+		service := padDigits(getInt(bytes, maxicodecommon.ServiceClassBits), 3)
+		var message string
+		message, hasECI, err = getMessage(bytes, 10, 84)
+		if err != nil {
+			return nil, err
+		}
+		structuredCarrierMessage := postcode + string(gs) + country + string(gs) + service + string(gs)
+		// The carrier message goes after the "[)>RS01GSyy" header of an
+		// ANSI MH10.8.3 message
+		if strings.HasPrefix(message, "[)>"+string(rs)+"01"+string(gs)) {
+			result = message[:9] + structuredCarrierMessage + message[9:]
+		} else {
+			result = structuredCarrierMessage + message
+		}
+	case 4, 6:
+		result, hasECI, err = getMessage(bytes, 1, 93)
+		if err != nil {
+			return nil, err
+		}
+	case 5:
+		result, hasECI, err = getMessage(bytes, 1, 77)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, core.ErrFormat
+	}
+
+	// ]U1 marks a structured carrier message, and ]U2 and ]U3 ECIs
+	symbologyModifier := 0
+	if mode == 2 || mode == 3 {
+		symbologyModifier = 1
+	}
+	if hasECI {
+		symbologyModifier += 2
+	}
+	return common.NewDecoderResultWithModifier(bytes, result, nil, strconv.Itoa(mode), symbologyModifier), nil
+}
+
+// getBit returns bit, counted from 1 at the most significant bit of the
+// first codeword, of the 6 bit codewords bytes.
+func getBit(bit int, bytes []uint8) int {
+	bit--
+	if bytes[bit/6]&(1<<uint(5-(bit%6))) == 0 {
+		return 0
+	}
+	return 1
+}
+
+// getInt assembles the given bits of bytes into a number, most
+// significant bit first.
+func getInt(bytes []uint8, bits []int) int {
+	val := 0
+	for _, bit := range bits {
+		val = val<<1 | getBit(bit, bytes)
+	}
+	return val
+}
+
+// getPostCode3 reads the 6 character alphanumeric postcode of mode 3,
+// which is padded with spaces.
+func getPostCode3(bytes []uint8) string {
+	postcode := make([]rune, len(maxicodecommon.PostCode3Bits))
+	for i, bits := range maxicodecommon.PostCode3Bits {
+		postcode[i] = sets[0][getInt(bytes, bits)]
+	}
+	return strings.TrimRight(string(postcode), " ")
+}
+
+// padDigits formats value with at least length digits.
+func padDigits(value, length int) string {
+	digits := strconv.Itoa(value)
+	for len(digits) < length {
+		digits = "0" + digits
+	}
+	return digits
+}
+
+// getMessage decodes the len codewords of bytes from start on, following
+// the shifts, latches and locks between code sets.
+// It returns the message and whether it holds an ECI, or core.ErrFormat
+// if the codewords end in the middle of a number or ECI, or designate an
+// unsupported character set.
+func getMessage(bytes []uint8, start, length int) (string, bool, error) {
+	sb := common.NewECIStringBuilder()
+	shift := -1
+	set := 0
+	lastset := 0
+	hasECI := false
+	end := start + length
+	for i := start; i < end; i++ {
+		c := sets[set][bytes[i]]
+		switch c {
+		case latchA:
+			set = 0
+			shift = -1
+		case latchB:
+			set = 1
+			shift = -1
+		case shiftA, shiftB, shiftC, shiftD, shiftE:
+			lastset = set
+			set = int(c - shiftA)
+			shift = 1
+		case twoShiftA:
+			lastset = set
+			set = 0
+			shift = 2
+		case threeShiftA:
+			lastset = set
+			set = 0
+			shift = 3
+		case ns:
+			// 5 codewords hold a 9 digit number
+			if i+5 >= end {
+				return "", false, core.ErrFormat
+			}
+			nsval := 0
+			for j := 0; j < 5; j++ {
+				i++
+				nsval = nsval<<6 | int(bytes[i])
+			}
+			sb.AppendString(padDigits(nsval, 9))
+		case lock:
+			shift = -1
+		case eci:
+			value, next, err := getECIValue(bytes, i+1, end)
+			if err != nil {
+				return "", false, err
+			}
+			if err := sb.AppendECI(value); err != nil {
+				return "", false, core.ErrFormat
+			}
+			hasECI = true
+			i = next - 1
+		case pad:
+			// Padding carries no data
+		default:
+			sb.AppendByte(uint8(c))
+		}
+		if shift == 0 {
+			set = lastset
+		}
+		shift--
+	}
+	return sb.String(), hasECI, nil
+}
+
+// getECIValue reads the ECI number starting at codeword i of bytes, whose
+// leading 1 bits give the number of further codewords it takes.
+// It returns the number and the index of the codeword after it, or
+// core.ErrFormat if it runs past end.
+func getECIValue(bytes []uint8, i, end int) (int, int, error) {
+	if i >= end {
+		return 0, 0, core.ErrFormat
+	}
+	first := int(bytes[i])
+	var value, count int
+	switch {
+	case first&0x20 == 0:
+		value, count = first, 0
+	case first&0x10 == 0:
+		value, count = first&0x0F, 1
+	case first&0x08 == 0:
+		value, count = first&0x07, 2
+	default:
+		value, count = first&0x03, 3
+	}
+	if i+count >= end {
+		return 0, 0, core.ErrFormat
+	}
+	for j := 1; j <= count; j++ {
+		value = value<<6 | int(bytes[i+j])
+	}
+	return value, i + count + 1, nil
+}
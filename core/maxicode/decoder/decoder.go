@@ -0,0 +1,133 @@
+/*
+ * Copyright 2011 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/common/reedsolomon"
+	maxicodecommon "github.com/discesoft/zxing-go/core/maxicode/common"
+)
+
+// Which codewords of a range an error correction block covers.
+const (
+	all = iota
+	even
+	odd
+)
+
+// Decoder is the main type which implements MaxiCode decoding -- as
+// opposed to locating and extracting the MaxiCode from an image.
+type Decoder struct {
+	rsDecoder *reedsolomon.ReedSolomonDecoder
+}
+
+func NewDecoder() *Decoder {
+	return &Decoder{reedsolomon.NewReedSolomonDecoder(reedsolomon.MaxiCodeField64)}
+}
+
+// Decode decodes a MaxiCode represented as a BitMatrix of
+// maxicodecommon.MatrixWidth by maxicodecommon.MatrixHeight modules,
+// where a set bit is a dark module.
+// It returns the text and bytes encoded within the MaxiCode, with the
+// mode as error correction level, or core.ErrFormat if the symbol cannot
+// be decoded, or core.ErrChecksum if error correction fails.
+func (d *Decoder) Decode(bits *common.BitMatrix) (*common.DecoderResult, error) {
+	parser, err := newBitMatrixParser(bits)
+	if err != nil {
+		return nil, err
+	}
+	return d.DecodeCodewords(parser.readCodewords())
+}
+
+// DecodeCodewords decodes the maxicodecommon.NumCodewords codewords of a MaxiCode, in
+// the order they are numbered in the symbol: the primary message first,
+// then the secondary message. codewords is corrected in-place.
+// It returns the text and bytes encoded within the MaxiCode, with the
+// mode as error correction level, or core.ErrFormat if the symbol cannot
+// be decoded, or core.ErrChecksum if error correction fails.
+func (d *Decoder) DecodeCodewords(codewords []uint8) (*common.DecoderResult, error) {
+	if len(codewords) != maxicodecommon.NumCodewords {
+		return nil, core.ErrFormat
+	}
+	errorsCorrected, err := d.correctErrors(codewords, 0, 10, 10, all)
+	if err != nil {
+		return nil, err
+	}
+	mode := int(codewords[0] & 0x0F)
+	var dataCodewords, ecCodewords int
+	switch mode {
+	case 2, 3, 4, 6:
+		dataCodewords, ecCodewords = 84, 40
+	case 5:
+		dataCodewords, ecCodewords = 68, 56
+	default:
+		return nil, core.ErrFormat
+	}
+	for _, half := range []int{even, odd} {
+		corrected, err := d.correctErrors(codewords, 20, dataCodewords, ecCodewords, half)
+		if err != nil {
+			return nil, err
+		}
+		errorsCorrected += corrected
+	}
+	datawords := make([]uint8, 10+dataCodewords)
+	copy(datawords, codewords[:10])
+	copy(datawords[10:], codewords[20:20+dataCodewords])
+
+	result, err := decodeBitStream(datawords, mode)
+	if err != nil {
+		return nil, err
+	}
+	result.SetErrorsCorrected(errorsCorrected)
+	return result, nil
+}
+
+// correctErrors corrects errors in-place in the dataCodewords data and
+// ecCodewords error correction codewords of codewordBytes from start on,
+// of which block selects all, or the even or odd ones only.
+// It returns the number of errors corrected, or core.ErrChecksum if error
+// correction fails.
+func (d *Decoder) correctErrors(codewordBytes []uint8, start, dataCodewords, ecCodewords, block int) (int, error) {
+	codewords := dataCodewords + ecCodewords
+
+	// in EVEN or ODD mode only half the codewords
+	divisor := 1
+	if block != all {
+		divisor = 2
+	}
+
+	// First read into an array of ints
+	codewordsInts := make([]int, codewords/divisor)
+	for i := 0; i < codewords; i++ {
+		if block == all || i%2 == block-1 {
+			codewordsInts[i/divisor] = int(codewordBytes[i+start])
+		}
+	}
+	errorsCorrected, err := d.rsDecoder.Decode(codewordsInts, ecCodewords/divisor)
+	if err != nil {
+		return 0, core.ErrChecksum
+	}
+	// Copy back into array of bytes -- only need to worry about the bytes that were data
+	// We don't care about errors in the error-correction codewords
+	for i := 0; i < dataCodewords; i++ {
+		if block == all || i%2 == block-1 {
+			codewordBytes[i+start] = uint8(codewordsInts[i/divisor])
+		}
+	}
+	return errorsCorrected, nil
+}
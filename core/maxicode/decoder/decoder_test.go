@@ -0,0 +1,282 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/common/reedsolomon"
+	"github.com/discesoft/zxing-go/core/internal"
+	maxicodecommon "github.com/discesoft/zxing-go/core/maxicode/common"
+)
+
+const padCodeword = 33
+
+// codewordsOf looks up the codewords of text in code set set.
+func codewordsOf(t *testing.T, set int, text string) []uint8 {
+	var codewords []uint8
+	for _, r := range text {
+		found := false
+		for i, c := range sets[set] {
+			if c == r {
+				codewords = append(codewords, uint8(i))
+				found = true
+				break
+			}
+		}
+		internal.AssertTrue(t, found, "no codeword for "+string(r))
+	}
+	return codewords
+}
+
+// setInt stores value into the given bits of bytes, most significant bit
+// first.
+func setInt(bytes []uint8, bits []int, value int) {
+	for i := len(bits) - 1; i >= 0; i-- {
+		bit := bits[i] - 1
+		if value&1 != 0 {
+			bytes[bit/6] |= 1 << uint(5-bit%6)
+		}
+		value >>= 1
+	}
+}
+
+// buildSymbol lays out the data codewords of a symbol, the 10 of the
+// primary message followed by those of the secondary message, padded with
+// PAD, and adds the error correction codewords.
+func buildSymbol(t *testing.T, data []uint8) []uint8 {
+	dataCodewords, ecCodewords := 84, 40
+	if data[0]&0x0F == 5 {
+		dataCodewords, ecCodewords = 68, 56
+	}
+	encoder := reedsolomon.NewReedSolomonEncoder(reedsolomon.MaxiCodeField64)
+	symbol := make([]uint8, maxicodecommon.NumCodewords)
+
+	primary := make([]int, 20)
+	for i := 0; i < 10; i++ {
+		primary[i] = int(data[i])
+	}
+	internal.AssertSuccess(t, encoder.Encode(primary, 10))
+	for i, c := range primary {
+		symbol[i] = uint8(c)
+	}
+
+	for half := 0; half < 2; half++ {
+		block := make([]int, (dataCodewords+ecCodewords)/2)
+		for i := 0; i < dataCodewords/2; i++ {
+			block[i] = padCodeword
+			if j := 10 + 2*i + half; j < len(data) {
+				block[i] = int(data[j])
+			}
+		}
+		internal.AssertSuccess(t, encoder.Encode(block, ecCodewords/2))
+		for i, c := range block {
+			symbol[20+2*i+half] = uint8(c)
+		}
+	}
+	return symbol
+}
+
+// buildMessageSymbol builds a mode 4, 5 or 6 symbol holding message.
+func buildMessageSymbol(t *testing.T, mode uint8, message []uint8) []uint8 {
+	data := []uint8{mode}
+	data = append(data, message...)
+	for len(data) < 10 {
+		data = append(data, padCodeword)
+	}
+	return buildSymbol(t, data)
+}
+
+// placeCodewords lays out the bits of symbol in a grid of modules, with
+// the dark modules of the orientation clusters.
+func placeCodewords(t *testing.T, symbol []uint8) *common.BitMatrix {
+	bits, err := common.NewBitMatrix(maxicodecommon.MatrixWidth, maxicodecommon.MatrixHeight)
+	internal.AssertSuccess(t, err)
+	for y := 0; y < maxicodecommon.MatrixHeight; y++ {
+		for x := 0; x < maxicodecommon.MatrixWidth; x++ {
+			bit := maxicodecommon.ModuleBits[y][x]
+			if bit == maxicodecommon.DarkModule || bit >= 0 && symbol[bit/6]&(1<<uint(5-bit%6)) != 0 {
+				bits.Set(uint32(x), uint32(y))
+			}
+		}
+	}
+	return bits
+}
+
+func assertDecodes(t *testing.T, symbol []uint8, expected, ecLevel string) {
+	result, err := NewDecoder().DecodeCodewords(symbol)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, expected, result.GetText(), "decoded "+result.GetText()+" instead of "+expected)
+	internal.AssertEquals(t, ecLevel, result.GetECLevel(), "mode")
+}
+
+func TestDecoder_Mode4(t *testing.T) {
+	message := codewordsOf(t, 0, "HELLO WORLD 0123456789")
+	assertDecodes(t, buildMessageSymbol(t, 4, message), "HELLO WORLD 0123456789", "4")
+}
+
+func TestDecoder_Mode5(t *testing.T) {
+	message := codewordsOf(t, 0, "ENHANCED ERROR CORRECTION")
+	assertDecodes(t, buildMessageSymbol(t, 5, message), "ENHANCED ERROR CORRECTION", "5")
+}
+
+func TestDecoder_Mode6(t *testing.T) {
+	message := codewordsOf(t, 0, "READER PROGRAMMING")
+	assertDecodes(t, buildMessageSymbol(t, 6, message), "READER PROGRAMMING", "6")
+}
+
+func TestDecoder_Mode2(t *testing.T) {
+	data := make([]uint8, 10)
+	data[0] = 2
+	setInt(data, maxicodecommon.PostCode2Bits, 1234)
+	setInt(data, maxicodecommon.PostCode2LengthBits, 5)
+	setInt(data, maxicodecommon.CountryBits, 840)
+	setInt(data, maxicodecommon.ServiceClassBits, 1)
+	// "[" is only in code set B
+	data = append(data, 59)
+	data = append(data, codewordsOf(t, 1, "[")...)
+	data = append(data, codewordsOf(t, 0, ")")...)
+	data = append(data, 59)
+	data = append(data, codewordsOf(t, 1, ">")...)
+	data = append(data, codewordsOf(t, 0, "\u001E01\u001D96ABC")...)
+	assertDecodes(t, buildSymbol(t, data), "[)>\u001E01\u001D9601234\u001D840\u001D001\u001DABC", "2")
+}
+
+func TestDecoder_Mode3(t *testing.T) {
+	data := make([]uint8, 10)
+	data[0] = 3
+	for i, c := range codewordsOf(t, 0, "B1050 ") {
+		setInt(data, maxicodecommon.PostCode3Bits[i], int(c))
+	}
+	setInt(data, maxicodecommon.CountryBits, 56)
+	setInt(data, maxicodecommon.ServiceClassBits, 999)
+	data = append(data, codewordsOf(t, 0, "PARCEL")...)
+	assertDecodes(t, buildSymbol(t, data), "B1050\u001D056\u001D999\u001DPARCEL", "3")
+}
+
+func TestDecoder_Mode2PostcodeTooLong(t *testing.T) {
+	data := make([]uint8, 10)
+	data[0] = 2
+	setInt(data, maxicodecommon.PostCode2LengthBits, 11)
+	_, err := NewDecoder().DecodeCodewords(buildSymbol(t, data))
+	internal.AssertEquals(t, core.ErrFormat, err, "postcode length")
+}
+
+func TestDecoder_ShiftsAndLatches(t *testing.T) {
+	var message []uint8
+	message = append(message, codewordsOf(t, 0, "A")...)
+	// Shift B for one character
+	message = append(message, 59)
+	message = append(message, codewordsOf(t, 1, "b")...)
+	message = append(message, codewordsOf(t, 0, "C")...)
+	// Latch B
+	message = append(message, 63)
+	message = append(message, codewordsOf(t, 1, "de")...)
+	// 2 Shift A
+	message = append(message, 56)
+	message = append(message, codewordsOf(t, 0, "FG")...)
+	message = append(message, codewordsOf(t, 1, "h")...)
+	// Shift C and D, then lock in E
+	message = append(message, 60)
+	message = append(message, codewordsOf(t, 2, "Ç")...)
+	message = append(message, 61)
+	message = append(message, codewordsOf(t, 3, "é")...)
+	message = append(message, 62, 62)
+	message = append(message, codewordsOf(t, 4, "\u0001\u0002")...)
+	// Latch A
+	message = append(message, 58)
+	message = append(message, codewordsOf(t, 0, "Z")...)
+	assertDecodes(t, buildMessageSymbol(t, 4, message), "AbCdeFGhÇé\u0001\u0002Z", "4")
+}
+
+func TestDecoder_NumericShift(t *testing.T) {
+	// 123456789 as 5 codewords of 6 bits
+	message := []uint8{31, 7, 22, 60, 52, 21}
+	message = append(message, codewordsOf(t, 0, "X")...)
+	assertDecodes(t, buildMessageSymbol(t, 4, message), "123456789X", "4")
+}
+
+func TestDecoder_ECI(t *testing.T) {
+	// ECI 26 (UTF-8), then the bytes C3 A9 of "é" from code sets C and E
+	message := []uint8{27, 26, 60}
+	message = append(message, codewordsOf(t, 2, "\u00C3")...)
+	message = append(message, 62)
+	message = append(message, codewordsOf(t, 4, "\u00A9")...)
+	message = append(message, codewordsOf(t, 0, "A")...)
+	assertDecodes(t, buildMessageSymbol(t, 4, message), "éA", "4")
+}
+
+func TestDecoder_SymbologyModifier(t *testing.T) {
+	for _, test := range []struct {
+		symbol   []uint8
+		modifier int
+	}{
+		{buildMessageSymbol(t, 4, codewordsOf(t, 0, "STANDARD")), 0},
+		{buildMessageSymbol(t, 5, []uint8{27, 3, 33}), 2},
+		{buildSymbol(t, []uint8{3, 0, 0, 0, 0, 0, 0, 0, 0, 0}), 1},
+		{buildSymbol(t, []uint8{2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 27, 26}), 3},
+	} {
+		result, err := NewDecoder().DecodeCodewords(test.symbol)
+		internal.AssertSuccess(t, err)
+		internal.AssertEquals(t, test.modifier, result.GetSymbologyModifier(), "symbology modifier of mode "+result.GetECLevel())
+	}
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	symbol := buildMessageSymbol(t, 4, codewordsOf(t, 0, "FROM THE MODULES"))
+	bits := placeCodewords(t, symbol)
+	// Damage a module of the secondary message
+	bits.Flip(0, 32)
+	result, err := NewDecoder().Decode(bits)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "FROM THE MODULES", result.GetText(), "text")
+	internal.AssertEquals(t, 1, result.GetErrorsCorrected(), "errors corrected")
+
+	wrong, err := common.NewBitMatrix(33, 30)
+	internal.AssertSuccess(t, err)
+	_, err = NewDecoder().Decode(wrong)
+	internal.AssertEquals(t, core.ErrFormat, err, "grid of the wrong size")
+}
+
+func TestDecoder_ErrorCorrection(t *testing.T) {
+	symbol := buildMessageSymbol(t, 4, codewordsOf(t, 0, "CORRECTED"))
+	symbol[3] ^= 0x15
+	symbol[25] ^= 0x2A
+	symbol[90] ^= 0x01
+	result, err := NewDecoder().DecodeCodewords(symbol)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "CORRECTED", result.GetText(), "text")
+	internal.AssertEquals(t, 3, result.GetErrorsCorrected(), "errors corrected")
+}
+
+func TestDecoder_TooManyErrors(t *testing.T) {
+	symbol := buildMessageSymbol(t, 4, codewordsOf(t, 0, "LOST"))
+	for i := 0; i < 8; i++ {
+		symbol[i] ^= 0x3F
+	}
+	_, err := NewDecoder().DecodeCodewords(symbol)
+	internal.AssertEquals(t, core.ErrChecksum, err, "too many errors")
+}
+
+func TestDecoder_UnknownMode(t *testing.T) {
+	_, err := NewDecoder().DecodeCodewords(buildMessageSymbol(t, 7, nil))
+	internal.AssertEquals(t, core.ErrFormat, err, "mode 7")
+	_, err = NewDecoder().DecodeCodewords(make([]uint8, 100))
+	internal.AssertEquals(t, core.ErrFormat, err, "wrong length")
+}
@@ -0,0 +1,516 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector
+
+import (
+	"math"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/common/detector"
+	maxicodecommon "github.com/discesoft/zxing-go/core/maxicode/common"
+)
+
+const (
+	// The bull's eye is centered on module centerColumn of row centerRow
+	centerColumn = 14
+	centerRow    = 16
+
+	// The least fraction of module centers which must lie well inside a
+	// module for a lattice to be accepted.
+	minLatticeScore = 0.75
+
+	// The least number of the orientation modules which must read as
+	// expected for an orientation to be accepted.
+	minOrientationScore = 15
+)
+
+// Detector encapsulates logic that can detect a MaxiCode in an image,
+// even if it is rotated or scaled, by finding its bull's eye and fitting
+// the hexagon lattice around it.
+type Detector struct {
+	image *common.BitMatrix
+}
+
+func NewDetector(image *common.BitMatrix) *Detector {
+	return &Detector{image}
+}
+
+// Detect detects a MaxiCode in an image and resamples it into a
+// maxicodecommon.MatrixWidth by maxicodecommon.MatrixHeight grid of
+// modules, where a set bit is a dark module.
+// The hexagon lattice looks the same each sixth of a turn around the bull's
+// eye, so the orientation is taken from the three modules of each of the
+// six orientation clusters around it.
+// It returns core.ErrNotFound if no MaxiCode can be found.
+func (d *Detector) Detect() (*detector.DetectorResult, error) {
+	eye, err := d.findBullsEye()
+	if err != nil {
+		return nil, err
+	}
+	grid, ok := d.fitLattice(eye)
+	if !ok {
+		return nil, core.ErrNotFound
+	}
+	grid, ok = d.orient(grid, eye.ringWidth/4)
+	if !ok {
+		return nil, core.ErrNotFound
+	}
+	return d.sampleGrid(grid, eye.ringWidth/4)
+}
+
+// bullsEye is the finder pattern of a MaxiCode: three dark rings around a
+// light center.
+type bullsEye struct {
+	x, y      float64
+	ringWidth float64
+	radius    float64
+}
+
+// findBullsEye scans the rows of the image for the runs of the bull's eye
+// and confirms each candidate across its center.
+// It returns core.ErrNotFound if there is none.
+func (d *Detector) findBullsEye() (*bullsEye, error) {
+	width := int(d.image.GetWidth())
+	height := int(d.image.GetHeight())
+	starts := make([]int, 0, width+1)
+	for y := 0; y < height; y++ {
+		// Record where each run starts, beginning with a dark one
+		starts = starts[:0]
+		previous := false
+		for x := 0; x < width; x++ {
+			black := d.get(x, y)
+			if black != previous {
+				starts = append(starts, x)
+				previous = black
+			}
+		}
+		starts = append(starts, width)
+
+		var runs [11]int
+		for i := 0; i+11 < len(starts); i += 2 {
+			for j := range runs {
+				runs[j] = starts[i+j+1] - starts[i+j]
+			}
+			if _, ok := checkBullsEye(runs); !ok {
+				continue
+			}
+			x := (starts[i+5] + starts[i+6]) / 2
+			if eye, ok := d.confirmBullsEye(x, y); ok {
+				return eye, nil
+			}
+		}
+	}
+	return nil, core.ErrNotFound
+}
+
+// confirmBullsEye measures the bull's eye around the light pixel (x, y)
+// vertically and then horizontally, moving to the middle of its center at
+// each step.
+func (d *Detector) confirmBullsEye(x, y int) (*bullsEye, bool) {
+	vertical, offset, ok := d.crossRuns(x, y, 0, 1)
+	if !ok {
+		return nil, false
+	}
+	y += offset
+	horizontal, offset, ok := d.crossRuns(x, y, 1, 0)
+	if !ok {
+		return nil, false
+	}
+	x += offset
+	vertical, offset, ok = d.crossRuns(x, y, 0, 1)
+	if !ok {
+		return nil, false
+	}
+	y += offset
+
+	// The rings should be round, measuring the same across the diagonals
+	var ringWidths, diameters [4]float64
+	for i, direction := range [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}} {
+		runs := horizontal
+		switch i {
+		case 1:
+			runs = vertical
+		case 2, 3:
+			runs, _, ok = d.crossRuns(x, y, direction[0], direction[1])
+			if !ok {
+				return nil, false
+			}
+		}
+		scale := math.Hypot(float64(direction[0]), float64(direction[1]))
+		ringWidth, _ := checkBullsEye(runs)
+		ringWidths[i] = ringWidth * scale
+		diameters[i] = float64(detector.Sum(runs[:])) * scale
+	}
+	ringWidth, diameter := 0.0, 0.0
+	for i := range diameters {
+		ringWidth += ringWidths[i] / 4
+		diameter += diameters[i] / 4
+	}
+	for i := range diameters {
+		if math.Abs(diameters[i]-diameter) > diameter*0.15 {
+			return nil, false
+		}
+	}
+	return &bullsEye{
+		x:         float64(x) + 0.5,
+		y:         float64(y) + 0.5,
+		ringWidth: ringWidth,
+		radius:    diameter / 2,
+	}, true
+}
+
+// crossRuns measures the light run containing (x, y) along the direction
+// (dx, dy), and the five runs either side of it.
+// It returns the runs, how far the middle of the light run is from (x, y),
+// and whether they look like a bull's eye.
+func (d *Detector) crossRuns(x, y, dx, dy int) ([11]int, int, bool) {
+	var runs [11]int
+	if d.get(x, y) {
+		return runs, 0, false
+	}
+	backward := d.countRuns(x, y, -dx, -dy, runs[:6], 5, -1)
+	forward := d.countRuns(x, y, dx, dy, runs[5:], 0, 1)
+	if backward < 0 || forward < 0 {
+		return runs, 0, false
+	}
+	runs[5] = backward + forward - 1
+	_, ok := checkBullsEye(runs)
+	return runs, (forward - backward) / 2, ok
+}
+
+// countRuns walks from (x, y) along (dx, dy) through the light run there
+// and five more, storing their lengths into runs from index first on by
+// step. It returns how much of the first run lies on the walk, or -1 if the
+// walk leaves the image before the sixth run ends.
+func (d *Detector) countRuns(x, y, dx, dy int, runs []int, first, step int) int {
+	black := false
+	index := first
+	for i := 0; i < 6; i++ {
+		length := 0
+		for d.isValid(x, y) && d.get(x, y) == black {
+			length++
+			x += dx
+			y += dy
+		}
+		if !d.isValid(x, y) && i < 5 {
+			return -1
+		}
+		runs[index] = length
+		index += step
+		black = !black
+	}
+	return runs[first]
+}
+
+// checkBullsEye reports whether runs, the dark and light runs across a
+// bull's eye with its light center in the middle, have the proportions of
+// one. The ten ring runs should be about equally wide, and the center up
+// to three times as wide as them.
+// It returns the average width of the rings.
+func checkBullsEye(runs [11]int) (float64, bool) {
+	total := 0
+	for i, run := range runs {
+		if i != 5 {
+			total += run
+		}
+	}
+	ringWidth := float64(total) / 10
+	if ringWidth < 1 {
+		return 0, false
+	}
+	for i, run := range runs {
+		if i == 5 {
+			continue
+		}
+		if math.Abs(float64(run)-ringWidth) > ringWidth/2 {
+			return 0, false
+		}
+	}
+	center := float64(runs[5])
+	return ringWidth, center >= 0.8*ringWidth && center <= 3*ringWidth
+}
+
+// sampleDirections are where sampleModule looks around the center of a
+// module, the center itself first.
+var sampleDirections = [][2]float64{
+	{0, 0}, {1, 0}, {0.5, math.Sqrt(3) / 2}, {-0.5, math.Sqrt(3) / 2},
+	{-1, 0}, {-0.5, -math.Sqrt(3) / 2}, {0.5, -math.Sqrt(3) / 2},
+}
+
+// moduleDistances holds how many modules each module is from the bull's
+// eye.
+var moduleDistances = func() [][]float64 {
+	unit := newHexGrid(0, 0, 1, 0)
+	distances := make([][]float64, maxicodecommon.MatrixHeight)
+	for y := range distances {
+		distances[y] = make([]float64, maxicodecommon.MatrixWidth)
+		for x := range distances[y] {
+			distances[y][x] = math.Hypot(unit.position(x, y))
+		}
+	}
+	return distances
+}()
+
+// hexGrid places the modules of a MaxiCode in the image.
+type hexGrid struct {
+	centerX, centerY float64
+	// One module along a row
+	ux, uy float64
+	// One row down
+	vx, vy float64
+}
+
+func newHexGrid(centerX, centerY, pitch, angle float64) hexGrid {
+	ux := pitch * math.Cos(angle)
+	uy := pitch * math.Sin(angle)
+	rowPitch := math.Sqrt(3) / 2
+	return hexGrid{centerX, centerY, ux, uy, -uy * rowPitch, ux * rowPitch}
+}
+
+// position returns the center of module x of row y.
+func (g hexGrid) position(x, y int) (float64, float64) {
+	column := float64(x) - centerColumn + float64(y&0x01)/2
+	row := float64(y - centerRow)
+	return g.centerX + column*g.ux + row*g.vx, g.centerY + column*g.uy + row*g.vy
+}
+
+// turned returns the grid turned by a number of sixths of a turn around
+// the bull's eye, each of which maps the lattice onto itself.
+func (g hexGrid) turned(sixths int) hexGrid {
+	angle := float64(sixths) * math.Pi / 3
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	return hexGrid{
+		g.centerX, g.centerY,
+		g.ux*cos - g.uy*sin, g.ux*sin + g.uy*cos,
+		g.vx*cos - g.vy*sin, g.vx*sin + g.vy*cos,
+	}
+}
+
+// orientationModules lists the modules of the orientation clusters, and
+// whether each is dark.
+var orientationModules = func() [][3]int {
+	var modules [][3]int
+	for y, row := range maxicodecommon.ModuleBits {
+		for x, bit := range row {
+			// The dark pair at the top right corner is not part of a
+			// cluster
+			if y == 0 {
+				continue
+			}
+			switch bit {
+			case maxicodecommon.LightModule:
+				modules = append(modules, [3]int{x, y, 0})
+			case maxicodecommon.DarkModule:
+				modules = append(modules, [3]int{x, y, 1})
+			}
+		}
+	}
+	return modules
+}()
+
+// orient turns grid by the sixth of a turn whose orientation clusters
+// read best.
+// It returns false if none reads well enough.
+func (d *Detector) orient(grid hexGrid, radius float64) (hexGrid, bool) {
+	bestScore := -1
+	var best hexGrid
+	for sixths := 0; sixths < 6; sixths++ {
+		turned := grid.turned(sixths)
+		score := 0
+		for _, module := range orientationModules {
+			px, py := turned.position(module[0], module[1])
+			dark, ok := d.sampleModule(px, py, radius)
+			if ok && (dark > 3) == (module[2] == 1) {
+				score++
+			}
+		}
+		if score > bestScore {
+			bestScore = score
+			best = turned
+		}
+	}
+	return best, bestScore >= minOrientationScore
+}
+
+// fitLattice finds the pitch and rotation of the hexagon lattice around
+// the bull's eye, first coarsely over the modules close to it and then
+// finely over the whole symbol.
+func (d *Detector) fitLattice(eye *bullsEye) (hexGrid, bool) {
+	radius := eye.ringWidth / 4
+	minPitch := eye.ringWidth * 0.8
+	maxPitch := eye.ringWidth * 2.4
+
+	bestScore := -1.0
+	bestPitch, bestAngle := 0.0, 0.0
+	for pitch := minPitch; pitch <= maxPitch; pitch *= 1.02 {
+		inner := eye.radius + pitch/2
+		outer := eye.radius + 4*pitch
+		for degrees := 0; degrees < 180; degrees++ {
+			angle := float64(degrees) * math.Pi / 180
+			grid := newHexGrid(eye.x, eye.y, pitch, angle)
+			if score := d.scoreGrid(grid, inner, outer, radius); score > bestScore {
+				bestScore = score
+				bestPitch, bestAngle = pitch, angle
+			}
+		}
+	}
+
+	// Many lattices close to the right one score about as well, so settle
+	// on the middle of them, looking further into each module
+	inner := eye.radius + bestPitch/2
+	radius = bestPitch * 0.3
+	var candidates []latticeCandidate
+	step := math.Pi / 1800
+	for pitch := bestPitch * 0.97; pitch <= bestPitch*1.03; pitch *= 1.0025 {
+		for angle := bestAngle - 15*step; angle <= bestAngle+15*step; angle += step {
+			grid := newHexGrid(eye.x, eye.y, pitch, angle)
+			score := d.scoreGrid(grid, inner, math.Inf(1), radius)
+			candidates = append(candidates, latticeCandidate{score, pitch, angle, 0, 0})
+		}
+	}
+	fitted := bestCandidates(candidates)
+	bestPitch, bestAngle = fitted.pitch, fitted.angle
+
+	// Let the center settle too, as the bull's eye was only measured to
+	// the pixel
+	candidates = candidates[:0]
+	for dy := -3; dy <= 3; dy++ {
+		for dx := -3; dx <= 3; dx++ {
+			offsetX := float64(dx) * bestPitch / 12
+			offsetY := float64(dy) * bestPitch / 12
+			grid := newHexGrid(eye.x+offsetX, eye.y+offsetY, bestPitch, bestAngle)
+			score := d.scoreGrid(grid, inner, math.Inf(1), radius)
+			candidates = append(candidates, latticeCandidate{score, bestPitch, bestAngle, offsetX, offsetY})
+		}
+	}
+	fitted = bestCandidates(candidates)
+	best := newHexGrid(eye.x+fitted.offsetX, eye.y+fitted.offsetY, fitted.pitch, fitted.angle)
+	return best, d.scoreGrid(best, inner, math.Inf(1), eye.ringWidth/4) >= minLatticeScore
+}
+
+// latticeCandidate is a lattice tried by fitLattice, and how well it fits.
+type latticeCandidate struct {
+	score            float64
+	pitch, angle     float64
+	offsetX, offsetY float64
+}
+
+// bestCandidates averages the parameters of the candidates scoring within
+// half a percent of the best.
+func bestCandidates(candidates []latticeCandidate) latticeCandidate {
+	bestScore := 0.0
+	for _, candidate := range candidates {
+		bestScore = math.Max(bestScore, candidate.score)
+	}
+	var average latticeCandidate
+	count := 0.0
+	for _, candidate := range candidates {
+		if candidate.score >= bestScore-0.005 {
+			average.pitch += candidate.pitch
+			average.angle += candidate.angle
+			average.offsetX += candidate.offsetX
+			average.offsetY += candidate.offsetY
+			count++
+		}
+	}
+	average.pitch /= count
+	average.angle /= count
+	average.offsetX /= count
+	average.offsetY /= count
+	average.score = bestScore
+	return average
+}
+
+// scoreGrid returns the fraction of the modules of grid between inner and
+// outer from the bull's eye whose center lies well within a module, that
+// is at least radius from where the image changes color.
+func (d *Detector) scoreGrid(grid hexGrid, inner, outer, radius float64) float64 {
+	pitch := math.Hypot(grid.ux, grid.uy)
+	count, stable := 0, 0
+	for y := 0; y < maxicodecommon.MatrixHeight; y++ {
+		for x := 0; x < maxicodecommon.MatrixWidth; x++ {
+			distance := moduleDistances[y][x] * pitch
+			if distance < inner || distance > outer {
+				continue
+			}
+			count++
+			px, py := grid.position(x, y)
+			if dark, ok := d.sampleModule(px, py, radius); ok && (dark == 0 || dark == 7) {
+				stable++
+			}
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return float64(stable) / float64(count)
+}
+
+// sampleModule samples the image at (x, y) and at six points radius away
+// around it.
+// It returns how many of the samples are dark, or false if any lies outside
+// the image.
+func (d *Detector) sampleModule(x, y, radius float64) (int, bool) {
+	dark := 0
+	for _, direction := range sampleDirections {
+		sx := x + radius*direction[0]
+		sy := y + radius*direction[1]
+		if sx < 0 || sy < 0 || !d.isValid(int(sx), int(sy)) {
+			return 0, false
+		}
+		if d.get(int(sx), int(sy)) {
+			dark++
+		}
+	}
+	return dark, true
+}
+
+// sampleGrid reads each module of grid as dark if most of its samples are.
+// It returns core.ErrNotFound if part of the grid lies outside the image.
+func (d *Detector) sampleGrid(grid hexGrid, radius float64) (*detector.DetectorResult, error) {
+	bits, err := common.NewBitMatrix(maxicodecommon.MatrixWidth, maxicodecommon.MatrixHeight)
+	if err != nil {
+		return nil, err
+	}
+	for y := 0; y < maxicodecommon.MatrixHeight; y++ {
+		for x := 0; x < maxicodecommon.MatrixWidth; x++ {
+			px, py := grid.position(x, y)
+			dark, ok := d.sampleModule(px, py, radius)
+			if !ok {
+				return nil, core.ErrNotFound
+			}
+			if dark > 3 {
+				bits.Set(uint32(x), uint32(y))
+			}
+		}
+	}
+	points := make([]*core.ResultPoint, 0, 4)
+	for _, corner := range [][2]int{{0, 0}, {maxicodecommon.MatrixWidth - 1, 0}, {maxicodecommon.MatrixWidth - 1, maxicodecommon.MatrixHeight - 1}, {0, maxicodecommon.MatrixHeight - 1}} {
+		px, py := grid.position(corner[0], corner[1])
+		points = append(points, core.NewResultPoint(float32(px), float32(py)))
+	}
+	return detector.NewDetectorResult(bits, points), nil
+}
+
+func (d *Detector) isValid(x, y int) bool {
+	return x >= 0 && x < int(d.image.GetWidth()) && y >= 0 && y < int(d.image.GetHeight())
+}
+
+func (d *Detector) get(x, y int) bool {
+	return d.image.Get(uint32(x), uint32(y))
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector_test
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/internal"
+	maxicodecommon "github.com/discesoft/zxing-go/core/maxicode/common"
+	"github.com/discesoft/zxing-go/core/maxicode/detector"
+)
+
+// The radii of the edges of the bull's eye rings, in modules, outermost
+// first, and the radius inside which there are no modules.
+var (
+	ringRadii   = []float64{4.34, 3.59, 2.84, 2.09, 1.32, 0.57}
+	clearRadius = 5.4
+)
+
+// moduleOffset returns where module x of row y lies from the bull's eye,
+// in modules, with rows running along the x axis.
+func moduleOffset(x, y int) (float64, float64) {
+	return float64(x) - 14 + float64(y&0x01)/2, float64(y-16) * math.Sqrt(3) / 2
+}
+
+// buildModules sets the dark modules of the orientation clusters and
+// random data modules, leaving the ones around the bull's eye light.
+func buildModules(t *testing.T, random *rand.Rand) *common.BitMatrix {
+	modules, err := common.NewBitMatrix(maxicodecommon.MatrixWidth, maxicodecommon.MatrixHeight)
+	internal.AssertSuccess(t, err)
+	for y := 0; y < maxicodecommon.MatrixHeight; y++ {
+		for x := 0; x < maxicodecommon.MatrixWidth; x++ {
+			switch maxicodecommon.ModuleBits[y][x] {
+			case maxicodecommon.DarkModule:
+				modules.Set(uint32(x), uint32(y))
+				continue
+			case maxicodecommon.LightModule, maxicodecommon.NoModule:
+				continue
+			}
+			if ox, oy := moduleOffset(x, y); math.Hypot(ox, oy) < clearRadius {
+				continue
+			}
+			if random.Intn(2) == 1 {
+				modules.Set(uint32(x), uint32(y))
+			}
+		}
+	}
+	return modules
+}
+
+// render draws modules as round dots pitch pixels apart around a bull's
+// eye at the center of an image of imageSize pixels square, with the rows
+// turned by angle radians.
+func render(t *testing.T, modules *common.BitMatrix, pitch, angle float64, imageSize int) *common.BitMatrix {
+	image, err := common.NewBitMatrixFromDimension(uint32(imageSize))
+	internal.AssertSuccess(t, err)
+	center := float64(imageSize) / 2
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	for py := 0; py < imageSize; py++ {
+		for px := 0; px < imageSize; px++ {
+			// Turn the pixel back into module units
+			dx := (float64(px) + 0.5 - center) / pitch
+			dy := (float64(py) + 0.5 - center) / pitch
+			mx := dx*cos + dy*sin
+			my := -dx*sin + dy*cos
+
+			if r := math.Hypot(mx, my); r < ringRadii[0] {
+				ring := 0
+				for ring+1 < len(ringRadii) && r < ringRadii[ring+1] {
+					ring++
+				}
+				if ring%2 == 0 {
+					image.Set(uint32(px), uint32(py))
+				}
+				continue
+			}
+			row := int(math.Floor(my/(math.Sqrt(3)/2)+0.5)) + 16
+			for y := row - 1; y <= row+1; y++ {
+				if y < 0 || y >= maxicodecommon.MatrixHeight {
+					continue
+				}
+				x := int(math.Floor(mx + 14 - float64(y&0x01)/2 + 0.5))
+				if x < 0 || x >= maxicodecommon.MatrixWidth {
+					continue
+				}
+				ox, oy := moduleOffset(x, y)
+				if math.Hypot(mx-ox, my-oy) < 0.5 && modules.Get(uint32(x), uint32(y)) {
+					image.Set(uint32(px), uint32(py))
+				}
+			}
+		}
+	}
+	return image
+}
+
+// sameModules compares the modules outside the bull's eye.
+func sameModules(expected, actual *common.BitMatrix) bool {
+	for y := 0; y < maxicodecommon.MatrixHeight; y++ {
+		for x := 0; x < maxicodecommon.MatrixWidth; x++ {
+			if ox, oy := moduleOffset(x, y); math.Hypot(ox, oy) < clearRadius {
+				continue
+			}
+			if expected.Get(uint32(x), uint32(y)) != actual.Get(uint32(x), uint32(y)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func assertDetects(t *testing.T, pitch, degrees float64, seed int64) {
+	modules := buildModules(t, rand.New(rand.NewSource(seed)))
+	image := render(t, modules, pitch, degrees*math.Pi/180, int(pitch*42))
+	description := strconv.FormatFloat(pitch, 'f', -1, 64) + " pixel modules at " + strconv.FormatFloat(degrees, 'f', -1, 64) + " degrees"
+
+	result, err := detector.NewDetector(image).Detect()
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 4, len(result.GetPoints()), "expected four corner points")
+	internal.AssertTrue(t, sameModules(modules, result.GetBits()), "sampled modules differ for "+description+":\n"+result.GetBits().String())
+}
+
+func TestDetector_Upright(t *testing.T) {
+	assertDetects(t, 8, 0, 1)
+	assertDetects(t, 12, 0, 2)
+}
+
+func TestDetector_Rotated(t *testing.T) {
+	for i, degrees := range []float64{7, 30, 45, 60, 100, 137, 180, 222.5, 300, 347} {
+		assertDetects(t, 9, degrees, int64(i+10))
+	}
+}
+
+func TestDetector_Scaled(t *testing.T) {
+	for i, pitch := range []float64{6, 7.5, 10.3, 15} {
+		assertDetects(t, pitch, 20, int64(i+20))
+	}
+}
+
+func TestDetector_NotFound(t *testing.T) {
+	image, err := common.NewBitMatrixFromDimension(200)
+	internal.AssertSuccess(t, err)
+	_, err = detector.NewDetector(image).Detect()
+	internal.AssertFailure(t, err, "expected no MaxiCode in a blank image")
+
+	// A symbol cut off by the edge of the image
+	modules := buildModules(t, rand.New(rand.NewSource(5)))
+	image = render(t, modules, 8, 0, 8*42)
+	cropped, err := common.NewBitMatrix(8*42, 8*26)
+	internal.AssertSuccess(t, err)
+	for y := uint32(0); y < cropped.GetHeight(); y++ {
+		for x := uint32(0); x < cropped.GetWidth(); x++ {
+			if image.Get(x, y+8*8) {
+				cropped.Set(x, y)
+			}
+		}
+	}
+	_, err = detector.NewDetector(cropped).Detect()
+	internal.AssertFailure(t, err, "expected a cut off MaxiCode to be rejected")
+}
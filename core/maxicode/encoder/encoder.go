@@ -0,0 +1,205 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/discesoft/zxing-go/core/common/reedsolomon"
+	maxicodecommon "github.com/discesoft/zxing-go/core/maxicode/common"
+)
+
+const (
+	gs = "\u001D"
+	// header starts an ANSI MH10.8.3 message, followed by a two digit
+	// year
+	header = "[)>\u001E01\u001D"
+)
+
+// FormatStructuredCarrierMessage formats the structured carrier message
+// of modes 2 and 3 the way the decoder reports it, ready to have the rest
+// of the message appended.
+func FormatStructuredCarrierMessage(postcode string, country, serviceClass int) string {
+	return postcode + gs + padDigits(country, 3) + gs + padDigits(serviceClass, 3) + gs
+}
+
+// Encode encodes contents as the maxicodecommon.NumCodewords codewords of
+// a MaxiCode in mode, from 2 to 6, with their error correction.
+// In modes 2 and 3 contents should start with a structured carrier
+// message as formatted by FormatStructuredCarrierMessage, or have one
+// right after the "[)>RS01GSyy" header of an ANSI MH10.8.3 message. Mode
+// 2 takes a numeric postcode of up to 9 digits, and mode 3 an
+// alphanumeric one of up to 6 characters.
+// It returns an error if contents does not fit in the mode.
+func Encode(contents string, mode int) ([]uint8, error) {
+	primary := make([]uint8, 10)
+	var message string
+	dataCodewords, ecCodewords := 84, 40
+	switch mode {
+	case 2, 3:
+		var err error
+		message, err = encodeStructuredCarrierMessage(primary, contents, mode)
+		if err != nil {
+			return nil, err
+		}
+	case 4, 6:
+		message = contents
+	case 5:
+		message = contents
+		dataCodewords, ecCodewords = 68, 56
+	default:
+		return nil, errors.New("MaxiCode mode must be between 2 and 6, but was " + strconv.Itoa(mode))
+	}
+	primary[0] |= uint8(mode)
+
+	capacity := dataCodewords
+	if mode >= 4 {
+		// The message begins in the primary message
+		capacity += 9
+	}
+	encoded, err := encodeHighLevel(message, capacity)
+	if err != nil {
+		return nil, err
+	}
+	if mode >= 4 {
+		copy(primary[1:], encoded[:9])
+		encoded = encoded[9:]
+	}
+	return generateErrorCorrection(primary, encoded, ecCodewords)
+}
+
+// encodeStructuredCarrierMessage packs the postcode, country code and
+// service class at the start of contents into primary.
+// It returns the rest of the message, with the header, if any.
+func encodeStructuredCarrierMessage(primary []uint8, contents string, mode int) (string, error) {
+	prefix := ""
+	if strings.HasPrefix(contents, header) && len(contents) >= len(header)+2 {
+		prefix = contents[:len(header)+2]
+		contents = contents[len(header)+2:]
+	}
+	fields := strings.SplitN(contents, gs, 4)
+	if len(fields) != 4 {
+		return "", errors.New("MaxiCode mode " + strconv.Itoa(mode) + " requires a structured carrier message")
+	}
+	country, err := parseField(fields[1], "country code")
+	if err != nil {
+		return "", err
+	}
+	serviceClass, err := parseField(fields[2], "service class")
+	if err != nil {
+		return "", err
+	}
+	postcode := fields[0]
+	if mode == 2 {
+		if len(postcode) == 0 || len(postcode) > 9 || !isDigits(postcode) {
+			return "", errors.New("Postcode of mode 2 must be 1 to 9 digits: " + postcode)
+		}
+		value, _ := strconv.Atoi(postcode)
+		setInt(primary, maxicodecommon.PostCode2Bits, value)
+		setInt(primary, maxicodecommon.PostCode2LengthBits, len(postcode))
+	} else {
+		if len(postcode) > len(maxicodecommon.PostCode3Bits) {
+			return "", errors.New("Postcode of mode 3 must be up to 6 characters: " + postcode)
+		}
+		postcode += strings.Repeat(" ", len(maxicodecommon.PostCode3Bits)-len(postcode))
+		for i, bits := range maxicodecommon.PostCode3Bits {
+			codeword, ok := codewordIn(setA, postcode[i])
+			if !ok {
+				return "", errors.New("Postcode of mode 3 contains a character not in code set A: " + postcode)
+			}
+			setInt(primary, bits, int(codeword))
+		}
+	}
+	setInt(primary, maxicodecommon.CountryBits, country)
+	setInt(primary, maxicodecommon.ServiceClassBits, serviceClass)
+	return prefix + fields[3], nil
+}
+
+// parseField parses a country code or service class of up to 3 digits.
+func parseField(field, name string) (int, error) {
+	if len(field) == 0 || len(field) > 3 || !isDigits(field) {
+		return 0, errors.New("MaxiCode " + name + " must be 1 to 3 digits: " + field)
+	}
+	value, _ := strconv.Atoi(field)
+	return value, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// padDigits formats value with at least length digits.
+func padDigits(value, length int) string {
+	digits := strconv.Itoa(value)
+	for len(digits) < length {
+		digits = "0" + digits
+	}
+	return digits
+}
+
+// setInt stores value into the given bits of bytes, most significant bit
+// first.
+func setInt(bytes []uint8, bits []int, value int) {
+	for i := len(bits) - 1; i >= 0; i-- {
+		bit := bits[i] - 1
+		if value&1 != 0 {
+			bytes[bit/6] |= 1 << uint(5-bit%6)
+		}
+		value >>= 1
+	}
+}
+
+// generateErrorCorrection lays out the primary message and the secondary
+// message data codewords, each followed by their error correction. The
+// secondary message is corrected as two interleaved halves.
+func generateErrorCorrection(primary, secondary []uint8, ecCodewords int) ([]uint8, error) {
+	encoder := reedsolomon.NewReedSolomonEncoder(reedsolomon.MaxiCodeField64)
+	codewords := make([]uint8, maxicodecommon.NumCodewords)
+
+	block := make([]int, 20)
+	for i, c := range primary {
+		block[i] = int(c)
+	}
+	if err := encoder.Encode(block, 10); err != nil {
+		return nil, err
+	}
+	for i, c := range block {
+		codewords[i] = uint8(c)
+	}
+
+	dataCodewords := len(secondary)
+	for half := 0; half < 2; half++ {
+		block = make([]int, (dataCodewords+ecCodewords)/2)
+		for i := 0; i < dataCodewords/2; i++ {
+			block[i] = int(secondary[2*i+half])
+		}
+		if err := encoder.Encode(block, ecCodewords/2); err != nil {
+			return nil, err
+		}
+		for i, c := range block {
+			codewords[20+2*i+half] = uint8(c)
+		}
+	}
+	return codewords, nil
+}
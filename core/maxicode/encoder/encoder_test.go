@@ -0,0 +1,118 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/internal"
+	maxicodecommon "github.com/discesoft/zxing-go/core/maxicode/common"
+	"github.com/discesoft/zxing-go/core/maxicode/decoder"
+	"github.com/discesoft/zxing-go/core/maxicode/encoder"
+)
+
+func assertRoundTrip(t *testing.T, contents string, mode int) {
+	codewords, err := encoder.Encode(contents, mode)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, maxicodecommon.NumCodewords, len(codewords), "codewords of "+contents)
+	modules, err := encoder.PlaceCodewords(codewords)
+	internal.AssertSuccess(t, err)
+	result, err := decoder.NewDecoder().Decode(modules)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, contents, result.GetText(), "decoded "+result.GetText()+" instead of "+contents)
+	internal.AssertEquals(t, 0, result.GetErrorsCorrected(), "errors corrected")
+}
+
+func TestEncoder_Mode2(t *testing.T) {
+	scm := encoder.FormatStructuredCarrierMessage("152382802", 840, 1)
+	internal.AssertEquals(t, "152382802\u001D840\u001D001\u001D", scm, "structured carrier message")
+	assertRoundTrip(t, scm+"1Z00004951\u001DUPSN\u001D06X610\u001D159\u001D1234567\u001D1/1\u001D\u001DY\u001D634 ALPHA DR\u001DPITTSBURGH\u001DPA\u001E\u0004", 2)
+	assertRoundTrip(t, "[)>\u001E01\u001D96"+encoder.FormatStructuredCarrierMessage("01234", 56, 999)+"Leading zero", 2)
+}
+
+func TestEncoder_Mode3(t *testing.T) {
+	assertRoundTrip(t, encoder.FormatStructuredCarrierMessage("B1050", 56, 999)+"CANADA", 3)
+	assertRoundTrip(t, "[)>\u001E01\u001D96"+encoder.FormatStructuredCarrierMessage("W1A 1A", 826, 12)+"London", 3)
+}
+
+func TestEncoder_Modes4To6(t *testing.T) {
+	assertRoundTrip(t, "MaxiCode (19 chars)", 4)
+	assertRoundTrip(t, "Enhanced error correction", 5)
+	assertRoundTrip(t, "Reader programming", 6)
+	assertRoundTrip(t, "", 4)
+}
+
+func TestEncoder_CodeSets(t *testing.T) {
+	assertRoundTrip(t, "aBc DEF ghIJKLmnop, q.R/s:T", 4)
+	assertRoundTrip(t, "Ärger über Öl: àéîõü ÀÉÎÕÜ ×÷ ©®", 4)
+	assertRoundTrip(t, "\u0000\u0001\u001B\u001F tab\tline\r\n", 4)
+	// Every byte, a few at a time
+	var all []rune
+	for c := 0; c < 256; c++ {
+		all = append(all, rune(c))
+	}
+	for start := 0; start < len(all); start += 32 {
+		assertRoundTrip(t, string(all[start:start+32]), 4)
+	}
+}
+
+func TestEncoder_Numbers(t *testing.T) {
+	assertRoundTrip(t, "123456789", 4)
+	assertRoundTrip(t, "Tracking 1234567890123456789012345678901234567890", 4)
+	codewords, err := encoder.Encode("123456789", 4)
+	internal.AssertSuccess(t, err)
+	// NS and five codewords in place of nine digits, then padding
+	internal.AssertEquals(t, uint8(31), codewords[1], "NS")
+	internal.AssertEquals(t, uint8(33), codewords[7], "PAD")
+}
+
+func TestEncoder_ECI(t *testing.T) {
+	assertRoundTrip(t, "Grüße aus 東京", 4)
+	assertRoundTrip(t, encoder.FormatStructuredCarrierMessage("12345", 840, 1)+"Привет", 2)
+}
+
+func TestEncoder_Capacity(t *testing.T) {
+	assertRoundTrip(t, strings.Repeat("A", 93), 4)
+	assertRoundTrip(t, strings.Repeat("A", 77), 5)
+	assertRoundTrip(t, encoder.FormatStructuredCarrierMessage("1", 1, 1)+strings.Repeat("A", 84), 2)
+	_, err := encoder.Encode(strings.Repeat("A", 94), 4)
+	internal.AssertFailure(t, err, "93 characters fit in mode 4")
+	_, err = encoder.Encode(strings.Repeat("A", 78), 5)
+	internal.AssertFailure(t, err, "77 characters fit in mode 5")
+	_, err = encoder.Encode(encoder.FormatStructuredCarrierMessage("1", 1, 1)+strings.Repeat("A", 85), 2)
+	internal.AssertFailure(t, err, "84 characters fit in mode 2")
+}
+
+func TestEncoder_Invalid(t *testing.T) {
+	_, err := encoder.Encode("mode", 1)
+	internal.AssertFailure(t, err, "mode 1 does not exist")
+	_, err = encoder.Encode("mode", 7)
+	internal.AssertFailure(t, err, "mode 7 does not exist")
+	_, err = encoder.Encode("no carrier message", 2)
+	internal.AssertFailure(t, err, "mode 2 needs a structured carrier message")
+	_, err = encoder.Encode(encoder.FormatStructuredCarrierMessage("1234567890", 840, 1), 2)
+	internal.AssertFailure(t, err, "mode 2 postcode is at most 9 digits")
+	_, err = encoder.Encode(encoder.FormatStructuredCarrierMessage("B1050", 840, 1), 2)
+	internal.AssertFailure(t, err, "mode 2 postcode is numeric")
+	_, err = encoder.Encode(encoder.FormatStructuredCarrierMessage("b1050", 840, 1), 3)
+	internal.AssertFailure(t, err, "mode 3 postcode is in code set A")
+	_, err = encoder.Encode(encoder.FormatStructuredCarrierMessage("B10500", 1000, 1), 3)
+	internal.AssertFailure(t, err, "country code is at most 3 digits")
+	_, err = encoder.PlaceCodewords(make([]uint8, 143))
+	internal.AssertFailure(t, err, "a MaxiCode holds 144 codewords")
+}
@@ -0,0 +1,246 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"errors"
+	"strconv"
+	"unicode/utf8"
+)
+
+// The code sets, and the codewords for switching between them. The shift
+// to code set C, D or E from another set is the same codeword as the lock
+// into it once shifted there.
+const (
+	setA = iota
+	setB
+	setC
+	setD
+	setE
+)
+
+const (
+	eciCodeword       = 27
+	nsCodeword        = 31
+	padCodeword       = 33
+	shiftBCodeword    = 59
+	shiftACodeword    = 59
+	twoShiftACodeword = 56
+	latchACodeword    = 58 // from code sets C, D and E
+	latchBCodeword    = 63 // from code sets A, C, D and E
+	latchAFromB       = 63
+
+	// utf8ECI designates UTF-8 for characters beyond ISO-8859-1
+	utf8ECI = 26
+)
+
+// control marks the codewords of a code set which are not characters.
+const control = "\uFFFF"
+
+var codeSets = [][]rune{
+	[]rune("\rABCDEFGHIJKLMNOPQRSTUVWXYZ" + control + "\u001C\u001D\u001E" + control + " " + control +
+		"\"#$%&'()*+,-./0123456789:" + control + control + control + control + control),
+	[]rune("`abcdefghijklmnopqrstuvwxyz" + control + "\u001C\u001D\u001E" + control + "{" + control +
+		"}~\u007F;<=>?[\\]^_ ,./:@!|" + control + control + control + control + control + control + control + control + control),
+	[]rune("ÀÁÂÃÄÅÆÇÈÉÊËÌÍÎÏÐÑÒÓÔÕÖ×ØÙÚ" + control + "\u001C\u001D\u001E" + control +
+		"ÛÜÝÞßª¬±²³µ¹º¼½¾\u0080\u0081\u0082\u0083\u0084\u0085\u0086\u0087\u0088\u0089" +
+		control + " " + control + control + control + control),
+	[]rune("àáâãäåæçèéêëìíîïðñòóôõö÷øùú" + control + "\u001C\u001D\u001E" + control +
+		"ûüýþÿ¡¨«¯°´·¸»¿\u008A\u008B\u008C\u008D\u008E\u008F\u0090\u0091\u0092\u0093\u0094" +
+		control + " " + control + control + control + control),
+	[]rune("\u0000\u0001\u0002\u0003\u0004\u0005\u0006\u0007\u0008\u0009\n\u000B\u000C\r\u000E\u000F\u0010\u0011\u0012\u0013\u0014\u0015\u0016\u0017\u0018\u0019\u001A" +
+		control + control + control + "\u001B" + control + "\u001C\u001D\u001E" +
+		"\u001F\u009F\u00A0¢£¤¥¦§©\u00AD®¶\u0095\u0096\u0097\u0098\u0099\u009A\u009B\u009C\u009D\u009E" +
+		control + " " + control + control + control + control),
+}
+
+// codewords maps each set and byte to its codeword plus one, or zero if the
+// byte is not in the set.
+var codewords = func() [][256]uint8 {
+	table := make([][256]uint8, len(codeSets))
+	for set, characters := range codeSets {
+		for codeword, c := range characters {
+			if c < 256 && table[set][c] == 0 {
+				table[set][c] = uint8(codeword + 1)
+			}
+		}
+	}
+	return table
+}()
+
+// codewordIn returns the codeword for b in set, and whether there is one.
+func codewordIn(set int, b uint8) (uint8, bool) {
+	codeword := codewords[set][b]
+	return codeword - 1, codeword != 0
+}
+
+// highLevelEncoder turns text into codewords, switching code sets as it
+// goes.
+type highLevelEncoder struct {
+	text      []uint8
+	codewords []uint8
+	set       int
+}
+
+// encodeHighLevel encodes message into exactly capacity codewords,
+// padding as needed. Text beyond ISO-8859-1 is encoded as UTF-8 after an
+// ECI.
+// It returns an error if message does not fit.
+func encodeHighLevel(message string, capacity int) ([]uint8, error) {
+	e := &highLevelEncoder{}
+	if isLatin1(message) {
+		for _, r := range message {
+			e.text = append(e.text, uint8(r))
+		}
+	} else {
+		e.text = []uint8(message)
+		e.codewords = append(e.codewords, eciCodeword, utf8ECI)
+	}
+	e.encode()
+
+	if len(e.codewords) < capacity && e.set != setA && e.set != setB {
+		// Only code sets A and B can pad
+		e.codewords = append(e.codewords, latchACodeword)
+	}
+	if len(e.codewords) > capacity {
+		return nil, errors.New("Message needs " + strconv.Itoa(len(e.codewords)) +
+			" codewords, more than the " + strconv.Itoa(capacity) + " available")
+	}
+	for len(e.codewords) < capacity {
+		e.codewords = append(e.codewords, padCodeword)
+	}
+	return e.codewords, nil
+}
+
+func isLatin1(message string) bool {
+	for _, r := range message {
+		if r > 0xFF || r == utf8.RuneError {
+			return false
+		}
+	}
+	return true
+}
+
+// encode encodes each byte of the text in turn, in the current code set
+// where possible and otherwise shifting or latching to the first set
+// holding it.
+func (e *highLevelEncoder) encode() {
+	for i := 0; i < len(e.text); {
+		if digits := e.countDigits(i); digits >= 9 {
+			e.encodeNumber(i)
+			i += 9
+			continue
+		}
+		b := e.text[i]
+		if codeword, ok := codewordIn(e.set, b); ok {
+			e.codewords = append(e.codewords, codeword)
+			i++
+			continue
+		}
+		target := setA
+		for ; target < setE; target++ {
+			if _, ok := codewordIn(target, b); ok {
+				break
+			}
+		}
+		run := e.countInSet(i, target)
+		switch {
+		case target == setA && e.set == setB:
+			switch run {
+			case 1:
+				e.codewords = append(e.codewords, shiftACodeword)
+			case 2, 3:
+				// 2 or 3 Shift A
+				e.codewords = append(e.codewords, twoShiftACodeword+uint8(run-2))
+			default:
+				e.codewords = append(e.codewords, latchAFromB)
+				e.set = setA
+				continue
+			}
+			e.encodeInSet(i, run, setA)
+			i += run
+		case target == setA:
+			e.codewords = append(e.codewords, latchACodeword)
+			e.set = setA
+		case target == setB && (e.set != setA || run > 1):
+			e.codewords = append(e.codewords, latchBCodeword)
+			e.set = setB
+		case target == setB:
+			e.codewords = append(e.codewords, shiftBCodeword)
+			e.encodeInSet(i, 1, setB)
+			i++
+		default:
+			// Shift to code set C, D or E, and lock there for a run
+			shift := uint8(latchACodeword + target)
+			e.codewords = append(e.codewords, shift)
+			if run >= 3 {
+				e.codewords = append(e.codewords, shift)
+				e.set = target
+				continue
+			}
+			e.encodeInSet(i, 1, target)
+			i++
+		}
+	}
+}
+
+// encodeInSet appends the codewords of count bytes from start, all of
+// which are in set.
+func (e *highLevelEncoder) encodeInSet(start, count, set int) {
+	for i := start; i < start+count; i++ {
+		codeword, _ := codewordIn(set, e.text[i])
+		e.codewords = append(e.codewords, codeword)
+	}
+}
+
+// encodeNumber appends the 9 digits from start as an NS codeword followed
+// by their value in 5 codewords.
+func (e *highLevelEncoder) encodeNumber(start int) {
+	value := 0
+	for _, digit := range e.text[start : start+9] {
+		value = value*10 + int(digit-'0')
+	}
+	e.codewords = append(e.codewords, nsCodeword)
+	for shift := uint(24); ; shift -= 6 {
+		e.codewords = append(e.codewords, uint8(value>>shift&0x3F))
+		if shift == 0 {
+			break
+		}
+	}
+}
+
+// countDigits returns the number of consecutive digits from start.
+func (e *highLevelEncoder) countDigits(start int) int {
+	i := start
+	for i < len(e.text) && e.text[i] >= '0' && e.text[i] <= '9' {
+		i++
+	}
+	return i - start
+}
+
+// countInSet returns the number of consecutive bytes from start which are
+// in set.
+func (e *highLevelEncoder) countInSet(start, set int) int {
+	i := start
+	for i < len(e.text) {
+		if _, ok := codewordIn(set, e.text[i]); !ok {
+			break
+		}
+		i++
+	}
+	return i - start
+}
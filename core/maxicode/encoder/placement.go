@@ -0,0 +1,54 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core/common"
+	maxicodecommon "github.com/discesoft/zxing-go/core/maxicode/common"
+)
+
+// PlaceCodewords lays out the maxicodecommon.NumCodewords codewords of a
+// MaxiCode, as returned by Encode, in a maxicodecommon.MatrixWidth by
+// maxicodecommon.MatrixHeight grid of modules following
+// maxicodecommon.ModuleBits, where a set bit is a dark module. The dark
+// modules of the orientation clusters are set too.
+// It returns an error if there are not maxicodecommon.NumCodewords
+// codewords.
+func PlaceCodewords(codewords []uint8) (*common.BitMatrix, error) {
+	if len(codewords) != maxicodecommon.NumCodewords {
+		return nil, errors.New("MaxiCode requires " + strconv.Itoa(maxicodecommon.NumCodewords) + " codewords, but got " + strconv.Itoa(len(codewords)))
+	}
+	modules, err := common.NewBitMatrix(maxicodecommon.MatrixWidth, maxicodecommon.MatrixHeight)
+	if err != nil {
+		return nil, err
+	}
+	for y, row := range maxicodecommon.ModuleBits {
+		for x, bit := range row {
+			dark := bit == maxicodecommon.DarkModule
+			if bit >= 0 {
+				dark = codewords[bit/6]&(1<<uint(5-bit%6)) != 0
+			}
+			if dark {
+				modules.Set(uint32(x), uint32(y))
+			}
+		}
+	}
+	return modules, nil
+}
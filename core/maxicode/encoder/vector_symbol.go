@@ -0,0 +1,138 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"errors"
+	"math"
+
+	"github.com/discesoft/zxing-go/core/common"
+	maxicodecommon "github.com/discesoft/zxing-go/core/maxicode/common"
+)
+
+// rowPitch is the distance between rows, in module widths.
+var rowPitch = math.Sqrt(3) / 2
+
+// RingRadii are the radii of the edges of the bull's eye rings, in module
+// widths, outermost first: each dark ring lies between an even and the
+// following odd radius.
+var RingRadii = []float64{4.34, 3.59, 2.84, 2.09, 1.32, 0.57}
+
+// VectorSymbol describes a MaxiCode as shapes, measured in module widths
+// from the top left corner of the symbol.
+type VectorSymbol struct {
+	// Width and Height are the size of the symbol.
+	Width, Height float64
+	// Hexagons holds the centers of the dark modules.
+	Hexagons [][2]float64
+	// HexagonRadius is the distance from the center of a hexagon to its
+	// corners, which point up and down.
+	HexagonRadius float64
+	// CenterX and CenterY are the center of the bull's eye.
+	CenterX, CenterY float64
+}
+
+// NewVectorSymbol lays out modules, a maxicodecommon.MatrixWidth by
+// maxicodecommon.MatrixHeight grid where a set bit is a dark module, as
+// hexagons around the bull's eye.
+// The hexagons are drawn slightly smaller than the grid, leaving a narrow
+// light gap between neighbours.
+// It returns an error if modules is not of the grid's size.
+func NewVectorSymbol(modules *common.BitMatrix) (*VectorSymbol, error) {
+	if modules.GetWidth() != maxicodecommon.MatrixWidth || modules.GetHeight() != maxicodecommon.MatrixHeight {
+		return nil, errors.New("MaxiCode modules must be a 30x33 grid")
+	}
+	radius := 0.95 / math.Sqrt(3)
+	symbol := &VectorSymbol{
+		Width:         maxicodecommon.MatrixWidth,
+		Height:        (maxicodecommon.MatrixHeight-1)*rowPitch + 2/math.Sqrt(3),
+		HexagonRadius: radius,
+		CenterX:       0.5 + 14,
+		CenterY:       1/math.Sqrt(3) + 16*rowPitch,
+	}
+	for y := 0; y < maxicodecommon.MatrixHeight; y++ {
+		for x := 0; x < maxicodecommon.MatrixWidth; x++ {
+			if modules.Get(uint32(x), uint32(y)) {
+				symbol.Hexagons = append(symbol.Hexagons, [2]float64{
+					float64(x) + 0.5 + float64(y&0x01)/2,
+					1/math.Sqrt(3) + float64(y)*rowPitch,
+				})
+			}
+		}
+	}
+	return symbol, nil
+}
+
+// HexagonCorners returns the six corners of the hexagon centered at
+// center, starting from the top and going clockwise.
+func (s *VectorSymbol) HexagonCorners(center [2]float64) [6][2]float64 {
+	var corners [6][2]float64
+	for i := range corners {
+		angle := float64(i)*math.Pi/3 - math.Pi/2
+		corners[i] = [2]float64{center[0] + s.HexagonRadius*math.Cos(angle), center[1] + s.HexagonRadius*math.Sin(angle)}
+	}
+	return corners
+}
+
+// Rasterize draws the symbol with modules moduleWidth pixels wide,
+// surrounded by a light margin of margin pixels.
+// It returns an error if moduleWidth is not positive.
+func (s *VectorSymbol) Rasterize(moduleWidth float64, margin int) (*common.BitMatrix, error) {
+	if moduleWidth <= 0 {
+		return nil, errors.New("module width must be positive")
+	}
+	width := int(math.Ceil(s.Width*moduleWidth)) + 2*margin
+	height := int(math.Ceil(s.Height*moduleWidth)) + 2*margin
+	image, err := common.NewBitMatrix(uint32(width), uint32(height))
+	if err != nil {
+		return nil, err
+	}
+	offset := float64(margin)
+
+	// Pixels are dark where their center is within a hexagon
+	radius := s.HexagonRadius * moduleWidth
+	halfWidth := radius * math.Sqrt(3) / 2
+	for _, center := range s.Hexagons {
+		cx := offset + center[0]*moduleWidth
+		cy := offset + center[1]*moduleWidth
+		for py := int(cy - radius); py <= int(cy+radius); py++ {
+			for px := int(cx - halfWidth); px <= int(cx+halfWidth); px++ {
+				dx := math.Abs(float64(px) + 0.5 - cx)
+				dy := math.Abs(float64(py) + 0.5 - cy)
+				if dx <= halfWidth && dy <= radius-dx/math.Sqrt(3) {
+					image.Set(uint32(px), uint32(py))
+				}
+			}
+		}
+	}
+
+	// and within a dark ring of the bull's eye
+	cx := offset + s.CenterX*moduleWidth
+	cy := offset + s.CenterY*moduleWidth
+	outer := RingRadii[0] * moduleWidth
+	for py := int(cy - outer); py <= int(cy+outer); py++ {
+		for px := int(cx - outer); px <= int(cx+outer); px++ {
+			distance := math.Hypot(float64(px)+0.5-cx, float64(py)+0.5-cy) / moduleWidth
+			for ring := 0; ring < len(RingRadii); ring += 2 {
+				if distance < RingRadii[ring] && distance >= RingRadii[ring+1] {
+					image.Set(uint32(px), uint32(py))
+				}
+			}
+		}
+	}
+	return image, nil
+}
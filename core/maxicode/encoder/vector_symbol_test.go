@@ -0,0 +1,93 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/internal"
+	maxicodecommon "github.com/discesoft/zxing-go/core/maxicode/common"
+	"github.com/discesoft/zxing-go/core/maxicode/detector"
+	"github.com/discesoft/zxing-go/core/maxicode/encoder"
+)
+
+// nearBullsEye reports whether module x of row y would overlap the
+// bull's eye or the light space around it.
+func nearBullsEye(x, y int) bool {
+	dx := float64(x) - 14 + float64(y&0x01)/2
+	dy := float64(y-16) * math.Sqrt(3) / 2
+	return math.Hypot(dx, dy) < 5.4
+}
+
+func TestVectorSymbol_Layout(t *testing.T) {
+	modules, err := common.NewBitMatrix(maxicodecommon.MatrixWidth, maxicodecommon.MatrixHeight)
+	internal.AssertSuccess(t, err)
+	modules.Set(0, 0)
+	modules.Set(28, 1)
+	symbol, err := encoder.NewVectorSymbol(modules)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 2, len(symbol.Hexagons), "dark modules")
+	internal.AssertEquals(t, 0.5, symbol.Hexagons[0][0], "first module x")
+	internal.AssertEquals(t, 29.0, symbol.Hexagons[1][0], "odd row offset")
+	internal.AssertEquals(t, 14.5, symbol.CenterX, "bull's eye x")
+	internal.AssertTrue(t, math.Abs(symbol.Hexagons[1][1]-symbol.Hexagons[0][1]-math.Sqrt(3)/2) < 1e-9, "row pitch")
+	corners := symbol.HexagonCorners(symbol.Hexagons[0])
+	internal.AssertTrue(t, math.Abs(corners[0][0]-0.5) < 1e-9 && corners[0][1] < symbol.Hexagons[0][1], "first corner at the top")
+
+	wrong, err := common.NewBitMatrix(33, 30)
+	internal.AssertSuccess(t, err)
+	_, err = encoder.NewVectorSymbol(wrong)
+	internal.AssertFailure(t, err, "grid is 30 modules wide")
+}
+
+func TestVectorSymbol_RasterizeAndDetect(t *testing.T) {
+	random := rand.New(rand.NewSource(49))
+	modules, err := common.NewBitMatrix(maxicodecommon.MatrixWidth, maxicodecommon.MatrixHeight)
+	internal.AssertSuccess(t, err)
+	for y := 0; y < maxicodecommon.MatrixHeight; y++ {
+		for x := 0; x < maxicodecommon.MatrixWidth; x++ {
+			switch bit := maxicodecommon.ModuleBits[y][x]; {
+			case bit == maxicodecommon.DarkModule:
+				modules.Set(uint32(x), uint32(y))
+			case bit >= 0 && random.Intn(2) == 1:
+				modules.Set(uint32(x), uint32(y))
+			}
+		}
+	}
+	symbol, err := encoder.NewVectorSymbol(modules)
+	internal.AssertSuccess(t, err)
+	image, err := symbol.Rasterize(8, 16)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, uint32(8*30+32), image.GetWidth(), "image width")
+
+	result, err := detector.NewDetector(image).Detect()
+	internal.AssertSuccess(t, err)
+	bits := result.GetBits()
+	for y := 0; y < maxicodecommon.MatrixHeight; y++ {
+		for x := 0; x < maxicodecommon.MatrixWidth; x++ {
+			if !nearBullsEye(x, y) {
+				internal.AssertEquals(t, modules.Get(uint32(x), uint32(y)), bits.Get(uint32(x), uint32(y)), "module read back")
+			}
+		}
+	}
+
+	_, err = symbol.Rasterize(0, 0)
+	internal.AssertFailure(t, err, "module width must be positive")
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2011 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package maxicode
+
+import (
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	maxicodecommon "github.com/discesoft/zxing-go/core/maxicode/common"
+	"github.com/discesoft/zxing-go/core/maxicode/decoder"
+	"github.com/discesoft/zxing-go/core/maxicode/detector"
+)
+
+// MaxiCodeReader can decode MaxiCodes in an image.
+type MaxiCodeReader struct {
+	decoder *decoder.Decoder
+}
+
+func NewMaxiCodeReader() *MaxiCodeReader {
+	return &MaxiCodeReader{decoder.NewDecoder()}
+}
+
+// Decode locates and decodes a MaxiCode in an image. With
+// core.DecodeHintPureBarcode the image must hold only an unrotated,
+// unskewed MaxiCode with white space around it.
+// It returns the decoded Result, core.ErrNotFound if no MaxiCode is
+// found, core.ErrFormat if the MaxiCode cannot be decoded, or
+// core.ErrChecksum if error correction fails.
+func (r *MaxiCodeReader) Decode(image *common.BitMatrix, hints map[core.DecodeHintType]interface{}) (*core.Result, error) {
+	var decoderResult *common.DecoderResult
+	var points []*core.ResultPoint
+	if _, ok := hints[core.DecodeHintPureBarcode]; ok {
+		bits, err := extractPureBits(image)
+		if err != nil {
+			return nil, err
+		}
+		decoderResult, err = r.decoder.Decode(bits)
+		if err != nil {
+			return nil, err
+		}
+		points = []*core.ResultPoint{}
+	} else {
+		detectorResult, err := detector.NewDetector(image).Detect()
+		if err != nil {
+			return nil, err
+		}
+		decoderResult, err = r.decoder.Decode(detectorResult.GetBits())
+		if err != nil {
+			return nil, err
+		}
+		points = detectorResult.GetPoints()
+	}
+	result := core.NewResult(decoderResult.GetText(), decoderResult.GetRawBytes(), points, core.MaxiCode)
+	result.PutMetadata(core.ResultMetadataErrorsCorrected, decoderResult.GetErrorsCorrected())
+	if ecLevel := decoderResult.GetECLevel(); ecLevel != "" {
+		result.PutMetadata(core.ResultMetadataErrorCorrectionLevel, ecLevel)
+	}
+	result.PutMetadata(core.ResultMetadataSymbologyIdentifier, "]U"+strconv.Itoa(decoderResult.GetSymbologyModifier()))
+	return result, nil
+}
+
+func (r *MaxiCodeReader) Reset() {
+	// do nothing
+}
+
+// extractPureBits samples the modules of a MaxiCode which fills the
+// enclosing rectangle of the dark pixels of image, taking odd rows to be
+// offset by half a module.
+// It returns core.ErrNotFound if image has no dark pixels.
+func extractPureBits(image *common.BitMatrix) (*common.BitMatrix, error) {
+	enclosingRectangle := image.GetEnclosingRectangle()
+	if enclosingRectangle == nil {
+		return nil, core.ErrNotFound
+	}
+	left := int(enclosingRectangle[0])
+	top := int(enclosingRectangle[1])
+	width := int(enclosingRectangle[2])
+	height := int(enclosingRectangle[3])
+
+	// Now just read off the bits
+	bits, err := common.NewBitMatrix(maxicodecommon.MatrixWidth, maxicodecommon.MatrixHeight)
+	if err != nil {
+		return nil, err
+	}
+	for y := 0; y < maxicodecommon.MatrixHeight; y++ {
+		iy := top + minInt((y*height+height/2)/maxicodecommon.MatrixHeight, height-1)
+		for x := 0; x < maxicodecommon.MatrixWidth; x++ {
+			// The formula can walk off the image if left + width is the
+			// right boundary, so cap it
+			ix := left + minInt((x*width+width/2+(y&0x01)*width/2)/maxicodecommon.MatrixWidth, width-1)
+			if image.Get(uint32(ix), uint32(iy)) {
+				bits.Set(uint32(x), uint32(y))
+			}
+		}
+	}
+	return bits, nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package maxicode_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/common/reedsolomon"
+	"github.com/discesoft/zxing-go/core/internal"
+	"github.com/discesoft/zxing-go/core/maxicode"
+	maxicodecommon "github.com/discesoft/zxing-go/core/maxicode/common"
+)
+
+const padCodeword = 33
+
+// buildSymbol returns the codewords of a mode 4 or 6 symbol holding
+// message, which may only hold capital letters and spaces of code set A.
+func buildSymbol(t *testing.T, mode uint8, message string) []uint8 {
+	data := []uint8{mode}
+	for _, c := range message {
+		if c == ' ' {
+			data = append(data, 32)
+		} else {
+			data = append(data, uint8(strings.IndexRune("\rABCDEFGHIJKLMNOPQRSTUVWXYZ", c)))
+		}
+	}
+	encoder := reedsolomon.NewReedSolomonEncoder(reedsolomon.MaxiCodeField64)
+	symbol := make([]uint8, maxicodecommon.NumCodewords)
+
+	// The primary message, then the even and odd halves of the secondary
+	// message, each with their error correction
+	primary := make([]int, 20)
+	for i := range primary[:10] {
+		primary[i] = padCodeword
+		if i < len(data) {
+			primary[i] = int(data[i])
+		}
+	}
+	internal.AssertSuccess(t, encoder.Encode(primary, 10))
+	for i, c := range primary {
+		symbol[i] = uint8(c)
+	}
+	for half := 0; half < 2; half++ {
+		block := make([]int, 62)
+		for i := 0; i < 42; i++ {
+			block[i] = padCodeword
+			if j := 10 + 2*i + half; j < len(data) {
+				block[i] = int(data[j])
+			}
+		}
+		internal.AssertSuccess(t, encoder.Encode(block, 20))
+		for i, c := range block {
+			symbol[20+2*i+half] = uint8(c)
+		}
+	}
+	return symbol
+}
+
+// renderPure draws the modules of a MaxiCode holding codewords as
+// rectangles moduleWidth pixels wide and rowHeight pixels high, odd rows
+// offset by half a module, the way extractPureBits expects them.
+func renderPure(t *testing.T, codewords []uint8, moduleWidth, rowHeight int) *common.BitMatrix {
+	image, err := common.NewBitMatrix(uint32(maxicodecommon.MatrixWidth*moduleWidth), uint32(maxicodecommon.MatrixHeight*rowHeight))
+	internal.AssertSuccess(t, err)
+	for y := 0; y < maxicodecommon.MatrixHeight; y++ {
+		for x := 0; x < maxicodecommon.MatrixWidth; x++ {
+			bit := maxicodecommon.ModuleBits[y][x]
+			if bit == maxicodecommon.DarkModule || bit >= 0 && codewords[bit/6]&(1<<uint(5-bit%6)) != 0 {
+				left := x*moduleWidth + (y&0x01)*moduleWidth/2
+				internal.AssertSuccess(t, image.SetRegion(uint32(left), uint32(y*rowHeight), uint32(moduleWidth), uint32(rowHeight)))
+			}
+		}
+	}
+	return image
+}
+
+var pureHints = map[core.DecodeHintType]interface{}{core.DecodeHintPureBarcode: true}
+
+func TestMaxiCodeReader_Pure(t *testing.T) {
+	image := renderPure(t, buildSymbol(t, 4, "READ FROM A PURE IMAGE"), 6, 5)
+	result, err := maxicode.NewMaxiCodeReader().Decode(image, pureHints)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "READ FROM A PURE IMAGE", result.GetText(), "text")
+	internal.AssertEquals(t, core.MaxiCode, result.GetBarcodeFormat(), "format")
+	metadata := result.GetResultMetadata()
+	internal.AssertEquals(t, "4", metadata[core.ResultMetadataErrorCorrectionLevel], "mode")
+	internal.AssertEquals(t, "]U0", metadata[core.ResultMetadataSymbologyIdentifier], "symbology identifier")
+	internal.AssertEquals(t, 0, metadata[core.ResultMetadataErrorsCorrected], "errors corrected")
+
+	image = renderPure(t, buildSymbol(t, 6, "READER PROGRAMMING"), 8, 7)
+	result, err = maxicode.NewMaxiCodeReader().Decode(image, pureHints)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "READER PROGRAMMING", result.GetText(), "text")
+	internal.AssertEquals(t, "6", result.GetResultMetadata()[core.ResultMetadataErrorCorrectionLevel], "mode")
+}
+
+func TestMaxiCodeReader_NotFound(t *testing.T) {
+	image, err := common.NewBitMatrixFromDimension(100)
+	internal.AssertSuccess(t, err)
+	_, err = maxicode.NewMaxiCodeReader().Decode(image, pureHints)
+	internal.AssertEquals(t, core.ErrNotFound, err, "blank image")
+	_, err = maxicode.NewMaxiCodeReader().Decode(image, nil)
+	internal.AssertEquals(t, core.ErrNotFound, err, "blank image without hints")
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package maxicode
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/maxicode/encoder"
+)
+
+const (
+	// defaultMode is the mode used unless core.EncodeHintMaxiCodeMode
+	// gives another, standard symbol with no structured carrier message.
+	defaultMode = 4
+
+	// minModuleWidth is the narrowest module, in pixels, that the
+	// hexagons and rings are drawn with.
+	minModuleWidth = 6
+)
+
+// MaxiCodeWriter renders a MaxiCode as a BitMatrix.
+type MaxiCodeWriter struct{}
+
+func NewMaxiCodeWriter() *MaxiCodeWriter {
+	return &MaxiCodeWriter{}
+}
+
+// Encode encodes contents as a MaxiCode, as large as fits within width by
+// height pixels where possible, and centered in them.
+// The core.EncodeHintMaxiCodeMode hint selects the mode, 4 by default; see
+// encoder.Encode for what modes 2 and 3 expect contents to start with.
+// The core.EncodeHintMargin hint gives the light margin around the symbol
+// in pixels, one module wide by default.
+// It returns an error if contents cannot be encoded.
+func (w *MaxiCodeWriter) Encode(contents string, format core.BarcodeFormat, width, height int, hints map[core.EncodeHintType]interface{}) (*common.BitMatrix, error) {
+	if format != core.MaxiCode {
+		return nil, errors.New("Can only encode MAXICODE, but got " + strconv.Itoa(int(format)))
+	}
+
+	mode := defaultMode
+	if requestedMode, ok := hints[core.EncodeHintMaxiCodeMode].(int); ok {
+		mode = requestedMode
+	}
+	codewords, err := encoder.Encode(contents, mode)
+	if err != nil {
+		return nil, err
+	}
+	modules, err := encoder.PlaceCodewords(codewords)
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := encoder.NewVectorSymbol(modules)
+	if err != nil {
+		return nil, err
+	}
+
+	var moduleWidth float64
+	margin, hasMargin := hints[core.EncodeHintMargin].(int)
+	if hasMargin {
+		moduleWidth = math.Min(float64(width-2*margin)/symbol.Width, float64(height-2*margin)/symbol.Height)
+	} else {
+		moduleWidth = math.Min(float64(width)/(symbol.Width+2), float64(height)/(symbol.Height+2))
+	}
+	moduleWidth = math.Max(moduleWidth, minModuleWidth)
+	if !hasMargin {
+		margin = int(moduleWidth)
+	}
+	image, err := symbol.Rasterize(moduleWidth, margin)
+	if err != nil {
+		return nil, err
+	}
+	return center(image, width, height)
+}
+
+// center places image in the middle of width by height pixels, or returns
+// it as it is if it is not smaller than them.
+func center(image *common.BitMatrix, width, height int) (*common.BitMatrix, error) {
+	imageWidth := int(image.GetWidth())
+	imageHeight := int(image.GetHeight())
+	if imageWidth >= width && imageHeight >= height {
+		return image, nil
+	}
+	if imageWidth > width {
+		width = imageWidth
+	}
+	if imageHeight > height {
+		height = imageHeight
+	}
+	leftPadding := (width - imageWidth) / 2
+	topPadding := (height - imageHeight) / 2
+
+	output, err := common.NewBitMatrix(uint32(width), uint32(height))
+	if err != nil {
+		return nil, err
+	}
+	for y := 0; y < imageHeight; y++ {
+		for x := 0; x < imageWidth; x++ {
+			if image.Get(uint32(x), uint32(y)) {
+				output.Set(uint32(leftPadding+x), uint32(topPadding+y))
+			}
+		}
+	}
+	return output, nil
+}
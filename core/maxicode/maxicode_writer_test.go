@@ -0,0 +1,96 @@
+/*
+ * Copyright 2026 zxing-go authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package maxicode_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/internal"
+	"github.com/discesoft/zxing-go/core/maxicode"
+	"github.com/discesoft/zxing-go/core/maxicode/encoder"
+)
+
+func encodeAndRead(t *testing.T, contents string, width, height int, hints map[core.EncodeHintType]interface{}) *core.Result {
+	image, err := maxicode.NewMaxiCodeWriter().Encode(contents, core.MaxiCode, width, height, hints)
+	internal.AssertSuccess(t, err)
+	result, err := maxicode.NewMaxiCodeReader().Decode(image, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, contents, result.GetText(), "decoded "+strconv.Quote(result.GetText())+" instead of "+strconv.Quote(contents))
+	return result
+}
+
+func TestMaxiCodeWriter_RoundTrip(t *testing.T) {
+	for _, contents := range []string{
+		"A",
+		"Hello, World!",
+		"aBc DEF ghIJKLmnop, q.R/s:T 0123456789",
+	} {
+		result := encodeAndRead(t, contents, 250, 250, nil)
+		metadata := result.GetResultMetadata()
+		internal.AssertEquals(t, "4", metadata[core.ResultMetadataErrorCorrectionLevel], "default mode of "+strconv.Quote(contents))
+		internal.AssertEquals(t, "]U0", metadata[core.ResultMetadataSymbologyIdentifier], "symbology identifier of "+strconv.Quote(contents))
+	}
+}
+
+func TestMaxiCodeWriter_Modes(t *testing.T) {
+	contents := map[int]string{
+		2: encoder.FormatStructuredCarrierMessage("152382802", 840, 1) + "1Z00004951\u001DUPSN\u001D06X610\u001D159",
+		3: encoder.FormatStructuredCarrierMessage("B1050", 56, 999) + "CANADA",
+		5: "Enhanced error correction",
+		6: "Reader programming",
+	}
+	for mode, message := range contents {
+		hints := map[core.EncodeHintType]interface{}{core.EncodeHintMaxiCodeMode: mode}
+		result := encodeAndRead(t, message, 300, 300, hints)
+		internal.AssertEquals(t, strconv.Itoa(mode), result.GetResultMetadata()[core.ResultMetadataErrorCorrectionLevel], "mode")
+	}
+}
+
+func TestMaxiCodeWriter_Size(t *testing.T) {
+	writer := maxicode.NewMaxiCodeWriter()
+	image, err := writer.Encode("Centered", core.MaxiCode, 400, 300, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, uint32(400), image.GetWidth(), "requested width")
+	internal.AssertEquals(t, uint32(300), image.GetHeight(), "requested height")
+	rectangle := image.GetEnclosingRectangle()
+	internal.AssertTrue(t, rectangle[0] > 50 && rectangle[0]+rectangle[2] < 350, "symbol should be centered horizontally")
+
+	// Too small an image grows to fit modules of the smallest width
+	image, err = writer.Encode("Grown", core.MaxiCode, 10, 10, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertTrue(t, image.GetWidth() >= 6*30, "image should grow to fit the symbol")
+	result, err := maxicode.NewMaxiCodeReader().Decode(image, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "Grown", result.GetText(), "text")
+
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintMargin: 0}
+	image, err = writer.Encode("No margin", core.MaxiCode, 300, 300, hints)
+	internal.AssertSuccess(t, err)
+	rectangle = image.GetEnclosingRectangle()
+	internal.AssertTrue(t, rectangle[0] < 2, "symbol should fill the width without a margin")
+}
+
+func TestMaxiCodeWriter_Invalid(t *testing.T) {
+	writer := maxicode.NewMaxiCodeWriter()
+	_, err := writer.Encode("format", core.Aztec, 100, 100, nil)
+	internal.AssertFailure(t, err, "only MaxiCode can be encoded")
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintMaxiCodeMode: 7}
+	_, err = writer.Encode("mode", core.MaxiCode, 100, 100, hints)
+	internal.AssertFailure(t, err, "mode 7 does not exist")
+}
@@ -0,0 +1,523 @@
+/*
+ * Copyright 2008 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oned
+
+import (
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+// code128Patterns holds the widths of the bars and spaces of each Code 128
+// symbol character, by value. The stop pattern has a seventh, final bar.
+var code128Patterns = [][]int{
+	{2, 1, 2, 2, 2, 2},    // 0
+	{2, 2, 2, 1, 2, 2},    // 1
+	{2, 2, 2, 2, 2, 1},    // 2
+	{1, 2, 1, 2, 2, 3},    // 3
+	{1, 2, 1, 3, 2, 2},    // 4
+	{1, 3, 1, 2, 2, 2},    // 5
+	{1, 2, 2, 2, 1, 3},    // 6
+	{1, 2, 2, 3, 1, 2},    // 7
+	{1, 3, 2, 2, 1, 2},    // 8
+	{2, 2, 1, 2, 1, 3},    // 9
+	{2, 2, 1, 3, 1, 2},    // 10
+	{2, 3, 1, 2, 1, 2},    // 11
+	{1, 1, 2, 2, 3, 2},    // 12
+	{1, 2, 2, 1, 3, 2},    // 13
+	{1, 2, 2, 2, 3, 1},    // 14
+	{1, 1, 3, 2, 2, 2},    // 15
+	{1, 2, 3, 1, 2, 2},    // 16
+	{1, 2, 3, 2, 2, 1},    // 17
+	{2, 2, 3, 2, 1, 1},    // 18
+	{2, 2, 1, 1, 3, 2},    // 19
+	{2, 2, 1, 2, 3, 1},    // 20
+	{2, 1, 3, 2, 1, 2},    // 21
+	{2, 2, 3, 1, 1, 2},    // 22
+	{3, 1, 2, 1, 3, 1},    // 23
+	{3, 1, 1, 2, 2, 2},    // 24
+	{3, 2, 1, 1, 2, 2},    // 25
+	{3, 2, 1, 2, 2, 1},    // 26
+	{3, 1, 2, 2, 1, 2},    // 27
+	{3, 2, 2, 1, 1, 2},    // 28
+	{3, 2, 2, 2, 1, 1},    // 29
+	{2, 1, 2, 1, 2, 3},    // 30
+	{2, 1, 2, 3, 2, 1},    // 31
+	{2, 3, 2, 1, 2, 1},    // 32
+	{1, 1, 1, 3, 2, 3},    // 33
+	{1, 3, 1, 1, 2, 3},    // 34
+	{1, 3, 1, 3, 2, 1},    // 35
+	{1, 1, 2, 3, 1, 3},    // 36
+	{1, 3, 2, 1, 1, 3},    // 37
+	{1, 3, 2, 3, 1, 1},    // 38
+	{2, 1, 1, 3, 1, 3},    // 39
+	{2, 3, 1, 1, 1, 3},    // 40
+	{2, 3, 1, 3, 1, 1},    // 41
+	{1, 1, 2, 1, 3, 3},    // 42
+	{1, 1, 2, 3, 3, 1},    // 43
+	{1, 3, 2, 1, 3, 1},    // 44
+	{1, 1, 3, 1, 2, 3},    // 45
+	{1, 1, 3, 3, 2, 1},    // 46
+	{1, 3, 3, 1, 2, 1},    // 47
+	{3, 1, 3, 1, 2, 1},    // 48
+	{2, 1, 1, 3, 3, 1},    // 49
+	{2, 3, 1, 1, 3, 1},    // 50
+	{2, 1, 3, 1, 1, 3},    // 51
+	{2, 1, 3, 3, 1, 1},    // 52
+	{2, 1, 3, 1, 3, 1},    // 53
+	{3, 1, 1, 1, 2, 3},    // 54
+	{3, 1, 1, 3, 2, 1},    // 55
+	{3, 3, 1, 1, 2, 1},    // 56
+	{3, 1, 2, 1, 1, 3},    // 57
+	{3, 1, 2, 3, 1, 1},    // 58
+	{3, 3, 2, 1, 1, 1},    // 59
+	{3, 1, 4, 1, 1, 1},    // 60
+	{2, 2, 1, 4, 1, 1},    // 61
+	{4, 3, 1, 1, 1, 1},    // 62
+	{1, 1, 1, 2, 2, 4},    // 63
+	{1, 1, 1, 4, 2, 2},    // 64
+	{1, 2, 1, 1, 2, 4},    // 65
+	{1, 2, 1, 4, 2, 1},    // 66
+	{1, 4, 1, 1, 2, 2},    // 67
+	{1, 4, 1, 2, 2, 1},    // 68
+	{1, 1, 2, 2, 1, 4},    // 69
+	{1, 1, 2, 4, 1, 2},    // 70
+	{1, 2, 2, 1, 1, 4},    // 71
+	{1, 2, 2, 4, 1, 1},    // 72
+	{1, 4, 2, 1, 1, 2},    // 73
+	{1, 4, 2, 2, 1, 1},    // 74
+	{2, 4, 1, 2, 1, 1},    // 75
+	{2, 2, 1, 1, 1, 4},    // 76
+	{4, 1, 3, 1, 1, 1},    // 77
+	{2, 4, 1, 1, 1, 2},    // 78
+	{1, 3, 4, 1, 1, 1},    // 79
+	{1, 1, 1, 2, 4, 2},    // 80
+	{1, 2, 1, 1, 4, 2},    // 81
+	{1, 2, 1, 2, 4, 1},    // 82
+	{1, 1, 4, 2, 1, 2},    // 83
+	{1, 2, 4, 1, 1, 2},    // 84
+	{1, 2, 4, 2, 1, 1},    // 85
+	{4, 1, 1, 2, 1, 2},    // 86
+	{4, 2, 1, 1, 1, 2},    // 87
+	{4, 2, 1, 2, 1, 1},    // 88
+	{2, 1, 2, 1, 4, 1},    // 89
+	{2, 1, 4, 1, 2, 1},    // 90
+	{4, 1, 2, 1, 2, 1},    // 91
+	{1, 1, 1, 1, 4, 3},    // 92
+	{1, 1, 1, 3, 4, 1},    // 93
+	{1, 3, 1, 1, 4, 1},    // 94
+	{1, 1, 4, 1, 1, 3},    // 95
+	{1, 1, 4, 3, 1, 1},    // 96
+	{4, 1, 1, 1, 1, 3},    // 97
+	{4, 1, 1, 3, 1, 1},    // 98
+	{1, 1, 3, 1, 4, 1},    // 99
+	{1, 1, 4, 1, 3, 1},    // 100
+	{3, 1, 1, 1, 4, 1},    // 101
+	{4, 1, 1, 1, 3, 1},    // 102
+	{2, 1, 1, 4, 1, 2},    // 103
+	{2, 1, 1, 2, 1, 4},    // 104
+	{2, 1, 1, 2, 3, 2},    // 105
+	{2, 3, 3, 1, 1, 1, 2}, // 106
+}
+
+const (
+	code128MaxAvgVariance        = 0.25
+	code128MaxIndividualVariance = 0.7
+
+	code128Shift   = 98
+	code128CodeC   = 99
+	code128CodeB   = 100
+	code128CodeA   = 101
+	code128FNC1    = 102
+	code128FNC2    = 97
+	code128FNC3    = 96
+	code128FNC4A   = 101
+	code128FNC4B   = 100
+	code128StartA  = 103
+	code128StartB  = 104
+	code128StartC  = 105
+	code128Stop    = 106
+	code128Modulus = 103
+)
+
+// Code128Reader decodes Code 128 barcodes.
+type Code128Reader struct{}
+
+func NewCode128Reader() *Code128Reader {
+	return &Code128Reader{}
+}
+
+// Decode locates and decodes a Code 128 barcode in an image. With the
+// core.DecodeHintAssumeGS1 hint, an FNC1 in the first position is
+// returned as "]C1" and any later FNC1 as the GS character.
+// The result carries the symbology identifier "]C0", or "]C1" for
+// GS1-128, "]C2" for an FNC1 in the second position and "]C4" for FNC2.
+// It returns the decoded Result, or core.ErrNotFound, core.ErrFormat or
+// core.ErrChecksum if no barcode can be decoded.
+func (r *Code128Reader) Decode(image *common.BitMatrix, hints map[core.DecodeHintType]interface{}) (*core.Result, error) {
+	return decodeOneD(image, hints, r)
+}
+
+func (r *Code128Reader) Reset() {
+	// do nothing
+}
+
+// findStartPattern finds a start pattern preceded by a quiet zone at
+// least half as wide as it.
+// It returns where the pattern starts and ends in row and which start
+// code it is, or core.ErrNotFound.
+func findStartPattern(row *common.BitArray) ([3]int, error) {
+	width := int(row.GetSize())
+	rowOffset := int(row.GetNextSet(0))
+
+	counterPosition := 0
+	counters := make([]int, 6)
+	patternStart := rowOffset
+	isWhite := false
+	patternLength := len(counters)
+
+	for i := rowOffset; i < width; i++ {
+		if row.Get(uint32(i)) != isWhite {
+			counters[counterPosition]++
+		} else {
+			if counterPosition == patternLength-1 {
+				bestVariance := float32(code128MaxAvgVariance)
+				bestMatch := -1
+				for startCode := code128StartA; startCode <= code128StartC; startCode++ {
+					variance := patternMatchVariance(counters, code128Patterns[startCode], code128MaxIndividualVariance)
+					if variance < bestVariance {
+						bestVariance = variance
+						bestMatch = startCode
+					}
+				}
+				// Look for whitespace before start pattern, >= 50% of width of start pattern
+				if bestMatch >= 0 {
+					quietStart := patternStart - (i-patternStart)/2
+					if quietStart < 0 {
+						quietStart = 0
+					}
+					if quiet, _ := row.IsRange(uint32(quietStart), uint32(patternStart), false); quiet {
+						return [3]int{patternStart, i, bestMatch}, nil
+					}
+				}
+				patternStart += counters[0] + counters[1]
+				copy(counters, counters[2:counterPosition+1])
+				counters[counterPosition-1] = 0
+				counters[counterPosition] = 0
+				counterPosition--
+			} else {
+				counterPosition++
+			}
+			counters[counterPosition] = 1
+			isWhite = !isWhite
+		}
+	}
+	return [3]int{}, core.ErrNotFound
+}
+
+// decodeCode reads the symbol character starting at rowOffset, recording
+// its bar and space widths into counters.
+// It returns its value, or core.ErrNotFound if it matches none closely
+// enough.
+func decodeCode(row *common.BitArray, counters []int, rowOffset int) (int, error) {
+	if err := recordPattern(row, rowOffset, counters); err != nil {
+		return 0, err
+	}
+	bestVariance := float32(code128MaxAvgVariance) // worst variance we'll accept
+	bestMatch := -1
+	for d, pattern := range code128Patterns {
+		variance := patternMatchVariance(counters, pattern, code128MaxIndividualVariance)
+		if variance < bestVariance {
+			bestVariance = variance
+			bestMatch = d
+		}
+	}
+	// TODO We're overlooking the fact that the STOP pattern has 7 values, not 6.
+	if bestMatch < 0 {
+		return 0, core.ErrNotFound
+	}
+	return bestMatch, nil
+}
+
+func (r *Code128Reader) decodeRow(rowNumber int, row *common.BitArray, hints map[core.DecodeHintType]interface{}) (*core.Result, error) {
+	_, convertFNC1 := hints[core.DecodeHintAssumeGS1]
+
+	symbologyModifier := 0
+
+	startPatternInfo, err := findStartPattern(row)
+	if err != nil {
+		return nil, err
+	}
+	startCode := startPatternInfo[2]
+
+	rawCodes := make([]uint8, 0, 20)
+	rawCodes = append(rawCodes, uint8(startCode))
+
+	var codeSet int
+	switch startCode {
+	case code128StartA:
+		codeSet = code128CodeA
+	case code128StartB:
+		codeSet = code128CodeB
+	default:
+		codeSet = code128CodeC
+	}
+
+	done := false
+	isNextShifted := false
+
+	var result []rune
+
+	lastStart := startPatternInfo[0]
+	nextStart := startPatternInfo[1]
+	counters := make([]int, 6)
+
+	lastCode := 0
+	code := 0
+	checksumTotal := startCode
+	multiplier := 0
+	lastCharacterWasPrintable := true
+	upperMode := false
+	shiftUpperMode := false
+
+	// appendCharacter appends value, which is ASCII, or Latin-1 above
+	// it after FNC4
+	appendCharacter := func(value int) {
+		if shiftUpperMode != upperMode {
+			value += 128
+		}
+		result = append(result, rune(value))
+		shiftUpperMode = false
+	}
+	// fnc1 handles an FNC1, whose meaning depends on its position
+	fnc1 := func() {
+		switch len(result) {
+		case 0:
+			// GS1 specification 5.4.3.7. and 5.4.6.4. If the first char after the start code
+			// is FNC1 then this is GS1-128 barcode data and the symbology modifier is 1
+			symbologyModifier = 1
+		case 1:
+			// If the first char after the start code is a letter or digit, and then an FNC1,
+			// this is AIM associated data and the symbology modifier is 2
+			symbologyModifier = 2
+		}
+		if convertFNC1 {
+			if len(result) == 0 {
+				// GS1 specification 5.4.3.7. and 5.4.6.4. If the first char after the start code
+				// is FNC1 then this is GS1-128 barcode data
+				result = append(result, []rune("]C1")...)
+			} else {
+				// GS1 specification 5.4.7.5. Every subsequent FNC1 is returned as ASCII 29 (GS)
+				result = append(result, 29)
+			}
+		}
+	}
+	// fnc4 handles an FNC4, which shifts the next character up by 128,
+	// or latches or unlatches that when doubled
+	fnc4 := func() {
+		if !upperMode && shiftUpperMode {
+			upperMode = true
+			shiftUpperMode = false
+		} else if upperMode && shiftUpperMode {
+			upperMode = false
+			shiftUpperMode = false
+		} else {
+			shiftUpperMode = true
+		}
+	}
+
+	for !done {
+		unshift := isNextShifted
+		isNextShifted = false
+
+		// Save off last code
+		lastCode = code
+
+		// Decode another code from image
+		code, err = decodeCode(row, counters, nextStart)
+		if err != nil {
+			return nil, err
+		}
+
+		rawCodes = append(rawCodes, uint8(code))
+
+		// Remember whether the last code was printable or not (excluding CODE_STOP)
+		if code != code128Stop {
+			lastCharacterWasPrintable = true
+		}
+
+		// Add to checksum computation (if not CODE_STOP of course)
+		if code != code128Stop {
+			multiplier++
+			checksumTotal += multiplier * code
+		}
+
+		// Advance to where the next code will to start
+		lastStart = nextStart
+		for _, counter := range counters {
+			nextStart += counter
+		}
+
+		// Take care of illegal start codes
+		switch code {
+		case code128StartA, code128StartB, code128StartC:
+			return nil, core.ErrFormat
+		}
+
+		switch codeSet {
+		case code128CodeA:
+			if code < 64 {
+				appendCharacter(' ' + code)
+			} else if code < 96 {
+				appendCharacter(code - 64)
+			} else {
+				// Don't let CODE_STOP, which always appears, affect whether whether we think the last
+				// code was printable or not.
+				if code != code128Stop {
+					lastCharacterWasPrintable = false
+				}
+				switch code {
+				case code128FNC1:
+					fnc1()
+				case code128FNC2:
+					symbologyModifier = 4
+				case code128FNC3:
+					// do nothing?
+				case code128FNC4A:
+					fnc4()
+				case code128Shift:
+					isNextShifted = true
+					codeSet = code128CodeB
+				case code128CodeB:
+					codeSet = code128CodeB
+				case code128CodeC:
+					codeSet = code128CodeC
+				case code128Stop:
+					done = true
+				}
+			}
+		case code128CodeB:
+			if code < 96 {
+				appendCharacter(' ' + code)
+			} else {
+				if code != code128Stop {
+					lastCharacterWasPrintable = false
+				}
+				switch code {
+				case code128FNC1:
+					fnc1()
+				case code128FNC2:
+					symbologyModifier = 4
+				case code128FNC3:
+					// do nothing?
+				case code128FNC4B:
+					fnc4()
+				case code128Shift:
+					isNextShifted = true
+					codeSet = code128CodeA
+				case code128CodeA:
+					codeSet = code128CodeA
+				case code128CodeC:
+					codeSet = code128CodeC
+				case code128Stop:
+					done = true
+				}
+			}
+		case code128CodeC:
+			if code < 100 {
+				if code < 10 {
+					result = append(result, '0')
+				}
+				result = append(result, []rune(strconv.Itoa(code))...)
+			} else {
+				if code != code128Stop {
+					lastCharacterWasPrintable = false
+				}
+				switch code {
+				case code128FNC1:
+					fnc1()
+				case code128CodeA:
+					codeSet = code128CodeA
+				case code128CodeB:
+					codeSet = code128CodeB
+				case code128Stop:
+					done = true
+				}
+			}
+		}
+
+		// Unshift back to another code set if we were shifted
+		if unshift {
+			if codeSet == code128CodeA {
+				codeSet = code128CodeB
+			} else {
+				codeSet = code128CodeA
+			}
+		}
+	}
+
+	lastPatternSize := nextStart - lastStart
+
+	// Check for ample whitespace following pattern, but, to do this we first need to remember that
+	// we fudged decoding CODE_STOP since it actually has 7 bars, not 6. There is a black bar left
+	// to read off. Would be slightly better to properly read. Here we just skip it:
+	nextStart = int(row.GetNextUnset(uint32(nextStart)))
+	quietEnd := nextStart + (nextStart-lastStart)/2
+	if quietEnd > int(row.GetSize()) {
+		quietEnd = int(row.GetSize())
+	}
+	if quiet, _ := row.IsRange(uint32(nextStart), uint32(quietEnd), false); !quiet {
+		return nil, core.ErrNotFound
+	}
+
+	// Pull out from sum the value of the penultimate check code
+	checksumTotal -= multiplier * lastCode
+	// lastCode is the checksum then:
+	if checksumTotal%code128Modulus != lastCode {
+		return nil, core.ErrChecksum
+	}
+
+	// Need to pull out the check digits from string
+	resultLength := len(result)
+	if resultLength == 0 {
+		// false positive
+		return nil, core.ErrNotFound
+	}
+
+	// Only bother if the result had at least one character, and if the checksum digit happened to
+	// be a printable character. If it was just interpreted as a control code, nothing to remove.
+	if lastCharacterWasPrintable {
+		if codeSet == code128CodeC {
+			result = result[:resultLength-2]
+		} else {
+			result = result[:resultLength-1]
+		}
+	}
+
+	left := float32(startPatternInfo[1]+startPatternInfo[0]) / 2
+	right := float32(lastStart) + float32(lastPatternSize)/2
+
+	resultObject := core.NewResult(string(result), rawCodes,
+		[]*core.ResultPoint{
+			core.NewResultPoint(left, float32(rowNumber)),
+			core.NewResultPoint(right, float32(rowNumber)),
+		},
+		core.Code128)
+	resultObject.PutMetadata(core.ResultMetadataSymbologyIdentifier, "]C"+strconv.Itoa(symbologyModifier))
+	return resultObject, nil
+}
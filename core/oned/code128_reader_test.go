@@ -0,0 +1,113 @@
+/*
+ * Copyright 2008 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oned
+
+import (
+	"testing"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+// code128Image draws codes, start code first, followed by the stop
+// pattern, across an image with quiet zones of ten modules. The check
+// character is left to codes.
+func code128Image(t *testing.T, codes []int) *common.BitMatrix {
+	bars := make([]bool, 10+len(codes)*11+13+10)
+	pos := 10
+	for _, code := range append(codes, code128Stop) {
+		pos += appendPattern(bars, pos, code128Patterns[code], true)
+	}
+	image, err := common.NewBitMatrix(uint32(len(bars)), 10)
+	internal.AssertSuccess(t, err)
+	for x, bar := range bars {
+		if bar {
+			internal.AssertSuccess(t, image.SetRegion(uint32(x), 0, 1, 10))
+		}
+	}
+	return image
+}
+
+// withCheckCharacter appends the check character to codes.
+func withCheckCharacter(codes []int) []int {
+	checkSum := codes[0]
+	for i := 1; i < len(codes); i++ {
+		checkSum += i * codes[i]
+	}
+	return append(codes, checkSum%code128Modulus)
+}
+
+func TestCode128Reader_Decode(t *testing.T) {
+	// "A1" in code set B, then "2345" in code set C
+	codes := withCheckCharacter([]int{code128StartB, 'A' - ' ', '1' - ' ', code128CodeC, 23, 45})
+	result, err := NewCode128Reader().Decode(code128Image(t, codes), nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "A12345", result.GetText(), "text")
+	// The raw bytes run through the stop code
+	internal.AssertEquals(t, len(codes)+1, len(result.GetRawBytes()), "raw bytes")
+	internal.AssertEquals(t, 2, len(result.GetResultPoints()), "result points")
+	internal.AssertEquals(t, float32(5), result.GetResultPoints()[0].GetY(), "row")
+	_, ok := result.GetResultMetadata()[core.ResultMetadataOrientation]
+	internal.AssertFalse(t, ok, "orientation")
+}
+
+func TestCode128Reader_Checksum(t *testing.T) {
+	codes := withCheckCharacter([]int{code128StartA, 'A' - ' ', 'B' - ' '})
+	codes[len(codes)-1]++
+	image := code128Image(t, codes)
+	_, err := NewCode128Reader().decodeRow(5, image.GetRow(5, nil), nil)
+	internal.AssertEquals(t, core.ErrChecksum, err, "bad check character")
+	_, err = NewCode128Reader().Decode(image, nil)
+	internal.AssertEquals(t, core.ErrNotFound, err, "bad check character")
+}
+
+func TestCode128Reader_QuietZone(t *testing.T) {
+	codes := withCheckCharacter([]int{code128StartB, 'A' - ' ', 'B' - ' '})
+	image := code128Image(t, codes)
+	// A bar in the quiet zone before the start code hides it
+	internal.AssertSuccess(t, image.SetRegion(7, 0, 2, 10))
+	_, err := NewCode128Reader().Decode(image, nil)
+	internal.AssertFailure(t, err, "no leading quiet zone")
+}
+
+func TestCode128Reader_Orientation(t *testing.T) {
+	codes := withCheckCharacter([]int{code128StartB, 'Z', 'A' - ' ', 'B' - ' '})
+	image := code128Image(t, codes)
+	internal.AssertSuccess(t, image.Rotate180())
+	result, err := NewCode128Reader().Decode(image, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "zAB", result.GetText(), "upside down")
+	internal.AssertEquals(t, 180, result.GetResultMetadata()[core.ResultMetadataOrientation], "upside down")
+
+	image = code128Image(t, codes)
+	image.Rotate90()
+	_, err = NewCode128Reader().Decode(image, nil)
+	internal.AssertFailure(t, err, "rotated without trying harder")
+	hints := map[core.DecodeHintType]interface{}{core.DecodeHintTryHarder: true}
+	result, err = NewCode128Reader().Decode(image, hints)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "zAB", result.GetText(), "rotated")
+	internal.AssertEquals(t, 90, result.GetResultMetadata()[core.ResultMetadataOrientation], "rotated")
+}
+
+func TestCode128Reader_NotFound(t *testing.T) {
+	image, err := common.NewBitMatrix(100, 10)
+	internal.AssertSuccess(t, err)
+	_, err = NewCode128Reader().Decode(image, nil)
+	internal.AssertEquals(t, core.ErrNotFound, err, "blank image")
+}
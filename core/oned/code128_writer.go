@@ -0,0 +1,473 @@
+/*
+ * Copyright 2010 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oned
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+// Characters standing for the Code 128 function codes in contents given
+// to Code128Writer.
+const (
+	Code128EscapeFNC1 = 'ñ'
+	Code128EscapeFNC2 = 'ò'
+	Code128EscapeFNC3 = 'ó'
+	Code128EscapeFNC4 = 'ô'
+)
+
+// code128MaxLength is the most characters Code128Writer encodes.
+const code128MaxLength = 80
+
+// Results of minimal lookahead for code C
+type cType uint8
+
+const (
+	cTypeUncodable cType = iota
+	cTypeOneDigit
+	cTypeTwoDigits
+	cTypeFNC1
+)
+
+// Code128Writer renders a Code 128 barcode as a BitMatrix.
+type Code128Writer struct{}
+
+func NewCode128Writer() *Code128Writer {
+	return &Code128Writer{}
+}
+
+// Encode encodes contents as a Code 128 barcode, scaled by a whole number
+// of pixels per module to fit within width pixels where possible, and
+// height pixels high. Contents is ASCII, with the function codes written
+// as Code128EscapeFNC1 to Code128EscapeFNC4.
+// Code sets are chosen by looking ahead for runs of digits, or with the
+// core.EncodeHintCode128Compact hint set, to give the fewest symbol
+// characters; the core.EncodeHintForceCodeSet hint forces code set "A",
+// "B" or "C" throughout instead. With the core.EncodeHintGS1Format hint
+// set, contents is taken as GS1 data, in either human readable or raw
+// form, and encoded as GS1-128 with FNC1 in the first position and after
+// each variable-length element. core.EncodeHintMargin gives the total
+// width of the quiet zones, in modules.
+// It returns an error if contents cannot be encoded.
+func (w *Code128Writer) Encode(contents string, format core.BarcodeFormat, width, height int, hints map[core.EncodeHintType]interface{}) (*common.BitMatrix, error) {
+	if format != core.Code128 {
+		return nil, errors.New("Can only encode CODE_128, but got " + strconv.Itoa(int(format)))
+	}
+	if gs1, ok := hints[core.EncodeHintGS1Format].(bool); ok && gs1 && len(contents) > 0 {
+		elements, err := common.ParseGS1ElementString(contents)
+		if err != nil {
+			return nil, err
+		}
+		contents = string(Code128EscapeFNC1) + strings.Replace(common.FormatGS1ElementString(elements),
+			string(rune(common.GS1GroupSeparator)), string(Code128EscapeFNC1), -1)
+	}
+	return encodeOneD(contents, width, height, hints, func(contents string) ([]bool, error) {
+		return encodeCode128(contents, hints)
+	})
+}
+
+// encodeCode128 turns contents into the bars of a Code 128 barcode.
+func encodeCode128(contents string, hints map[core.EncodeHintType]interface{}) ([]bool, error) {
+	characters := []rune(contents)
+	forcedCodeSet, err := checkCode128(characters, hints)
+	if err != nil {
+		return nil, err
+	}
+	length := len(characters)
+	if length < 1 || length > code128MaxLength {
+		return nil, errors.New("Contents length should be between 1 and 80 characters, but got " + strconv.Itoa(length))
+	}
+	if compact, ok := hints[core.EncodeHintCode128Compact].(bool); ok && compact {
+		return produceCode128Result(encodeCode128Minimal(characters))
+	}
+	codes, err := encodeCode128Fast(characters, forcedCodeSet)
+	if err != nil {
+		return nil, err
+	}
+	return produceCode128Result(codes)
+}
+
+// checkCode128 checks that every character can be encoded, in the code
+// set forced by the core.EncodeHintForceCodeSet hint if there is one.
+// It returns the forced code set, or -1 if there is none.
+func checkCode128(characters []rune, hints map[core.EncodeHintType]interface{}) (int, error) {
+	// Check for forced code set hint.
+	forcedCodeSet := -1
+	if codeSetHint, ok := hints[core.EncodeHintForceCodeSet].(string); ok {
+		switch codeSetHint {
+		case "A":
+			forcedCodeSet = code128CodeA
+		case "B":
+			forcedCodeSet = code128CodeB
+		case "C":
+			forcedCodeSet = code128CodeC
+		default:
+			return 0, errors.New("Unsupported code set hint: " + codeSetHint)
+		}
+	}
+
+	// Check content
+	for _, c := range characters {
+		// check for non ascii characters that are not special GS1 characters
+		switch c {
+		case Code128EscapeFNC1, Code128EscapeFNC2, Code128EscapeFNC3, Code128EscapeFNC4:
+		default:
+			if c > 127 {
+				// no full Latin-1 character set available at the moment
+				// shift and manual code change are not supported
+				return 0, errors.New("Bad character in input: ASCII value=" + strconv.Itoa(int(c)))
+			}
+		}
+		// check characters for compatibility with forced code set
+		switch forcedCodeSet {
+		case code128CodeA:
+			// allows no ascii above 95 (no lower caps, no special symbols)
+			if c > 95 && c <= 127 {
+				return 0, errors.New("Bad character in input for forced code set A: ASCII value=" + strconv.Itoa(int(c)))
+			}
+		case code128CodeB:
+			// allows no ascii below 32 (terminal symbols)
+			if c < 32 {
+				return 0, errors.New("Bad character in input for forced code set B: ASCII value=" + strconv.Itoa(int(c)))
+			}
+		case code128CodeC:
+			// allows only numbers and no FNC 2/3/4
+			if c < 48 || (c > 57 && c <= 127) || c == Code128EscapeFNC2 || c == Code128EscapeFNC3 || c == Code128EscapeFNC4 {
+				return 0, errors.New("Bad character in input for forced code set C: ASCII value=" + strconv.Itoa(int(c)))
+			}
+		}
+	}
+	return forcedCodeSet, nil
+}
+
+// encodeCode128Fast chooses code sets by looking a little ahead, unless
+// forcedCodeSet is one.
+// It returns the symbol character values, start code first and without
+// the check character.
+func encodeCode128Fast(characters []rune, forcedCodeSet int) ([]int, error) {
+	length := len(characters)
+	var codes []int
+	codeSet := 0  // selected code (CODE_CODE_B or CODE_CODE_C)
+	position := 0 // position in contents
+
+	for position < length {
+		//Select code to use
+		newCodeSet := forcedCodeSet
+		if forcedCodeSet == -1 {
+			newCodeSet = chooseCode(characters, position, codeSet)
+		}
+
+		//Get the pattern index
+		var patternIndex int
+		if newCodeSet == codeSet {
+			// Encode the current character
+			// First handle escapes
+			switch characters[position] {
+			case Code128EscapeFNC1:
+				patternIndex = code128FNC1
+			case Code128EscapeFNC2:
+				patternIndex = code128FNC2
+			case Code128EscapeFNC3:
+				patternIndex = code128FNC3
+			case Code128EscapeFNC4:
+				if codeSet == code128CodeA {
+					patternIndex = code128FNC4A
+				} else {
+					patternIndex = code128FNC4B
+				}
+			default:
+				// Then handle normal characters otherwise
+				switch codeSet {
+				case code128CodeA:
+					patternIndex = int(characters[position] - ' ')
+					if patternIndex < 0 {
+						// everything below a space character comes behind the underscore in the code patterns table
+						patternIndex += '`'
+					}
+				case code128CodeB:
+					patternIndex = int(characters[position] - ' ')
+				default:
+					// CODE_CODE_C
+					if position+1 == length {
+						// this is the last character, but the encoding is C, which always encodes two characers
+						return nil, errors.New("Bad number of characters for digit only encoding.")
+					}
+					patternIndex = int(characters[position]-'0')*10 + int(characters[position+1]-'0')
+					position++ // Also incremented below
+				}
+			}
+			position++
+		} else {
+			// Should we change the current code?
+			// Do we have a code set?
+			if codeSet == 0 {
+				// No, we don't have a code set
+				switch newCodeSet {
+				case code128CodeA:
+					patternIndex = code128StartA
+				case code128CodeB:
+					patternIndex = code128StartB
+				default:
+					patternIndex = code128StartC
+				}
+			} else {
+				// Yes, we have a code set
+				patternIndex = newCodeSet
+			}
+			codeSet = newCodeSet
+		}
+		codes = append(codes, patternIndex)
+	}
+	return codes, nil
+}
+
+// produceCode128Result appends the check character and stop pattern to
+// codes, and lays out their bars.
+func produceCode128Result(codes []int) ([]bool, error) {
+	// Compute checksum
+	checkSum := codes[0]
+	for i := 1; i < len(codes); i++ {
+		checkSum += i * codes[i]
+	}
+	checkSum %= code128Modulus
+	if checkSum < 0 {
+		return nil, errors.New("Unable to compute a valid input checksum")
+	}
+	codes = append(codes, checkSum, code128Stop)
+
+	// Compute result
+	codeWidth := 0
+	for _, code := range codes {
+		for _, width := range code128Patterns[code] {
+			codeWidth += width
+		}
+	}
+	result := make([]bool, codeWidth)
+	pos := 0
+	for _, code := range codes {
+		pos += appendPattern(result, pos, code128Patterns[code], true)
+	}
+	return result, nil
+}
+
+func findCType(value []rune, start int) cType {
+	last := len(value)
+	if start >= last {
+		return cTypeUncodable
+	}
+	c := value[start]
+	if c == Code128EscapeFNC1 {
+		return cTypeFNC1
+	}
+	if c < '0' || c > '9' {
+		return cTypeUncodable
+	}
+	if start+1 >= last {
+		return cTypeOneDigit
+	}
+	c = value[start+1]
+	if c < '0' || c > '9' {
+		return cTypeOneDigit
+	}
+	return cTypeTwoDigits
+}
+
+func chooseCode(value []rune, start, oldCode int) int {
+	lookahead := findCType(value, start)
+	if lookahead == cTypeOneDigit {
+		if oldCode == code128CodeA {
+			return code128CodeA
+		}
+		return code128CodeB
+	}
+	if lookahead == cTypeUncodable {
+		if start < len(value) {
+			c := value[start]
+			if c < ' ' || (oldCode == code128CodeA && (c < '`' || (c >= Code128EscapeFNC1 && c <= Code128EscapeFNC4))) {
+				// can continue in code A, encodes ASCII 0 to 95 or FNC1 to FNC4
+				return code128CodeA
+			}
+		}
+		return code128CodeB // no choice
+	}
+	if oldCode == code128CodeA && lookahead == cTypeFNC1 {
+		return code128CodeA
+	}
+	if oldCode == code128CodeC { // can continue in code C
+		return code128CodeC
+	}
+	if oldCode == code128CodeB {
+		if lookahead == cTypeFNC1 {
+			return code128CodeB // can continue in code B
+		}
+		// Seen two consecutive digits, see what follows
+		lookahead = findCType(value, start+2)
+		if lookahead == cTypeUncodable || lookahead == cTypeOneDigit {
+			return code128CodeB // not worth switching now
+		}
+		if lookahead == cTypeFNC1 { // two digits, then FNC_1...
+			lookahead = findCType(value, start+3)
+			if lookahead == cTypeTwoDigits { // then two more digits, switch
+				return code128CodeC
+			}
+			return code128CodeB // otherwise not worth switching
+		}
+		// At this point, there are at least 4 consecutive digits.
+		// Look ahead to choose whether to switch now or on the next round.
+		index := start + 4
+		for {
+			lookahead = findCType(value, index)
+			if lookahead != cTypeTwoDigits {
+				break
+			}
+			index += 2
+		}
+		if lookahead == cTypeOneDigit { // odd number of digits, switch later
+			return code128CodeB
+		}
+		return code128CodeC // even number of digits, switch now
+	}
+	// Here oldCode == 0, which means we are choosing the initial code
+	if lookahead == cTypeFNC1 { // ignore FNC_1
+		lookahead = findCType(value, start+1)
+	}
+	if lookahead == cTypeTwoDigits { // at least two digits, start in code C
+		return code128CodeC
+	}
+	return code128CodeB
+}
+
+// Code sets indexing the tables of encodeCode128Minimal.
+const (
+	minimalSetA = iota
+	minimalSetB
+	minimalSetC
+)
+
+// Actions chosen by encodeCode128Minimal at each position.
+const (
+	minimalEncode = iota
+	minimalShift
+	minimalLatch
+)
+
+// code128Value returns the value encoding characters[position] in code
+// set, and the number of characters it encodes, or zero if the set cannot
+// encode them.
+func code128Value(characters []rune, position, set int) (int, int) {
+	c := characters[position]
+	switch c {
+	case Code128EscapeFNC1:
+		return code128FNC1, 1
+	case Code128EscapeFNC2, Code128EscapeFNC3, Code128EscapeFNC4:
+		if set == minimalSetC {
+			return 0, 0
+		}
+		switch c {
+		case Code128EscapeFNC2:
+			return code128FNC2, 1
+		case Code128EscapeFNC3:
+			return code128FNC3, 1
+		}
+		if set == minimalSetA {
+			return code128FNC4A, 1
+		}
+		return code128FNC4B, 1
+	}
+	switch set {
+	case minimalSetA:
+		if c < ' ' {
+			return int(c) + 64, 1
+		}
+		if c < '`' {
+			return int(c - ' '), 1
+		}
+	case minimalSetB:
+		if c >= ' ' {
+			return int(c - ' '), 1
+		}
+	default:
+		if findCType(characters, position) == cTypeTwoDigits {
+			return int(c-'0')*10 + int(characters[position+1]-'0'), 2
+		}
+	}
+	return 0, 0
+}
+
+// encodeCode128Minimal chooses the code sets, shifts and latches giving the
+// fewest symbol characters, working back from the end of characters.
+// It returns the symbol character values, start code first and without
+// the check character.
+func encodeCode128Minimal(characters []rune) []int {
+	const unreachable = 1 << 30
+	length := len(characters)
+	costs := make([][3]int, length+1)
+	actions := make([][3]int, length+1)
+	targets := make([][3]int, length+1)
+	for position := length - 1; position >= 0; position-- {
+		for set := minimalSetA; set <= minimalSetC; set++ {
+			best, action, target := unreachable, 0, set
+			if _, n := code128Value(characters, position, set); n > 0 {
+				best, action = 1+costs[position+n][set], minimalEncode
+			}
+			if set != minimalSetC {
+				if _, n := code128Value(characters, position, 1-set); n > 0 && 2+costs[position+1][set] < best {
+					best, action = 2+costs[position+1][set], minimalShift
+				}
+			}
+			for other := minimalSetA; other <= minimalSetC; other++ {
+				if other == set {
+					continue
+				}
+				if _, n := code128Value(characters, position, other); n > 0 && 2+costs[position+n][other] < best {
+					best, action, target = 2+costs[position+n][other], minimalLatch, other
+				}
+			}
+			costs[position][set], actions[position][set], targets[position][set] = best, action, target
+		}
+	}
+
+	// Start in the cheapest set, preferring B, then C, then A
+	set := minimalSetB
+	for _, candidate := range []int{minimalSetC, minimalSetA} {
+		if costs[0][candidate] < costs[0][set] {
+			set = candidate
+		}
+	}
+	codes := []int{[]int{code128StartA, code128StartB, code128StartC}[set]}
+	latches := []int{code128CodeA, code128CodeB, code128CodeC}
+	for position := 0; position < length; {
+		switch actions[position][set] {
+		case minimalShift:
+			value, _ := code128Value(characters, position, 1-set)
+			codes = append(codes, code128Shift, value)
+			position++
+			continue
+		case minimalLatch:
+			set = targets[position][set]
+			codes = append(codes, latches[set])
+		}
+		value, n := code128Value(characters, position, set)
+		codes = append(codes, value)
+		position += n
+	}
+	return codes
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright 2010 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oned_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/internal"
+	"github.com/discesoft/zxing-go/core/oned"
+)
+
+func encode(t *testing.T, contents string, hints map[core.EncodeHintType]interface{}) *common.BitMatrix {
+	hints = withMargin(hints)
+	matrix, err := oned.NewCode128Writer().Encode(contents, core.Code128, 0, 10, hints)
+	internal.AssertSuccess(t, err)
+	return matrix
+}
+
+// withMargin copies hints, leaving no quiet zones, so that the width of
+// an encoded barcode is its number of modules.
+func withMargin(hints map[core.EncodeHintType]interface{}) map[core.EncodeHintType]interface{} {
+	copied := map[core.EncodeHintType]interface{}{core.EncodeHintMargin: 0}
+	for hint, value := range hints {
+		copied[hint] = value
+	}
+	return copied
+}
+
+// symbolCharacters returns how many symbol characters, including the
+// start code and check character, make up matrix.
+func symbolCharacters(matrix *common.BitMatrix) int {
+	// Each symbol character is 11 modules wide and the stop pattern 13
+	return (int(matrix.GetWidth()) - 13) / 11
+}
+
+func decode(t *testing.T, matrix *common.BitMatrix, hints map[core.DecodeHintType]interface{}) *core.Result {
+	// Give the barcode quiet zones to be found in
+	image, err := common.NewBitMatrix(matrix.GetWidth()+20, matrix.GetHeight())
+	internal.AssertSuccess(t, err)
+	for y := uint32(0); y < matrix.GetHeight(); y++ {
+		for x := uint32(0); x < matrix.GetWidth(); x++ {
+			if matrix.Get(x, y) {
+				image.Set(x+10, y)
+			}
+		}
+	}
+	result, err := oned.NewCode128Reader().Decode(image, hints)
+	internal.AssertSuccess(t, err)
+	return result
+}
+
+func TestCode128Writer_RoundTrip(t *testing.T) {
+	for _, contents := range []string{
+		"Hello, World!",
+		"1234567890",
+		"A1234567890",
+		"123456789",
+		"\x01ABC\x1F",
+		"abc\x01def",
+		"12\x1Fab34",
+		"Code 128 \x7F~",
+	} {
+		for _, compact := range []bool{false, true} {
+			hints := map[core.EncodeHintType]interface{}{core.EncodeHintCode128Compact: compact}
+			result := decode(t, encode(t, contents, hints), nil)
+			internal.AssertEquals(t, contents, result.GetText(), "text")
+			internal.AssertEquals(t, core.Code128, result.GetBarcodeFormat(), "format")
+			internal.AssertEquals(t, "]C0", result.GetResultMetadata()[core.ResultMetadataSymbologyIdentifier], "symbology identifier")
+		}
+	}
+}
+
+func TestCode128Writer_FunctionCodes(t *testing.T) {
+	// FNC4 gives the Latin-1 character 128 above the next one
+	result := decode(t, encode(t, "cafôi", nil), nil)
+	internal.AssertEquals(t, "café", result.GetText(), "FNC4")
+
+	// FNC1 in the first position marks GS1 data
+	result = decode(t, encode(t, "ñ01234567890", nil), nil)
+	internal.AssertEquals(t, "01234567890", result.GetText(), "FNC1 first")
+	internal.AssertEquals(t, "]C1", result.GetResultMetadata()[core.ResultMetadataSymbologyIdentifier], "FNC1 first")
+
+	result = decode(t, encode(t, "òABC", nil), nil)
+	internal.AssertEquals(t, "ABC", result.GetText(), "FNC2")
+	internal.AssertEquals(t, "]C4", result.GetResultMetadata()[core.ResultMetadataSymbologyIdentifier], "FNC2")
+}
+
+func TestCode128Writer_GS1Format(t *testing.T) {
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintGS1Format: true}
+	decodeHints := map[core.DecodeHintType]interface{}{core.DecodeHintAssumeGS1: true}
+	expected := "]C10109501101530003" + "10ABC123\x1D" + "3103000250"
+
+	result := decode(t, encode(t, "(01)09501101530003(10)ABC123(3103)000250", hints), decodeHints)
+	internal.AssertEquals(t, expected, result.GetText(), "human readable")
+	internal.AssertEquals(t, "]C1", result.GetResultMetadata()[core.ResultMetadataSymbologyIdentifier], "human readable")
+
+	result = decode(t, encode(t, "010950110153000310ABC123\x1D3103000250", hints), decodeHints)
+	internal.AssertEquals(t, expected, result.GetText(), "raw")
+
+	// Without the hint, the reader leaves FNC1 out
+	result = decode(t, encode(t, "(01)09501101530003(10)ABC123(3103)000250", hints), nil)
+	internal.AssertEquals(t, "0109501101530003"+"10ABC123"+"3103000250", result.GetText(), "no GS1 hint")
+
+	_, err := oned.NewCode128Writer().Encode("(01)0950110153000", core.Code128, 0, 0, hints)
+	internal.AssertFailure(t, err, "short GTIN")
+}
+
+func TestCode128Writer_ForceCodeSet(t *testing.T) {
+	for _, test := range []struct {
+		codeSet  string
+		contents string
+	}{
+		{"A", "ABC\x01DEF"},
+		{"B", "abc123"},
+		{"C", "123456"},
+		{"C", "ñ1234"},
+	} {
+		hints := map[core.EncodeHintType]interface{}{core.EncodeHintForceCodeSet: test.codeSet}
+		matrix := encode(t, test.contents, hints)
+		// Every character takes a symbol character of its own, or two digits in code set C
+		expected := len([]rune(test.contents)) + 2
+		if test.codeSet == "C" {
+			expected = len(strings.Trim(test.contents, "ñ"))/2 + strings.Count(test.contents, "ñ") + 2
+		}
+		internal.AssertEquals(t, expected, symbolCharacters(matrix), test.codeSet+" "+test.contents)
+		result := decode(t, matrix, nil)
+		internal.AssertEquals(t, strings.Trim(test.contents, "ñ"), result.GetText(), test.codeSet+" "+test.contents)
+	}
+
+	for _, test := range []struct {
+		codeSet  string
+		contents string
+	}{
+		{"A", "abc"},
+		{"B", "AB\x01"},
+		{"C", "12a4"},
+		{"C", "123"},
+		{"C", "12ô"},
+		{"D", "1234"},
+	} {
+		hints := map[core.EncodeHintType]interface{}{core.EncodeHintForceCodeSet: test.codeSet}
+		_, err := oned.NewCode128Writer().Encode(test.contents, core.Code128, 0, 0, hints)
+		internal.AssertFailure(t, err, test.codeSet+" "+test.contents)
+	}
+}
+
+func TestCode128Writer_Compact(t *testing.T) {
+	for _, test := range []struct {
+		contents string
+		fast     int
+		compact  int
+	}{
+		// Shifting into code set A beats latching to it and back
+		{"a\x01b\x01c", 11, 9},
+		{"ABC\x01abc\x01ABC", 16, 15},
+		// Both latch to code set C for six digits between letters
+		{"a123456b", 9, 9},
+		{"\x01\x02abc\x03", 10, 10},
+	} {
+		fast := encode(t, test.contents, nil)
+		compact := encode(t, test.contents, map[core.EncodeHintType]interface{}{core.EncodeHintCode128Compact: true})
+		internal.AssertEquals(t, test.fast, symbolCharacters(fast), "fast "+test.contents)
+		internal.AssertEquals(t, test.compact, symbolCharacters(compact), "compact "+test.contents)
+		internal.AssertEquals(t, test.contents, decode(t, compact, nil).GetText(), "compact "+test.contents)
+	}
+}
+
+func TestCode128Writer_Errors(t *testing.T) {
+	writer := oned.NewCode128Writer()
+	_, err := writer.Encode("", core.Code128, 0, 0, nil)
+	internal.AssertFailure(t, err, "empty contents")
+	_, err = writer.Encode("ABC", core.QRCode, 0, 0, nil)
+	internal.AssertFailure(t, err, "wrong format")
+	_, err = writer.Encode("ABC", core.Code128, -1, 0, nil)
+	internal.AssertFailure(t, err, "negative width")
+	_, err = writer.Encode("café", core.Code128, 0, 0, nil)
+	internal.AssertFailure(t, err, "non-ASCII")
+	_, err = writer.Encode(strings.Repeat("A", 81), core.Code128, 0, 0, nil)
+	internal.AssertFailure(t, err, "too long")
+}
+
+func TestCode128Writer_Size(t *testing.T) {
+	writer := oned.NewCode128Writer()
+	// "ABC" is 5 symbol characters and the stop pattern, 78 modules with the default margin
+	matrix, err := writer.Encode("ABC", core.Code128, 0, 0, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, uint32(78), matrix.GetWidth(), "width")
+	internal.AssertEquals(t, uint32(1), matrix.GetHeight(), "height")
+
+	matrix, err = writer.Encode("ABC", core.Code128, 200, 50, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, uint32(200), matrix.GetWidth(), "width")
+	internal.AssertEquals(t, uint32(50), matrix.GetHeight(), "height")
+	// Two pixels per module, centred
+	internal.AssertTrue(t, matrix.Get(32, 25) && matrix.Get(33, 25), "first bar")
+	internal.AssertFalse(t, matrix.Get(31, 25), "quiet zone")
+}
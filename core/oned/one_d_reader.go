@@ -0,0 +1,204 @@
+/*
+ * Copyright 2008 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oned
+
+import (
+	"math"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+// rowDecoder is implemented by the one dimensional barcode readers, which
+// decode a barcode from a single row of an image.
+type rowDecoder interface {
+	// decodeRow attempts to decode a barcode in row, which is row
+	// rowNumber of the image.
+	// It returns the Result, or core.ErrNotFound, core.ErrFormat or
+	// core.ErrChecksum if no barcode can be decoded in the row.
+	decodeRow(rowNumber int, row *common.BitArray, hints map[core.DecodeHintType]interface{}) (*core.Result, error)
+}
+
+// decodeOneD scans rows of image for a barcode which reader can decode.
+// If the core.DecodeHintTryHarder hint is set and the barcode is not
+// found, the image is also scanned turned a quarter turn, for barcodes
+// printed vertically.
+// It returns the first Result found, or the error from the last row
+// tried.
+func decodeOneD(image *common.BitMatrix, hints map[core.DecodeHintType]interface{}, reader rowDecoder) (*core.Result, error) {
+	result, err := doDecode(image, hints, reader)
+	if err == nil {
+		return result, nil
+	}
+	if _, tryHarder := hints[core.DecodeHintTryHarder]; !tryHarder {
+		return nil, err
+	}
+	rotatedImage := image.Clone()
+	rotatedImage.Rotate90()
+	result, err = doDecode(rotatedImage, hints, reader)
+	if err != nil {
+		return nil, err
+	}
+	// Record that we found it rotated 90 degrees CCW / 270 degrees CW
+	orientation := 270
+	if previous, ok := result.GetResultMetadata()[core.ResultMetadataOrientation].(int); ok {
+		orientation = (orientation + previous) % 360
+	}
+	result.PutMetadata(core.ResultMetadataOrientation, orientation)
+	// Update result points
+	height := float32(rotatedImage.GetHeight())
+	for i, point := range result.GetResultPoints() {
+		result.GetResultPoints()[i] = core.NewResultPoint(height-point.GetY()-1, point.GetX())
+	}
+	return result, nil
+}
+
+// doDecode tries to decode rows of image, starting in the middle and
+// moving alternately up and down, each row forwards and then backwards.
+// The core.DecodeHintTryHarder hint makes it try more rows, closer
+// together.
+// It returns the first Result found, or core.ErrNotFound.
+func doDecode(image *common.BitMatrix, hints map[core.DecodeHintType]interface{}, reader rowDecoder) (*core.Result, error) {
+	width := int(image.GetWidth())
+	height := int(image.GetHeight())
+	row := common.NewBitArray(uint32(width))
+
+	_, tryHarder := hints[core.DecodeHintTryHarder]
+	shift := uint(5)
+	maxLines := 15 // 15 rows spaced 1/32 apart is roughly the middle half of the image
+	if tryHarder {
+		shift = 8
+		maxLines = height // Look at the whole image, not just the center
+	}
+	rowStep := height >> shift
+	if rowStep < 1 {
+		rowStep = 1
+	}
+
+	middle := height / 2
+	for x := 0; x < maxLines; x++ {
+		// Scanning from the middle out. Determine which row we're looking at next:
+		rowStepsAboveOrBelow := (x + 1) / 2
+		isAbove := (x & 0x01) == 0 // i.e. is x even?
+		rowNumber := middle - rowStep*rowStepsAboveOrBelow
+		if isAbove {
+			rowNumber = middle + rowStep*rowStepsAboveOrBelow
+		}
+		if rowNumber < 0 || rowNumber >= height {
+			// Oops, if we run off the top or bottom, stop
+			break
+		}
+
+		row = image.GetRow(uint32(rowNumber), row)
+
+		// While we have the image data in a BitArray, it's fairly cheap to reverse it in place to
+		// handle decoding upside down barcodes.
+		for attempt := 0; attempt < 2; attempt++ {
+			if attempt == 1 {
+				row.Reverse()
+			}
+			result, err := reader.decodeRow(rowNumber, row, hints)
+			if err != nil {
+				continue
+			}
+			if attempt == 1 {
+				// We found our barcode upside down
+				result.PutMetadata(core.ResultMetadataOrientation, 180)
+				// And remember to flip the result points horizontally.
+				points := result.GetResultPoints()
+				for i, point := range points {
+					points[i] = core.NewResultPoint(float32(width)-point.GetX()-1, point.GetY())
+				}
+			}
+			return result, nil
+		}
+	}
+	return nil, core.ErrNotFound
+}
+
+// recordPattern records the size of successive runs of white and black
+// pixels in row, starting at start, into counters. The first run is of the
+// color at start.
+// It returns core.ErrNotFound if the row ends before all counters are
+// filled, unless it ends exactly at the last one.
+func recordPattern(row *common.BitArray, start int, counters []int) error {
+	numCounters := len(counters)
+	for i := range counters {
+		counters[i] = 0
+	}
+	end := int(row.GetSize())
+	if start >= end {
+		return core.ErrNotFound
+	}
+	isWhite := !row.Get(uint32(start))
+	counterPosition := 0
+	i := start
+	for ; i < end; i++ {
+		if row.Get(uint32(i)) != isWhite {
+			counters[counterPosition]++
+		} else {
+			counterPosition++
+			if counterPosition == numCounters {
+				break
+			}
+			counters[counterPosition] = 1
+			isWhite = !isWhite
+		}
+	}
+	// If we read fully the last section of pixels and filled up our counters -- or filled
+	// the last counter but ran off the side of the image, OK. Otherwise, a problem.
+	if !(counterPosition == numCounters || (counterPosition == numCounters-1 && i == end)) {
+		return core.ErrNotFound
+	}
+	return nil
+}
+
+// patternMatchVariance determines how closely a set of observed counts of
+// runs of black/white values matches a given target pattern. This is
+// reported as the ratio of the total variance from the expected pattern
+// proportions across all pattern elements, to the length of the pattern.
+// maxIndividualVariance is the most any single counter may vary from the
+// pattern, as a fraction of the width of a module.
+// It returns the ratio, or positive infinity if any counter varies too
+// much.
+func patternMatchVariance(counters, pattern []int, maxIndividualVariance float32) float32 {
+	total := 0
+	patternLength := 0
+	for i, counter := range counters {
+		total += counter
+		patternLength += pattern[i]
+	}
+	if total < patternLength {
+		// If we don't even have one pixel per unit of bar width, assume this is too small
+		// to reliably match, so fail:
+		return float32(math.Inf(1))
+	}
+
+	unitBarWidth := float32(total) / float32(patternLength)
+	maxIndividualVariance *= unitBarWidth
+
+	totalVariance := float32(0)
+	for x, counter := range counters {
+		scaledPattern := float32(pattern[x]) * unitBarWidth
+		variance := float32(math.Abs(float64(float32(counter) - scaledPattern)))
+		if variance > maxIndividualVariance {
+			return float32(math.Inf(1))
+		}
+		totalVariance += variance
+	}
+	return totalVariance / float32(total)
+}
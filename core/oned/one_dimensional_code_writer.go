@@ -0,0 +1,103 @@
+/*
+ * Copyright 2011 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oned
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+// defaultMargin is the total width of the quiet zones either side of a one
+// dimensional barcode, in modules, unless core.EncodeHintMargin is given.
+const defaultMargin = 10
+
+// encodeOneD checks the arguments common to the one dimensional writers
+// other than the format, has encode turn contents into bars, and renders
+// them.
+// It returns an error if the arguments are invalid or encode fails.
+func encodeOneD(contents string, width, height int, hints map[core.EncodeHintType]interface{},
+	encode func(contents string) ([]bool, error)) (*common.BitMatrix, error) {
+	if len(contents) == 0 {
+		return nil, errors.New("Found empty contents")
+	}
+	if width < 0 || height < 0 {
+		return nil, errors.New("Negative size is not allowed. Input: " + strconv.Itoa(width) + "x" + strconv.Itoa(height))
+	}
+	sidesMargin := defaultMargin
+	if margin, ok := hints[core.EncodeHintMargin].(int); ok {
+		sidesMargin = margin
+	}
+
+	code, err := encode(contents)
+	if err != nil {
+		return nil, err
+	}
+	return renderOneDResult(code, width, height, sidesMargin)
+}
+
+// renderOneDResult scales code, one bool per module with true for a bar,
+// by a whole number of pixels per module to fit within width, centered
+// between margins of at least sidesMargin modules in total.
+func renderOneDResult(code []bool, width, height, sidesMargin int) (*common.BitMatrix, error) {
+	inputWidth := len(code)
+	// Add quiet zone on both sides.
+	fullWidth := inputWidth + sidesMargin
+	outputWidth := width
+	if outputWidth < fullWidth {
+		outputWidth = fullWidth
+	}
+	outputHeight := height
+	if outputHeight < 1 {
+		outputHeight = 1
+	}
+
+	multiple := outputWidth / fullWidth
+	leftPadding := (outputWidth - (inputWidth * multiple)) / 2
+
+	output, err := common.NewBitMatrix(uint32(outputWidth), uint32(outputHeight))
+	if err != nil {
+		return nil, err
+	}
+	for inputX, outputX := 0, leftPadding; inputX < inputWidth; inputX, outputX = inputX+1, outputX+multiple {
+		if code[inputX] {
+			if err := output.SetRegion(uint32(outputX), 0, uint32(multiple), uint32(outputHeight)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return output, nil
+}
+
+// appendPattern appends the bars and spaces whose widths are pattern to
+// target at pos, starting with a bar if startColor is true.
+// It returns the number of modules appended.
+func appendPattern(target []bool, pos int, pattern []int, startColor bool) int {
+	color := startColor
+	numAdded := 0
+	for _, length := range pattern {
+		for j := 0; j < length; j++ {
+			target[pos] = color
+			pos++
+		}
+		numAdded += length
+		color = !color // flip color after each segment
+	}
+	return numAdded
+}
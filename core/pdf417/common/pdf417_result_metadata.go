@@ -0,0 +1,138 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+// PDF417ResultMetadata holds the Macro PDF417 control block of a symbol,
+// which ties together the segments of a message spread over several
+// symbols.
+// Text fields are empty and numeric fields -1 if the optional field is
+// not present.
+type PDF417ResultMetadata struct {
+	segmentIndex int
+	fileID       string
+	lastSegment  bool
+	segmentCount int
+	sender       string
+	addressee    string
+	fileName     string
+	fileSize     int64
+	timestamp    int64
+	checksum     int
+}
+
+// NewPDF417ResultMetadata creates metadata without any optional fields.
+func NewPDF417ResultMetadata() *PDF417ResultMetadata {
+	return &PDF417ResultMetadata{
+		segmentCount: -1,
+		fileSize:     -1,
+		timestamp:    -1,
+		checksum:     -1,
+	}
+}
+
+// GetSegmentIndex returns the index of this segment, counting from 0.
+func (m *PDF417ResultMetadata) GetSegmentIndex() int {
+	return m.segmentIndex
+}
+
+func (m *PDF417ResultMetadata) SetSegmentIndex(segmentIndex int) {
+	m.segmentIndex = segmentIndex
+}
+
+// GetFileID returns the file ID shared by all segments, as the decimal
+// digits of its codewords, three per codeword.
+func (m *PDF417ResultMetadata) GetFileID() string {
+	return m.fileID
+}
+
+func (m *PDF417ResultMetadata) SetFileID(fileID string) {
+	m.fileID = fileID
+}
+
+// IsLastSegment reports whether this is the last segment of the message.
+func (m *PDF417ResultMetadata) IsLastSegment() bool {
+	return m.lastSegment
+}
+
+func (m *PDF417ResultMetadata) SetLastSegment(lastSegment bool) {
+	m.lastSegment = lastSegment
+}
+
+// GetSegmentCount returns the number of segments, or -1 if not set.
+func (m *PDF417ResultMetadata) GetSegmentCount() int {
+	return m.segmentCount
+}
+
+func (m *PDF417ResultMetadata) SetSegmentCount(segmentCount int) {
+	m.segmentCount = segmentCount
+}
+
+// GetSender returns the sender, or "" if not set.
+func (m *PDF417ResultMetadata) GetSender() string {
+	return m.sender
+}
+
+func (m *PDF417ResultMetadata) SetSender(sender string) {
+	m.sender = sender
+}
+
+// GetAddressee returns the addressee, or "" if not set.
+func (m *PDF417ResultMetadata) GetAddressee() string {
+	return m.addressee
+}
+
+func (m *PDF417ResultMetadata) SetAddressee(addressee string) {
+	m.addressee = addressee
+}
+
+// GetFileName returns the file name, or "" if not set.
+func (m *PDF417ResultMetadata) GetFileName() string {
+	return m.fileName
+}
+
+func (m *PDF417ResultMetadata) SetFileName(fileName string) {
+	m.fileName = fileName
+}
+
+// GetFileSize returns the size of the file in bytes, or -1 if not set.
+func (m *PDF417ResultMetadata) GetFileSize() int64 {
+	return m.fileSize
+}
+
+func (m *PDF417ResultMetadata) SetFileSize(fileSize int64) {
+	m.fileSize = fileSize
+}
+
+// GetChecksum returns the 16-bit CRC checksum of all segments, using
+// CCITT-16, or -1 if not set.
+func (m *PDF417ResultMetadata) GetChecksum() int {
+	return m.checksum
+}
+
+func (m *PDF417ResultMetadata) SetChecksum(checksum int) {
+	m.checksum = checksum
+}
+
+// GetTimestamp returns the time the file was sent, in seconds since the
+// Unix epoch, or -1 if not set.
+func (m *PDF417ResultMetadata) GetTimestamp() int64 {
+	return m.timestamp
+}
+
+func (m *PDF417ResultMetadata) SetTimestamp(timestamp int64) {
+	m.timestamp = timestamp
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+// barcodeMetadata holds the dimensions and error correction level of a
+// PDF417 symbol, as encoded in its row indicator columns.
+type barcodeMetadata struct {
+	columnCount          int
+	errorCorrectionLevel int
+	rowCountUpperPart    int
+	rowCountLowerPart    int
+	rowCount             int
+}
+
+func newBarcodeMetadata(columnCount, rowCountUpperPart, rowCountLowerPart, errorCorrectionLevel int) *barcodeMetadata {
+	return &barcodeMetadata{
+		columnCount:          columnCount,
+		errorCorrectionLevel: errorCorrectionLevel,
+		rowCountUpperPart:    rowCountUpperPart,
+		rowCountLowerPart:    rowCountLowerPart,
+		rowCount:             rowCountUpperPart + rowCountLowerPart,
+	}
+}
+
+func (bm *barcodeMetadata) getColumnCount() int {
+	return bm.columnCount
+}
+
+func (bm *barcodeMetadata) getErrorCorrectionLevel() int {
+	return bm.errorCorrectionLevel
+}
+
+func (bm *barcodeMetadata) getRowCount() int {
+	return bm.rowCount
+}
+
+func (bm *barcodeMetadata) getRowCountUpperPart() int {
+	return bm.rowCountUpperPart
+}
+
+func (bm *barcodeMetadata) getRowCountLowerPart() int {
+	return bm.rowCountLowerPart
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import "sort"
+
+// barcodeValue counts how often each value was read for one position of
+// the barcode matrix.
+type barcodeValue struct {
+	values map[int]int
+}
+
+func newBarcodeValue() *barcodeValue {
+	return &barcodeValue{make(map[int]int)}
+}
+
+// setValue adds an occurrence of value.
+func (bv *barcodeValue) setValue(value int) {
+	bv.values[value]++
+}
+
+// getValue determines the most likely values, which are the ones with the
+// highest confidence. Several values are returned, in ascending order, if
+// they share the highest confidence.
+func (bv *barcodeValue) getValue() []int {
+	maxConfidence := -1
+	var result []int
+	for value, confidence := range bv.values {
+		if confidence > maxConfidence {
+			maxConfidence = confidence
+			result = append(result[:0], value)
+		} else if confidence == maxConfidence {
+			result = append(result, value)
+		}
+	}
+	sort.Ints(result)
+	return result
+}
+
+// getConfidence returns how often value was read.
+func (bv *barcodeValue) getConfidence(value int) int {
+	return bv.values[value]
+}
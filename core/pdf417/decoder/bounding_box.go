@@ -0,0 +1,169 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+// boundingBox is the area of the image occupied by a PDF417 symbol, given
+// by its four corners.
+type boundingBox struct {
+	image       *common.BitMatrix
+	topLeft     *core.ResultPoint
+	bottomLeft  *core.ResultPoint
+	topRight    *core.ResultPoint
+	bottomRight *core.ResultPoint
+	minX        int
+	maxX        int
+	minY        int
+	maxY        int
+}
+
+// newBoundingBox creates a boundingBox. Either the left or the right pair of
+// corners may be missing, in which case the box extends to that edge of
+// the image.
+// It returns core.ErrNotFound if both pairs are missing.
+func newBoundingBox(image *common.BitMatrix, topLeft, bottomLeft, topRight, bottomRight *core.ResultPoint) (*boundingBox, error) {
+	leftUnspecified := topLeft == nil || bottomLeft == nil
+	rightUnspecified := topRight == nil || bottomRight == nil
+	if leftUnspecified && rightUnspecified {
+		return nil, core.ErrNotFound
+	}
+	if leftUnspecified {
+		topLeft = core.NewResultPoint(0, topRight.GetY())
+		bottomLeft = core.NewResultPoint(0, bottomRight.GetY())
+	} else if rightUnspecified {
+		topRight = core.NewResultPoint(float32(image.GetWidth()-1), topLeft.GetY())
+		bottomRight = core.NewResultPoint(float32(image.GetWidth()-1), bottomLeft.GetY())
+	}
+	return &boundingBox{
+		image:       image,
+		topLeft:     topLeft,
+		bottomLeft:  bottomLeft,
+		topRight:    topRight,
+		bottomRight: bottomRight,
+		minX:        int(min32(topLeft.GetX(), bottomLeft.GetX())),
+		maxX:        int(max32(topRight.GetX(), bottomRight.GetX())),
+		minY:        int(min32(topLeft.GetY(), topRight.GetY())),
+		maxY:        int(max32(bottomLeft.GetY(), bottomRight.GetY())),
+	}, nil
+}
+
+// mergeBoundingBoxes combines the left corners of leftBox with the right
+// corners of rightBox. Either may be nil.
+func mergeBoundingBoxes(leftBox, rightBox *boundingBox) (*boundingBox, error) {
+	if leftBox == nil {
+		return rightBox, nil
+	}
+	if rightBox == nil {
+		return leftBox, nil
+	}
+	return newBoundingBox(leftBox.image, leftBox.topLeft, leftBox.bottomLeft, rightBox.topRight, rightBox.bottomRight)
+}
+
+// addMissingRows extends the left or right side of the box by the given
+// number of pixel rows at the top and bottom.
+func (bb *boundingBox) addMissingRows(missingStartRows, missingEndRows int, isLeft bool) (*boundingBox, error) {
+	newTopLeft := bb.topLeft
+	newBottomLeft := bb.bottomLeft
+	newTopRight := bb.topRight
+	newBottomRight := bb.bottomRight
+
+	if missingStartRows > 0 {
+		top := bb.topRight
+		if isLeft {
+			top = bb.topLeft
+		}
+		newMinY := int(top.GetY()) - missingStartRows
+		if newMinY < 0 {
+			newMinY = 0
+		}
+		newTop := core.NewResultPoint(top.GetX(), float32(newMinY))
+		if isLeft {
+			newTopLeft = newTop
+		} else {
+			newTopRight = newTop
+		}
+	}
+
+	if missingEndRows > 0 {
+		bottom := bb.bottomRight
+		if isLeft {
+			bottom = bb.bottomLeft
+		}
+		newMaxY := int(bottom.GetY()) + missingEndRows
+		if newMaxY >= int(bb.image.GetHeight()) {
+			newMaxY = int(bb.image.GetHeight()) - 1
+		}
+		newBottom := core.NewResultPoint(bottom.GetX(), float32(newMaxY))
+		if isLeft {
+			newBottomLeft = newBottom
+		} else {
+			newBottomRight = newBottom
+		}
+	}
+
+	return newBoundingBox(bb.image, newTopLeft, newBottomLeft, newTopRight, newBottomRight)
+}
+
+func (bb *boundingBox) getMinX() int {
+	return bb.minX
+}
+
+func (bb *boundingBox) getMaxX() int {
+	return bb.maxX
+}
+
+func (bb *boundingBox) getMinY() int {
+	return bb.minY
+}
+
+func (bb *boundingBox) getMaxY() int {
+	return bb.maxY
+}
+
+func (bb *boundingBox) getTopLeft() *core.ResultPoint {
+	return bb.topLeft
+}
+
+func (bb *boundingBox) getTopRight() *core.ResultPoint {
+	return bb.topRight
+}
+
+func (bb *boundingBox) getBottomLeft() *core.ResultPoint {
+	return bb.bottomLeft
+}
+
+func (bb *boundingBox) getBottomRight() *core.ResultPoint {
+	return bb.bottomRight
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
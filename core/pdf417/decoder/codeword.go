@@ -0,0 +1,53 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+const barcodeRowUnknown = -1
+
+// codeword is a single codeword read from the image, together with its
+// position and the cluster it was found in.
+type codeword struct {
+	startX    int
+	endX      int
+	bucket    int
+	value     int
+	rowNumber int
+}
+
+func newCodeword(startX, endX, bucket, value int) *codeword {
+	return &codeword{startX, endX, bucket, value, barcodeRowUnknown}
+}
+
+func (c *codeword) hasValidRowNumber() bool {
+	return c.isValidRowNumber(c.rowNumber)
+}
+
+// isValidRowNumber reports whether the codeword's cluster matches the one
+// used by rowNumber: clusters 0, 3 and 6 cycle through the rows.
+func (c *codeword) isValidRowNumber(rowNumber int) bool {
+	return rowNumber != barcodeRowUnknown && c.bucket == (rowNumber%3)*3
+}
+
+// setRowNumberAsRowIndicatorColumn derives the row number from the value
+// of a row indicator codeword.
+func (c *codeword) setRowNumberAsRowIndicatorColumn() {
+	c.rowNumber = (c.value/30)*3 + c.bucket/3
+}
+
+func (c *codeword) getWidth() int {
+	return c.endX - c.startX
+}
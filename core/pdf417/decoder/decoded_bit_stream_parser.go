@@ -0,0 +1,613 @@
+/*
+ * Copyright 2009 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+)
+
+// The decoded bit stream parser takes the data codewords of a PDF417
+// symbol and decodes them into text, according to ISO/IEC 15438:2015 5.4.
+
+type mode int
+
+const (
+	alphaMode mode = iota
+	lowerMode
+	mixedMode
+	punctMode
+	alphaShiftMode
+	punctShiftMode
+)
+
+const (
+	textCompactionModeLatch       = 900
+	byteCompactionModeLatch       = 901
+	numericCompactionModeLatch    = 902
+	byteCompactionModeLatch6      = 924
+	eciUserDefined                = 925
+	eciGeneralPurpose             = 926
+	eciCharset                    = 927
+	beginMacroPDF417ControlBlock  = 928
+	beginMacroPDF417OptionalField = 923
+	macroPDF417Terminator         = 922
+	modeShiftToByteCompactionMode = 913
+	maxNumericCodewords           = 15
+
+	macroPDF417OptionalFieldFileName     = 0
+	macroPDF417OptionalFieldSegmentCount = 1
+	macroPDF417OptionalFieldTimeStamp    = 2
+	macroPDF417OptionalFieldSender       = 3
+	macroPDF417OptionalFieldAddressee    = 4
+	macroPDF417OptionalFieldFileSize     = 5
+	macroPDF417OptionalFieldChecksum     = 6
+
+	numberOfSequenceCodewords = 2
+)
+
+// Text compaction sub-mode switches.
+const (
+	pl  = 25
+	ll  = 27
+	as  = 27
+	ml  = 28
+	al  = 28
+	ps  = 29
+	pal = 29
+)
+
+const (
+	punctChars = ";<>@[\\]_`~!\r\t,:\n-.$/\"|*()?{}'"
+	mixedChars = "0123456789&\r\t,:#-.$/+%*=^"
+)
+
+// exp900 holds the powers of 900 used by numeric compaction.
+var exp900 = buildExp900()
+
+func buildExp900() []*big.Int {
+	table := make([]*big.Int, 16)
+	table[0] = big.NewInt(1)
+	nineHundred := big.NewInt(900)
+	for i := 1; i < len(table); i++ {
+		table[i] = new(big.Int).Mul(table[i-1], nineHundred)
+	}
+	return table
+}
+
+// decodeBitStream decodes the data codewords of a symbol, the first of
+// which is the symbol length descriptor. A Macro PDF417 control block is
+// attached to the result as its other data, a
+// *pdf417common.PDF417ResultMetadata.
+// It returns the decoded result, or core.ErrFormat if the codewords are
+// not a valid encodation.
+func decodeBitStream(codewords []int, ecLevel string) (*common.DecoderResult, error) {
+	if len(codewords) == 0 || codewords[0] < 1 || codewords[0] > len(codewords) {
+		return nil, core.ErrFormat
+	}
+	result := common.NewECIStringBuilder()
+	var resultMetadata *pdf417common.PDF417ResultMetadata
+	codeIndex, err := textCompaction(codewords, 1, result)
+	if err != nil {
+		return nil, err
+	}
+	for codeIndex < codewords[0] {
+		code := codewords[codeIndex]
+		codeIndex++
+		switch code {
+		case textCompactionModeLatch:
+			codeIndex, err = textCompaction(codewords, codeIndex, result)
+		case byteCompactionModeLatch, byteCompactionModeLatch6:
+			codeIndex, err = byteCompaction(code, codewords, codeIndex, result)
+		case modeShiftToByteCompactionMode:
+			if codeIndex >= codewords[0] {
+				return nil, core.ErrFormat
+			}
+			result.AppendByte(uint8(codewords[codeIndex]))
+			codeIndex++
+		case numericCompactionModeLatch:
+			codeIndex, err = numericCompaction(codewords, codeIndex, result)
+		case eciCharset:
+			if codeIndex >= codewords[0] {
+				return nil, core.ErrFormat
+			}
+			err = appendECI(result, codewords[codeIndex])
+			codeIndex++
+		case eciGeneralPurpose:
+			// Can't do anything with generic ECI; skip its 2 characters
+			codeIndex += 2
+		case eciUserDefined:
+			// Can't do anything with user ECI; skip its 1 character
+			codeIndex++
+		case beginMacroPDF417ControlBlock:
+			resultMetadata = pdf417common.NewPDF417ResultMetadata()
+			codeIndex, err = decodeMacroBlock(codewords, codeIndex, resultMetadata)
+		case beginMacroPDF417OptionalField, macroPDF417Terminator:
+			// Should not see these outside a macro block
+			return nil, core.ErrFormat
+		default:
+			// Default to text compaction. During testing numerous barcodes
+			// appeared to be missing the starting mode. In these cases
+			// defaulting to text compaction seems to work.
+			codeIndex, err = textCompaction(codewords, codeIndex-1, result)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if result.IsEmpty() && resultMetadata == nil {
+		return nil, core.ErrFormat
+	}
+	decoderResult := common.NewDecoderResult(nil, result.String(), nil, ecLevel)
+	if resultMetadata != nil {
+		decoderResult.SetOther(resultMetadata)
+	}
+	return decoderResult, nil
+}
+
+// decodeMacroBlock reads a Macro PDF417 control block into
+// resultMetadata, from just after its 928 codeword to the end of the
+// data.
+// It returns the index of the next codeword to read, or core.ErrFormat if
+// the block is malformed.
+func decodeMacroBlock(codewords []int, codeIndex int, resultMetadata *pdf417common.PDF417ResultMetadata) (int, error) {
+	if codeIndex+numberOfSequenceCodewords > codewords[0] {
+		// we must have at least two codewords left for the segment index
+		return 0, core.ErrFormat
+	}
+	segmentIndexString, err := decodeBase900toBase10(codewords[codeIndex:], numberOfSequenceCodewords)
+	if err != nil {
+		return 0, err
+	}
+	codeIndex += numberOfSequenceCodewords
+	segmentIndex := 0
+	if segmentIndexString != "" {
+		if segmentIndex, err = strconv.Atoi(segmentIndexString); err != nil {
+			return 0, core.ErrFormat
+		}
+	}
+	resultMetadata.SetSegmentIndex(segmentIndex)
+
+	// Decoding the fileId codewords as 0-899 numbers, each 0-filled to
+	// width 3. This follows the spec (See ISO/IEC 15438:2015 Annex H.6)
+	// and preserves all info, but some generators write the fileId using
+	// text compaction, so in those cases the fileId will appear mangled.
+	var fileID []byte
+	for codeIndex < codewords[0] &&
+		codewords[codeIndex] != macroPDF417Terminator &&
+		codewords[codeIndex] != beginMacroPDF417OptionalField {
+		code := codewords[codeIndex]
+		if code >= textCompactionModeLatch {
+			return 0, core.ErrFormat
+		}
+		fileID = append(fileID, byte('0'+code/100), byte('0'+code/10%10), byte('0'+code%10))
+		codeIndex++
+	}
+	if len(fileID) == 0 {
+		// at least one fileId codeword is required (Annex H.2)
+		return 0, core.ErrFormat
+	}
+	resultMetadata.SetFileID(string(fileID))
+
+	for codeIndex < codewords[0] {
+		switch codewords[codeIndex] {
+		case beginMacroPDF417OptionalField:
+			codeIndex++
+			if codeIndex >= codewords[0] {
+				return 0, core.ErrFormat
+			}
+			field := codewords[codeIndex]
+			value := common.NewECIStringBuilder()
+			switch field {
+			case macroPDF417OptionalFieldFileName, macroPDF417OptionalFieldSender, macroPDF417OptionalFieldAddressee:
+				codeIndex, err = textCompaction(codewords, codeIndex+1, value)
+			case macroPDF417OptionalFieldSegmentCount, macroPDF417OptionalFieldTimeStamp,
+				macroPDF417OptionalFieldFileSize, macroPDF417OptionalFieldChecksum:
+				codeIndex, err = numericCompaction(codewords, codeIndex+1, value)
+			default:
+				return 0, core.ErrFormat
+			}
+			if err != nil {
+				return 0, err
+			}
+			if err := setOptionalField(resultMetadata, field, value.String()); err != nil {
+				return 0, err
+			}
+		case macroPDF417Terminator:
+			codeIndex++
+			resultMetadata.SetLastSegment(true)
+		default:
+			return 0, core.ErrFormat
+		}
+	}
+	return codeIndex, nil
+}
+
+// setOptionalField stores the decoded value of a Macro PDF417 optional
+// field in resultMetadata.
+// It returns core.ErrFormat if a numeric field holds no valid number.
+func setOptionalField(resultMetadata *pdf417common.PDF417ResultMetadata, field int, value string) error {
+	switch field {
+	case macroPDF417OptionalFieldFileName:
+		resultMetadata.SetFileName(value)
+	case macroPDF417OptionalFieldSender:
+		resultMetadata.SetSender(value)
+	case macroPDF417OptionalFieldAddressee:
+		resultMetadata.SetAddressee(value)
+	default:
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return core.ErrFormat
+		}
+		switch field {
+		case macroPDF417OptionalFieldSegmentCount:
+			resultMetadata.SetSegmentCount(int(number))
+		case macroPDF417OptionalFieldTimeStamp:
+			resultMetadata.SetTimestamp(number)
+		case macroPDF417OptionalFieldFileSize:
+			resultMetadata.SetFileSize(number)
+		case macroPDF417OptionalFieldChecksum:
+			resultMetadata.SetChecksum(int(number))
+		}
+	}
+	return nil
+}
+
+func appendECI(result *common.ECIStringBuilder, value int) error {
+	if err := result.AppendECI(value); err != nil {
+		return core.ErrFormat
+	}
+	return nil
+}
+
+// textCompaction reads codewords in Text Compaction mode, where each
+// codeword holds two characters of 30 possible values each.
+// It returns the index of the next codeword to read.
+func textCompaction(codewords []int, codeIndex int, result *common.ECIStringBuilder) (int, error) {
+	// 2 character per codeword
+	textCompactionData := make([]int, (codewords[0]-codeIndex)*2)
+	// Used to hold the byte compaction value if there is a mode shift
+	byteCompactionData := make([]int, (codewords[0]-codeIndex)*2)
+
+	index := 0
+	end := false
+	subMode := alphaMode
+	for codeIndex < codewords[0] && !end {
+		code := codewords[codeIndex]
+		codeIndex++
+		if code < textCompactionModeLatch {
+			textCompactionData[index] = code / 30
+			textCompactionData[index+1] = code % 30
+			index += 2
+		} else {
+			switch code {
+			case textCompactionModeLatch:
+				// reinitialize text compaction mode to alpha sub mode
+				textCompactionData[index] = textCompactionModeLatch
+				index++
+			case byteCompactionModeLatch, byteCompactionModeLatch6,
+				numericCompactionModeLatch, beginMacroPDF417ControlBlock,
+				beginMacroPDF417OptionalField, macroPDF417Terminator:
+				codeIndex--
+				end = true
+			case modeShiftToByteCompactionMode:
+				// The Mode Shift codeword 913 shall cause a temporary
+				// switch from Text Compaction mode to Byte Compaction
+				// mode. This switch shall be in effect for only the next
+				// codeword, after which the mode shall revert to the
+				// prevailing sub-mode of the Text Compaction mode.
+				if codeIndex >= codewords[0] {
+					return 0, core.ErrFormat
+				}
+				textCompactionData[index] = modeShiftToByteCompactionMode
+				byteCompactionData[index] = codewords[codeIndex]
+				codeIndex++
+				index++
+			case eciCharset:
+				subMode = decodeTextCompaction(textCompactionData, byteCompactionData, index, result, subMode)
+				if codeIndex >= codewords[0] {
+					return 0, core.ErrFormat
+				}
+				if err := appendECI(result, codewords[codeIndex]); err != nil {
+					return 0, err
+				}
+				codeIndex++
+				textCompactionData = make([]int, (codewords[0]-codeIndex)*2)
+				byteCompactionData = make([]int, (codewords[0]-codeIndex)*2)
+				index = 0
+			}
+		}
+	}
+	decodeTextCompaction(textCompactionData, byteCompactionData, index, result, subMode)
+	return codeIndex, nil
+}
+
+// decodeTextCompaction turns the Text Compaction values into characters,
+// starting in startMode. The Text Compaction Alpha sub-mode is always in
+// effect at the start of each symbol, and after a latch to Text
+// Compaction mode.
+// It returns the sub-mode latched at the end.
+func decodeTextCompaction(textCompactionData, byteCompactionData []int, length int, result *common.ECIStringBuilder, startMode mode) mode {
+	subMode := startMode
+	priorToShiftMode := startMode
+	latchedMode := startMode
+	for i := 0; i < length; i++ {
+		subModeCh := textCompactionData[i]
+		var ch uint8
+		switch subMode {
+		case alphaMode:
+			// Alpha (uppercase alphabetic)
+			if subModeCh < 26 {
+				ch = uint8('A' + subModeCh)
+			} else {
+				switch subModeCh {
+				case 26:
+					ch = ' '
+				case ll:
+					subMode = lowerMode
+					latchedMode = subMode
+				case ml:
+					subMode = mixedMode
+					latchedMode = subMode
+				case ps:
+					// Shift to punctuation
+					priorToShiftMode = subMode
+					subMode = punctShiftMode
+				case modeShiftToByteCompactionMode:
+					result.AppendByte(uint8(byteCompactionData[i]))
+				case textCompactionModeLatch:
+					subMode = alphaMode
+					latchedMode = subMode
+				}
+			}
+
+		case lowerMode:
+			// Lower (lowercase alphabetic)
+			if subModeCh < 26 {
+				ch = uint8('a' + subModeCh)
+			} else {
+				switch subModeCh {
+				case 26:
+					ch = ' '
+				case as:
+					// Shift to alpha
+					priorToShiftMode = subMode
+					subMode = alphaShiftMode
+				case ml:
+					subMode = mixedMode
+					latchedMode = subMode
+				case ps:
+					// Shift to punctuation
+					priorToShiftMode = subMode
+					subMode = punctShiftMode
+				case modeShiftToByteCompactionMode:
+					result.AppendByte(uint8(byteCompactionData[i]))
+				case textCompactionModeLatch:
+					subMode = alphaMode
+					latchedMode = subMode
+				}
+			}
+
+		case mixedMode:
+			// Mixed (numeric and some punctuation)
+			if subModeCh < pl {
+				ch = mixedChars[subModeCh]
+			} else {
+				switch subModeCh {
+				case pl:
+					subMode = punctMode
+					latchedMode = subMode
+				case 26:
+					ch = ' '
+				case ll:
+					subMode = lowerMode
+					latchedMode = subMode
+				case al:
+					subMode = alphaMode
+					latchedMode = subMode
+				case ps:
+					// Shift to punctuation
+					priorToShiftMode = subMode
+					subMode = punctShiftMode
+				case modeShiftToByteCompactionMode:
+					result.AppendByte(uint8(byteCompactionData[i]))
+				case textCompactionModeLatch:
+					subMode = alphaMode
+					latchedMode = subMode
+				}
+			}
+
+		case punctMode:
+			// Punctuation
+			if subModeCh < pal {
+				ch = punctChars[subModeCh]
+			} else {
+				switch subModeCh {
+				case pal, textCompactionModeLatch:
+					subMode = alphaMode
+					latchedMode = subMode
+				case modeShiftToByteCompactionMode:
+					result.AppendByte(uint8(byteCompactionData[i]))
+				}
+			}
+
+		case alphaShiftMode:
+			// Restore sub-mode
+			subMode = priorToShiftMode
+			if subModeCh < 26 {
+				ch = uint8('A' + subModeCh)
+			} else {
+				switch subModeCh {
+				case 26:
+					ch = ' '
+				case textCompactionModeLatch:
+					subMode = alphaMode
+				}
+			}
+
+		case punctShiftMode:
+			// Restore sub-mode
+			subMode = priorToShiftMode
+			if subModeCh < pal {
+				ch = punctChars[subModeCh]
+			} else {
+				switch subModeCh {
+				case pal, textCompactionModeLatch:
+					subMode = alphaMode
+				case modeShiftToByteCompactionMode:
+					// PS before Shift-to-Byte is used as a padding
+					// character, see 5.4.2.4 of the specification
+					result.AppendByte(uint8(byteCompactionData[i]))
+				}
+			}
+		}
+		if ch != 0 {
+			// Append decoded character to result
+			result.AppendByte(ch)
+		}
+	}
+	return latchedMode
+}
+
+// byteCompaction reads codewords in Byte Compaction mode, where each group
+// of 5 codewords holds 6 bytes in base 900. A trailing group of fewer
+// than 6 bytes holds one byte per codeword, unless mode is 924, which
+// signals that the byte count is a multiple of 6.
+// It returns the index of the next codeword to read.
+func byteCompaction(mode int, codewords []int, codeIndex int, result *common.ECIStringBuilder) (int, error) {
+	end := false
+	for codeIndex < codewords[0] && !end {
+		// handle leading ECIs
+		for codeIndex < codewords[0] && codewords[codeIndex] == eciCharset {
+			codeIndex++
+			if codeIndex >= codewords[0] {
+				return 0, core.ErrFormat
+			}
+			if err := appendECI(result, codewords[codeIndex]); err != nil {
+				return 0, err
+			}
+			codeIndex++
+		}
+
+		if codeIndex >= codewords[0] || codewords[codeIndex] >= textCompactionModeLatch {
+			end = true
+		} else {
+			// decode one block of 5 codewords to 6 bytes
+			var value int64
+			count := 0
+			for {
+				value = 900*value + int64(codewords[codeIndex])
+				codeIndex++
+				count++
+				if count >= 5 || codeIndex >= codewords[0] || codewords[codeIndex] >= textCompactionModeLatch {
+					break
+				}
+			}
+			if count == 5 && (mode == byteCompactionModeLatch6 ||
+				codeIndex < codewords[0] && codewords[codeIndex] < textCompactionModeLatch) {
+				for i := 0; i < 6; i++ {
+					result.AppendByte(uint8(value >> uint(8*(5-i))))
+				}
+			} else {
+				codeIndex -= count
+				for codeIndex < codewords[0] && !end {
+					code := codewords[codeIndex]
+					codeIndex++
+					if code < textCompactionModeLatch {
+						result.AppendByte(uint8(code))
+					} else if code == eciCharset {
+						if codeIndex >= codewords[0] {
+							return 0, core.ErrFormat
+						}
+						if err := appendECI(result, codewords[codeIndex]); err != nil {
+							return 0, err
+						}
+						codeIndex++
+					} else {
+						codeIndex--
+						end = true
+					}
+				}
+			}
+		}
+	}
+	return codeIndex, nil
+}
+
+// numericCompaction reads codewords in Numeric Compaction mode, where
+// groups of up to 15 codewords hold up to 44 digits in base 900.
+// It returns the index of the next codeword to read.
+func numericCompaction(codewords []int, codeIndex int, result *common.ECIStringBuilder) (int, error) {
+	count := 0
+	end := false
+
+	numericCodewords := make([]int, maxNumericCodewords)
+
+	for codeIndex < codewords[0] && !end {
+		code := codewords[codeIndex]
+		codeIndex++
+		if codeIndex == codewords[0] {
+			end = true
+		}
+		if code < textCompactionModeLatch {
+			numericCodewords[count] = code
+			count++
+		} else {
+			switch code {
+			case textCompactionModeLatch, byteCompactionModeLatch,
+				byteCompactionModeLatch6, beginMacroPDF417ControlBlock,
+				beginMacroPDF417OptionalField, macroPDF417Terminator,
+				eciCharset:
+				codeIndex--
+				end = true
+			}
+		}
+		if (count%maxNumericCodewords == 0 || code == numericCompactionModeLatch || end) && count > 0 {
+			// Re-invoking Numeric Compaction mode (by using codeword 902
+			// while in Numeric Compaction mode) serves to terminate the
+			// current Numeric Compaction mode grouping as described in
+			// 5.4.4.2, and then to start a new one grouping.
+			s, err := decodeBase900toBase10(numericCodewords, count)
+			if err != nil {
+				return 0, err
+			}
+			result.AppendString(s)
+			count = 0
+		}
+	}
+	return codeIndex, nil
+}
+
+// decodeBase900toBase10 converts count base 900 codewords to the digits
+// they encode. The encoder prefixes the digits with a '1', which is
+// checked and removed.
+func decodeBase900toBase10(codewords []int, count int) (string, error) {
+	result := new(big.Int)
+	for i := 0; i < count; i++ {
+		term := new(big.Int).Mul(exp900[count-i-1], big.NewInt(int64(codewords[i])))
+		result.Add(result, term)
+	}
+	resultString := result.String()
+	if resultString[0] != '1' {
+		return "", core.ErrFormat
+	}
+	return resultString[1:], nil
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright 2009 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/internal"
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+)
+
+func assertDecodes(t *testing.T, codewords []int, expected string) {
+	result, err := decodeBitStream(codewords, "0")
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, expected, result.GetText(), "decoded "+result.GetText()+" instead of "+expected)
+}
+
+func TestDecodedBitStreamParser_Text(t *testing.T) {
+	// H, latch to lower, e, l, l, o
+	assertDecodes(t, []int{4, 7*30 + 27, 4*30 + 11, 11*30 + 14}, "Hello")
+}
+
+func TestDecodedBitStreamParser_TextMixedAndPunct(t *testing.T) {
+	// A, latch to mixed, 1, shift to punctuation, !
+	assertDecodes(t, []int{4, 0*30 + 28, 1*30 + 29, 10*30 + 29}, "A1!")
+}
+
+func TestDecodedBitStreamParser_TextLatch(t *testing.T) {
+	// Latch to lower, a, then a text latch returns to alpha
+	assertDecodes(t, []int{4, 27*30 + 0, 900, 1*30 + 2}, "aBC")
+}
+
+func TestDecodedBitStreamParser_Numeric(t *testing.T) {
+	// 11234 is 12 * 900 + 434, the leading 1 being dropped
+	assertDecodes(t, []int{4, 902, 12, 434}, "1234")
+}
+
+func TestDecodedBitStreamParser_NumericWithoutLeadingOne(t *testing.T) {
+	_, err := decodeBitStream([]int{3, 902, 5}, "0")
+	internal.AssertEquals(t, core.ErrFormat, err, "numeric group without a leading 1")
+}
+
+func TestDecodedBitStreamParser_Byte(t *testing.T) {
+	assertDecodes(t, []int{5, 901, 'a', 'b', 'c'}, "abc")
+}
+
+func TestDecodedBitStreamParser_Byte6(t *testing.T) {
+	// "ABCDEF" as 0x414243444546 in base 900
+	assertDecodes(t, []int{7, 924, 109, 326, 368, 127, 330}, "ABCDEF")
+	// With latch 901 a full group is only used when more data follows
+	assertDecodes(t, []int{8, 901, 109, 326, 368, 127, 330, 'G'}, "ABCDEFG")
+}
+
+func TestDecodedBitStreamParser_ShiftToByte(t *testing.T) {
+	assertDecodes(t, []int{5, 1*30 + 29, 913, '#', 2*30 + 29}, "B#C")
+}
+
+func TestDecodedBitStreamParser_ECI(t *testing.T) {
+	// ECI 26 is UTF-8
+	assertDecodes(t, []int{6, 927, 26, 901, 0xC3, 0xA9}, "é")
+	// Without an ECI the bytes are ISO-8859-1
+	assertDecodes(t, []int{4, 901, 0xC3, 0xA9}, "Ã©")
+}
+
+func TestDecodedBitStreamParser_Invalid(t *testing.T) {
+	_, err := decodeBitStream([]int{1}, "0")
+	internal.AssertEquals(t, core.ErrFormat, err, "no data")
+	_, err = decodeBitStream([]int{5, 1, 2}, "0")
+	internal.AssertEquals(t, core.ErrFormat, err, "length descriptor too large")
+	_, err = decodeBitStream([]int{3, 922, 1}, "0")
+	internal.AssertEquals(t, core.ErrFormat, err, "macro terminator outside a macro block")
+	_, err = decodeBitStream([]int{3, 901, 927}, "0")
+	internal.AssertEquals(t, core.ErrFormat, err, "truncated ECI")
+}
+
+func decodeMacroBlockAt(t *testing.T, codewords []int, codeIndex int) *pdf417common.PDF417ResultMetadata {
+	resultMetadata := pdf417common.NewPDF417ResultMetadata()
+	_, err := decodeMacroBlock(codewords, codeIndex, resultMetadata)
+	internal.AssertSuccess(t, err)
+	return resultMetadata
+}
+
+func TestDecodedBitStreamParser_MacroStandardSample1(t *testing.T) {
+	resultMetadata := decodeMacroBlockAt(t, []int{20, 928, 111, 100, 17, 53, 923, 1, 111, 104, 923, 3, 64, 416, 34, 923, 4, 258, 446, 67,
+		// we should never reach these
+		1000, 1000, 1000}, 2)
+	internal.AssertEquals(t, 0, resultMetadata.GetSegmentIndex(), "segment index")
+	internal.AssertEquals(t, "017053", resultMetadata.GetFileID(), "file ID")
+	internal.AssertFalse(t, resultMetadata.IsLastSegment(), "last segment")
+	internal.AssertEquals(t, 4, resultMetadata.GetSegmentCount(), "segment count")
+	internal.AssertEquals(t, "CEN BE", resultMetadata.GetSender(), "sender")
+	internal.AssertEquals(t, "ISO CH", resultMetadata.GetAddressee(), "addressee")
+}
+
+func TestDecodedBitStreamParser_MacroStandardSample2(t *testing.T) {
+	resultMetadata := decodeMacroBlockAt(t, []int{11, 928, 111, 103, 17, 53, 923, 1, 111, 104, 922,
+		// we should never reach these
+		1000, 1000, 1000}, 2)
+	internal.AssertEquals(t, 3, resultMetadata.GetSegmentIndex(), "segment index")
+	internal.AssertEquals(t, "017053", resultMetadata.GetFileID(), "file ID")
+	internal.AssertTrue(t, resultMetadata.IsLastSegment(), "last segment")
+	internal.AssertEquals(t, 4, resultMetadata.GetSegmentCount(), "segment count")
+	internal.AssertEquals(t, "", resultMetadata.GetSender(), "sender")
+	internal.AssertEquals(t, "", resultMetadata.GetAddressee(), "addressee")
+}
+
+func TestDecodedBitStreamParser_MacroStandardSample3(t *testing.T) {
+	resultMetadata := decodeMacroBlockAt(t, []int{7, 928, 111, 100, 100, 200, 300, 0}, 2)
+	internal.AssertEquals(t, 0, resultMetadata.GetSegmentIndex(), "segment index")
+	internal.AssertEquals(t, "100200300", resultMetadata.GetFileID(), "file ID")
+	internal.AssertFalse(t, resultMetadata.IsLastSegment(), "last segment")
+	internal.AssertEquals(t, -1, resultMetadata.GetSegmentCount(), "segment count")
+}
+
+func TestDecodedBitStreamParser_MacroFileName(t *testing.T) {
+	resultMetadata := decodeMacroBlockAt(t, []int{23, 477, 928, 111, 100, 0, 252, 21, 86, 923, 0, 815, 251, 133, 12, 148, 537, 593,
+		599, 923, 1, 111, 102, 98, 311, 355, 522, 920, 779, 40, 628, 33, 749, 267, 506, 213, 928, 465, 248,
+		493, 72, 780, 699, 780, 493, 755, 84, 198, 628, 368, 156, 198, 809, 19, 113}, 3)
+	internal.AssertEquals(t, 0, resultMetadata.GetSegmentIndex(), "segment index")
+	internal.AssertEquals(t, "000252021086", resultMetadata.GetFileID(), "file ID")
+	internal.AssertFalse(t, resultMetadata.IsLastSegment(), "last segment")
+	internal.AssertEquals(t, 2, resultMetadata.GetSegmentCount(), "segment count")
+	internal.AssertEquals(t, "filename.txt", resultMetadata.GetFileName(), "file name")
+}
+
+func TestDecodedBitStreamParser_MacroNumericValues(t *testing.T) {
+	resultMetadata := decodeMacroBlockAt(t, []int{25, 477, 928, 111, 100, 0, 252, 21, 86, 923, 2, 2, 0, 1, 0, 0, 0, 923, 5, 130, 923,
+		6, 1, 500, 13, 0}, 3)
+	internal.AssertEquals(t, "000252021086", resultMetadata.GetFileID(), "file ID")
+	internal.AssertEquals(t, int64(180980729000000), resultMetadata.GetTimestamp(), "timestamp")
+	internal.AssertEquals(t, int64(30), resultMetadata.GetFileSize(), "file size")
+	internal.AssertEquals(t, 260013, resultMetadata.GetChecksum(), "checksum")
+}
+
+func TestDecodedBitStreamParser_Macro(t *testing.T) {
+	// "AB", padding, then the control block of the last segment
+	result, err := decodeBitStream([]int{13, 1, 900, 900, 928, 111, 103, 17, 53, 923, 3, 89, 922}, "0")
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "AB", result.GetText(), "text")
+	resultMetadata, ok := result.GetOther().(*pdf417common.PDF417ResultMetadata)
+	internal.AssertTrue(t, ok, "no macro metadata")
+	internal.AssertEquals(t, 3, resultMetadata.GetSegmentIndex(), "segment index")
+	internal.AssertEquals(t, "017053", resultMetadata.GetFileID(), "file ID")
+	internal.AssertEquals(t, "C", resultMetadata.GetSender(), "sender")
+	internal.AssertTrue(t, resultMetadata.IsLastSegment(), "last segment")
+
+	result, err = decodeBitStream([]int{2, 1}, "0")
+	internal.AssertSuccess(t, err)
+	internal.AssertTrue(t, result.GetOther() == nil, "macro metadata without a control block")
+}
+
+func TestDecodedBitStreamParser_MacroInvalid(t *testing.T) {
+	for _, codewords := range [][]int{
+		// no room for the segment index
+		{3, 928, 111},
+		// no file ID
+		{4, 928, 111, 100},
+		// unknown optional field
+		{7, 928, 111, 100, 17, 923, 7},
+		// numeric field without digits
+		{7, 928, 111, 100, 17, 923, 1},
+		// stray codeword after the file ID
+		{7, 928, 111, 100, 17, 922, 1},
+	} {
+		_, err := decodeBitStream(codewords, "0")
+		internal.AssertEquals(t, core.ErrFormat, err, "malformed macro block")
+	}
+}
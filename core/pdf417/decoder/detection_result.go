@@ -0,0 +1,276 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+)
+
+const adjustRowNumberSkip = 2
+
+// detectionResult collects the columns read from a barcode, and assigns
+// barcode row numbers to their codewords.
+type detectionResult struct {
+	barcodeMetadata        *barcodeMetadata
+	detectionResultColumns []*detectionResultColumn
+	rowIndicatorColumns    [2]*detectionResultRowIndicatorColumn
+	boundingBox            *boundingBox
+	barcodeColumnCount     int
+}
+
+func newDetectionResult(metadata *barcodeMetadata, boundingBox *boundingBox) *detectionResult {
+	return &detectionResult{
+		barcodeMetadata:        metadata,
+		barcodeColumnCount:     metadata.getColumnCount(),
+		boundingBox:            boundingBox,
+		detectionResultColumns: make([]*detectionResultColumn, metadata.getColumnCount()+2),
+	}
+}
+
+// getDetectionResultColumns returns all columns, including the row
+// indicator columns, after the row numbers of their codewords have been
+// settled.
+func (dr *detectionResult) getDetectionResultColumns() []*detectionResultColumn {
+	for _, rowIndicatorColumn := range dr.rowIndicatorColumns {
+		if rowIndicatorColumn != nil {
+			rowIndicatorColumn.adjustCompleteIndicatorColumnRowNumbers(dr.barcodeMetadata)
+		}
+	}
+	unadjustedCodewordCount := pdf417common.MaxCodewordsInBarcode
+	for {
+		previousUnadjustedCount := unadjustedCodewordCount
+		unadjustedCodewordCount = dr.adjustRowNumbers()
+		if unadjustedCodewordCount <= 0 || unadjustedCodewordCount >= previousUnadjustedCount {
+			break
+		}
+	}
+	return dr.detectionResultColumns
+}
+
+// adjustRowNumbers returns the number of codewords which don't have a
+// valid row number. Note that the count is not accurate as codewords will
+// be counted several times. It just serves as an indicator to see when we
+// can stop adjusting row numbers.
+func (dr *detectionResult) adjustRowNumbers() int {
+	unadjustedCount := dr.adjustRowNumbersByRow()
+	if unadjustedCount == 0 {
+		return 0
+	}
+	for barcodeColumn := 1; barcodeColumn < dr.barcodeColumnCount+1; barcodeColumn++ {
+		codewords := dr.detectionResultColumns[barcodeColumn].getCodewords()
+		for codewordsRow, cw := range codewords {
+			if cw == nil {
+				continue
+			}
+			if !cw.hasValidRowNumber() {
+				dr.adjustRowNumbersAt(barcodeColumn, codewordsRow, codewords)
+			}
+		}
+	}
+	return unadjustedCount
+}
+
+func (dr *detectionResult) adjustRowNumbersByRow() int {
+	dr.adjustRowNumbersFromBothRI()
+	// TODO we should only do full row adjustments if row numbers of left
+	// and right row indicator column match.
+	unadjustedCount := dr.adjustRowNumbersFromLRI()
+	return unadjustedCount + dr.adjustRowNumbersFromRRI()
+}
+
+func (dr *detectionResult) adjustRowNumbersFromBothRI() {
+	if dr.detectionResultColumns[0] == nil || dr.detectionResultColumns[dr.barcodeColumnCount+1] == nil {
+		return
+	}
+	LRIcodewords := dr.detectionResultColumns[0].getCodewords()
+	RRIcodewords := dr.detectionResultColumns[dr.barcodeColumnCount+1].getCodewords()
+	for codewordsRow := range LRIcodewords {
+		if LRIcodewords[codewordsRow] != nil &&
+			RRIcodewords[codewordsRow] != nil &&
+			LRIcodewords[codewordsRow].rowNumber == RRIcodewords[codewordsRow].rowNumber {
+			for barcodeColumn := 1; barcodeColumn <= dr.barcodeColumnCount; barcodeColumn++ {
+				codewords := dr.detectionResultColumns[barcodeColumn].getCodewords()
+				cw := codewords[codewordsRow]
+				if cw == nil {
+					continue
+				}
+				cw.rowNumber = LRIcodewords[codewordsRow].rowNumber
+				if !cw.hasValidRowNumber() {
+					codewords[codewordsRow] = nil
+				}
+			}
+		}
+	}
+}
+
+func (dr *detectionResult) adjustRowNumbersFromRRI() int {
+	if dr.detectionResultColumns[dr.barcodeColumnCount+1] == nil {
+		return 0
+	}
+	unadjustedCount := 0
+	codewords := dr.detectionResultColumns[dr.barcodeColumnCount+1].getCodewords()
+	for codewordsRow, rowIndicator := range codewords {
+		if rowIndicator == nil {
+			continue
+		}
+		rowIndicatorRowNumber := rowIndicator.rowNumber
+		invalidRowCounts := 0
+		for barcodeColumn := dr.barcodeColumnCount + 1; barcodeColumn > 0 && invalidRowCounts < adjustRowNumberSkip; barcodeColumn-- {
+			cw := dr.detectionResultColumns[barcodeColumn].getCodewords()[codewordsRow]
+			if cw != nil {
+				invalidRowCounts = adjustRowNumberIfValid(rowIndicatorRowNumber, invalidRowCounts, cw)
+				if !cw.hasValidRowNumber() {
+					unadjustedCount++
+				}
+			}
+		}
+	}
+	return unadjustedCount
+}
+
+func (dr *detectionResult) adjustRowNumbersFromLRI() int {
+	if dr.detectionResultColumns[0] == nil {
+		return 0
+	}
+	unadjustedCount := 0
+	codewords := dr.detectionResultColumns[0].getCodewords()
+	for codewordsRow, rowIndicator := range codewords {
+		if rowIndicator == nil {
+			continue
+		}
+		rowIndicatorRowNumber := rowIndicator.rowNumber
+		invalidRowCounts := 0
+		for barcodeColumn := 1; barcodeColumn < dr.barcodeColumnCount+1 && invalidRowCounts < adjustRowNumberSkip; barcodeColumn++ {
+			cw := dr.detectionResultColumns[barcodeColumn].getCodewords()[codewordsRow]
+			if cw != nil {
+				invalidRowCounts = adjustRowNumberIfValid(rowIndicatorRowNumber, invalidRowCounts, cw)
+				if !cw.hasValidRowNumber() {
+					unadjustedCount++
+				}
+			}
+		}
+	}
+	return unadjustedCount
+}
+
+func adjustRowNumberIfValid(rowIndicatorRowNumber, invalidRowCounts int, cw *codeword) int {
+	if !cw.hasValidRowNumber() {
+		if cw.isValidRowNumber(rowIndicatorRowNumber) {
+			cw.rowNumber = rowIndicatorRowNumber
+			invalidRowCounts = 0
+		} else {
+			invalidRowCounts++
+		}
+	}
+	return invalidRowCounts
+}
+
+// adjustRowNumbersAt takes the row number for the codeword at codewordsRow
+// from a neighbouring codeword of the same cluster.
+func (dr *detectionResult) adjustRowNumbersAt(barcodeColumn, codewordsRow int, codewords []*codeword) {
+	cw := codewords[codewordsRow]
+	previousColumnCodewords := codewords
+	if dr.detectionResultColumns[barcodeColumn-1] != nil {
+		previousColumnCodewords = dr.detectionResultColumns[barcodeColumn-1].getCodewords()
+	}
+	nextColumnCodewords := previousColumnCodewords
+	if dr.detectionResultColumns[barcodeColumn+1] != nil {
+		nextColumnCodewords = dr.detectionResultColumns[barcodeColumn+1].getCodewords()
+	}
+
+	otherCodewords := make([]*codeword, 14)
+
+	otherCodewords[2] = previousColumnCodewords[codewordsRow]
+	otherCodewords[3] = nextColumnCodewords[codewordsRow]
+
+	if codewordsRow > 0 {
+		otherCodewords[0] = codewords[codewordsRow-1]
+		otherCodewords[4] = previousColumnCodewords[codewordsRow-1]
+		otherCodewords[5] = nextColumnCodewords[codewordsRow-1]
+	}
+	if codewordsRow > 1 {
+		otherCodewords[8] = codewords[codewordsRow-2]
+		otherCodewords[10] = previousColumnCodewords[codewordsRow-2]
+		otherCodewords[11] = nextColumnCodewords[codewordsRow-2]
+	}
+	if codewordsRow < len(codewords)-1 {
+		otherCodewords[1] = codewords[codewordsRow+1]
+		otherCodewords[6] = previousColumnCodewords[codewordsRow+1]
+		otherCodewords[7] = nextColumnCodewords[codewordsRow+1]
+	}
+	if codewordsRow < len(codewords)-2 {
+		otherCodewords[9] = codewords[codewordsRow+2]
+		otherCodewords[12] = previousColumnCodewords[codewordsRow+2]
+		otherCodewords[13] = nextColumnCodewords[codewordsRow+2]
+	}
+	for _, otherCodeword := range otherCodewords {
+		if adjustRowNumber(cw, otherCodeword) {
+			return
+		}
+	}
+}
+
+// adjustRowNumber copies the row number of otherCodeword to cw if it is
+// valid and both belong to the same cluster.
+func adjustRowNumber(cw, otherCodeword *codeword) bool {
+	if otherCodeword == nil {
+		return false
+	}
+	if otherCodeword.hasValidRowNumber() && otherCodeword.bucket == cw.bucket {
+		cw.rowNumber = otherCodeword.rowNumber
+		return true
+	}
+	return false
+}
+
+func (dr *detectionResult) getBarcodeColumnCount() int {
+	return dr.barcodeColumnCount
+}
+
+func (dr *detectionResult) getBarcodeRowCount() int {
+	return dr.barcodeMetadata.getRowCount()
+}
+
+func (dr *detectionResult) getBarcodeECLevel() int {
+	return dr.barcodeMetadata.getErrorCorrectionLevel()
+}
+
+func (dr *detectionResult) setBoundingBox(boundingBox *boundingBox) {
+	dr.boundingBox = boundingBox
+}
+
+func (dr *detectionResult) getBoundingBox() *boundingBox {
+	return dr.boundingBox
+}
+
+func (dr *detectionResult) setDetectionResultColumn(barcodeColumn int, column *detectionResultColumn) {
+	dr.detectionResultColumns[barcodeColumn] = column
+}
+
+// setRowIndicatorColumn sets the left or right row indicator column.
+func (dr *detectionResult) setRowIndicatorColumn(column *detectionResultRowIndicatorColumn) {
+	barcodeColumn, index := dr.barcodeColumnCount+1, 1
+	if column.isLeft {
+		barcodeColumn, index = 0, 0
+	}
+	dr.rowIndicatorColumns[index] = column
+	dr.detectionResultColumns[barcodeColumn] = column.detectionResultColumn
+}
+
+func (dr *detectionResult) getDetectionResultColumn(barcodeColumn int) *detectionResultColumn {
+	return dr.detectionResultColumns[barcodeColumn]
+}
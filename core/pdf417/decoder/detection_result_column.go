@@ -0,0 +1,80 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+const maxNearbyDistance = 5
+
+// detectionResultColumn holds the codewords read for one column of the
+// barcode, indexed by the image row they were read from.
+type detectionResultColumn struct {
+	boundingBox *boundingBox
+	codewords   []*codeword
+}
+
+func newDetectionResultColumn(boundingBox *boundingBox) *detectionResultColumn {
+	return &detectionResultColumn{
+		boundingBox: boundingBox,
+		codewords:   make([]*codeword, boundingBox.getMaxY()-boundingBox.getMinY()+1),
+	}
+}
+
+// getCodewordNearby returns the codeword read from imageRow, or else the
+// closest one within a few rows, or nil.
+func (c *detectionResultColumn) getCodewordNearby(imageRow int) *codeword {
+	if cw := c.getCodeword(imageRow); cw != nil {
+		return cw
+	}
+	for i := 1; i < maxNearbyDistance; i++ {
+		nearImageRow := c.imageRowToCodewordIndex(imageRow) - i
+		if nearImageRow >= 0 {
+			if cw := c.codewords[nearImageRow]; cw != nil {
+				return cw
+			}
+		}
+		nearImageRow = c.imageRowToCodewordIndex(imageRow) + i
+		if nearImageRow < len(c.codewords) {
+			if cw := c.codewords[nearImageRow]; cw != nil {
+				return cw
+			}
+		}
+	}
+	return nil
+}
+
+func (c *detectionResultColumn) imageRowToCodewordIndex(imageRow int) int {
+	return imageRow - c.boundingBox.getMinY()
+}
+
+func (c *detectionResultColumn) setCodeword(imageRow int, cw *codeword) {
+	c.codewords[c.imageRowToCodewordIndex(imageRow)] = cw
+}
+
+func (c *detectionResultColumn) getCodeword(imageRow int) *codeword {
+	index := c.imageRowToCodewordIndex(imageRow)
+	if index < 0 || index >= len(c.codewords) {
+		return nil
+	}
+	return c.codewords[index]
+}
+
+func (c *detectionResultColumn) getBoundingBox() *boundingBox {
+	return c.boundingBox
+}
+
+func (c *detectionResultColumn) getCodewords() []*codeword {
+	return c.codewords
+}
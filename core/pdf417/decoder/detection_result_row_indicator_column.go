@@ -0,0 +1,235 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+)
+
+// detectionResultRowIndicatorColumn is the left or right row indicator
+// column of a barcode. Its codewords encode the row number together with
+// the barcode's dimensions and error correction level.
+type detectionResultRowIndicatorColumn struct {
+	*detectionResultColumn
+	isLeft bool
+}
+
+func newDetectionResultRowIndicatorColumn(boundingBox *boundingBox, isLeft bool) *detectionResultRowIndicatorColumn {
+	return &detectionResultRowIndicatorColumn{newDetectionResultColumn(boundingBox), isLeft}
+}
+
+func (c *detectionResultRowIndicatorColumn) setRowNumbers() {
+	for _, cw := range c.codewords {
+		if cw != nil {
+			cw.setRowNumberAsRowIndicatorColumn()
+		}
+	}
+}
+
+// imageRowRange returns the codeword indices of the top and bottom of the
+// column.
+func (c *detectionResultRowIndicatorColumn) imageRowRange() (int, int) {
+	top := c.boundingBox.getTopRight()
+	bottom := c.boundingBox.getBottomRight()
+	if c.isLeft {
+		top = c.boundingBox.getTopLeft()
+		bottom = c.boundingBox.getBottomLeft()
+	}
+	return c.imageRowToCodewordIndex(int(top.GetY())), c.imageRowToCodewordIndex(int(bottom.GetY()))
+}
+
+// adjustCompleteIndicatorColumnRowNumbers assigns row numbers to the
+// codewords and removes those whose row numbers are out of sequence.
+func (c *detectionResultRowIndicatorColumn) adjustCompleteIndicatorColumnRowNumbers(metadata *barcodeMetadata) {
+	codewords := c.codewords
+	c.setRowNumbers()
+	c.removeIncorrectCodewords(metadata)
+	firstRow, lastRow := c.imageRowRange()
+	// We need to be careful using the average row height. Barcode could be
+	// skewed so that we have smaller and taller rows
+	barcodeRow := -1
+	maxRowHeight := 1
+	currentRowHeight := 0
+	for codewordsRow := firstRow; codewordsRow < lastRow; codewordsRow++ {
+		cw := codewords[codewordsRow]
+		if cw == nil {
+			continue
+		}
+
+		rowDifference := cw.rowNumber - barcodeRow
+
+		if rowDifference == 0 {
+			currentRowHeight++
+		} else if rowDifference == 1 {
+			if currentRowHeight > maxRowHeight {
+				maxRowHeight = currentRowHeight
+			}
+			currentRowHeight = 1
+			barcodeRow = cw.rowNumber
+		} else if rowDifference < 0 ||
+			cw.rowNumber >= metadata.getRowCount() ||
+			rowDifference > codewordsRow {
+			codewords[codewordsRow] = nil
+		} else {
+			var checkedRows int
+			if maxRowHeight > 2 {
+				checkedRows = (maxRowHeight - 2) * rowDifference
+			} else {
+				checkedRows = rowDifference
+			}
+			closePreviousCodewordFound := checkedRows >= codewordsRow
+			for i := 1; i <= checkedRows && !closePreviousCodewordFound; i++ {
+				// there must be (height * rowDifference) number of codewords
+				// missing. For now we assume height = 1. This should
+				// hopefully get rid of most problems already.
+				closePreviousCodewordFound = codewords[codewordsRow-i] != nil
+			}
+			if closePreviousCodewordFound {
+				codewords[codewordsRow] = nil
+			} else {
+				barcodeRow = cw.rowNumber
+				currentRowHeight = 1
+			}
+		}
+	}
+}
+
+// getRowHeights returns the number of image rows read for each barcode
+// row, or nil if the barcode metadata could not be determined.
+func (c *detectionResultRowIndicatorColumn) getRowHeights() []int {
+	metadata := c.getBarcodeMetadata()
+	if metadata == nil {
+		return nil
+	}
+	c.adjustIncompleteIndicatorColumnRowNumbers(metadata)
+	result := make([]int, metadata.getRowCount())
+	for _, cw := range c.codewords {
+		if cw != nil {
+			rowNumber := cw.rowNumber
+			if rowNumber < 0 || rowNumber >= len(result) {
+				// We have more rows than the barcode metadata allows for,
+				// ignore them.
+				continue
+			}
+			result[rowNumber]++
+		}
+	}
+	return result
+}
+
+func (c *detectionResultRowIndicatorColumn) adjustIncompleteIndicatorColumnRowNumbers(metadata *barcodeMetadata) {
+	firstRow, lastRow := c.imageRowRange()
+	codewords := c.codewords
+	barcodeRow := -1
+	for codewordsRow := firstRow; codewordsRow < lastRow; codewordsRow++ {
+		cw := codewords[codewordsRow]
+		if cw == nil {
+			continue
+		}
+
+		cw.setRowNumberAsRowIndicatorColumn()
+
+		rowDifference := cw.rowNumber - barcodeRow
+		if rowDifference == 0 {
+			continue
+		} else if rowDifference != 1 && cw.rowNumber >= metadata.getRowCount() {
+			codewords[codewordsRow] = nil
+		} else {
+			barcodeRow = cw.rowNumber
+		}
+	}
+}
+
+// getBarcodeMetadata determines the barcode's dimensions and error
+// correction level by majority vote over the column's codewords.
+// It returns nil if they cannot be determined.
+func (c *detectionResultRowIndicatorColumn) getBarcodeMetadata() *barcodeMetadata {
+	barcodeColumnCount := newBarcodeValue()
+	barcodeRowCountUpperPart := newBarcodeValue()
+	barcodeRowCountLowerPart := newBarcodeValue()
+	barcodeECLevel := newBarcodeValue()
+	for _, cw := range c.codewords {
+		if cw == nil {
+			continue
+		}
+		cw.setRowNumberAsRowIndicatorColumn()
+		rowIndicatorValue := cw.value % 30
+		codewordRowNumber := cw.rowNumber
+		if !c.isLeft {
+			codewordRowNumber += 2
+		}
+		switch codewordRowNumber % 3 {
+		case 0:
+			barcodeRowCountUpperPart.setValue(rowIndicatorValue*3 + 1)
+		case 1:
+			barcodeECLevel.setValue(rowIndicatorValue / 3)
+			barcodeRowCountLowerPart.setValue(rowIndicatorValue % 3)
+		case 2:
+			barcodeColumnCount.setValue(rowIndicatorValue + 1)
+		}
+	}
+	// Maybe we should check if we have ambiguous values?
+	columnCount := barcodeColumnCount.getValue()
+	rowCountUpperPart := barcodeRowCountUpperPart.getValue()
+	rowCountLowerPart := barcodeRowCountLowerPart.getValue()
+	ecLevel := barcodeECLevel.getValue()
+	if len(columnCount) == 0 || len(rowCountUpperPart) == 0 ||
+		len(rowCountLowerPart) == 0 || len(ecLevel) == 0 ||
+		columnCount[0] < 1 ||
+		rowCountUpperPart[0]+rowCountLowerPart[0] < pdf417common.MinRowsInBarcode ||
+		rowCountUpperPart[0]+rowCountLowerPart[0] > pdf417common.MaxRowsInBarcode {
+		return nil
+	}
+	metadata := newBarcodeMetadata(columnCount[0], rowCountUpperPart[0], rowCountLowerPart[0], ecLevel[0])
+	c.removeIncorrectCodewords(metadata)
+	return metadata
+}
+
+// removeIncorrectCodewords removes the codewords which do not match the
+// metadata.
+func (c *detectionResultRowIndicatorColumn) removeIncorrectCodewords(metadata *barcodeMetadata) {
+	codewords := c.codewords
+	for codewordRow, cw := range codewords {
+		if cw == nil {
+			continue
+		}
+		rowIndicatorValue := cw.value % 30
+		codewordRowNumber := cw.rowNumber
+		if codewordRowNumber > metadata.getRowCount() {
+			codewords[codewordRow] = nil
+			continue
+		}
+		if !c.isLeft {
+			codewordRowNumber += 2
+		}
+		switch codewordRowNumber % 3 {
+		case 0:
+			if rowIndicatorValue*3+1 != metadata.getRowCountUpperPart() {
+				codewords[codewordRow] = nil
+			}
+		case 1:
+			if rowIndicatorValue/3 != metadata.getErrorCorrectionLevel() ||
+				rowIndicatorValue%3 != metadata.getRowCountLowerPart() {
+				codewords[codewordRow] = nil
+			}
+		case 2:
+			if rowIndicatorValue+1 != metadata.getColumnCount() {
+				codewords[codewordRow] = nil
+			}
+		}
+	}
+}
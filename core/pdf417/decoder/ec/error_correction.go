@@ -0,0 +1,192 @@
+/*
+ * Copyright 2012 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ec
+
+import (
+	"github.com/discesoft/zxing-go/core"
+)
+
+// ErrorCorrection implements PDF417 error correction, which uses a
+// Reed-Solomon style code over GF(929) rather than GF(2^n).
+//
+// Known erasures are taken into account: each erased codeword costs one
+// error-correction codeword instead of the two an unknown error costs.
+type ErrorCorrection struct {
+	field *modulusGF
+}
+
+// NewErrorCorrection creates an ErrorCorrection over the PDF417 field.
+func NewErrorCorrection() *ErrorCorrection {
+	return &ErrorCorrection{pdf417GF}
+}
+
+// Decode corrects received, which holds both data and error-correction
+// codewords, in place. numECCodewords is the number of error-correction
+// codewords and erasures lists the positions of codewords known to be
+// unreadable. It returns the number of codewords corrected, or
+// core.ErrChecksum if the codewords could not be corrected.
+func (ec *ErrorCorrection) Decode(received []int, numECCodewords int, erasures []int) (int, error) {
+	if len(erasures) > numECCodewords {
+		return 0, core.ErrChecksum
+	}
+
+	poly := newModulusPoly(ec.field, received)
+	S := make([]int, numECCodewords)
+	noError := true
+	for i := numECCodewords; i > 0; i-- {
+		eval := poly.evaluateAt(ec.field.exp(i))
+		S[numECCodewords-i] = eval
+		if eval != 0 {
+			noError = false
+		}
+	}
+	if noError {
+		return 0, nil
+	}
+
+	// The erasure locator is the product of (1 - X*x) over all erased
+	// positions, X being the field element denoting the position.
+	erasureLocator := ec.field.getOne()
+	for _, erasure := range erasures {
+		if erasure < 0 || erasure >= len(received) {
+			return 0, core.ErrChecksum
+		}
+		b := ec.field.exp(len(received) - 1 - erasure)
+		term := newModulusPoly(ec.field, []int{ec.field.subtract(0, b), 1})
+		erasureLocator = erasureLocator.multiply(term)
+	}
+
+	syndrome := newModulusPoly(ec.field, S)
+	modifiedSyndrome := syndrome.multiply(erasureLocator).truncate(numECCodewords)
+	sigma, omega, err := ec.runEuclideanAlgorithm(ec.field.buildMonomial(numECCodewords, 1),
+		modifiedSyndrome, numECCodewords+len(erasures))
+	if err != nil {
+		return 0, err
+	}
+	sigma = sigma.multiply(erasureLocator)
+
+	errorLocations, err := ec.findErrorLocations(sigma)
+	if err != nil {
+		return 0, err
+	}
+	errorMagnitudes, err := ec.findErrorMagnitudes(omega, sigma, errorLocations)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, location := range errorLocations {
+		position := len(received) - 1 - ec.field.log(location)
+		if position < 0 {
+			return 0, core.ErrChecksum
+		}
+		received[position] = ec.field.subtract(received[position], errorMagnitudes[i])
+	}
+	return len(errorLocations), nil
+}
+
+// runEuclideanAlgorithm solves the key equation, stopping once the
+// remainder's degree drops below R/2.
+func (ec *ErrorCorrection) runEuclideanAlgorithm(a, b *modulusPoly, R int) (*modulusPoly, *modulusPoly, error) {
+	// Assume a's degree is >= b's
+	if a.getDegree() < b.getDegree() {
+		a, b = b, a
+	}
+
+	rLast := a
+	r := b
+	tLast := ec.field.getZero()
+	t := ec.field.getOne()
+
+	// Run Euclidean algorithm until r's degree is less than R/2
+	for 2*r.getDegree() >= R {
+		rLastLast := rLast
+		tLastLast := tLast
+		rLast = r
+		tLast = t
+
+		// Divide rLastLast by rLast, with quotient in q and remainder in r
+		if rLast.isZero() {
+			// Oops, Euclidean algorithm already terminated?
+			return nil, nil, core.ErrChecksum
+		}
+		r = rLastLast
+		q := ec.field.getZero()
+		denominatorLeadingTerm := rLast.getCoefficient(rLast.getDegree())
+		dltInverse := ec.field.inverse(denominatorLeadingTerm)
+		for r.getDegree() >= rLast.getDegree() && !r.isZero() {
+			degreeDiff := r.getDegree() - rLast.getDegree()
+			scale := ec.field.multiply(r.getCoefficient(r.getDegree()), dltInverse)
+			q = q.add(ec.field.buildMonomial(degreeDiff, scale))
+			r = r.subtract(rLast.multiplyByMonomial(degreeDiff, scale))
+		}
+
+		t = q.multiply(tLast).subtract(tLastLast).negative()
+	}
+
+	sigmaTildeAtZero := t.getCoefficient(0)
+	if sigmaTildeAtZero == 0 {
+		return nil, nil, core.ErrChecksum
+	}
+
+	inverse := ec.field.inverse(sigmaTildeAtZero)
+	sigma := t.multiplyScalar(inverse)
+	omega := r.multiplyScalar(inverse)
+	return sigma, omega, nil
+}
+
+func (ec *ErrorCorrection) findErrorLocations(errorLocator *modulusPoly) ([]int, error) {
+	// This is a direct application of Chien's search
+	numErrors := errorLocator.getDegree()
+	result := make([]int, numErrors)
+	e := 0
+	for i := 1; i < ec.field.getSize() && e < numErrors; i++ {
+		if errorLocator.evaluateAt(i) == 0 {
+			result[e] = ec.field.inverse(i)
+			e++
+		}
+	}
+	if e != numErrors {
+		return nil, core.ErrChecksum
+	}
+	return result, nil
+}
+
+func (ec *ErrorCorrection) findErrorMagnitudes(errorEvaluator, errorLocator *modulusPoly, errorLocations []int) ([]int, error) {
+	errorLocatorDegree := errorLocator.getDegree()
+	if errorLocatorDegree < 1 {
+		return nil, core.ErrChecksum
+	}
+	formalDerivativeCoefficients := make([]int, errorLocatorDegree)
+	for i := 1; i <= errorLocatorDegree; i++ {
+		formalDerivativeCoefficients[errorLocatorDegree-i] =
+			ec.field.multiply(i, errorLocator.getCoefficient(i))
+	}
+	formalDerivative := newModulusPoly(ec.field, formalDerivativeCoefficients)
+
+	// This is directly applying Forney's Formula
+	result := make([]int, len(errorLocations))
+	for i, location := range errorLocations {
+		xiInverse := ec.field.inverse(location)
+		denominator := formalDerivative.evaluateAt(xiInverse)
+		if denominator == 0 {
+			return nil, core.ErrChecksum
+		}
+		numerator := ec.field.subtract(0, errorEvaluator.evaluateAt(xiInverse))
+		result[i] = ec.field.multiply(numerator, ec.field.inverse(denominator))
+	}
+	return result, nil
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2012 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ec
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/internal"
+)
+
+// generator returns the PDF417 generator polynomial for numECCodewords
+// error-correction codewords: the product of (x - 3^i) for i = 1..k.
+func generator(numECCodewords int) *modulusPoly {
+	g := pdf417GF.getOne()
+	for i := 1; i <= numECCodewords; i++ {
+		g = g.multiply(newModulusPoly(pdf417GF, []int{1, pdf417GF.subtract(0, pdf417GF.exp(i))}))
+	}
+	return g
+}
+
+// encode appends numECCodewords error-correction codewords to data.
+func encode(data []int, numECCodewords int) []int {
+	g := generator(numECCodewords)
+	r := newModulusPoly(pdf417GF, data).multiplyByMonomial(numECCodewords, 1)
+	for r.getDegree() >= g.getDegree() && !r.isZero() {
+		degreeDiff := r.getDegree() - g.getDegree()
+		r = r.subtract(g.multiplyByMonomial(degreeDiff, r.getCoefficient(r.getDegree())))
+	}
+	result := make([]int, len(data)+numECCodewords)
+	copy(result, data)
+	for i := 0; i < numECCodewords; i++ {
+		if i <= r.getDegree() {
+			result[len(result)-1-i] = pdf417GF.subtract(0, r.getCoefficient(i))
+		}
+	}
+	return result
+}
+
+func randomMessage(random *rand.Rand, dataSize, ecSize int) []int {
+	data := make([]int, dataSize)
+	for i := range data {
+		data[i] = random.Intn(929)
+	}
+	return encode(data, ecSize)
+}
+
+func TestGenerator(t *testing.T) {
+	// Level 0 uses two error-correction codewords, x^2 + 917x + 27
+	g := generator(2)
+	internal.AssertEquals(t, 27, g.getCoefficient(0), "x^0 coefficient")
+	internal.AssertEquals(t, 917, g.getCoefficient(1), "x^1 coefficient")
+	internal.AssertEquals(t, 1, g.getCoefficient(2), "x^2 coefficient")
+}
+
+func TestNoError(t *testing.T) {
+	random := rand.New(rand.NewSource(0xDEADBEEF))
+	received := randomMessage(random, 20, 8)
+	corrected, err := NewErrorCorrection().Decode(received, 8, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 0, corrected, "clean codewords reported corrections")
+}
+
+func TestErrorsAndErasures(t *testing.T) {
+	random := rand.New(rand.NewSource(0xDEADBEEF))
+	for _, ecSize := range []int{2, 8, 16, 64} {
+		// Trade two errors for each pair of erasures, up to the capacity
+		for numErasures := 0; numErasures <= ecSize; numErasures += 2 {
+			numErrors := (ecSize - numErasures) / 2
+			expected := randomMessage(random, 30, ecSize)
+			received := make([]int, len(expected))
+			copy(received, expected)
+
+			positions := random.Perm(len(received))[:numErrors+numErasures]
+			erasures := positions[numErrors:]
+			for _, position := range positions[:numErrors] {
+				received[position] = (received[position] + 1 + random.Intn(928)) % 929
+			}
+			for _, position := range erasures {
+				received[position] = 0
+			}
+
+			label := strconv.Itoa(ecSize) + "/" + strconv.Itoa(numErasures)
+			_, err := NewErrorCorrection().Decode(received, ecSize, erasures)
+			internal.AssertSuccess(t, err)
+			for i := range expected {
+				internal.AssertEquals(t, expected[i], received[i], label+": codeword "+strconv.Itoa(i)+" was not corrected")
+			}
+		}
+	}
+}
+
+func TestTooManyErrors(t *testing.T) {
+	random := rand.New(rand.NewSource(0xDEADBEEF))
+	expected := randomMessage(random, 30, 8)
+	failures := 0
+	for iteration := 0; iteration < 20; iteration++ {
+		received := make([]int, len(expected))
+		copy(received, expected)
+		for _, position := range random.Perm(len(received))[:8] {
+			received[position] = (received[position] + 1 + random.Intn(928)) % 929
+		}
+		if _, err := NewErrorCorrection().Decode(received, 8, nil); err != nil {
+			failures++
+		}
+	}
+	// Miscorrections are possible, but should be rare
+	internal.AssertTrue(t, failures > 10, "too many errors were accepted")
+}
+
+func TestTooManyErasures(t *testing.T) {
+	random := rand.New(rand.NewSource(0xDEADBEEF))
+	received := randomMessage(random, 10, 4)
+	_, err := NewErrorCorrection().Decode(received, 4, []int{0, 1, 2, 3, 4})
+	internal.AssertFailure(t, err, "more erasures than error-correction codewords")
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2012 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ec
+
+// modulusGF is a field based on powers of a generator integer, modulo some
+// modulus.
+type modulusGF struct {
+	expTable []int
+	logTable []int
+	zero     *modulusPoly
+	one      *modulusPoly
+	modulus  int
+}
+
+// pdf417GF is the field used by PDF417: GF(929) with generator 3.
+var pdf417GF = newModulusGF(929, 3)
+
+func newModulusGF(modulus, generator int) *modulusGF {
+	gf := &modulusGF{
+		expTable: make([]int, modulus),
+		logTable: make([]int, modulus),
+		modulus:  modulus,
+	}
+	x := 1
+	for i := 0; i < modulus; i++ {
+		gf.expTable[i] = x
+		x = (x * generator) % modulus
+	}
+	for i := 0; i < modulus-1; i++ {
+		gf.logTable[gf.expTable[i]] = i
+	}
+	// logTable[0] == 0 but this should never be used
+	gf.zero = newModulusPoly(gf, []int{0})
+	gf.one = newModulusPoly(gf, []int{1})
+	return gf
+}
+
+func (gf *modulusGF) getZero() *modulusPoly {
+	return gf.zero
+}
+
+func (gf *modulusGF) getOne() *modulusPoly {
+	return gf.one
+}
+
+func (gf *modulusGF) buildMonomial(degree, coefficient int) *modulusPoly {
+	if degree < 0 {
+		panic("ec: negative degree")
+	}
+	if coefficient == 0 {
+		return gf.zero
+	}
+	coefficients := make([]int, degree+1)
+	coefficients[0] = coefficient
+	return newModulusPoly(gf, coefficients)
+}
+
+func (gf *modulusGF) add(a, b int) int {
+	return (a + b) % gf.modulus
+}
+
+func (gf *modulusGF) subtract(a, b int) int {
+	return (gf.modulus + a - b) % gf.modulus
+}
+
+func (gf *modulusGF) exp(a int) int {
+	return gf.expTable[a]
+}
+
+// log returns the log of a to the base of the generator. a must not be 0.
+func (gf *modulusGF) log(a int) int {
+	if a == 0 {
+		panic("ec: log of 0")
+	}
+	return gf.logTable[a]
+}
+
+// inverse returns the multiplicative inverse of a. a must not be 0.
+func (gf *modulusGF) inverse(a int) int {
+	if a == 0 {
+		panic("ec: inverse of 0")
+	}
+	return gf.expTable[gf.modulus-gf.logTable[a]-1]
+}
+
+func (gf *modulusGF) multiply(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gf.expTable[(gf.logTable[a]+gf.logTable[b])%(gf.modulus-1)]
+}
+
+func (gf *modulusGF) getSize() int {
+	return gf.modulus
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright 2012 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ec
+
+// modulusPoly is a polynomial whose coefficients are elements of a
+// modulusGF, the highest degree coefficient first.
+type modulusPoly struct {
+	field        *modulusGF
+	coefficients []int
+}
+
+func newModulusPoly(field *modulusGF, coefficients []int) *modulusPoly {
+	if len(coefficients) == 0 {
+		panic("ec: no coefficients")
+	}
+	coefficientsLength := len(coefficients)
+	if coefficientsLength > 1 && coefficients[0] == 0 {
+		// Leading term must be non-zero for anything except the constant polynomial "0"
+		firstNonZero := 1
+		for firstNonZero < coefficientsLength && coefficients[firstNonZero] == 0 {
+			firstNonZero++
+		}
+		if firstNonZero == coefficientsLength {
+			coefficients = []int{0}
+		} else {
+			coefficients = coefficients[firstNonZero:]
+		}
+	}
+	return &modulusPoly{field, coefficients}
+}
+
+func (p *modulusPoly) getDegree() int {
+	return len(p.coefficients) - 1
+}
+
+func (p *modulusPoly) isZero() bool {
+	return p.coefficients[0] == 0
+}
+
+// getCoefficient returns the coefficient of the x^degree term.
+func (p *modulusPoly) getCoefficient(degree int) int {
+	return p.coefficients[len(p.coefficients)-1-degree]
+}
+
+// evaluateAt returns the value of the polynomial at a.
+func (p *modulusPoly) evaluateAt(a int) int {
+	if a == 0 {
+		// Just return the x^0 coefficient
+		return p.getCoefficient(0)
+	}
+	if a == 1 {
+		// Just the sum of the coefficients
+		result := 0
+		for _, coefficient := range p.coefficients {
+			result = p.field.add(result, coefficient)
+		}
+		return result
+	}
+	result := p.coefficients[0]
+	for _, coefficient := range p.coefficients[1:] {
+		result = p.field.add(p.field.multiply(a, result), coefficient)
+	}
+	return result
+}
+
+func (p *modulusPoly) add(other *modulusPoly) *modulusPoly {
+	if p.isZero() {
+		return other
+	}
+	if other.isZero() {
+		return p
+	}
+
+	smallerCoefficients := p.coefficients
+	largerCoefficients := other.coefficients
+	if len(smallerCoefficients) > len(largerCoefficients) {
+		smallerCoefficients, largerCoefficients = largerCoefficients, smallerCoefficients
+	}
+	sumDiff := make([]int, len(largerCoefficients))
+	lengthDiff := len(largerCoefficients) - len(smallerCoefficients)
+	// Copy high-order terms only found in higher-degree polynomial's coefficients
+	copy(sumDiff, largerCoefficients[:lengthDiff])
+	for i := lengthDiff; i < len(largerCoefficients); i++ {
+		sumDiff[i] = p.field.add(smallerCoefficients[i-lengthDiff], largerCoefficients[i])
+	}
+	return newModulusPoly(p.field, sumDiff)
+}
+
+func (p *modulusPoly) subtract(other *modulusPoly) *modulusPoly {
+	if other.isZero() {
+		return p
+	}
+	return p.add(other.negative())
+}
+
+func (p *modulusPoly) multiply(other *modulusPoly) *modulusPoly {
+	if p.isZero() || other.isZero() {
+		return p.field.getZero()
+	}
+	aCoefficients := p.coefficients
+	bCoefficients := other.coefficients
+	product := make([]int, len(aCoefficients)+len(bCoefficients)-1)
+	for i, aCoeff := range aCoefficients {
+		for j, bCoeff := range bCoefficients {
+			product[i+j] = p.field.add(product[i+j], p.field.multiply(aCoeff, bCoeff))
+		}
+	}
+	return newModulusPoly(p.field, product)
+}
+
+func (p *modulusPoly) negative() *modulusPoly {
+	negativeCoefficients := make([]int, len(p.coefficients))
+	for i, coefficient := range p.coefficients {
+		negativeCoefficients[i] = p.field.subtract(0, coefficient)
+	}
+	return newModulusPoly(p.field, negativeCoefficients)
+}
+
+func (p *modulusPoly) multiplyScalar(scalar int) *modulusPoly {
+	if scalar == 0 {
+		return p.field.getZero()
+	}
+	if scalar == 1 {
+		return p
+	}
+	product := make([]int, len(p.coefficients))
+	for i, coefficient := range p.coefficients {
+		product[i] = p.field.multiply(coefficient, scalar)
+	}
+	return newModulusPoly(p.field, product)
+}
+
+func (p *modulusPoly) multiplyByMonomial(degree, coefficient int) *modulusPoly {
+	if degree < 0 {
+		panic("ec: negative degree")
+	}
+	if coefficient == 0 {
+		return p.field.getZero()
+	}
+	product := make([]int, len(p.coefficients)+degree)
+	for i, c := range p.coefficients {
+		product[i] = p.field.multiply(c, coefficient)
+	}
+	return newModulusPoly(p.field, product)
+}
+
+// truncate returns the polynomial modulo x^degree.
+func (p *modulusPoly) truncate(degree int) *modulusPoly {
+	if p.getDegree() < degree {
+		return p
+	}
+	return newModulusPoly(p.field, p.coefficients[len(p.coefficients)-degree:])
+}
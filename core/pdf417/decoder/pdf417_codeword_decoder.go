@@ -0,0 +1,123 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"math"
+
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+)
+
+// ratiosTable holds, for each entry of the symbol table, the width of each
+// bar and space relative to the width of the codeword.
+var ratiosTable = buildRatiosTable()
+
+func buildRatiosTable() [][]float32 {
+	table := make([][]float32, len(pdf417common.SymbolTable))
+	for i, currentSymbol := range pdf417common.SymbolTable {
+		table[i] = make([]float32, pdf417common.BarsInModule)
+		currentBit := currentSymbol & 0x1
+		for j := 0; j < pdf417common.BarsInModule; j++ {
+			var size float32
+			for (currentSymbol & 0x1) == currentBit {
+				size++
+				currentSymbol >>= 1
+			}
+			currentBit = currentSymbol & 0x1
+			table[i][pdf417common.BarsInModule-j-1] = size / pdf417common.ModulesInCodeword
+		}
+	}
+	return table
+}
+
+// getDecodedValue turns the measured widths of the 8 bars and spaces of a
+// codeword into its 17-bit pattern, or -1 if no pattern is close enough.
+func getDecodedValue(moduleBitCount []int) int {
+	resultValue := getDecodedCodewordValue(sampleBitCounts(moduleBitCount))
+	if resultValue != -1 {
+		return resultValue
+	}
+	return getClosestDecodedValue(moduleBitCount)
+}
+
+// sampleBitCounts resamples the measured widths to a total of 17 modules.
+func sampleBitCounts(moduleBitCount []int) []int {
+	bitCountSum := float32(pdf417common.GetBitCountSum(moduleBitCount))
+	result := make([]int, pdf417common.BarsInModule)
+	bitCountIndex := 0
+	sumPreviousBits := 0
+	for i := 0; i < pdf417common.ModulesInCodeword; i++ {
+		sampleIndex := bitCountSum/(2*pdf417common.ModulesInCodeword) +
+			(float32(i)*bitCountSum)/pdf417common.ModulesInCodeword
+		if bitCountIndex < len(result)-1 && float32(sumPreviousBits+moduleBitCount[bitCountIndex]) <= sampleIndex {
+			sumPreviousBits += moduleBitCount[bitCountIndex]
+			bitCountIndex++
+		}
+		result[bitCountIndex]++
+	}
+	return result
+}
+
+func getDecodedCodewordValue(moduleBitCount []int) int {
+	decodedValue := getBitValue(moduleBitCount)
+	if pdf417common.GetCodeword(decodedValue) == -1 {
+		return -1
+	}
+	return decodedValue
+}
+
+func getBitValue(moduleBitCount []int) int {
+	result := 0
+	for i, count := range moduleBitCount {
+		for bit := 0; bit < count; bit++ {
+			result <<= 1
+			if i%2 == 0 {
+				result |= 1
+			}
+		}
+	}
+	return result
+}
+
+// getClosestDecodedValue returns the pattern whose relative bar widths are
+// closest to the measured ones.
+func getClosestDecodedValue(moduleBitCount []int) int {
+	bitCountSum := pdf417common.GetBitCountSum(moduleBitCount)
+	bitCountRatios := make([]float32, pdf417common.BarsInModule)
+	if bitCountSum > 1 {
+		for i := range bitCountRatios {
+			bitCountRatios[i] = float32(moduleBitCount[i]) / float32(bitCountSum)
+		}
+	}
+	bestMatchError := float32(math.MaxFloat32)
+	bestMatch := -1
+	for j, ratioTableRow := range ratiosTable {
+		var matchError float32
+		for k := 0; k < pdf417common.BarsInModule; k++ {
+			diff := ratioTableRow[k] - bitCountRatios[k]
+			matchError += diff * diff
+			if matchError >= bestMatchError {
+				break
+			}
+		}
+		if matchError < bestMatchError {
+			bestMatchError = matchError
+			bestMatch = pdf417common.SymbolTable[j]
+		}
+	}
+	return bestMatch
+}
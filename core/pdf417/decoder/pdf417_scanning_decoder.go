@@ -0,0 +1,650 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/common/detector"
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+	"github.com/discesoft/zxing-go/core/pdf417/decoder/ec"
+)
+
+const (
+	codewordSkewSize = 2
+	maxErrors        = 3
+	maxECCodewords   = 512
+)
+
+var errorCorrection = ec.NewErrorCorrection()
+
+// Decode reads the PDF417 symbol within the given corners of image, which
+// are as located by the detector. Either the left or the right pair of
+// corners may be nil, as is the right pair for a compact (truncated)
+// symbol, which has no right row indicator. minCodewordWidth and
+// maxCodewordWidth are the expected range of codeword widths in pixels.
+// It returns the decoded result, or core.ErrNotFound if no symbol could be
+// read, core.ErrFormat if its contents are invalid, or core.ErrChecksum if
+// error correction failed.
+func Decode(image *common.BitMatrix, imageTopLeft, imageBottomLeft, imageTopRight, imageBottomRight *core.ResultPoint,
+	minCodewordWidth, maxCodewordWidth int) (*common.DecoderResult, error) {
+	boundingBox, err := newBoundingBox(image, imageTopLeft, imageBottomLeft, imageTopRight, imageBottomRight)
+	if err != nil {
+		return nil, err
+	}
+	var leftRowIndicatorColumn, rightRowIndicatorColumn *detectionResultRowIndicatorColumn
+	var result *detectionResult
+	for firstPass := true; ; firstPass = false {
+		if imageTopLeft != nil {
+			leftRowIndicatorColumn = getRowIndicatorColumn(image, boundingBox, imageTopLeft, true, minCodewordWidth, maxCodewordWidth)
+		}
+		if imageTopRight != nil {
+			rightRowIndicatorColumn = getRowIndicatorColumn(image, boundingBox, imageTopRight, false, minCodewordWidth, maxCodewordWidth)
+		}
+		result, err = merge(leftRowIndicatorColumn, rightRowIndicatorColumn)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			return nil, core.ErrNotFound
+		}
+		resultBox := result.getBoundingBox()
+		if firstPass && resultBox != nil &&
+			(resultBox.getMinY() < boundingBox.getMinY() || resultBox.getMaxY() > boundingBox.getMaxY()) {
+			boundingBox = resultBox
+		} else {
+			break
+		}
+	}
+	result.setBoundingBox(boundingBox)
+	maxBarcodeColumn := result.getBarcodeColumnCount() + 1
+	if leftRowIndicatorColumn != nil {
+		result.setRowIndicatorColumn(leftRowIndicatorColumn)
+	}
+	if rightRowIndicatorColumn != nil {
+		result.setRowIndicatorColumn(rightRowIndicatorColumn)
+	}
+
+	leftToRight := leftRowIndicatorColumn != nil
+	for barcodeColumnCount := 1; barcodeColumnCount <= maxBarcodeColumn; barcodeColumnCount++ {
+		barcodeColumn := barcodeColumnCount
+		if !leftToRight {
+			barcodeColumn = maxBarcodeColumn - barcodeColumnCount
+		}
+		if result.getDetectionResultColumn(barcodeColumn) != nil {
+			// This will be the case for the opposite row indicator column,
+			// which doesn't need to be decoded again.
+			continue
+		}
+		var column *detectionResultColumn
+		if barcodeColumn == 0 || barcodeColumn == maxBarcodeColumn {
+			rowIndicatorColumn := newDetectionResultRowIndicatorColumn(boundingBox, barcodeColumn == 0)
+			result.setRowIndicatorColumn(rowIndicatorColumn)
+			column = rowIndicatorColumn.detectionResultColumn
+		} else {
+			column = newDetectionResultColumn(boundingBox)
+			result.setDetectionResultColumn(barcodeColumn, column)
+		}
+		startColumn := -1
+		previousStartColumn := startColumn
+		// TODO start at a row for which we know the start position, then
+		// detect upwards and downwards from there.
+		for imageRow := boundingBox.getMinY(); imageRow <= boundingBox.getMaxY(); imageRow++ {
+			startColumn = getStartColumn(result, barcodeColumn, imageRow, leftToRight)
+			if startColumn < 0 || startColumn > boundingBox.getMaxX() {
+				if previousStartColumn == -1 {
+					continue
+				}
+				startColumn = previousStartColumn
+			}
+			cw := detectCodeword(image, boundingBox.getMinX(), boundingBox.getMaxX(), leftToRight,
+				startColumn, imageRow, minCodewordWidth, maxCodewordWidth)
+			if cw != nil {
+				column.setCodeword(imageRow, cw)
+				previousStartColumn = startColumn
+				if cw.getWidth() < minCodewordWidth {
+					minCodewordWidth = cw.getWidth()
+				}
+				if cw.getWidth() > maxCodewordWidth {
+					maxCodewordWidth = cw.getWidth()
+				}
+			}
+		}
+	}
+	return createDecoderResult(result)
+}
+
+// merge creates a detectionResult from whichever row indicator columns
+// were found, or returns nil if the barcode metadata cannot be read.
+func merge(leftRowIndicatorColumn, rightRowIndicatorColumn *detectionResultRowIndicatorColumn) (*detectionResult, error) {
+	if leftRowIndicatorColumn == nil && rightRowIndicatorColumn == nil {
+		return nil, nil
+	}
+	metadata := getBarcodeMetadata(leftRowIndicatorColumn, rightRowIndicatorColumn)
+	if metadata == nil {
+		return nil, nil
+	}
+	leftBox, err := adjustBoundingBox(leftRowIndicatorColumn)
+	if err != nil {
+		return nil, err
+	}
+	rightBox, err := adjustBoundingBox(rightRowIndicatorColumn)
+	if err != nil {
+		return nil, err
+	}
+	boundingBox, err := mergeBoundingBoxes(leftBox, rightBox)
+	if err != nil {
+		return nil, err
+	}
+	return newDetectionResult(metadata, boundingBox), nil
+}
+
+// adjustBoundingBox extends the bounding box of rowIndicatorColumn to
+// include barcode rows which were not read at its top and bottom.
+func adjustBoundingBox(rowIndicatorColumn *detectionResultRowIndicatorColumn) (*boundingBox, error) {
+	if rowIndicatorColumn == nil {
+		return nil, nil
+	}
+	rowHeights := rowIndicatorColumn.getRowHeights()
+	if rowHeights == nil {
+		return nil, nil
+	}
+	maxRowHeight := getMax(rowHeights)
+	missingStartRows := 0
+	for _, rowHeight := range rowHeights {
+		missingStartRows += maxRowHeight - rowHeight
+		if rowHeight > 0 {
+			break
+		}
+	}
+	codewords := rowIndicatorColumn.getCodewords()
+	for row := 0; missingStartRows > 0 && row < len(codewords) && codewords[row] == nil; row++ {
+		missingStartRows--
+	}
+	missingEndRows := 0
+	for row := len(rowHeights) - 1; row >= 0; row-- {
+		missingEndRows += maxRowHeight - rowHeights[row]
+		if rowHeights[row] > 0 {
+			break
+		}
+	}
+	for row := len(codewords) - 1; missingEndRows > 0 && row >= 0 && codewords[row] == nil; row-- {
+		missingEndRows--
+	}
+	return rowIndicatorColumn.getBoundingBox().addMissingRows(missingStartRows, missingEndRows, rowIndicatorColumn.isLeft)
+}
+
+func getMax(values []int) int {
+	maxValue := -1
+	for _, value := range values {
+		if value > maxValue {
+			maxValue = value
+		}
+	}
+	return maxValue
+}
+
+func getBarcodeMetadata(leftRowIndicatorColumn, rightRowIndicatorColumn *detectionResultRowIndicatorColumn) *barcodeMetadata {
+	var leftBarcodeMetadata *barcodeMetadata
+	if leftRowIndicatorColumn != nil {
+		leftBarcodeMetadata = leftRowIndicatorColumn.getBarcodeMetadata()
+	}
+	if leftBarcodeMetadata == nil {
+		if rightRowIndicatorColumn == nil {
+			return nil
+		}
+		return rightRowIndicatorColumn.getBarcodeMetadata()
+	}
+	var rightBarcodeMetadata *barcodeMetadata
+	if rightRowIndicatorColumn != nil {
+		rightBarcodeMetadata = rightRowIndicatorColumn.getBarcodeMetadata()
+	}
+	if rightBarcodeMetadata == nil {
+		return leftBarcodeMetadata
+	}
+
+	if leftBarcodeMetadata.getColumnCount() != rightBarcodeMetadata.getColumnCount() &&
+		leftBarcodeMetadata.getErrorCorrectionLevel() != rightBarcodeMetadata.getErrorCorrectionLevel() &&
+		leftBarcodeMetadata.getRowCount() != rightBarcodeMetadata.getRowCount() {
+		return nil
+	}
+	return leftBarcodeMetadata
+}
+
+// getRowIndicatorColumn reads the row indicator column starting at
+// startPoint, both downwards and upwards.
+func getRowIndicatorColumn(image *common.BitMatrix, boundingBox *boundingBox, startPoint *core.ResultPoint, leftToRight bool,
+	minCodewordWidth, maxCodewordWidth int) *detectionResultRowIndicatorColumn {
+	rowIndicatorColumn := newDetectionResultRowIndicatorColumn(boundingBox, leftToRight)
+	for i := 0; i < 2; i++ {
+		increment := 1
+		if i != 0 {
+			increment = -1
+		}
+		startColumn := int(startPoint.GetX())
+		for imageRow := int(startPoint.GetY()); imageRow <= boundingBox.getMaxY() && imageRow >= boundingBox.getMinY(); imageRow += increment {
+			cw := detectCodeword(image, 0, int(image.GetWidth()), leftToRight, startColumn, imageRow, minCodewordWidth, maxCodewordWidth)
+			if cw != nil {
+				rowIndicatorColumn.setCodeword(imageRow, cw)
+				if leftToRight {
+					startColumn = cw.startX
+				} else {
+					startColumn = cw.endX
+				}
+			}
+		}
+	}
+	return rowIndicatorColumn
+}
+
+// adjustCodewordCount makes sure the symbol length descriptor agrees with
+// the dimensions read from the row indicator columns.
+func adjustCodewordCount(result *detectionResult, barcodeMatrix [][]*barcodeValue) error {
+	barcodeMatrix01 := barcodeMatrix[0][1]
+	numberOfCodewords := barcodeMatrix01.getValue()
+	calculatedNumberOfCodewords := result.getBarcodeColumnCount()*result.getBarcodeRowCount() -
+		getNumberOfECCodeWords(result.getBarcodeECLevel())
+	if len(numberOfCodewords) == 0 {
+		if calculatedNumberOfCodewords < 1 || calculatedNumberOfCodewords > pdf417common.MaxCodewordsInBarcode {
+			return core.ErrNotFound
+		}
+		barcodeMatrix01.setValue(calculatedNumberOfCodewords)
+	} else if numberOfCodewords[0] != calculatedNumberOfCodewords {
+		if calculatedNumberOfCodewords >= 1 && calculatedNumberOfCodewords <= pdf417common.MaxCodewordsInBarcode {
+			// The calculated one is more reliable as it is derived from
+			// the row indicator columns
+			barcodeMatrix01.setValue(calculatedNumberOfCodewords)
+		}
+	}
+	return nil
+}
+
+func createDecoderResult(result *detectionResult) (*common.DecoderResult, error) {
+	barcodeMatrix := createBarcodeMatrix(result)
+	if err := adjustCodewordCount(result, barcodeMatrix); err != nil {
+		return nil, err
+	}
+	var erasures []int
+	codewords := make([]int, result.getBarcodeRowCount()*result.getBarcodeColumnCount())
+	var ambiguousIndexValues [][]int
+	var ambiguousIndexes []int
+	for row := 0; row < result.getBarcodeRowCount(); row++ {
+		for column := 0; column < result.getBarcodeColumnCount(); column++ {
+			values := barcodeMatrix[row][column+1].getValue()
+			codewordIndex := row*result.getBarcodeColumnCount() + column
+			if len(values) == 0 {
+				erasures = append(erasures, codewordIndex)
+			} else if len(values) == 1 {
+				codewords[codewordIndex] = values[0]
+			} else {
+				ambiguousIndexes = append(ambiguousIndexes, codewordIndex)
+				ambiguousIndexValues = append(ambiguousIndexValues, values)
+			}
+		}
+	}
+	return createDecoderResultFromAmbiguousValues(result.getBarcodeECLevel(), codewords, erasures,
+		ambiguousIndexes, ambiguousIndexValues)
+}
+
+// createDecoderResultFromAmbiguousValues deals with the fact that the
+// decoding process doesn't always yield a single most likely value. It is
+// better to provide a value for ambiguous codewords instead of treating
+// them as erasures, but we don't know which of the values to choose. We
+// try to decode using the first value, and if that fails, we use another
+// of the ambiguous values and try to decode again. This usually only
+// happens on very hard to read and decode barcodes, so decoding the
+// normal barcodes is not affected by this.
+func createDecoderResultFromAmbiguousValues(ecLevel int, codewords, erasureArray, ambiguousIndexes []int,
+	ambiguousIndexValues [][]int) (*common.DecoderResult, error) {
+	ambiguousIndexCount := make([]int, len(ambiguousIndexes))
+
+	for tries := 100; tries > 0; tries-- {
+		for i, count := range ambiguousIndexCount {
+			codewords[ambiguousIndexes[i]] = ambiguousIndexValues[i][count]
+		}
+		result, err := decodeCodewords(codewords, ecLevel, erasureArray)
+		if err != core.ErrChecksum {
+			return result, err
+		}
+		if len(ambiguousIndexCount) == 0 {
+			return nil, core.ErrChecksum
+		}
+		for i := range ambiguousIndexCount {
+			if ambiguousIndexCount[i] < len(ambiguousIndexValues[i])-1 {
+				ambiguousIndexCount[i]++
+				break
+			}
+			ambiguousIndexCount[i] = 0
+			if i == len(ambiguousIndexCount)-1 {
+				return nil, core.ErrChecksum
+			}
+		}
+	}
+	return nil, core.ErrChecksum
+}
+
+func createBarcodeMatrix(result *detectionResult) [][]*barcodeValue {
+	barcodeMatrix := make([][]*barcodeValue, result.getBarcodeRowCount())
+	for row := range barcodeMatrix {
+		barcodeMatrix[row] = make([]*barcodeValue, result.getBarcodeColumnCount()+2)
+		for column := range barcodeMatrix[row] {
+			barcodeMatrix[row][column] = newBarcodeValue()
+		}
+	}
+
+	for column, detectionResultColumn := range result.getDetectionResultColumns() {
+		if detectionResultColumn == nil {
+			continue
+		}
+		for _, cw := range detectionResultColumn.getCodewords() {
+			if cw == nil {
+				continue
+			}
+			rowNumber := cw.rowNumber
+			if rowNumber >= 0 {
+				if rowNumber >= len(barcodeMatrix) {
+					// We have more rows than the barcode metadata allows
+					// for, ignore them.
+					continue
+				}
+				barcodeMatrix[rowNumber][column].setValue(cw.value)
+			}
+		}
+	}
+	return barcodeMatrix
+}
+
+func isValidBarcodeColumn(result *detectionResult, barcodeColumn int) bool {
+	return barcodeColumn >= 0 && barcodeColumn <= result.getBarcodeColumnCount()+1
+}
+
+// getStartColumn estimates where the codeword of barcodeColumn starts in
+// imageRow, from the codewords read so far.
+func getStartColumn(result *detectionResult, barcodeColumn, imageRow int, leftToRight bool) int {
+	offset := 1
+	if !leftToRight {
+		offset = -1
+	}
+	leadingEdge := func(cw *codeword) int {
+		if leftToRight {
+			return cw.startX
+		}
+		return cw.endX
+	}
+	trailingEdge := func(cw *codeword) int {
+		if leftToRight {
+			return cw.endX
+		}
+		return cw.startX
+	}
+
+	var cw *codeword
+	if isValidBarcodeColumn(result, barcodeColumn-offset) {
+		if column := result.getDetectionResultColumn(barcodeColumn - offset); column != nil {
+			cw = column.getCodeword(imageRow)
+		}
+	}
+	if cw != nil {
+		return trailingEdge(cw)
+	}
+	cw = result.getDetectionResultColumn(barcodeColumn).getCodewordNearby(imageRow)
+	if cw != nil {
+		return leadingEdge(cw)
+	}
+	if isValidBarcodeColumn(result, barcodeColumn-offset) {
+		if column := result.getDetectionResultColumn(barcodeColumn - offset); column != nil {
+			cw = column.getCodewordNearby(imageRow)
+		}
+	}
+	if cw != nil {
+		return trailingEdge(cw)
+	}
+	skippedColumns := 0
+
+	for isValidBarcodeColumn(result, barcodeColumn-offset) {
+		barcodeColumn -= offset
+		if column := result.getDetectionResultColumn(barcodeColumn); column != nil {
+			for _, previousRowCodeword := range column.getCodewords() {
+				if previousRowCodeword != nil {
+					return trailingEdge(previousRowCodeword) +
+						offset*skippedColumns*(previousRowCodeword.endX-previousRowCodeword.startX)
+				}
+			}
+		}
+		skippedColumns++
+	}
+	if leftToRight {
+		return result.getBoundingBox().getMinX()
+	}
+	return result.getBoundingBox().getMaxX()
+}
+
+// detectCodeword reads the codeword starting, or ending if reading right to
+// left, at startColumn in imageRow.
+// It returns nil if no valid codeword is found there.
+func detectCodeword(image *common.BitMatrix, minColumn, maxColumn int, leftToRight bool, startColumn, imageRow,
+	minCodewordWidth, maxCodewordWidth int) *codeword {
+	startColumn = adjustCodewordStartColumn(image, minColumn, maxColumn, leftToRight, startColumn, imageRow)
+	// we usually know fairly exact now how long a codeword is. We should
+	// provide minimum and maximum expected length and try to adjust the
+	// read pixels, e.g. remove single pixel errors or try to cut off
+	// exceeding pixels. min and maxCodewordWidth should not be used as
+	// they are calculated for the whole barcode an can be inaccurate for
+	// the current position
+	moduleBitCount := getModuleBitCount(image, minColumn, maxColumn, leftToRight, startColumn, imageRow)
+	if moduleBitCount == nil {
+		return nil
+	}
+	var endColumn int
+	codewordBitCount := detector.Sum(moduleBitCount)
+	if leftToRight {
+		endColumn = startColumn + codewordBitCount
+	} else {
+		for i, j := 0, len(moduleBitCount)-1; i < j; i, j = i+1, j-1 {
+			moduleBitCount[i], moduleBitCount[j] = moduleBitCount[j], moduleBitCount[i]
+		}
+		endColumn = startColumn
+		startColumn = endColumn - codewordBitCount
+	}
+
+	// We could also use the width of surrounding codewords for more
+	// accurate results, but this seems sufficient for now
+	if !checkCodewordSkew(codewordBitCount, minCodewordWidth, maxCodewordWidth) {
+		// We could try to use the startX and endX position of the codeword
+		// in the same column in the previous row, create the bit count
+		// from it and normalize it to 8. This would help with single pixel
+		// errors.
+		return nil
+	}
+
+	decodedValue := getDecodedValue(moduleBitCount)
+	value := pdf417common.GetCodeword(decodedValue)
+	if value == -1 {
+		return nil
+	}
+	return newCodeword(startColumn, endColumn, getCodewordBucketNumber(decodedValue), value)
+}
+
+// getModuleBitCount measures the widths of the 8 bars and spaces of the
+// codeword at startColumn.
+func getModuleBitCount(image *common.BitMatrix, minColumn, maxColumn int, leftToRight bool, startColumn, imageRow int) []int {
+	imageColumn := startColumn
+	moduleBitCount := make([]int, 8)
+	moduleNumber := 0
+	increment := 1
+	if !leftToRight {
+		increment = -1
+	}
+	previousPixelValue := leftToRight
+	for (leftToRight && imageColumn < maxColumn || !leftToRight && imageColumn >= minColumn) &&
+		moduleNumber < len(moduleBitCount) {
+		if getPixel(image, imageColumn, imageRow) == previousPixelValue {
+			moduleBitCount[moduleNumber]++
+			imageColumn += increment
+		} else {
+			moduleNumber++
+			previousPixelValue = !previousPixelValue
+		}
+	}
+	limit := maxColumn
+	if !leftToRight {
+		limit = minColumn
+	}
+	if moduleNumber == len(moduleBitCount) ||
+		(imageColumn == limit && moduleNumber == len(moduleBitCount)-1) {
+		return moduleBitCount
+	}
+	return nil
+}
+
+// getPixel returns the pixel at x, y, treating pixels outside the image as
+// white.
+func getPixel(image *common.BitMatrix, x, y int) bool {
+	if x < 0 || y < 0 || x >= int(image.GetWidth()) || y >= int(image.GetHeight()) {
+		return false
+	}
+	return image.Get(uint32(x), uint32(y))
+}
+
+func getNumberOfECCodeWords(barcodeECLevel int) int {
+	return 2 << uint(barcodeECLevel)
+}
+
+// adjustCodewordStartColumn moves the start column so that there are no
+// black pixels before it, by at most codewordSkewSize pixels.
+func adjustCodewordStartColumn(image *common.BitMatrix, minColumn, maxColumn int, leftToRight bool,
+	codewordStartColumn, imageRow int) int {
+	correctedStartColumn := codewordStartColumn
+	increment := -1
+	if !leftToRight {
+		increment = 1
+	}
+	// there should be no black pixels before the start column. If there
+	// are, then we need to start earlier.
+	for i := 0; i < 2; i++ {
+		for (leftToRight && correctedStartColumn >= minColumn || !leftToRight && correctedStartColumn < maxColumn) &&
+			leftToRight == getPixel(image, correctedStartColumn, imageRow) {
+			if abs(codewordStartColumn-correctedStartColumn) > codewordSkewSize {
+				return codewordStartColumn
+			}
+			correctedStartColumn += increment
+		}
+		increment = -increment
+		leftToRight = !leftToRight
+	}
+	return correctedStartColumn
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func checkCodewordSkew(codewordSize, minCodewordWidth, maxCodewordWidth int) bool {
+	return minCodewordWidth-codewordSkewSize <= codewordSize &&
+		codewordSize <= maxCodewordWidth+codewordSkewSize
+}
+
+// decodeCodewords corrects errors in the codewords of a symbol and decodes
+// them.
+func decodeCodewords(codewords []int, ecLevel int, erasures []int) (*common.DecoderResult, error) {
+	if len(codewords) == 0 {
+		return nil, core.ErrFormat
+	}
+
+	numECCodewords := 1 << uint(ecLevel+1)
+	correctedErrorsCount, err := correctErrors(codewords, erasures, numECCodewords)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyCodewordCount(codewords, numECCodewords); err != nil {
+		return nil, err
+	}
+
+	// Decode the codewords
+	result, err := decodeBitStream(codewords, strconv.Itoa(ecLevel))
+	if err != nil {
+		return nil, err
+	}
+	result.SetErrorsCorrected(correctedErrorsCount)
+	result.SetErasures(len(erasures))
+	return result, nil
+}
+
+// correctErrors corrects errors and erasures in codewords in place.
+// It returns the number of codewords corrected, or core.ErrChecksum if
+// there are too many of them.
+func correctErrors(codewords, erasures []int, numECCodewords int) (int, error) {
+	if len(erasures) > numECCodewords/2+maxErrors || numECCodewords < 0 || numECCodewords > maxECCodewords {
+		// Too many errors or EC Codewords is corrupted
+		return 0, core.ErrChecksum
+	}
+	return errorCorrection.Decode(codewords, numECCodewords, erasures)
+}
+
+// verifyCodewordCount checks the symbol length descriptor, the first
+// codeword, which encodes the number of data codewords including itself
+// but excluding the error correction codewords.
+func verifyCodewordCount(codewords []int, numECCodewords int) error {
+	if len(codewords) < 4 {
+		// Codeword array size should be at least 4 allowing for Count CW,
+		// At least one Data CW, Error Correction CW, Error Correction CW
+		return core.ErrFormat
+	}
+	numberOfCodewords := codewords[0]
+	if numberOfCodewords > len(codewords) {
+		return core.ErrFormat
+	}
+	if numberOfCodewords == 0 {
+		// Reset to the length of the array less the error correction
+		// codewords
+		if numECCodewords < len(codewords) {
+			codewords[0] = len(codewords) - numECCodewords
+		} else {
+			return core.ErrFormat
+		}
+	}
+	return nil
+}
+
+func getBitCountForCodeword(cw int) []int {
+	result := make([]int, 8)
+	previousValue := 0
+	i := len(result) - 1
+	for {
+		if (cw & 0x1) != previousValue {
+			previousValue = cw & 0x1
+			i--
+			if i < 0 {
+				break
+			}
+		}
+		result[i]++
+		cw >>= 1
+	}
+	return result
+}
+
+// getCodewordBucketNumber returns the cluster of the pattern cw: 0, 3 or 6.
+func getCodewordBucketNumber(cw int) int {
+	moduleBitCount := getBitCountForCodeword(cw)
+	return (moduleBitCount[0] - moduleBitCount[2] + moduleBitCount[4] - moduleBitCount[6] + 9) % 9
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright 2013 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/internal"
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+)
+
+const (
+	testModuleWidth = 2
+	testRowHeight   = 6
+	testQuietZone   = 10
+	startPattern    = 0x1fea8
+	stopPattern     = 0x3fa29
+)
+
+// codewordPatterns maps each cluster, 0 to 2, and codeword to its bar
+// pattern.
+func codewordPatterns() [3]map[int]int {
+	var patterns [3]map[int]int
+	for i := range patterns {
+		patterns[i] = make(map[int]int)
+	}
+	for _, symbol := range pdf417common.SymbolTable {
+		patterns[getCodewordBucketNumber(symbol)/3][pdf417common.GetCodeword(symbol)] = symbol
+	}
+	return patterns
+}
+
+// withErrorCorrection appends the error correction codewords for ecLevel.
+func withErrorCorrection(data []int, ecLevel int) []int {
+	numECCodewords := 2 << uint(ecLevel)
+	// The generator polynomial is the product of (x - 3^i), i = 1..k,
+	// highest degree coefficient first
+	generator := []int{1}
+	power := 1
+	for i := 1; i <= numECCodewords; i++ {
+		power = power * 3 % 929
+		next := make([]int, len(generator)+1)
+		for j, c := range generator {
+			next[j] = (next[j] + c) % 929
+			next[j+1] = (next[j+1] + 929 - c*power%929) % 929
+		}
+		generator = next
+	}
+	remainder := make([]int, len(data)+numECCodewords)
+	copy(remainder, data)
+	for i := range data {
+		factor := remainder[i]
+		for j, c := range generator {
+			remainder[i+j] = (remainder[i+j] + 929 - factor*c%929) % 929
+		}
+	}
+	result := make([]int, len(data), len(remainder))
+	copy(result, data)
+	for _, c := range remainder[len(data):] {
+		result = append(result, (929-c)%929)
+	}
+	return result
+}
+
+// renderSymbol draws a PDF417 symbol holding data, which must fill the
+// given number of rows and columns, and returns the image together with
+// the corners of the symbol's data region.
+func renderSymbol(t *testing.T, data []int, rows, columns, ecLevel int) (*common.BitMatrix, []*core.ResultPoint) {
+	codewords := withErrorCorrection(data, ecLevel)
+	internal.AssertEquals(t, rows*columns, len(codewords), "codewords do not fill the symbol")
+	patterns := codewordPatterns()
+
+	modules := 17 + 17 + 17*columns + 17 + 18
+	width := 2*testQuietZone + modules*testModuleWidth
+	height := 2*testQuietZone + rows*testRowHeight
+	image, err := common.NewBitMatrix(uint32(width), uint32(height))
+	internal.AssertSuccess(t, err)
+
+	for row := 0; row < rows; row++ {
+		cluster := row % 3
+		var left, right int
+		switch cluster {
+		case 0:
+			left = (rows - 1) / 3
+			right = columns - 1
+		case 1:
+			left = ecLevel*3 + (rows-1)%3
+			right = (rows - 1) / 3
+		case 2:
+			left = columns - 1
+			right = ecLevel*3 + (rows-1)%3
+		}
+		rowIndicator := 30 * (row / 3)
+
+		x := testQuietZone
+		draw := func(pattern, numModules int) {
+			for i := numModules - 1; i >= 0; i-- {
+				if pattern&(1<<uint(i)) != 0 {
+					image.SetRegion(uint32(x), uint32(testQuietZone+row*testRowHeight), testModuleWidth, testRowHeight)
+				}
+				x += testModuleWidth
+			}
+		}
+		draw(startPattern, 17)
+		draw(patterns[cluster][rowIndicator+left], 17)
+		for column := 0; column < columns; column++ {
+			draw(patterns[cluster][codewords[row*columns+column]], 17)
+		}
+		draw(patterns[cluster][rowIndicator+right], 17)
+		draw(stopPattern, 18)
+	}
+
+	top := float32(testQuietZone)
+	bottom := float32(testQuietZone + rows*testRowHeight - 1)
+	left := float32(testQuietZone + 17*testModuleWidth)
+	right := float32(testQuietZone + (modules-18)*testModuleWidth)
+	return image, []*core.ResultPoint{
+		core.NewResultPoint(left, top), core.NewResultPoint(left, bottom),
+		core.NewResultPoint(right, top), core.NewResultPoint(right, bottom),
+	}
+}
+
+// helloData is "Hello" in text compaction.
+var helloData = []int{4, 7*30 + 27, 4*30 + 11, 11*30 + 14}
+
+// paddedHelloData pads helloData with text latches to length codewords.
+func paddedHelloData(length int) []int {
+	data := append([]int{length}, helloData[1:]...)
+	for len(data) < length {
+		data = append(data, 900)
+	}
+	return data
+}
+
+func decodeSymbol(image *common.BitMatrix, points []*core.ResultPoint) (*common.DecoderResult, error) {
+	codewordWidth := 17 * testModuleWidth
+	return Decode(image, points[0], points[1], points[2], points[3], codewordWidth, codewordWidth)
+}
+
+func TestDecode(t *testing.T) {
+	image, points := renderSymbol(t, paddedHelloData(6), 4, 2, 0)
+	result, err := decodeSymbol(image, points)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "Hello", result.GetText(), "decoded text")
+	internal.AssertEquals(t, "0", result.GetECLevel(), "error correction level")
+	internal.AssertEquals(t, 0, result.GetErrorsCorrected(), "errors corrected")
+}
+
+func TestDecode_SingleSide(t *testing.T) {
+	image, points := renderSymbol(t, paddedHelloData(14), 6, 3, 1)
+
+	result, err := Decode(image, points[0], points[1], nil, nil, 17*testModuleWidth, 17*testModuleWidth)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "Hello", result.GetText(), "decoded text from the left")
+
+	result, err = Decode(image, nil, nil, points[2], points[3], 17*testModuleWidth, 17*testModuleWidth)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "Hello", result.GetText(), "decoded text from the right")
+
+	_, err = Decode(image, nil, nil, nil, nil, 17*testModuleWidth, 17*testModuleWidth)
+	internal.AssertEquals(t, core.ErrNotFound, err, "no corners")
+}
+
+func TestDecode_Damaged(t *testing.T) {
+	image, points := renderSymbol(t, paddedHelloData(16), 6, 4, 2)
+	// Blot out the second data codeword of the first two rows entirely, and
+	// invert part of one in a later row
+	x := uint32(testQuietZone + (17+17+17)*testModuleWidth)
+	internal.AssertSuccess(t, image.SetRegion(x, testQuietZone, 17*testModuleWidth, 2*testRowHeight))
+	x = uint32(testQuietZone + (17+17+2*17)*testModuleWidth)
+	for dy := uint32(0); dy < testRowHeight; dy++ {
+		for dx := uint32(0); dx < 6*testModuleWidth; dx++ {
+			image.Flip(x+dx, testQuietZone+4*testRowHeight+dy)
+		}
+	}
+	result, err := decodeSymbol(image, points)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "Hello", result.GetText(), "decoded text")
+	internal.AssertTrue(t, result.GetErrorsCorrected() >= 2, "damaged codewords were not corrected")
+}
+
+func TestDecode_TooDamaged(t *testing.T) {
+	image, points := renderSymbol(t, paddedHelloData(6), 4, 2, 0)
+	x := uint32(testQuietZone + (17+17)*testModuleWidth)
+	internal.AssertSuccess(t, image.SetRegion(x, testQuietZone, 2*17*testModuleWidth, 2*testRowHeight))
+	_, err := decodeSymbol(image, points)
+	internal.AssertFailure(t, err, "decoded a symbol with too many erasures")
+}
@@ -0,0 +1,331 @@
+/*
+ * Copyright 2009 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector
+
+import (
+	"math"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+var (
+	indexesStartPattern = []int{0, 4, 1, 5}
+	indexesStopPattern  = []int{6, 2, 7, 3}
+)
+
+const (
+	maxAvgVariance        = 0.42
+	maxIndividualVariance = 0.8
+
+	maxPixelDrift   = 3
+	maxPatternDrift = 5
+	// if we set the value too low, then we don't detect the correct height
+	// of the bar if the start patterns are damaged. if we set the value
+	// too high, then we might detect the start pattern from a neighbor
+	// barcode.
+	skippedRowCountMax = 25
+	// A PDF471 barcode should have at least 3 rows, with each row being >=
+	// 3 times the module width. Therefore it should be at least 9 pixels
+	// tall. To be conservative, we use about half the size to ensure we
+	// don't miss it.
+	rowStep          = 5
+	barcodeMinHeight = 10
+)
+
+var (
+	// B S B S B S B S Bar/Space pattern
+	// 11111111 0 1 0 1 0 1 000
+	startPattern = []int{8, 1, 1, 1, 1, 1, 1, 3}
+	// 1111111 0 1 000 1 0 1 00 1
+	stopPattern = []int{7, 1, 1, 3, 1, 1, 1, 2, 1}
+
+	rotations = []int{0, 180, 270, 90}
+)
+
+// Detect looks for PDF417 symbols in image by their start and stop
+// patterns, trying the image in each orientation until some are found. If
+// multiple is false, it stops after the first symbol.
+//
+// The vertices of each symbol are:
+//
+//	[0] x, y top left barcode
+//	[1] x, y bottom left barcode
+//	[2] x, y top right barcode
+//	[3] x, y bottom right barcode
+//	[4] x, y top left codeword area
+//	[5] x, y bottom left codeword area
+//	[6] x, y top right codeword area
+//	[7] x, y bottom right codeword area
+//
+// Those on the side of a missing start or stop pattern are nil.
+// It returns a result without any points if no symbol is found.
+func Detect(image *common.BitMatrix, multiple bool) (*PDF417DetectorResult, error) {
+	// TODO detection improvement, tryHarder could try several different
+	// luminance thresholds/blackpoints or even different binarizers
+	for _, rotation := range rotations {
+		bitMatrix, err := applyRotation(image, rotation)
+		if err != nil {
+			return nil, err
+		}
+		barcodeCoordinates := detect(multiple, bitMatrix)
+		if len(barcodeCoordinates) > 0 {
+			return NewPDF417DetectorResult(bitMatrix, barcodeCoordinates, rotation), nil
+		}
+	}
+	return NewPDF417DetectorResult(image, nil, 0), nil
+}
+
+// applyRotation returns a copy of matrix rotated by rotation degrees, or
+// matrix itself if there is no rotation.
+func applyRotation(matrix *common.BitMatrix, rotation int) (*common.BitMatrix, error) {
+	if rotation%360 == 0 {
+		return matrix, nil
+	}
+	newMatrix := matrix.Clone()
+	if err := newMatrix.Rotate(rotation); err != nil {
+		return nil, err
+	}
+	return newMatrix, nil
+}
+
+// detect finds the vertices of the symbols in bitMatrix, scanning rows
+// from the top.
+func detect(multiple bool, bitMatrix *common.BitMatrix) [][]*core.ResultPoint {
+	var barcodeCoordinates [][]*core.ResultPoint
+	row := 0
+	column := 0
+	foundBarcodeInRow := false
+	for row < int(bitMatrix.GetHeight()) {
+		vertices := findVertices(bitMatrix, row, column)
+
+		if vertices[0] == nil && vertices[3] == nil {
+			if !foundBarcodeInRow {
+				// we didn't find any barcode so that's the end of searching
+				break
+			}
+			// we didn't find a barcode starting at the given column and
+			// row. Try again from the first column and slightly below the
+			// lowest barcode we found so far.
+			foundBarcodeInRow = false
+			column = 0
+			for _, barcodeCoordinate := range barcodeCoordinates {
+				if barcodeCoordinate[1] != nil && int(barcodeCoordinate[1].GetY()) > row {
+					row = int(barcodeCoordinate[1].GetY())
+				}
+				if barcodeCoordinate[3] != nil && int(barcodeCoordinate[3].GetY()) > row {
+					row = int(barcodeCoordinate[3].GetY())
+				}
+			}
+			row += rowStep
+			continue
+		}
+		foundBarcodeInRow = true
+		barcodeCoordinates = append(barcodeCoordinates, vertices)
+		if !multiple {
+			break
+		}
+		// if we didn't find a right row indicator column, then continue the
+		// search for the next barcode after the start pattern of the
+		// barcode just found.
+		if vertices[2] != nil {
+			column = int(vertices[2].GetX())
+			row = int(vertices[2].GetY())
+		} else {
+			column = int(vertices[4].GetX())
+			row = int(vertices[4].GetY())
+		}
+	}
+	return barcodeCoordinates
+}
+
+// findVertices locates the vertices and the codewords area of a black blob
+// using the start and stop patterns as locators.
+func findVertices(matrix *common.BitMatrix, startRow, startColumn int) []*core.ResultPoint {
+	height := int(matrix.GetHeight())
+	width := int(matrix.GetWidth())
+
+	result := make([]*core.ResultPoint, 8)
+	copyToResult(result, findRowsWithPattern(matrix, height, width, startRow, startColumn, startPattern),
+		indexesStartPattern)
+
+	if result[4] != nil {
+		startColumn = int(result[4].GetX())
+		startRow = int(result[4].GetY())
+	}
+	copyToResult(result, findRowsWithPattern(matrix, height, width, startRow, startColumn, stopPattern),
+		indexesStopPattern)
+	return result
+}
+
+func copyToResult(result, tmpResult []*core.ResultPoint, destinationIndexes []int) {
+	for i, index := range destinationIndexes {
+		result[index] = tmpResult[i]
+	}
+}
+
+// findRowsWithPattern finds the first and last rows containing pattern,
+// starting at startRow. It returns the start and end of the pattern in the
+// first row, then in the last row, or all nil if the pattern is not found
+// in enough rows.
+func findRowsWithPattern(matrix *common.BitMatrix, height, width, startRow, startColumn int, pattern []int) []*core.ResultPoint {
+	result := make([]*core.ResultPoint, 4)
+	found := false
+	counters := make([]int, len(pattern))
+	for ; startRow < height; startRow += rowStep {
+		loc := findGuardPattern(matrix, startColumn, startRow, width, pattern, counters)
+		if loc != nil {
+			for startRow > 0 {
+				startRow--
+				previousRowLoc := findGuardPattern(matrix, startColumn, startRow, width, pattern, counters)
+				if previousRowLoc != nil {
+					loc = previousRowLoc
+				} else {
+					startRow++
+					break
+				}
+			}
+			result[0] = core.NewResultPoint(float32(loc[0]), float32(startRow))
+			result[1] = core.NewResultPoint(float32(loc[1]), float32(startRow))
+			found = true
+			break
+		}
+	}
+	stopRow := startRow + 1
+	// Last row of the current symbol that contains pattern
+	if found {
+		skippedRowCount := 0
+		previousRowLoc := []int{int(result[0].GetX()), int(result[1].GetX())}
+		for ; stopRow < height; stopRow++ {
+			loc := findGuardPattern(matrix, previousRowLoc[0], stopRow, width, pattern, counters)
+			// a found pattern is only considered to belong to the same
+			// barcode if the start and end positions don't differ too
+			// much. Pattern drift should be not bigger than two for
+			// consecutive rows. With a higher number of skipped rows drift
+			// could be larger. To keep it simple for now, we allow a
+			// slightly larger drift and don't check for skipped rows.
+			if loc != nil &&
+				abs(previousRowLoc[0]-loc[0]) < maxPatternDrift &&
+				abs(previousRowLoc[1]-loc[1]) < maxPatternDrift {
+				previousRowLoc = loc
+				skippedRowCount = 0
+			} else if skippedRowCount > skippedRowCountMax {
+				break
+			} else {
+				skippedRowCount++
+			}
+		}
+		stopRow -= skippedRowCount + 1
+		result[2] = core.NewResultPoint(float32(previousRowLoc[0]), float32(stopRow))
+		result[3] = core.NewResultPoint(float32(previousRowLoc[1]), float32(stopRow))
+	}
+	if stopRow-startRow < barcodeMinHeight {
+		for i := range result {
+			result[i] = nil
+		}
+	}
+	return result
+}
+
+// findGuardPattern searches row from column for pattern, the counts of
+// black and white pixels. counters is reused to hold the counts read.
+// It returns the start and end of the pattern, or nil if not found.
+func findGuardPattern(matrix *common.BitMatrix, column, row, width int, pattern, counters []int) []int {
+	for i := range counters {
+		counters[i] = 0
+	}
+	patternStart := column
+	pixelDrift := 0
+
+	// if there are black pixels left of the current pixel shift to the
+	// left, but only for maxPixelDrift pixels
+	for patternStart > 0 && pixelDrift < maxPixelDrift && matrix.Get(uint32(patternStart), uint32(row)) {
+		pixelDrift++
+		patternStart--
+	}
+	x := patternStart
+	counterPosition := 0
+	patternLength := len(pattern)
+	for isWhite := false; x < width; x++ {
+		pixel := matrix.Get(uint32(x), uint32(row))
+		if pixel != isWhite {
+			counters[counterPosition]++
+		} else {
+			if counterPosition == patternLength-1 {
+				if patternMatchVariance(counters, pattern) < maxAvgVariance {
+					return []int{patternStart, x}
+				}
+				patternStart += counters[0] + counters[1]
+				copy(counters, counters[2:counterPosition+1])
+				counters[counterPosition-1] = 0
+				counters[counterPosition] = 0
+				counterPosition--
+			} else {
+				counterPosition++
+			}
+			counters[counterPosition] = 1
+			isWhite = !isWhite
+		}
+	}
+	if counterPosition == patternLength-1 &&
+		patternMatchVariance(counters, pattern) < maxAvgVariance {
+		return []int{patternStart, x - 1}
+	}
+	return nil
+}
+
+// patternMatchVariance determines how closely a set of observed counts of
+// runs of black/white values matches a given target pattern. This is
+// reported as the ratio of the total variance from the expected pattern
+// proportions across all pattern elements, to the length of the pattern.
+// It returns +Inf if any element varies too much.
+func patternMatchVariance(counters, pattern []int) float32 {
+	total := 0
+	patternLength := 0
+	for i, counter := range counters {
+		total += counter
+		patternLength += pattern[i]
+	}
+	if total < patternLength {
+		// If we don't even have one pixel per unit of bar width, assume
+		// this is too small to reliably match, so fail:
+		return float32(math.Inf(1))
+	}
+	unitBarWidth := float32(total) / float32(patternLength)
+	maxVariance := maxIndividualVariance * unitBarWidth
+
+	var totalVariance float32
+	for x, counter := range counters {
+		scaledPattern := float32(pattern[x]) * unitBarWidth
+		variance := float32(counter) - scaledPattern
+		if variance < 0 {
+			variance = -variance
+		}
+		if variance > maxVariance {
+			return float32(math.Inf(1))
+		}
+		totalVariance += variance
+	}
+	return totalVariance / float32(total)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2009 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector_test
+
+import (
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/internal"
+	"github.com/discesoft/zxing-go/core/pdf417/detector"
+)
+
+const (
+	moduleWidth  = 2
+	symbolHeight = 40
+	// Codeword area between the start and stop patterns, in modules
+	codewordModules = 3 * 17
+)
+
+// drawGuards draws the start and stop patterns of a symbol whose top left
+// corner is at x, y, with a single bar in between to end the start
+// pattern's last space.
+func drawGuards(t *testing.T, image *common.BitMatrix, x, y int) {
+	draw := func(pattern string) {
+		for _, module := range pattern {
+			if module == '1' {
+				internal.AssertSuccess(t, image.SetRegion(uint32(x), uint32(y), moduleWidth, symbolHeight))
+			}
+			x += moduleWidth
+		}
+	}
+	draw("11111111010101000")
+	draw("1")
+	x += (codewordModules - 1) * moduleWidth
+	draw("111111101000101001")
+}
+
+// symbolWidth is the width in pixels of a symbol drawn by drawGuards.
+const symbolWidth = (17 + codewordModules + 18) * moduleWidth
+
+func TestDetect_Single(t *testing.T) {
+	image, err := common.NewBitMatrix(300, 100)
+	internal.AssertSuccess(t, err)
+	drawGuards(t, image, 10, 20)
+
+	result, err := detector.Detect(image, false)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 0, result.GetRotation(), "rotation")
+	internal.AssertEquals(t, 1, len(result.GetPoints()), "number of symbols")
+
+	vertices := result.GetPoints()[0]
+	internal.AssertEquals(t, float32(10), vertices[0].GetX(), "top left x")
+	internal.AssertEquals(t, float32(20), vertices[0].GetY(), "top left y")
+	internal.AssertEquals(t, float32(20+symbolHeight-1), vertices[1].GetY(), "bottom left y")
+	internal.AssertEquals(t, float32(10+17*moduleWidth), vertices[4].GetX(), "codeword area left")
+	internal.AssertEquals(t, float32(10+(17+codewordModules)*moduleWidth), vertices[6].GetX(), "codeword area right")
+	internal.AssertEquals(t, float32(10+symbolWidth), vertices[2].GetX(), "top right x")
+	internal.AssertEquals(t, float32(20+symbolHeight-1), vertices[3].GetY(), "bottom right y")
+}
+
+func TestDetect_Multiple(t *testing.T) {
+	image, err := common.NewBitMatrix(2*symbolWidth+40, 150)
+	internal.AssertSuccess(t, err)
+	// Two symbols side by side, and one below them
+	drawGuards(t, image, 10, 10)
+	drawGuards(t, image, symbolWidth+30, 12)
+	drawGuards(t, image, 10, 90)
+
+	result, err := detector.Detect(image, true)
+	internal.AssertSuccess(t, err)
+	points := result.GetPoints()
+	internal.AssertEquals(t, 3, len(points), "number of symbols")
+	internal.AssertEquals(t, float32(10), points[0][0].GetX(), "first symbol x")
+	internal.AssertEquals(t, float32(symbolWidth+30), points[1][0].GetX(), "second symbol x")
+	internal.AssertEquals(t, float32(90), points[2][0].GetY(), "third symbol y")
+
+	result, err = detector.Detect(image, false)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 1, len(result.GetPoints()), "number of symbols when not multiple")
+}
+
+func TestDetect_Rotated(t *testing.T) {
+	image, err := common.NewBitMatrix(300, 100)
+	internal.AssertSuccess(t, err)
+	drawGuards(t, image, 10, 20)
+
+	upsideDown := image.Clone()
+	internal.AssertSuccess(t, upsideDown.Rotate180())
+	result, err := detector.Detect(upsideDown, false)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 180, result.GetRotation(), "rotation of upside down symbol")
+	internal.AssertTrue(t, result.GetBits().Equals(image), "rotated image")
+
+	sideways := image.Clone()
+	sideways.Rotate90()
+	result, err = detector.Detect(sideways, false)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 270, result.GetRotation(), "rotation of sideways symbol")
+	internal.AssertEquals(t, 1, len(result.GetPoints()), "number of symbols")
+}
+
+func TestDetect_NotFound(t *testing.T) {
+	image, err := common.NewBitMatrix(300, 100)
+	internal.AssertSuccess(t, err)
+	result, err := detector.Detect(image, true)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 0, len(result.GetPoints()), "symbols found in a blank image")
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detector
+
+import (
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+// PDF417DetectorResult holds the vertices of each PDF417 symbol found in
+// an image, in the coordinates of the possibly rotated image it was found
+// in.
+type PDF417DetectorResult struct {
+	bits     *common.BitMatrix
+	points   [][]*core.ResultPoint
+	rotation int
+}
+
+func NewPDF417DetectorResult(bits *common.BitMatrix, points [][]*core.ResultPoint, rotation int) *PDF417DetectorResult {
+	return &PDF417DetectorResult{bits, points, rotation}
+}
+
+// GetBits returns the image the symbols were found in, rotated by
+// GetRotation degrees.
+func (r *PDF417DetectorResult) GetBits() *common.BitMatrix {
+	return r.bits
+}
+
+// GetPoints returns the eight vertices of each symbol found, as described
+// by Detect.
+func (r *PDF417DetectorResult) GetPoints() [][]*core.ResultPoint {
+	return r.points
+}
+
+// GetRotation returns the counterclockwise rotation in degrees which was
+// applied to the image before the symbols were found.
+func (r *PDF417DetectorResult) GetRotation() int {
+	return r.rotation
+}
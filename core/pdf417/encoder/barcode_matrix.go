@@ -0,0 +1,88 @@
+/*
+ * Copyright 2011 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"errors"
+
+	"github.com/discesoft/zxing-go/core/common"
+)
+
+// BarcodeMatrix holds the modules of a PDF417 symbol, row by row.
+type BarcodeMatrix struct {
+	matrix     []*barcodeRow
+	currentRow int
+	height     int
+	width      int
+}
+
+// newBarcodeMatrix creates a matrix of height rows, each width modules
+// wide.
+func newBarcodeMatrix(height, width int) *BarcodeMatrix {
+	matrix := make([]*barcodeRow, height)
+	for i := range matrix {
+		matrix[i] = newBarcodeRow(width)
+	}
+	return &BarcodeMatrix{matrix, -1, height, width}
+}
+
+func (m *BarcodeMatrix) startRow() {
+	m.currentRow++
+}
+
+func (m *BarcodeMatrix) getCurrentRow() *barcodeRow {
+	return m.matrix[m.currentRow]
+}
+
+// GetWidth returns the width of the symbol in modules.
+func (m *BarcodeMatrix) GetWidth() int {
+	return m.width
+}
+
+// GetHeight returns the number of rows of the symbol.
+func (m *BarcodeMatrix) GetHeight() int {
+	return m.height
+}
+
+// GetMatrix renders the symbol with one pixel per module and row.
+func (m *BarcodeMatrix) GetMatrix() (*common.BitMatrix, error) {
+	return m.GetScaledMatrix(1, 1)
+}
+
+// GetScaledMatrix renders the symbol with xScale pixels per module and
+// yScale pixels per row, so yScale sets the row height as a multiple of
+// the module width.
+// It returns an error if either scale is not positive.
+func (m *BarcodeMatrix) GetScaledMatrix(xScale, yScale int) (*common.BitMatrix, error) {
+	if xScale < 1 || yScale < 1 {
+		return nil, errors.New("scale must be positive")
+	}
+	output, err := common.NewBitMatrix(uint32(m.width*xScale), uint32(m.height*yScale))
+	if err != nil {
+		return nil, err
+	}
+	for y, row := range m.matrix {
+		for x, black := range row.row {
+			if black {
+				if err := output.SetRegion(uint32(x*xScale), uint32(y*yScale), uint32(xScale), uint32(yScale)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return output, nil
+}
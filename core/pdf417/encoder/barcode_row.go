@@ -0,0 +1,37 @@
+/*
+ * Copyright 2011 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+// barcodeRow holds the modules of one row of a PDF417 symbol.
+type barcodeRow struct {
+	row []bool
+	// A tacker for position in the bar
+	currentLocation int
+}
+
+// newBarcodeRow creates a row of width modules, all white.
+func newBarcodeRow(width int) *barcodeRow {
+	return &barcodeRow{row: make([]bool, width)}
+}
+
+// addBar appends a bar of width modules, black or white.
+func (r *barcodeRow) addBar(black bool, width int) {
+	for ii := 0; ii < width; ii++ {
+		r.row[r.currentLocation] = black
+		r.currentLocation++
+	}
+}
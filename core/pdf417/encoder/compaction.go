@@ -0,0 +1,31 @@
+/*
+ * Copyright 2011 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+// Compaction enumerates the compaction modes a PDF417 encoder may use.
+type Compaction uint8
+
+const (
+	// CompactionAuto switches between the modes as the data suggests.
+	CompactionAuto Compaction = iota
+	// CompactionText only uses text compaction.
+	CompactionText
+	// CompactionByte only uses byte compaction.
+	CompactionByte
+	// CompactionNumeric only uses numeric compaction.
+	CompactionNumeric
+)
@@ -0,0 +1,338 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core/common"
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+)
+
+const (
+	// The start pattern (17 bits)
+	startPattern = 0x1fea8
+	// The stop pattern (18 bits)
+	stopPattern = 0x3fa29
+
+	// DefaultPreferredRatio is the width to height ratio, in modules,
+	// symbols are chosen to be closest to, unless SetPreferredRatio says
+	// otherwise.
+	DefaultPreferredRatio = 3.0
+	// DefaultRowHeight is the height of a row as a multiple of the module
+	// width, unless SetRowHeight says otherwise.
+	DefaultRowHeight = 4
+)
+
+// codewordTable holds the bar pattern of each codeword in each of the
+// three clusters.
+var codewordTable = makeCodewordTable()
+
+// makeCodewordTable sorts the patterns of pdf417common.SymbolTable into
+// their clusters, telling them apart by their bar widths.
+func makeCodewordTable() [3][]int {
+	var table [3][]int
+	for i := range table {
+		table[i] = make([]int, numberOfCodewords)
+	}
+	for _, symbol := range pdf417common.SymbolTable {
+		var widths [pdf417common.BarsInModule]int
+		bar := 0
+		for bit := uint(pdf417common.ModulesInCodeword); bit > 0; bit-- {
+			black := symbol&(1<<(bit-1)) != 0
+			if bit < pdf417common.ModulesInCodeword && black != (bar%2 == 0) {
+				bar++
+			}
+			widths[bar]++
+		}
+		cluster := (widths[0] - widths[2] + widths[4] - widths[6] + 9) % 9
+		table[cluster/3][pdf417common.GetCodeword(symbol)] = symbol
+	}
+	return table
+}
+
+// PDF417 encodes data as a PDF417 symbol.
+type PDF417 struct {
+	barcodeMatrix  *BarcodeMatrix
+	compact        bool
+	compaction     Compaction
+	encoding       *common.CharacterSetECI
+	macroMetadata  *pdf417common.PDF417ResultMetadata
+	minCols        int
+	maxCols        int
+	maxRows        int
+	minRows        int
+	preferredRatio float64
+	rowHeight      int
+}
+
+// NewPDF417 creates an encoder using automatic compaction and ISO-8859-1,
+// choosing from 1 to 30 columns and 3 to 90 rows closest to
+// DefaultPreferredRatio, with rows DefaultRowHeight modules high.
+// A compact (truncated) PDF417 symbol omits the right row indicator and
+// the stop pattern but for its first bar.
+func NewPDF417(compact bool) *PDF417 {
+	return &PDF417{
+		compact:    compact,
+		compaction: CompactionAuto,
+		minCols:    1,
+		maxCols:    30,
+		maxRows:    pdf417common.MaxRowsInBarcode,
+		minRows:    pdf417common.MinRowsInBarcode,
+
+		preferredRatio: DefaultPreferredRatio,
+		rowHeight:      DefaultRowHeight,
+	}
+}
+
+// GetBarcodeMatrix returns the symbol generated by the last successful
+// call to GenerateBarcodeLogic, or nil.
+func (p *PDF417) GetBarcodeMatrix() *BarcodeMatrix {
+	return p.barcodeMatrix
+}
+
+// calculateNumberOfRows calculates the necessary number of rows as
+// described in annex Q of ISO/IEC 15438:2001(E), for m data codewords, k
+// error correction codewords and c columns.
+func calculateNumberOfRows(m, k, c int) int {
+	r := ((m + 1 + k) / c) + 1
+	if c*r >= (m + 1 + k + c) {
+		r--
+	}
+	return r
+}
+
+// getNumberOfPadCodewords calculates the number of pad codewords as
+// described in 4.9.2 of ISO/IEC 15438:2001(E), for m data codewords, k
+// error correction codewords, c columns and r rows.
+func getNumberOfPadCodewords(m, k, c, r int) int {
+	n := c*r - k
+	if n > m+1 {
+		return n - m - 1
+	}
+	return 0
+}
+
+// encodeChar appends the len most significant of the 18 bits of pattern
+// to logic as bars.
+func encodeChar(pattern, len int, logic *barcodeRow) {
+	bitMap := 1 << uint(len-1)
+	last := pattern&bitMap != 0 //Initialize to inverse of first bit
+	width := 0
+	for i := 0; i < len; i++ {
+		black := pattern&bitMap != 0
+		if last == black {
+			width++
+		} else {
+			logic.addBar(last, width)
+			last = black
+			width = 1
+		}
+		bitMap >>= 1
+	}
+	logic.addBar(last, width)
+}
+
+func (p *PDF417) encodeLowLevel(fullCodewords []int, c, r, errorCorrectionLevel int, logic *BarcodeMatrix) {
+	idx := 0
+	for y := 0; y < r; y++ {
+		cluster := y % 3
+		logic.startRow()
+		encodeChar(startPattern, 17, logic.getCurrentRow())
+
+		var left, right int
+		switch cluster {
+		case 0:
+			left = (30 * (y / 3)) + ((r - 1) / 3)
+			right = (30 * (y / 3)) + (c - 1)
+		case 1:
+			left = (30 * (y / 3)) + (errorCorrectionLevel * 3) + ((r - 1) % 3)
+			right = (30 * (y / 3)) + ((r - 1) / 3)
+		default:
+			left = (30 * (y / 3)) + (c - 1)
+			right = (30 * (y / 3)) + (errorCorrectionLevel * 3) + ((r - 1) % 3)
+		}
+
+		encodeChar(codewordTable[cluster][left], 17, logic.getCurrentRow())
+		for x := 0; x < c; x++ {
+			encodeChar(codewordTable[cluster][fullCodewords[idx]], 17, logic.getCurrentRow())
+			idx++
+		}
+
+		if p.compact {
+			encodeChar(stopPattern, 1, logic.getCurrentRow()) // encodes stop line for compact pdf417
+		} else {
+			encodeChar(codewordTable[cluster][right], 17, logic.getCurrentRow())
+			encodeChar(stopPattern, 18, logic.getCurrentRow())
+		}
+	}
+}
+
+// GenerateBarcodeLogic generates the symbol for msg at
+// errorCorrectionLevel, from 0 to 8, available from GetBarcodeMatrix
+// afterwards. autoECI selects UTF-8 when no encoding is set and msg
+// doesn't fit ISO-8859-1.
+// It returns an error if msg cannot be encoded or doesn't fit the
+// dimensions.
+func (p *PDF417) GenerateBarcodeLogic(msg string, errorCorrectionLevel int, autoECI bool) error {
+	//1. step: High-level encoding
+	errorCorrectionCodeWords, err := GetErrorCorrectionCodewordCount(errorCorrectionLevel)
+	if err != nil {
+		return err
+	}
+	highLevel, err := encodeHighLevel(msg, p.compaction, p.encoding, autoECI)
+	if err != nil {
+		return err
+	}
+	var macroBlock []int
+	if p.macroMetadata != nil {
+		if macroBlock, err = encodeMacroBlock(p.macroMetadata); err != nil {
+			return err
+		}
+	}
+	sourceCodeWords := len(highLevel) + len(macroBlock)
+
+	cols, rows, err := p.determineDimensions(sourceCodeWords, errorCorrectionCodeWords)
+	if err != nil {
+		return err
+	}
+
+	pad := getNumberOfPadCodewords(sourceCodeWords, errorCorrectionCodeWords, cols, rows)
+
+	//2. step: construct data codewords
+	if sourceCodeWords+errorCorrectionCodeWords+1 > pdf417common.MaxCodewordsInBarcode { // +1 for symbol length CW
+		return errors.New("Encoded message contains too many code words, message too big (" +
+			strconv.Itoa(len(msg)) + " bytes)")
+	}
+	n := sourceCodeWords + pad + 1
+	dataCodewords := make([]int, 0, n+errorCorrectionCodeWords)
+	dataCodewords = append(dataCodewords, n)
+	dataCodewords = append(dataCodewords, highLevel...)
+	for i := 0; i < pad; i++ {
+		dataCodewords = append(dataCodewords, latchToText) //PAD characters
+	}
+	// The Macro PDF417 control block comes last, after the padding
+	dataCodewords = append(dataCodewords, macroBlock...)
+
+	//3. step: Error correction
+	ec := generateErrorCorrection(dataCodewords, errorCorrectionLevel)
+
+	//4. step: low-level encoding
+	width := (cols+4)*pdf417common.ModulesInCodeword + 1
+	if p.compact {
+		width = (cols+2)*pdf417common.ModulesInCodeword + 1
+	}
+	p.barcodeMatrix = newBarcodeMatrix(rows, width)
+	p.encodeLowLevel(append(dataCodewords, ec...), cols, rows, errorCorrectionLevel, p.barcodeMatrix)
+	return nil
+}
+
+// determineDimensions determines the number of columns and rows for
+// sourceCodeWords data and errorCorrectionCodeWords error correction
+// codewords, choosing the symbol whose width to height ratio, with rows
+// of the row height, is closest to the preferred aspect ratio.
+// It returns an error if the codewords fit no allowed dimensions.
+func (p *PDF417) determineDimensions(sourceCodeWords, errorCorrectionCodeWords int) (cols, rows int, err error) {
+	ratio := 0.0
+	found := false
+
+	for c := p.minCols; c <= p.maxCols; c++ {
+		r := calculateNumberOfRows(sourceCodeWords, errorCorrectionCodeWords, c)
+
+		if r < p.minRows {
+			break
+		}
+
+		if r > p.maxRows {
+			continue
+		}
+
+		newRatio := float64(17*c+69) / float64(r*p.rowHeight)
+
+		// ignore if previous ratio is closer to preferred ratio
+		if found && math.Abs(newRatio-p.preferredRatio) > math.Abs(ratio-p.preferredRatio) {
+			continue
+		}
+
+		ratio = newRatio
+		cols, rows, found = c, r, true
+	}
+
+	// Handling case when min values were too high
+	if !found && p.minCols > 0 {
+		r := calculateNumberOfRows(sourceCodeWords, errorCorrectionCodeWords, p.minCols)
+		if r < p.minRows {
+			cols, rows, found = p.minCols, p.minRows, true
+		}
+	}
+
+	if !found {
+		return 0, 0, errors.New("Unable to fit message in columns")
+	}
+	return cols, rows, nil
+}
+
+// SetDimensions sets the allowed range of columns and rows.
+func (p *PDF417) SetDimensions(maxCols, minCols, maxRows, minRows int) {
+	p.maxCols = maxCols
+	p.minCols = minCols
+	p.maxRows = maxRows
+	p.minRows = minRows
+}
+
+// SetPreferredRatio sets the width to height ratio of the symbol, in
+// modules, which the number of columns and rows is chosen to come closest
+// to.
+func (p *PDF417) SetPreferredRatio(ratio float64) {
+	p.preferredRatio = ratio
+}
+
+// SetRowHeight sets the height of a row as a multiple of the module
+// width.
+func (p *PDF417) SetRowHeight(rowHeight int) {
+	p.rowHeight = rowHeight
+}
+
+// GetRowHeight returns the height of a row as a multiple of the module
+// width.
+func (p *PDF417) GetRowHeight() int {
+	return p.rowHeight
+}
+
+// SetCompaction sets the compaction mode.
+func (p *PDF417) SetCompaction(compaction Compaction) {
+	p.compaction = compaction
+}
+
+// SetCompact sets whether to generate a compact (truncated) symbol.
+func (p *PDF417) SetCompact(compact bool) {
+	p.compact = compact
+}
+
+// SetMacroMetadata sets the Macro PDF417 control block to add to the
+// symbol, making it a segment of a larger message, or nil for none.
+func (p *PDF417) SetMacroMetadata(metadata *pdf417common.PDF417ResultMetadata) {
+	p.macroMetadata = metadata
+}
+
+// SetEncoding sets the character set byte compacted text is encoded in;
+// any other than ISO-8859-1 is designated by an ECI.
+func (p *PDF417) SetEncoding(encoding *common.CharacterSetECI) {
+	p.encoding = encoding
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"errors"
+	"strconv"
+
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+)
+
+const numberOfCodewords = pdf417common.NumberOfCodewords
+
+// ecCoefficients holds, for each error correction level, the
+// coefficients of the generator polynomial, lowest degree first and
+// without the leading 1.
+var ecCoefficients = makeECCoefficients()
+
+// makeECCoefficients computes the generator polynomials
+// (x - 3)(x - 3^2)...(x - 3^k) over GF(929) for k = 2, 4, ..., 512.
+func makeECCoefficients() [][]int {
+	coefficients := make([][]int, 9)
+	for level := range coefficients {
+		k := 1 << uint(level+1)
+		// g holds the coefficients of the product so far, lowest first.
+		g := []int{1}
+		root := 1
+		for i := 0; i < k; i++ {
+			root = root * 3 % numberOfCodewords
+			next := make([]int, len(g)+1)
+			for j, c := range g {
+				next[j+1] = (next[j+1] + c) % numberOfCodewords
+				next[j] = (next[j] + numberOfCodewords - c*root%numberOfCodewords) % numberOfCodewords
+			}
+			g = next
+		}
+		coefficients[level] = g[:k]
+	}
+	return coefficients
+}
+
+// GetErrorCorrectionCodewordCount determines the number of error
+// correction codewords for an error correction level.
+// It returns an error unless errorCorrectionLevel is between 0 and 8.
+func GetErrorCorrectionCodewordCount(errorCorrectionLevel int) (int, error) {
+	if errorCorrectionLevel < 0 || errorCorrectionLevel > 8 {
+		return 0, errors.New("Error correction level must be between 0 and 8, but was " + strconv.Itoa(errorCorrectionLevel))
+	}
+	return 1 << uint(errorCorrectionLevel+1), nil
+}
+
+// GetRecommendedMinimumErrorCorrectionLevel returns the recommended
+// minimum error correction level as described in annex E of ISO/IEC
+// 15438:2001(E).
+// It returns an error if n, the number of data codewords, is not between
+// 1 and 863.
+func GetRecommendedMinimumErrorCorrectionLevel(n int) (int, error) {
+	switch {
+	case n <= 0:
+		return 0, errors.New("n must be > 0")
+	case n <= 40:
+		return 2, nil
+	case n <= 160:
+		return 3, nil
+	case n <= 320:
+		return 4, nil
+	case n <= 863:
+		return 5, nil
+	}
+	return 0, errors.New("No recommendation possible")
+}
+
+// generateErrorCorrection computes the error correction codewords for
+// dataCodewords at errorCorrectionLevel, which must be valid.
+func generateErrorCorrection(dataCodewords []int, errorCorrectionLevel int) []int {
+	coefficients := ecCoefficients[errorCorrectionLevel]
+	k := len(coefficients)
+	e := make([]int, k)
+	for _, codeword := range dataCodewords {
+		t1 := (codeword + e[k-1]) % numberOfCodewords
+		for j := k - 1; j >= 1; j-- {
+			t2 := t1 * coefficients[j] % numberOfCodewords
+			e[j] = (e[j-1] + numberOfCodewords - t2) % numberOfCodewords
+		}
+		t2 := t1 * coefficients[0] % numberOfCodewords
+		e[0] = (numberOfCodewords - t2) % numberOfCodewords
+	}
+	result := make([]int, k)
+	for j := k - 1; j >= 0; j-- {
+		if e[j] != 0 {
+			e[j] = numberOfCodewords - e[j]
+		}
+		result[k-1-j] = e[j]
+	}
+	return result
+}
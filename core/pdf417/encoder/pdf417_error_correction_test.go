@@ -0,0 +1,67 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"testing"
+
+	. "github.com/discesoft/zxing-go/core/internal"
+)
+
+func TestECCoefficients(t *testing.T) {
+	expected := [][]int{
+		{27, 917},
+		{522, 568, 723, 809},
+		{237, 308, 436, 284, 646, 653, 428, 379},
+	}
+	for level, coefficients := range expected {
+		assertCodewords(t, coefficients, ecCoefficients[level], "coefficients")
+	}
+	assertCodewords(t, []int{352, 77, 373, 504, 35, 599}, ecCoefficients[8][:6], "level 8 coefficients")
+	AssertEquals(t, 512, len(ecCoefficients[8]), "level 8 count")
+}
+
+func TestGetErrorCorrectionCodewordCount(t *testing.T) {
+	count, err := GetErrorCorrectionCodewordCount(0)
+	AssertSuccess(t, err)
+	AssertEquals(t, 2, count, "level 0")
+	count, err = GetErrorCorrectionCodewordCount(8)
+	AssertSuccess(t, err)
+	AssertEquals(t, 512, count, "level 8")
+	_, err = GetErrorCorrectionCodewordCount(9)
+	AssertFailure(t, err, "level 9")
+	_, err = GetErrorCorrectionCodewordCount(-1)
+	AssertFailure(t, err, "level -1")
+}
+
+func TestGenerateErrorCorrection(t *testing.T) {
+	// The remainder of data and error correction codewords, read as a
+	// polynomial, must vanish at the roots 3^1 ... 3^k
+	data := []int{5, 453, 178, 121, 239}
+	for level := 0; level <= 3; level++ {
+		codewords := append(append([]int{}, data...), generateErrorCorrection(data, level)...)
+		root := 1
+		for i := 0; i < len(ecCoefficients[level]); i++ {
+			root = root * 3 % numberOfCodewords
+			value := 0
+			for _, c := range codewords {
+				value = (value*root + c) % numberOfCodewords
+			}
+			AssertEquals(t, 0, value, "syndrome")
+		}
+	}
+}
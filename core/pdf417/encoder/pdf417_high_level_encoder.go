@@ -0,0 +1,580 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core/common"
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+)
+
+const (
+	// code for Text compaction
+	textCompaction = 0
+	// code for Byte compaction
+	byteCompaction = 1
+	// code for Numeric compaction
+	numericCompaction = 2
+
+	// Text compaction submodes
+	submodeAlpha       = 0
+	submodeLower       = 1
+	submodeMixed       = 2
+	submodePunctuation = 3
+
+	// mode latch to Text Compaction mode
+	latchToText = 900
+	// mode latch to Byte Compaction mode (number of characters NOT a
+	// multiple of 6)
+	latchToBytePadded = 901
+	// mode latch to Numeric Compaction mode
+	latchToNumeric = 902
+	// mode shift to Byte Compaction mode
+	shiftToByte = 913
+	// mode latch to Byte Compaction mode (number of characters a multiple
+	// of 6)
+	latchToByte = 924
+	// identifier for a user defined Extended Channel Interpretation (ECI)
+	eciUserDefined = 925
+	// identifier for a general purpose ECI format
+	eciGeneralPurpose = 926
+	// identifier for an ECI of a character set or code page
+	eciCharset = 927
+	// begin of a Macro PDF417 control block
+	beginMacroPDF417ControlBlock = 928
+	// begin of a Macro PDF417 optional field
+	beginMacroPDF417OptionalField = 923
+	// marks the last segment of a Macro PDF417 message
+	macroPDF417Terminator = 922
+
+	// Macro PDF417 optional field designators
+	macroPDF417OptionalFieldFileName     = 0
+	macroPDF417OptionalFieldSegmentCount = 1
+	macroPDF417OptionalFieldTimeStamp    = 2
+	macroPDF417OptionalFieldSender       = 3
+	macroPDF417OptionalFieldAddressee    = 4
+	macroPDF417OptionalFieldFileSize     = 5
+	macroPDF417OptionalFieldChecksum     = 6
+
+	// the largest segment index, which takes 5 digits
+	maxSegmentIndex = 99998
+)
+
+// textMixedRaw holds the raw code table for text compaction Mixed
+// sub-mode.
+var textMixedRaw = []uint8{
+	48, 49, 50, 51, 52, 53, 54, 55, 56, 57, 38, 13, 9, 44, 58,
+	35, 45, 46, 36, 47, 43, 37, 42, 61, 94, 0, 32, 0, 0, 0}
+
+// textPunctuationRaw holds the raw code table for text compaction
+// Punctuation sub-mode.
+var textPunctuationRaw = []uint8{
+	59, 60, 62, 64, 91, 92, 93, 95, 96, 126, 33, 13, 9, 44, 58,
+	10, 45, 46, 36, 47, 34, 124, 42, 40, 41, 63, 123, 125, 39, 0}
+
+// mixed and punctuation map an ASCII character to its value in the
+// Mixed and Punctuation sub-modes, or to -1 if it has none.
+var (
+	mixed       = makeInverseTable(textMixedRaw)
+	punctuation = makeInverseTable(textPunctuationRaw)
+)
+
+func makeInverseTable(raw []uint8) []int {
+	table := make([]int, 128)
+	for i := range table {
+		table[i] = -1
+	}
+	for i, b := range raw {
+		if b > 0 {
+			table[b] = i
+		}
+	}
+	return table
+}
+
+// encodeHighLevel performs the high-level encoding of msg into data
+// codewords, using compaction.
+// encoding is the character set byte compacted text is encoded in,
+// designated by an ECI unless it is ISO-8859-1; nil means ISO-8859-1.
+// If autoECI is set and encoding is nil, ISO-8859-1 is used when msg
+// allows it and UTF-8 otherwise.
+// It returns an error if msg is empty, cannot be represented in the
+// character set, or doesn't suit a forced compaction.
+func encodeHighLevel(msg string, compaction Compaction, encoding *common.CharacterSetECI, autoECI bool) ([]int, error) {
+	if msg == "" {
+		return nil, errors.New("Empty message not allowed")
+	}
+	input := []rune(msg)
+
+	if encoding == nil {
+		encoding = common.ISO8859_1
+		if autoECI && !encoding.CanEncode(msg) {
+			encoding = common.UTF8
+		}
+	}
+	if _, ok := encoding.Encode(msg); !ok {
+		// Tell a character the character set lacks from one we cannot
+		// transcode to at all
+		if encoding.CanEncode("") {
+			for _, ch := range input {
+				if !encoding.CanEncode(string(ch)) {
+					return nil, errors.New("Non-encodable character detected: " + string(ch) +
+						" (Unicode: " + strconv.Itoa(int(ch)) + ")")
+				}
+			}
+		}
+		return nil, errors.New("Cannot encode contents in " + encoding.GetName())
+	}
+
+	//the codewords 0..928 are encoded as ints
+	var sb []int
+	if encoding != common.ISO8859_1 {
+		var err error
+		if sb, err = encodingECI(encoding.GetValue(), sb); err != nil {
+			return nil, err
+		}
+	}
+
+	length := len(input)
+	p := 0
+	textSubMode := submodeAlpha
+
+	switch compaction {
+	case CompactionText:
+		for _, ch := range input {
+			if !isText(ch) {
+				return nil, errors.New("Cannot use text compaction for " + strconv.Quote(string(ch)))
+			}
+		}
+		sb, _ = encodeText(input, p, length, sb, textSubMode)
+
+	case CompactionByte:
+		msgBytes, _ := encoding.Encode(msg)
+		sb = encodeBinary(msgBytes, byteCompaction, sb)
+
+	case CompactionNumeric:
+		for _, ch := range input {
+			if !isDigit(ch) {
+				return nil, errors.New("Cannot use numeric compaction for " + strconv.Quote(string(ch)))
+			}
+		}
+		sb = append(sb, latchToNumeric)
+		sb = encodeNumeric(input, p, length, sb)
+
+	default:
+		encodingMode := textCompaction //Default mode, see 4.4.2.1
+		for p < length {
+			n := determineConsecutiveDigitCount(input, p)
+			if n >= 13 {
+				sb = append(sb, latchToNumeric)
+				encodingMode = numericCompaction
+				textSubMode = submodeAlpha //Reset after latch
+				sb = encodeNumeric(input, p, n, sb)
+				p += n
+			} else {
+				t := determineConsecutiveTextCount(input, p)
+				if t >= 5 || n == length {
+					if encodingMode != textCompaction {
+						sb = append(sb, latchToText)
+						encodingMode = textCompaction
+						textSubMode = submodeAlpha //start with submode alpha after latch
+					}
+					sb, textSubMode = encodeText(input, p, t, sb, textSubMode)
+					p += t
+				} else {
+					b := determineConsecutiveBinaryCount(input, p)
+					if b == 0 {
+						b = 1
+					}
+					bytes, _ := encoding.Encode(string(input[p : p+b]))
+					if len(bytes) == 1 && encodingMode == textCompaction {
+						//Switch for one byte (instead of latch)
+						sb = encodeBinary(bytes, textCompaction, sb)
+					} else {
+						//Mode latch performed by encodeBinary()
+						sb = encodeBinary(bytes, encodingMode, sb)
+						encodingMode = byteCompaction
+						textSubMode = submodeAlpha //Reset after latch
+					}
+					p += b
+				}
+			}
+		}
+	}
+	return sb, nil
+}
+
+// encodeText encodes count characters of input, from startpos on, using
+// text compaction, appending the codewords to sb.
+// It returns sb and the text sub-mode it ended in.
+func encodeText(input []rune, startpos, count int, sb []int, initialSubmode int) ([]int, int) {
+	tmp := make([]int, 0, count)
+	submode := initialSubmode
+	idx := 0
+	for {
+		ch := input[startpos+idx]
+		switch submode {
+		case submodeAlpha:
+			if isAlphaUpper(ch) {
+				if ch == ' ' {
+					tmp = append(tmp, 26) //space
+				} else {
+					tmp = append(tmp, int(ch-'A'))
+				}
+			} else if isAlphaLower(ch) {
+				submode = submodeLower
+				tmp = append(tmp, 27) //ll
+				continue
+			} else if isMixed(ch) {
+				submode = submodeMixed
+				tmp = append(tmp, 28) //ml
+				continue
+			} else {
+				tmp = append(tmp, 29) //ps
+				tmp = append(tmp, punctuation[ch])
+			}
+		case submodeLower:
+			if isAlphaLower(ch) {
+				if ch == ' ' {
+					tmp = append(tmp, 26) //space
+				} else {
+					tmp = append(tmp, int(ch-'a'))
+				}
+			} else if isAlphaUpper(ch) {
+				tmp = append(tmp, 27) //as
+				tmp = append(tmp, int(ch-'A'))
+				//space cannot happen here, it is also in "Lower"
+			} else if isMixed(ch) {
+				submode = submodeMixed
+				tmp = append(tmp, 28) //ml
+				continue
+			} else {
+				tmp = append(tmp, 29) //ps
+				tmp = append(tmp, punctuation[ch])
+			}
+		case submodeMixed:
+			if isMixed(ch) {
+				tmp = append(tmp, mixed[ch])
+			} else if isAlphaUpper(ch) {
+				submode = submodeAlpha
+				tmp = append(tmp, 28) //al
+				continue
+			} else if isAlphaLower(ch) {
+				submode = submodeLower
+				tmp = append(tmp, 27) //ll
+				continue
+			} else {
+				if idx+1 < count && isPunctuation(input[startpos+idx+1]) {
+					submode = submodePunctuation
+					tmp = append(tmp, 25) //pl
+					continue
+				}
+				tmp = append(tmp, 29) //ps
+				tmp = append(tmp, punctuation[ch])
+			}
+		default: //submodePunctuation
+			if isPunctuation(ch) {
+				tmp = append(tmp, punctuation[ch])
+			} else {
+				submode = submodeAlpha
+				tmp = append(tmp, 29) //al
+				continue
+			}
+		}
+		idx++
+		if idx >= count {
+			break
+		}
+	}
+	h := 0
+	for i, value := range tmp {
+		if i%2 != 0 {
+			h = h*30 + value
+			sb = append(sb, h)
+		} else {
+			h = value
+		}
+	}
+	if len(tmp)%2 != 0 {
+		sb = append(sb, h*30+29) //ps
+	}
+	return sb, submode
+}
+
+// encodeBinary encodes bytes using byte compaction, appending the
+// codewords to sb. startmode is the compaction mode in effect before.
+func encodeBinary(bytes []uint8, startmode int, sb []int) []int {
+	count := len(bytes)
+	if count == 1 && startmode == textCompaction {
+		sb = append(sb, shiftToByte)
+	} else if count%6 == 0 {
+		sb = append(sb, latchToByte)
+	} else {
+		sb = append(sb, latchToBytePadded)
+	}
+
+	idx := 0
+	// Encode sixpacks
+	var chars [5]int
+	for count-idx >= 6 {
+		var t int64
+		for i := 0; i < 6; i++ {
+			t <<= 8
+			t += int64(bytes[idx+i])
+		}
+		for i := range chars {
+			chars[i] = int(t % 900)
+			t /= 900
+		}
+		for i := len(chars) - 1; i >= 0; i-- {
+			sb = append(sb, chars[i])
+		}
+		idx += 6
+	}
+	//Encode rest (remaining n<5 bytes if any)
+	for _, b := range bytes[idx:] {
+		sb = append(sb, int(b))
+	}
+	return sb
+}
+
+// encodeNumeric encodes count digits of input, from startpos on, using
+// numeric compaction, appending the codewords to sb.
+func encodeNumeric(input []rune, startpos, count int, sb []int) []int {
+	idx := 0
+	num900 := big.NewInt(900)
+	var tmp []int
+	for idx < count {
+		tmp = tmp[:0]
+		length := count - idx
+		if length > 44 {
+			length = 44
+		}
+		part := "1" + string(input[startpos+idx:startpos+idx+length])
+		bigint, _ := new(big.Int).SetString(part, 10)
+		mod := new(big.Int)
+		for {
+			bigint.DivMod(bigint, num900, mod)
+			tmp = append(tmp, int(mod.Int64()))
+			if bigint.Sign() == 0 {
+				break
+			}
+		}
+		//Reverse temporary string
+		for i := len(tmp) - 1; i >= 0; i-- {
+			sb = append(sb, tmp[i])
+		}
+		idx += length
+	}
+	return sb
+}
+
+func isDigit(ch rune) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isAlphaUpper(ch rune) bool {
+	return ch == ' ' || (ch >= 'A' && ch <= 'Z')
+}
+
+func isAlphaLower(ch rune) bool {
+	return ch == ' ' || (ch >= 'a' && ch <= 'z')
+}
+
+func isMixed(ch rune) bool {
+	return ch < 128 && mixed[ch] != -1
+}
+
+func isPunctuation(ch rune) bool {
+	return ch < 128 && punctuation[ch] != -1
+}
+
+func isText(ch rune) bool {
+	return ch == '\t' || ch == '\n' || ch == '\r' || (ch >= 32 && ch <= 126)
+}
+
+// determineConsecutiveDigitCount determines the number of consecutive
+// digits in input from startpos on.
+func determineConsecutiveDigitCount(input []rune, startpos int) int {
+	count := 0
+	for idx := startpos; idx < len(input) && isDigit(input[idx]); idx++ {
+		count++
+	}
+	return count
+}
+
+// determineConsecutiveTextCount determines the number of consecutive
+// characters in input from startpos on that are encodable using text
+// compaction.
+func determineConsecutiveTextCount(input []rune, startpos int) int {
+	length := len(input)
+	idx := startpos
+	for idx < length {
+		numericCount := 0
+		for numericCount < 13 && idx < length && isDigit(input[idx]) {
+			numericCount++
+			idx++
+		}
+		if numericCount >= 13 {
+			return idx - startpos - numericCount
+		}
+		if numericCount > 0 {
+			//Heuristic: All text-encodable chars or digits are binary encodable
+			continue
+		}
+
+		//Check if character is encodable
+		if !isText(input[idx]) {
+			break
+		}
+		idx++
+	}
+	return idx - startpos
+}
+
+// determineConsecutiveBinaryCount determines the number of consecutive
+// characters in input from startpos on that are best encoded using byte
+// compaction, up to the next run of 13 digits.
+func determineConsecutiveBinaryCount(input []rune, startpos int) int {
+	length := len(input)
+	idx := startpos
+	for idx < length {
+		numericCount := 0
+		for i := idx; numericCount < 13 && i < length && isDigit(input[i]); i++ {
+			numericCount++
+		}
+		if numericCount >= 13 {
+			return idx - startpos
+		}
+		idx++
+	}
+	return idx - startpos
+}
+
+// encodingECI appends the codewords designating the ECI eci to sb.
+// It returns an error if eci is out of range.
+func encodingECI(eci int, sb []int) ([]int, error) {
+	switch {
+	case eci >= 0 && eci < 900:
+		sb = append(sb, eciCharset, eci)
+	case eci >= 900 && eci < 810900:
+		sb = append(sb, eciGeneralPurpose, eci/900-1, eci%900)
+	case eci >= 810900 && eci < 811800:
+		sb = append(sb, eciUserDefined, eci-810900)
+	default:
+		return nil, errors.New("ECI number not in valid range from 0..811799, but was " + strconv.Itoa(eci))
+	}
+	return sb, nil
+}
+
+// encodeMacroBlock encodes metadata as a Macro PDF417 control block,
+// which follows the data and pad codewords.
+// It returns an error if the segment index or count is out of range, the
+// file ID isn't made of groups of 3 digits from 000 to 899, or a text
+// field cannot be text compacted.
+func encodeMacroBlock(metadata *pdf417common.PDF417ResultMetadata) ([]int, error) {
+	segmentIndex := metadata.GetSegmentIndex()
+	if segmentIndex < 0 || segmentIndex > maxSegmentIndex {
+		return nil, errors.New("Segment index must be between 0 and " + strconv.Itoa(maxSegmentIndex) +
+			", but was " + strconv.Itoa(segmentIndex))
+	}
+	if metadata.GetSegmentCount() > maxSegmentIndex+1 {
+		return nil, errors.New("Segment count must be at most " + strconv.Itoa(maxSegmentIndex+1) +
+			", but was " + strconv.Itoa(metadata.GetSegmentCount()))
+	}
+	sb := []int{beginMacroPDF417ControlBlock}
+	// The segment index always takes 5 digits, so 2 codewords
+	sb = encodeNumeric(fiveDigits(int64(segmentIndex)), 0, 5, sb)
+
+	fileID := metadata.GetFileID()
+	if fileID == "" || len(fileID)%3 != 0 {
+		return nil, errors.New("File ID must be a non-empty sequence of 3 digit groups, but was " + strconv.Quote(fileID))
+	}
+	codeword := 0
+	for i, ch := range fileID {
+		if !isDigit(ch) {
+			return nil, errors.New("File ID must be a non-empty sequence of 3 digit groups, but was " + strconv.Quote(fileID))
+		}
+		codeword = codeword*10 + int(ch-'0')
+		if i%3 == 2 {
+			if codeword >= latchToText {
+				return nil, errors.New("File ID group " + fileID[i-2:i+1] + " is not below 900")
+			}
+			sb = append(sb, codeword)
+			codeword = 0
+		}
+	}
+
+	for _, field := range []struct {
+		designator int
+		value      string
+	}{
+		{macroPDF417OptionalFieldFileName, metadata.GetFileName()},
+		{macroPDF417OptionalFieldSender, metadata.GetSender()},
+		{macroPDF417OptionalFieldAddressee, metadata.GetAddressee()},
+	} {
+		if field.value == "" {
+			continue
+		}
+		input := []rune(field.value)
+		for _, ch := range input {
+			if !isText(ch) {
+				return nil, errors.New("Cannot use text compaction for " + strconv.Quote(string(ch)))
+			}
+		}
+		sb = append(sb, beginMacroPDF417OptionalField, field.designator)
+		sb, _ = encodeText(input, 0, len(input), sb, submodeAlpha)
+	}
+
+	for _, field := range []struct {
+		designator int
+		value      int64
+	}{
+		{macroPDF417OptionalFieldSegmentCount, int64(metadata.GetSegmentCount())},
+		{macroPDF417OptionalFieldTimeStamp, metadata.GetTimestamp()},
+		{macroPDF417OptionalFieldFileSize, metadata.GetFileSize()},
+		{macroPDF417OptionalFieldChecksum, int64(metadata.GetChecksum())},
+	} {
+		if field.value < 0 {
+			continue
+		}
+		digits := []rune(strconv.FormatInt(field.value, 10))
+		if field.designator == macroPDF417OptionalFieldSegmentCount {
+			// Like the segment index
+			digits = fiveDigits(field.value)
+		}
+		sb = append(sb, beginMacroPDF417OptionalField, field.designator)
+		sb = encodeNumeric(digits, 0, len(digits), sb)
+	}
+
+	if metadata.IsLastSegment() {
+		sb = append(sb, macroPDF417Terminator)
+	}
+	return sb, nil
+}
+
+// fiveDigits formats value, at most 99999, as 5 digits.
+func fiveDigits(value int64) []rune {
+	digits := strconv.FormatInt(value, 10)
+	for len(digits) < 5 {
+		digits = "0" + digits
+	}
+	return []rune(digits)
+}
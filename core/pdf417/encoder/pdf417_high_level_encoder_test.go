@@ -0,0 +1,118 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core/common"
+	. "github.com/discesoft/zxing-go/core/internal"
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+)
+
+func assertCodewords(t *testing.T, expected, actual []int, msg string) {
+	AssertEquals(t, len(expected), len(actual), msg+": length")
+	for i := 0; i < len(expected) && i < len(actual); i++ {
+		AssertEquals(t, expected[i], actual[i], msg+": codeword "+strconv.Itoa(i))
+	}
+}
+
+func testEncodeHighLevel(t *testing.T, msg string, compaction Compaction, expected ...int) {
+	codewords, err := encodeHighLevel(msg, compaction, nil, false)
+	AssertSuccess(t, err)
+	assertCodewords(t, expected, codewords, msg)
+}
+
+func TestEncodeAuto(t *testing.T) {
+	testEncodeHighLevel(t, "Super !", CompactionAuto, 567, 615, 137, 809, 329)
+	testEncodeHighLevel(t, "Super ", CompactionAuto, 567, 615, 137, 809)
+	testEncodeHighLevel(t, "ABC123", CompactionAuto, 1, 88, 32, 119)
+	testEncodeHighLevel(t, "123ABC", CompactionAuto, 841, 63, 840, 32)
+	testEncodeHighLevel(t, "1234", CompactionAuto, 841, 63, 149)
+	// A single byte is shifted to, not latched to
+	testEncodeHighLevel(t, "ABCDEé", CompactionAuto, 1, 63, 149, 913, 233)
+	testEncodeHighLevel(t, "ABCDEéFGHIJ", CompactionAuto, 1, 63, 149, 924, 390, 835, 417, 146, 818)
+	// 13 digits latch to numeric compaction
+	testEncodeHighLevel(t, "AB1234567890123", CompactionAuto, 901, 65, 66, 902, 17, 110, 836, 811, 223)
+}
+
+func TestEncodeForced(t *testing.T) {
+	testEncodeHighLevel(t, "alcool", CompactionByte, 924, 163, 238, 432, 766, 244)
+	testEncodeHighLevel(t, "alcoolique", CompactionByte, 901, 163, 238, 432, 766, 244, 105, 113, 117, 101)
+	testEncodeHighLevel(t, "1234", CompactionNumeric, 902, 12, 434)
+	testEncodeHighLevel(t, "ABCD", CompactionText, 1, 63)
+
+	_, err := encodeHighLevel("12A4", CompactionNumeric, nil, false)
+	AssertFailure(t, err, "letter in numeric compaction")
+	_, err = encodeHighLevel("ABé", CompactionText, nil, false)
+	AssertFailure(t, err, "byte in text compaction")
+}
+
+func TestEncodeECI(t *testing.T) {
+	codewords, err := encodeHighLevel("ABCD", CompactionAuto, common.UTF8, false)
+	AssertSuccess(t, err)
+	assertCodewords(t, []int{927, 26, 901, 65, 66, 67, 68}, codewords, "UTF-8")
+
+	_, err = encodeHighLevel("€", CompactionAuto, nil, false)
+	AssertFailure(t, err, "euro in ISO-8859-1")
+	codewords, err = encodeHighLevel("€", CompactionAuto, nil, true)
+	AssertSuccess(t, err)
+	assertCodewords(t, []int{927, 26, 901, 226, 130, 172}, codewords, "euro with auto ECI")
+
+	codewords, err = encodingECI(1000, nil)
+	AssertSuccess(t, err)
+	assertCodewords(t, []int{926, 0, 100}, codewords, "general purpose ECI")
+	codewords, err = encodingECI(811000, nil)
+	AssertSuccess(t, err)
+	assertCodewords(t, []int{925, 100}, codewords, "user defined ECI")
+	_, err = encodingECI(811800, nil)
+	AssertFailure(t, err, "ECI out of range")
+}
+
+func TestEncodeEmpty(t *testing.T) {
+	_, err := encodeHighLevel("", CompactionAuto, nil, false)
+	AssertFailure(t, err, "empty message")
+}
+
+func TestEncodeMacroBlock(t *testing.T) {
+	metadata := pdf417common.NewPDF417ResultMetadata()
+	metadata.SetSegmentIndex(3)
+	metadata.SetFileID("017053")
+	metadata.SetSegmentCount(4)
+	metadata.SetLastSegment(true)
+	codewords, err := encodeMacroBlock(metadata)
+	AssertSuccess(t, err)
+	assertCodewords(t, []int{928, 111, 103, 17, 53, 923, 1, 111, 104, 922}, codewords, "macro block")
+
+	metadata.SetLastSegment(false)
+	metadata.SetSegmentCount(-1)
+	metadata.SetSender("CEN BE")
+	codewords, err = encodeMacroBlock(metadata)
+	AssertSuccess(t, err)
+	assertCodewords(t, []int{928, 111, 103, 17, 53, 923, 3, 64, 416, 34}, codewords, "macro block with sender")
+
+	for _, fileID := range []string{"", "12", "1a3", "900", "+12"} {
+		metadata.SetFileID(fileID)
+		_, err = encodeMacroBlock(metadata)
+		AssertFailure(t, err, "invalid file ID "+fileID)
+	}
+	metadata.SetFileID("017053")
+	metadata.SetSegmentIndex(99999)
+	_, err = encodeMacroBlock(metadata)
+	AssertFailure(t, err, "segment index out of range")
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2006-2007 Jeremias Maerki.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoder
+
+import (
+	"testing"
+
+	. "github.com/discesoft/zxing-go/core/internal"
+)
+
+func TestCodewordTable(t *testing.T) {
+	AssertEquals(t, 0x1d5c0, codewordTable[0][0], "cluster 0, codeword 0")
+	AssertEquals(t, 0x1eaf0, codewordTable[0][1], "cluster 0, codeword 1")
+	AssertEquals(t, 0x1f560, codewordTable[1][0], "cluster 3, codeword 0")
+	AssertEquals(t, 0x1abe0, codewordTable[2][0], "cluster 6, codeword 0")
+	for cluster := range codewordTable {
+		for _, pattern := range codewordTable[cluster] {
+			AssertTrue(t, pattern != 0, "missing pattern")
+		}
+	}
+}
+
+func TestDetermineDimensions(t *testing.T) {
+	code := NewPDF417(false)
+	cols, rows, err := code.determineDimensions(100, 8)
+	AssertSuccess(t, err)
+	AssertEquals(t, 7, cols, "columns")
+	AssertEquals(t, 16, rows, "rows")
+
+	// A lower preferred ratio gives a taller symbol
+	code.SetPreferredRatio(1)
+	cols, rows, err = code.determineDimensions(100, 8)
+	AssertSuccess(t, err)
+	AssertEquals(t, 3, cols, "columns for a ratio of 1")
+	AssertEquals(t, 37, rows, "rows for a ratio of 1")
+	code.SetPreferredRatio(DefaultPreferredRatio)
+
+	// Taller rows need fewer of them for the same ratio
+	code.SetRowHeight(8)
+	cols, rows, err = code.determineDimensions(100, 8)
+	AssertSuccess(t, err)
+	AssertEquals(t, 11, cols, "columns for rows 8 modules high")
+	AssertEquals(t, 10, rows, "rows 8 modules high")
+	code.SetRowHeight(2)
+	cols, rows, err = code.determineDimensions(100, 8)
+	AssertSuccess(t, err)
+	AssertEquals(t, 5, cols, "columns for rows 2 modules high")
+	AssertEquals(t, 22, rows, "rows 2 modules high")
+	code.SetRowHeight(DefaultRowHeight)
+
+	code.SetDimensions(3, 3, 90, 3)
+	cols, rows, err = code.determineDimensions(100, 8)
+	AssertSuccess(t, err)
+	AssertEquals(t, 3, cols, "fixed columns")
+	AssertEquals(t, 37, rows, "rows for fixed columns")
+
+	// Too few codewords for the minimum rows: they are padded
+	code.SetDimensions(10, 10, 30, 10)
+	cols, rows, err = code.determineDimensions(5, 2)
+	AssertSuccess(t, err)
+	AssertEquals(t, 10, cols, "minimum columns")
+	AssertEquals(t, 10, rows, "minimum rows")
+
+	code.SetDimensions(2, 1, 5, 3)
+	_, _, err = code.determineDimensions(100, 8)
+	AssertFailure(t, err, "too many codewords for the dimensions")
+}
+
+func TestGenerateBarcodeLogic(t *testing.T) {
+	code := NewPDF417(false)
+	code.SetDimensions(2, 2, 90, 3)
+	AssertSuccess(t, code.GenerateBarcodeLogic("PDF417 TEST", 1, false))
+	matrix := code.GetBarcodeMatrix()
+	AssertEquals(t, (2+4)*17+1, matrix.GetWidth(), "width")
+	AssertEquals(t, 6, matrix.GetHeight(), "height")
+
+	scaled, err := matrix.GetScaledMatrix(2, 5)
+	AssertSuccess(t, err)
+	AssertEquals(t, uint32(2*matrix.GetWidth()), scaled.GetWidth(), "scaled width")
+	AssertEquals(t, uint32(5*matrix.GetHeight()), scaled.GetHeight(), "scaled height")
+	for y := uint32(0); y < scaled.GetHeight(); y++ {
+		// Every row starts with the start pattern, 8 black modules
+		AssertTrue(t, scaled.Get(15, y), "start pattern")
+		AssertFalse(t, scaled.Get(16, y), "start pattern")
+	}
+	_, err = matrix.GetScaledMatrix(1, 0)
+	AssertFailure(t, err, "zero row height")
+
+	code.SetCompact(true)
+	AssertSuccess(t, code.GenerateBarcodeLogic("PDF417 TEST", 1, false))
+	AssertEquals(t, (2+2)*17+1, code.GetBarcodeMatrix().GetWidth(), "compact width")
+
+	AssertFailure(t, code.GenerateBarcodeLogic("PDF417 TEST", 9, false), "error correction level 9")
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright 2009 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pdf417
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+	"github.com/discesoft/zxing-go/core/pdf417/decoder"
+	"github.com/discesoft/zxing-go/core/pdf417/detector"
+)
+
+// PDF417Reader can detect and decode PDF417 codes in an image.
+type PDF417Reader struct{}
+
+func NewPDF417Reader() *PDF417Reader {
+	return &PDF417Reader{}
+}
+
+// Decode locates and decodes a PDF417 code in an image.
+// It returns the decoded Result, core.ErrNotFound if a PDF417 code cannot
+// be found, core.ErrFormat if it cannot be decoded, or core.ErrChecksum if
+// error correction fails.
+func (r *PDF417Reader) Decode(image *common.BitMatrix, hints map[core.DecodeHintType]interface{}) (*core.Result, error) {
+	results, err := decode(image, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, core.ErrNotFound
+	}
+	return results[0], nil
+}
+
+// DecodeMultiple locates and decodes all PDF417 codes in an image, such as
+// a form carrying several of them.
+// It returns the decoded Results, which may be empty, or core.ErrNotFound
+// if any code that was found cannot be decoded.
+func (r *PDF417Reader) DecodeMultiple(image *common.BitMatrix, hints map[core.DecodeHintType]interface{}) ([]*core.Result, error) {
+	results, err := decode(image, true)
+	if err != nil {
+		return nil, core.ErrNotFound
+	}
+	return results, nil
+}
+
+func decode(image *common.BitMatrix, multiple bool) ([]*core.Result, error) {
+	detectorResult, err := detector.Detect(image, multiple)
+	if err != nil {
+		return nil, err
+	}
+	var results []*core.Result
+	for _, points := range detectorResult.GetPoints() {
+		decoderResult, err := decoder.Decode(detectorResult.GetBits(), points[4], points[5], points[6], points[7],
+			getMinCodewordWidth(points), getMaxCodewordWidth(points))
+		if err != nil {
+			return nil, err
+		}
+		result := core.NewResult(decoderResult.GetText(), decoderResult.GetRawBytes(), points, core.PDF417)
+		result.PutMetadata(core.ResultMetadataErrorCorrectionLevel, decoderResult.GetECLevel())
+		result.PutMetadata(core.ResultMetadataErrorsCorrected, decoderResult.GetErrorsCorrected())
+		result.PutMetadata(core.ResultMetadataErasuresCorrected, decoderResult.GetErasures())
+		if other := decoderResult.GetOther(); other != nil {
+			result.PutMetadata(core.ResultMetadataPDF417ExtraMetadata, other)
+		}
+		result.PutMetadata(core.ResultMetadataOrientation, detectorResult.GetRotation())
+		result.PutMetadata(core.ResultMetadataSymbologyIdentifier, "]L"+strconv.Itoa(decoderResult.GetSymbologyModifier()))
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func getMaxWidth(p1, p2 *core.ResultPoint) int {
+	if p1 == nil || p2 == nil {
+		return 0
+	}
+	return int(math.Abs(float64(p1.GetX() - p2.GetX())))
+}
+
+func getMinWidth(p1, p2 *core.ResultPoint) int {
+	if p1 == nil || p2 == nil {
+		return math.MaxInt32
+	}
+	return int(math.Abs(float64(p1.GetX() - p2.GetX())))
+}
+
+// getMaxCodewordWidth estimates the widest codeword from the widths of the
+// start and stop patterns.
+func getMaxCodewordWidth(p []*core.ResultPoint) int {
+	return maxInt(
+		maxInt(getMaxWidth(p[0], p[4]), stopPatternToCodewordWidth(getMaxWidth(p[6], p[2]))),
+		maxInt(getMaxWidth(p[1], p[5]), stopPatternToCodewordWidth(getMaxWidth(p[7], p[3]))))
+}
+
+// getMinCodewordWidth estimates the narrowest codeword from the widths of
+// the start and stop patterns.
+func getMinCodewordWidth(p []*core.ResultPoint) int {
+	return minInt(
+		minInt(getMinWidth(p[0], p[4]), stopPatternToCodewordWidth(getMinWidth(p[6], p[2]))),
+		minInt(getMinWidth(p[1], p[5]), stopPatternToCodewordWidth(getMinWidth(p[7], p[3]))))
+}
+
+// stopPatternToCodewordWidth scales the width of the 18 module stop
+// pattern to that of a 17 module codeword.
+func stopPatternToCodewordWidth(width int) int {
+	if width == math.MaxInt32 {
+		return width
+	}
+	return width * pdf417common.ModulesInCodeword / pdf417common.ModulesInStopPattern
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (r *PDF417Reader) Reset() {
+	// do nothing
+}
@@ -0,0 +1,184 @@
+/*
+ * Copyright 2009 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pdf417_test
+
+import (
+	"testing"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	"github.com/discesoft/zxing-go/core/internal"
+	"github.com/discesoft/zxing-go/core/pdf417"
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+)
+
+const (
+	moduleWidth = 2
+	rowHeight   = 6
+)
+
+// clusterOf returns the cluster, 0 to 2, of a 17 bit codeword pattern.
+func clusterOf(pattern int) int {
+	widths := make([]int, 8)
+	for i, bit := 7, pattern&1; i >= 0; i-- {
+		for pattern&1 == bit && widths[i] < 17 {
+			widths[i]++
+			pattern >>= 1
+		}
+		bit ^= 1
+	}
+	return ((widths[0] - widths[2] + widths[4] - widths[6] + 9) % 9) / 3
+}
+
+// errorCorrection returns the error correction codewords for data.
+func errorCorrection(data []int, numECCodewords int) []int {
+	generator := []int{1}
+	power := 1
+	for i := 1; i <= numECCodewords; i++ {
+		power = power * 3 % 929
+		next := make([]int, len(generator)+1)
+		for j, c := range generator {
+			next[j] = (next[j] + c) % 929
+			next[j+1] = (next[j+1] + 929 - c*power%929) % 929
+		}
+		generator = next
+	}
+	remainder := make([]int, len(data)+numECCodewords)
+	copy(remainder, data)
+	for i := range data {
+		factor := remainder[i]
+		for j, c := range generator {
+			remainder[i+j] = (remainder[i+j] + 929 - factor*c%929) % 929
+		}
+	}
+	result := make([]int, numECCodewords)
+	for i, c := range remainder[len(data):] {
+		result[i] = (929 - c) % 929
+	}
+	return result
+}
+
+// drawSymbol draws a PDF417 symbol of 4 rows and 2 columns at error
+// correction level 0, holding text, which must be at most 8 upper case
+// letters, with its top left corner at x, y.
+func drawSymbol(t *testing.T, image *common.BitMatrix, x, y int, text string) {
+	const rows, columns, ecLevel = 4, 2, 0
+
+	data := []int{6}
+	for i := 0; i < 10; i += 2 {
+		first, second := 29, 29
+		if i < len(text) {
+			first = int(text[i] - 'A')
+		}
+		if i+1 < len(text) {
+			second = int(text[i+1] - 'A')
+		}
+		data = append(data, first*30+second)
+	}
+	codewords := append(data, errorCorrection(data, 2<<ecLevel)...)
+
+	var patterns [3]map[int]int
+	for i := range patterns {
+		patterns[i] = make(map[int]int)
+	}
+	for _, pattern := range pdf417common.SymbolTable {
+		patterns[clusterOf(pattern)][pdf417common.GetCodeword(pattern)] = pattern
+	}
+
+	for row := 0; row < rows; row++ {
+		cluster := row % 3
+		indicators := [][]int{
+			{(rows - 1) / 3, columns - 1},
+			{ecLevel*3 + (rows-1)%3, (rows - 1) / 3},
+			{columns - 1, ecLevel*3 + (rows-1)%3},
+		}[cluster]
+		rowCodewords := []int{30*(row/3) + indicators[0]}
+		rowCodewords = append(rowCodewords, codewords[row*columns:(row+1)*columns]...)
+		rowCodewords = append(rowCodewords, 30*(row/3)+indicators[1])
+
+		px := x
+		draw := func(pattern, numModules int) {
+			for i := numModules - 1; i >= 0; i-- {
+				if pattern&(1<<uint(i)) != 0 {
+					internal.AssertSuccess(t, image.SetRegion(uint32(px), uint32(y+row*rowHeight), moduleWidth, rowHeight))
+				}
+				px += moduleWidth
+			}
+		}
+		draw(0x1fea8, 17)
+		for _, cw := range rowCodewords {
+			draw(patterns[cluster][cw], 17)
+		}
+		draw(0x3fa29, 18)
+	}
+}
+
+// symbolWidth is the width in pixels of a symbol drawn by drawSymbol.
+const symbolWidth = (17*5 + 18) * moduleWidth
+
+func TestPDF417Reader_Decode(t *testing.T) {
+	image, err := common.NewBitMatrix(symbolWidth+40, 60)
+	internal.AssertSuccess(t, err)
+	drawSymbol(t, image, 20, 15, "PDFCODE")
+
+	result, err := pdf417.NewPDF417Reader().Decode(image, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "PDFCODE", result.GetText(), "decoded text")
+	internal.AssertEquals(t, core.PDF417, result.GetBarcodeFormat(), "barcode format")
+	metadata := result.GetResultMetadata()
+	internal.AssertEquals(t, "0", metadata[core.ResultMetadataErrorCorrectionLevel], "error correction level")
+	internal.AssertEquals(t, 0, metadata[core.ResultMetadataOrientation], "orientation")
+	internal.AssertEquals(t, "]L0", metadata[core.ResultMetadataSymbologyIdentifier], "symbology identifier")
+}
+
+func TestPDF417Reader_Rotated(t *testing.T) {
+	image, err := common.NewBitMatrix(symbolWidth+40, 60)
+	internal.AssertSuccess(t, err)
+	drawSymbol(t, image, 20, 15, "ROTATED")
+	image.Rotate90()
+
+	result, err := pdf417.NewPDF417Reader().Decode(image, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "ROTATED", result.GetText(), "decoded text")
+	internal.AssertEquals(t, 270, result.GetResultMetadata()[core.ResultMetadataOrientation], "orientation")
+}
+
+func TestPDF417Reader_DecodeMultiple(t *testing.T) {
+	image, err := common.NewBitMatrix(2*symbolWidth+60, 120)
+	internal.AssertSuccess(t, err)
+	drawSymbol(t, image, 20, 10, "FIRST")
+	drawSymbol(t, image, symbolWidth+40, 10, "SECOND")
+	drawSymbol(t, image, 20, 70, "THIRD")
+
+	results, err := pdf417.NewPDF417Reader().DecodeMultiple(image, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 3, len(results), "number of symbols")
+	for i, expected := range []string{"FIRST", "SECOND", "THIRD"} {
+		internal.AssertEquals(t, expected, results[i].GetText(), "decoded text")
+	}
+}
+
+func TestPDF417Reader_NotFound(t *testing.T) {
+	image, err := common.NewBitMatrix(100, 100)
+	internal.AssertSuccess(t, err)
+	_, err = pdf417.NewPDF417Reader().Decode(image, nil)
+	internal.AssertEquals(t, core.ErrNotFound, err, "blank image")
+
+	results, err := pdf417.NewPDF417Reader().DecodeMultiple(image, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, 0, len(results), "symbols found in a blank image")
+}
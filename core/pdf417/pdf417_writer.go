@@ -0,0 +1,169 @@
+/*
+ * Copyright 2012 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pdf417
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/common"
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+	"github.com/discesoft/zxing-go/core/pdf417/encoder"
+)
+
+const (
+	// whiteSpace is the default white space around the symbol, in
+	// pixels.
+	whiteSpace = 30
+
+	// DefaultErrorCorrectionLevel is the error correction level used
+	// unless core.EncodeHintErrorCorrection says otherwise.
+	DefaultErrorCorrectionLevel = 2
+)
+
+// PDF417Writer renders a PDF417 code as a BitMatrix.
+type PDF417Writer struct{}
+
+func NewPDF417Writer() *PDF417Writer {
+	return &PDF417Writer{}
+}
+
+// Encode encodes contents as a PDF417 code, scaled by a whole number of
+// pixels per module to fit within width by height pixels where possible,
+// and rotated if that suits a taller than wide area better.
+// The core.EncodeHintPDF417Compact, core.EncodeHintPDF417Compaction and
+// core.EncodeHintPDF417Dimensions hints shape the symbol, as does
+// core.EncodeHintPDF417AspectRatio, the width to height ratio in modules
+// the dimensions are chosen to come closest to. The
+// core.EncodeHintPDF417RowHeight hint gives the height of a row as a
+// multiple of the module width, core.EncodeHintErrorCorrection the error
+// correction level from 0 to 8, and core.EncodeHintMargin the white space
+// around the symbol in pixels. The core.EncodeHintCharacterSet hint selects the character set,
+// which is then designated by an ECI; with core.EncodeHintPDF417AutoECI
+// set UTF-8 is chosen if contents needs it. The
+// core.EncodeHintPDF417MacroMetadata hint makes the symbol a segment of a
+// Macro PDF417 message.
+// It returns an error if contents cannot be encoded.
+func (w *PDF417Writer) Encode(contents string, format core.BarcodeFormat, width, height int, hints map[core.EncodeHintType]interface{}) (*common.BitMatrix, error) {
+	if format != core.PDF417 {
+		return nil, errors.New("Can only encode PDF_417, but got " + strconv.Itoa(int(format)))
+	}
+
+	code := encoder.NewPDF417(false)
+	margin := whiteSpace
+	errorCorrectionLevel := DefaultErrorCorrectionLevel
+	autoECI := false
+
+	if compact, ok := hints[core.EncodeHintPDF417Compact].(bool); ok {
+		code.SetCompact(compact)
+	}
+	if compaction, ok := hints[core.EncodeHintPDF417Compaction].(encoder.Compaction); ok {
+		code.SetCompaction(compaction)
+	}
+	if dimensions, ok := hints[core.EncodeHintPDF417Dimensions].(*encoder.Dimensions); ok {
+		code.SetDimensions(dimensions.GetMaxCols(), dimensions.GetMinCols(), dimensions.GetMaxRows(), dimensions.GetMinRows())
+	}
+	if ratio, ok := hints[core.EncodeHintPDF417AspectRatio].(float64); ok {
+		if ratio <= 0 {
+			return nil, errors.New("Aspect ratio must be positive: " + strconv.FormatFloat(ratio, 'f', -1, 64))
+		}
+		code.SetPreferredRatio(ratio)
+	}
+	if rowHeight, ok := hints[core.EncodeHintPDF417RowHeight].(int); ok {
+		if rowHeight < 1 {
+			return nil, errors.New("Row height must be positive: " + strconv.Itoa(rowHeight))
+		}
+		code.SetRowHeight(rowHeight)
+	}
+	if requestedMargin, ok := hints[core.EncodeHintMargin].(int); ok {
+		margin = requestedMargin
+	}
+	if level, ok := hints[core.EncodeHintErrorCorrection].(int); ok {
+		errorCorrectionLevel = level
+	}
+	if name, ok := hints[core.EncodeHintCharacterSet].(string); ok {
+		charset := common.GetCharacterSetECIByName(name)
+		if charset == nil {
+			return nil, errors.New("Unsupported character set " + name)
+		}
+		code.SetEncoding(charset)
+	}
+	if requestedAutoECI, ok := hints[core.EncodeHintPDF417AutoECI].(bool); ok {
+		autoECI = requestedAutoECI
+	}
+	if metadata, ok := hints[core.EncodeHintPDF417MacroMetadata].(*pdf417common.PDF417ResultMetadata); ok {
+		code.SetMacroMetadata(metadata)
+	}
+
+	return bitMatrixFromEncoder(code, contents, errorCorrectionLevel, width, height, margin, autoECI)
+}
+
+// bitMatrixFromEncoder encodes contents with code and renders the
+// symbol, rotated if width and height suggest so, with margin white
+// pixels around it.
+func bitMatrixFromEncoder(code *encoder.PDF417, contents string, errorCorrectionLevel, width, height, margin int, autoECI bool) (*common.BitMatrix, error) {
+	if err := code.GenerateBarcodeLogic(contents, errorCorrectionLevel, autoECI); err != nil {
+		return nil, err
+	}
+
+	barcodeMatrix := code.GetBarcodeMatrix()
+	rowHeight := code.GetRowHeight()
+	symbolWidth := barcodeMatrix.GetWidth()
+	symbolHeight := barcodeMatrix.GetHeight() * rowHeight
+	rotated := (height > width) != (symbolWidth < symbolHeight)
+	if rotated {
+		symbolWidth, symbolHeight = symbolHeight, symbolWidth
+	}
+
+	scale := width / symbolWidth
+	if scaleY := height / symbolHeight; scaleY < scale {
+		scale = scaleY
+	}
+	if scale < 1 {
+		scale = 1
+	}
+
+	matrix, err := barcodeMatrix.GetScaledMatrix(scale, scale*rowHeight)
+	if err != nil {
+		return nil, err
+	}
+	if rotated {
+		matrix.Rotate90()
+	}
+	return bitMatrixWithMargin(matrix, margin)
+}
+
+// bitMatrixWithMargin copies input into the middle of a matrix margin
+// pixels larger on each side.
+func bitMatrixWithMargin(input *common.BitMatrix, margin int) (*common.BitMatrix, error) {
+	if margin < 0 {
+		return nil, errors.New("Margin can't be negative: " + strconv.Itoa(margin))
+	}
+	output, err := common.NewBitMatrix(input.GetWidth()+uint32(2*margin), input.GetHeight()+uint32(2*margin))
+	if err != nil {
+		return nil, err
+	}
+	for y := uint32(0); y < input.GetHeight(); y++ {
+		for x := uint32(0); x < input.GetWidth(); x++ {
+			if input.Get(x, y) {
+				output.Set(x+uint32(margin), y+uint32(margin))
+			}
+		}
+	}
+	return output, nil
+}
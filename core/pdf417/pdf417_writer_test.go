@@ -0,0 +1,240 @@
+/*
+ * Copyright 2012 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pdf417_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/discesoft/zxing-go/core"
+	"github.com/discesoft/zxing-go/core/internal"
+	"github.com/discesoft/zxing-go/core/pdf417"
+	pdf417common "github.com/discesoft/zxing-go/core/pdf417/common"
+	"github.com/discesoft/zxing-go/core/pdf417/encoder"
+)
+
+func encodeAndDecode(t *testing.T, contents string, width, height int, hints map[core.EncodeHintType]interface{}) *core.Result {
+	matrix, err := pdf417.NewPDF417Writer().Encode(contents, core.PDF417, width, height, hints)
+	internal.AssertSuccess(t, err)
+	result, err := pdf417.NewPDF417Reader().Decode(matrix, nil)
+	internal.AssertSuccess(t, err)
+	return result
+}
+
+func assertRoundTrips(t *testing.T, contents string, hints map[core.EncodeHintType]interface{}) {
+	result := encodeAndDecode(t, contents, 800, 400, hints)
+	internal.AssertEquals(t, contents, result.GetText(), "decoded "+strconv.Quote(result.GetText())+" instead of "+strconv.Quote(contents))
+}
+
+func TestPDF417Writer_RoundTrip(t *testing.T) {
+	for _, contents := range []string{
+		"A",
+		"Hello, World!",
+		"1234567890123456789012345678901234567890",
+		"SHIP TO: 123 Main St., Springfield (Dept. #42) [ref 555-0199]",
+		"«äöüé»",
+		"\x00\x01\x02binary\u00ff",
+		"Mixed 12345678901234 digits and text\n\tand control",
+	} {
+		assertRoundTrips(t, contents, nil)
+	}
+}
+
+func TestPDF417Writer_Compaction(t *testing.T) {
+	for _, test := range []struct {
+		compaction encoder.Compaction
+		contents   string
+	}{
+		{encoder.CompactionAuto, "AUTO 0123456789 auto"},
+		{encoder.CompactionText, "Text only, please!"},
+		{encoder.CompactionByte, "Bytes 0123456789"},
+		{encoder.CompactionNumeric, "01234567890123456789012345678901234567890123456789"},
+	} {
+		hints := map[core.EncodeHintType]interface{}{core.EncodeHintPDF417Compaction: test.compaction}
+		assertRoundTrips(t, test.contents, hints)
+	}
+
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintPDF417Compaction: encoder.CompactionNumeric}
+	_, err := pdf417.NewPDF417Writer().Encode("12AB", core.PDF417, 0, 0, hints)
+	internal.AssertFailure(t, err, "letters in numeric compaction")
+}
+
+func TestPDF417Writer_ErrorCorrection(t *testing.T) {
+	for level := 0; level <= 8; level++ {
+		hints := map[core.EncodeHintType]interface{}{core.EncodeHintErrorCorrection: level}
+		result := encodeAndDecode(t, "Error correction level", 2000, 2000, hints)
+		internal.AssertEquals(t, strconv.Itoa(level), result.GetResultMetadata()[core.ResultMetadataErrorCorrectionLevel], "error correction level")
+	}
+
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintErrorCorrection: 9}
+	_, err := pdf417.NewPDF417Writer().Encode("level 9", core.PDF417, 0, 0, hints)
+	internal.AssertFailure(t, err, "error correction level 9")
+}
+
+func TestPDF417Writer_Dimensions(t *testing.T) {
+	hints := map[core.EncodeHintType]interface{}{
+		core.EncodeHintPDF417Dimensions: encoder.NewDimensions(4, 4, 3, 90),
+		core.EncodeHintMargin:           5,
+	}
+	matrix, err := pdf417.NewPDF417Writer().Encode("FOUR COLUMNS", core.PDF417, 0, 0, hints)
+	internal.AssertSuccess(t, err)
+	// 4 data columns plus start, stop and the row indicators, with the
+	// margin on each side
+	internal.AssertEquals(t, uint32((4+4)*17+1+2*5), matrix.GetWidth(), "width")
+	assertRoundTrips(t, "FOUR COLUMNS", hints)
+
+	hints[core.EncodeHintPDF417Dimensions] = encoder.NewDimensions(1, 1, 3, 4)
+	_, err = pdf417.NewPDF417Writer().Encode("Far too long for a single column of four rows", core.PDF417, 0, 0, hints)
+	internal.AssertFailure(t, err, "message too long for dimensions")
+}
+
+func TestPDF417Writer_RowHeight(t *testing.T) {
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintMargin: 0}
+	matrix, err := pdf417.NewPDF417Writer().Encode("ROWS", core.PDF417, 0, 0, hints)
+	internal.AssertSuccess(t, err)
+	rows := matrix.GetHeight() / encoder.DefaultRowHeight
+	internal.AssertEquals(t, rows*encoder.DefaultRowHeight, matrix.GetHeight(), "default height")
+
+	// Taller rows keep the aspect ratio with fewer, wider rows
+	hints[core.EncodeHintPDF417RowHeight] = 7
+	tall, err := pdf417.NewPDF417Writer().Encode("ROWS", core.PDF417, 0, 0, hints)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, uint32(0), tall.GetHeight()%7, "height with 7 module rows")
+	internal.AssertTrue(t, tall.GetHeight()/7 < rows, "taller rows should give fewer rows")
+	internal.AssertTrue(t, tall.GetWidth() > matrix.GetWidth(), "taller rows should give more columns")
+	assertRoundTrips(t, "ROWS", hints)
+
+	hints[core.EncodeHintPDF417RowHeight] = 0
+	_, err = pdf417.NewPDF417Writer().Encode("ROWS", core.PDF417, 0, 0, hints)
+	internal.AssertFailure(t, err, "rows without height")
+}
+
+func TestPDF417Writer_AspectRatio(t *testing.T) {
+	contents := "A message long enough to need several columns and rows of codewords"
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintMargin: 0}
+	wide, err := pdf417.NewPDF417Writer().Encode(contents, core.PDF417, 0, 0, hints)
+	internal.AssertSuccess(t, err)
+	hints[core.EncodeHintPDF417AspectRatio] = 1.5
+	narrow, err := pdf417.NewPDF417Writer().Encode(contents, core.PDF417, 0, 0, hints)
+	internal.AssertSuccess(t, err)
+	internal.AssertTrue(t, narrow.GetWidth() < wide.GetWidth(), "a lower ratio should give a narrower symbol")
+	internal.AssertTrue(t, narrow.GetHeight() > wide.GetHeight(), "a lower ratio should give a taller symbol")
+	assertRoundTrips(t, contents, hints)
+
+	hints[core.EncodeHintPDF417AspectRatio] = -1.0
+	_, err = pdf417.NewPDF417Writer().Encode(contents, core.PDF417, 0, 0, hints)
+	internal.AssertFailure(t, err, "negative aspect ratio")
+}
+
+func TestPDF417Writer_Rotated(t *testing.T) {
+	// A tall area turns the symbol on its side
+	matrix, err := pdf417.NewPDF417Writer().Encode("ROTATED", core.PDF417, 100, 1000, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertTrue(t, matrix.GetHeight() > matrix.GetWidth(), "symbol was not rotated")
+	result, err := pdf417.NewPDF417Reader().Decode(matrix, nil)
+	internal.AssertSuccess(t, err)
+	internal.AssertEquals(t, "ROTATED", result.GetText(), "rotated text")
+}
+
+func TestPDF417Writer_CharacterSet(t *testing.T) {
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintCharacterSet: "UTF-8"}
+	assertRoundTrips(t, "Grüße, 世界", hints)
+
+	_, err := pdf417.NewPDF417Writer().Encode("世界", core.PDF417, 0, 0, nil)
+	internal.AssertFailure(t, err, "non ISO-8859-1 text without a character set")
+	hints = map[core.EncodeHintType]interface{}{core.EncodeHintPDF417AutoECI: true}
+	assertRoundTrips(t, "世界", hints)
+
+	hints = map[core.EncodeHintType]interface{}{core.EncodeHintCharacterSet: "no-such-charset"}
+	_, err = pdf417.NewPDF417Writer().Encode("text", core.PDF417, 0, 0, hints)
+	internal.AssertFailure(t, err, "unknown character set")
+}
+
+func TestPDF417Writer_WrongFormat(t *testing.T) {
+	_, err := pdf417.NewPDF417Writer().Encode("text", core.Aztec, 0, 0, nil)
+	internal.AssertFailure(t, err, "wrong format")
+}
+
+func TestPDF417Writer_Macro(t *testing.T) {
+	metadata := pdf417common.NewPDF417ResultMetadata()
+	metadata.SetSegmentIndex(1)
+	metadata.SetFileID("123456789")
+	metadata.SetSegmentCount(2)
+	metadata.SetLastSegment(true)
+	metadata.SetFileName("label.txt")
+	metadata.SetSender("Warehouse 7")
+	metadata.SetAddressee("ACME Corp.")
+	metadata.SetTimestamp(1700000000)
+	metadata.SetFileSize(1234)
+	metadata.SetChecksum(65535)
+	hints := map[core.EncodeHintType]interface{}{core.EncodeHintPDF417MacroMetadata: metadata}
+	result := encodeAndDecode(t, "Second half of the label", 800, 400, hints)
+	internal.AssertEquals(t, "Second half of the label", result.GetText(), "text")
+
+	decoded, ok := result.GetResultMetadata()[core.ResultMetadataPDF417ExtraMetadata].(*pdf417common.PDF417ResultMetadata)
+	internal.AssertTrue(t, ok, "no macro metadata")
+	internal.AssertEquals(t, 1, decoded.GetSegmentIndex(), "segment index")
+	internal.AssertEquals(t, "123456789", decoded.GetFileID(), "file ID")
+	internal.AssertEquals(t, 2, decoded.GetSegmentCount(), "segment count")
+	internal.AssertTrue(t, decoded.IsLastSegment(), "last segment")
+	internal.AssertEquals(t, "label.txt", decoded.GetFileName(), "file name")
+	internal.AssertEquals(t, "Warehouse 7", decoded.GetSender(), "sender")
+	internal.AssertEquals(t, "ACME Corp.", decoded.GetAddressee(), "addressee")
+	internal.AssertEquals(t, int64(1700000000), decoded.GetTimestamp(), "timestamp")
+	internal.AssertEquals(t, int64(1234), decoded.GetFileSize(), "file size")
+	internal.AssertEquals(t, 65535, decoded.GetChecksum(), "checksum")
+
+	result = encodeAndDecode(t, "No macro", 800, 400, nil)
+	_, ok = result.GetResultMetadata()[core.ResultMetadataPDF417ExtraMetadata]
+	internal.AssertFalse(t, ok, "macro metadata without a control block")
+}
+
+func TestPDF417Writer_Compact(t *testing.T) {
+	hints := map[core.EncodeHintType]interface{}{
+		core.EncodeHintPDF417Compact: true,
+		core.EncodeHintMargin:        0,
+	}
+	full, err := pdf417.NewPDF417Writer().Encode("COMPACT", core.PDF417, 0, 0, nil)
+	internal.AssertSuccess(t, err)
+	compact, err := pdf417.NewPDF417Writer().Encode("COMPACT", core.PDF417, 0, 0, hints)
+	internal.AssertSuccess(t, err)
+	// The right row indicator and all but one module of the stop pattern
+	// are left out
+	internal.AssertEquals(t, full.GetWidth()-2*30-17-17, compact.GetWidth(), "compact width")
+
+	for _, contents := range []string{
+		"A",
+		"Narrow label",
+		"SHIP TO: 123 Main St., Springfield (Dept. #42) [ref 555-0199]",
+	} {
+		for _, dimensions := range []*encoder.Dimensions{nil, encoder.NewDimensions(1, 1, 3, 90), encoder.NewDimensions(5, 5, 3, 90)} {
+			hints := map[core.EncodeHintType]interface{}{core.EncodeHintPDF417Compact: true}
+			if dimensions != nil {
+				hints[core.EncodeHintPDF417Dimensions] = dimensions
+			}
+			assertRoundTrips(t, contents, hints)
+
+			// Upside down the start pattern is on the right
+			matrix, err := pdf417.NewPDF417Writer().Encode(contents, core.PDF417, 0, 0, hints)
+			internal.AssertSuccess(t, err)
+			internal.AssertSuccess(t, matrix.Rotate180())
+			result, err := pdf417.NewPDF417Reader().Decode(matrix, nil)
+			internal.AssertSuccess(t, err)
+			internal.AssertEquals(t, contents, result.GetText(), "upside down compact symbol")
+		}
+	}
+}
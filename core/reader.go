@@ -0,0 +1,33 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import "github.com/discesoft/zxing-go/core/common"
+
+// Reader is implemented by the barcode readers, which decode a barcode
+// from an image that has already been binarized into a BitMatrix.
+type Reader interface {
+	// Decode locates and decodes a barcode in image. hints may be nil,
+	// or may guide the reader as described by each DecodeHintType.
+	// It returns the decoded Result, or one of ErrNotFound, ErrFormat or
+	// ErrChecksum.
+	Decode(image *common.BitMatrix, hints map[DecodeHintType]interface{}) (*Result, error)
+
+	// Reset resets any internal state the implementation has after a
+	// decode, to prepare it for reuse.
+	Reset()
+}
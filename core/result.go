@@ -0,0 +1,104 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import "time"
+
+// Result encapsulates the result of decoding a barcode within an image.
+type Result struct {
+	text           string
+	rawBytes       []uint8
+	numBits        int
+	resultPoints   []*ResultPoint
+	format         BarcodeFormat
+	resultMetadata map[ResultMetadataType]interface{}
+	timestamp      int64
+}
+
+// NewResult constructs a Result timestamped with the current time, in
+// milliseconds.
+func NewResult(text string, rawBytes []uint8, resultPoints []*ResultPoint, format BarcodeFormat) *Result {
+	return NewResultWithNumBits(text, rawBytes, 8*len(rawBytes), resultPoints, format, time.Now().UnixNano()/int64(time.Millisecond))
+}
+
+// NewResultWithNumBits constructs a Result whose raw bytes only hold
+// numBits valid bits, with the given timestamp.
+func NewResultWithNumBits(text string, rawBytes []uint8, numBits int, resultPoints []*ResultPoint, format BarcodeFormat, timestamp int64) *Result {
+	return &Result{text, rawBytes, numBits, resultPoints, format, nil, timestamp}
+}
+
+// GetText returns the raw text encoded by the barcode.
+func (r *Result) GetText() string {
+	return r.text
+}
+
+// GetRawBytes returns the raw bytes encoded by the barcode, if applicable,
+// otherwise nil.
+func (r *Result) GetRawBytes() []uint8 {
+	return r.rawBytes
+}
+
+// GetNumBits returns how many bits of GetRawBytes are valid; typically 8
+// times its length.
+func (r *Result) GetNumBits() int {
+	return r.numBits
+}
+
+// GetResultPoints returns the points related to the barcode in the image.
+// These are typically points identifying finder patterns or the corners of
+// the barcode. The exact meaning is specific to the type of barcode that
+// was decoded.
+func (r *Result) GetResultPoints() []*ResultPoint {
+	return r.resultPoints
+}
+
+// GetBarcodeFormat returns the format of the barcode that was decoded.
+func (r *Result) GetBarcodeFormat() BarcodeFormat {
+	return r.format
+}
+
+// GetResultMetadata returns the map of ResultMetadataType keys to values,
+// which may be nil. This contains optional metadata about what was
+// detected about the barcode, like orientation.
+func (r *Result) GetResultMetadata() map[ResultMetadataType]interface{} {
+	return r.resultMetadata
+}
+
+func (r *Result) PutMetadata(metadataType ResultMetadataType, value interface{}) {
+	if r.resultMetadata == nil {
+		r.resultMetadata = make(map[ResultMetadataType]interface{})
+	}
+	r.resultMetadata[metadataType] = value
+}
+
+func (r *Result) PutAllMetadata(metadata map[ResultMetadataType]interface{}) {
+	for metadataType, value := range metadata {
+		r.PutMetadata(metadataType, value)
+	}
+}
+
+func (r *Result) AddResultPoints(newPoints []*ResultPoint) {
+	r.resultPoints = append(r.resultPoints, newPoints...)
+}
+
+func (r *Result) GetTimestamp() int64 {
+	return r.timestamp
+}
+
+func (r *Result) String() string {
+	return r.text
+}
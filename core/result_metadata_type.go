@@ -0,0 +1,81 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+// ResultMetadataType represents some type of metadata about the result of
+// the decoding that the decoder wishes to communicate back to the caller.
+type ResultMetadataType uint8
+
+const (
+	// ResultMetadataOther is unspecified, application-specific metadata.
+	// Maps to an unspecified value.
+	ResultMetadataOther ResultMetadataType = iota
+
+	// ResultMetadataOrientation denotes the likely approximate orientation
+	// of the barcode in the image. This value is given as degrees rotated
+	// clockwise from the normal, upright orientation, as an int.
+	ResultMetadataOrientation
+
+	// ResultMetadataByteSegments holds, for some formats, the raw bytes of
+	// the runs of byte-encoded data, as a [][]uint8.
+	ResultMetadataByteSegments
+
+	// ResultMetadataErrorCorrectionLevel is the name of the error
+	// correction level used, if applicable, as a string.
+	ResultMetadataErrorCorrectionLevel
+
+	// ResultMetadataErrorsCorrected is the number of errors corrected, as
+	// an int.
+	ResultMetadataErrorsCorrected
+
+	// ResultMetadataErasuresCorrected is the number of erasures corrected,
+	// as an int.
+	ResultMetadataErasuresCorrected
+
+	// ResultMetadataIssueNumber is, for some periodicals, the issue number
+	// as a string.
+	ResultMetadataIssueNumber
+
+	// ResultMetadataSuggestedPrice is, for some products, the suggested
+	// retail price in the barcode as a formatted string.
+	ResultMetadataSuggestedPrice
+
+	// ResultMetadataPossibleCountry is, for some products, the possible
+	// country of manufacture as a string denoting the ISO country code.
+	ResultMetadataPossibleCountry
+
+	// ResultMetadataUPCEANExtension is, for some products, the extension
+	// text.
+	ResultMetadataUPCEANExtension
+
+	// ResultMetadataPDF417ExtraMetadata holds PDF417-specific metadata.
+	ResultMetadataPDF417ExtraMetadata
+
+	// ResultMetadataStructuredAppendSequence is, if the code format
+	// supports structured append and the current scanned code is part of
+	// one, the sequence number, as an int.
+	ResultMetadataStructuredAppendSequence
+
+	// ResultMetadataStructuredAppendParity is, if the code format supports
+	// structured append and the current scanned code is part of one, the
+	// parity data, as an int.
+	ResultMetadataStructuredAppendParity
+
+	// ResultMetadataSymbologyIdentifier is the barcode symbology
+	// identifier, such as "]d1", as a string.
+	ResultMetadataSymbologyIdentifier
+)
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2007 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import (
+	"math"
+	"strconv"
+)
+
+// ResultPoint encapsulates a point of interest in an image containing a
+// barcode. Typically, this would be the location of a finder pattern or
+// the corner of the barcode, for example.
+type ResultPoint struct {
+	x, y float32
+}
+
+func NewResultPoint(x, y float32) *ResultPoint {
+	return &ResultPoint{x, y}
+}
+
+func (rp *ResultPoint) GetX() float32 {
+	return rp.x
+}
+
+func (rp *ResultPoint) GetY() float32 {
+	return rp.y
+}
+
+func (rp *ResultPoint) Equals(other *ResultPoint) bool {
+	return rp.x == other.x && rp.y == other.y
+}
+
+func (rp *ResultPoint) String() string {
+	return "(" + strconv.FormatFloat(float64(rp.x), 'f', -1, 32) + "," + strconv.FormatFloat(float64(rp.y), 'f', -1, 32) + ")"
+}
+
+// OrderBestPatterns orders an array of three ResultPoints in an order
+// [A,B,C] such that AB is less than AC and BC is less than AC, and the
+// angle between BC and BA is less than 180 degrees.
+// patterns is reordered in place.
+func OrderBestPatterns(patterns []*ResultPoint) {
+	// Find distances between pattern centers
+	zeroOneDistance := Distance(patterns[0], patterns[1])
+	oneTwoDistance := Distance(patterns[1], patterns[2])
+	zeroTwoDistance := Distance(patterns[0], patterns[2])
+
+	var pointA, pointB, pointC *ResultPoint
+	// Assume one closest to other two is B; A and C will just be guesses at first
+	if oneTwoDistance >= zeroOneDistance && oneTwoDistance >= zeroTwoDistance {
+		pointB = patterns[0]
+		pointA = patterns[1]
+		pointC = patterns[2]
+	} else if zeroTwoDistance >= oneTwoDistance && zeroTwoDistance >= zeroOneDistance {
+		pointB = patterns[1]
+		pointA = patterns[0]
+		pointC = patterns[2]
+	} else {
+		pointB = patterns[2]
+		pointA = patterns[0]
+		pointC = patterns[1]
+	}
+
+	// Use cross product to figure out whether A and C are correct or flipped.
+	// This asks whether BC x BA has a positive z component, which is the arrangement
+	// we want for A, B, C. If it's negative, then we've got it flipped around and
+	// should swap A and C.
+	if crossProductZ(pointA, pointB, pointC) < 0.0 {
+		pointA, pointC = pointC, pointA
+	}
+
+	patterns[0] = pointA
+	patterns[1] = pointB
+	patterns[2] = pointC
+}
+
+// Distance returns the distance between pattern1 and pattern2.
+func Distance(pattern1, pattern2 *ResultPoint) float32 {
+	xDiff := float64(pattern1.x - pattern2.x)
+	yDiff := float64(pattern1.y - pattern2.y)
+	return float32(math.Sqrt(xDiff*xDiff + yDiff*yDiff))
+}
+
+// crossProductZ returns the z component of the cross product between
+// vectors BC and BA.
+func crossProductZ(pointA, pointB, pointC *ResultPoint) float32 {
+	bX := pointB.x
+	bY := pointB.y
+	return ((pointC.x - bX) * (pointA.y - bY)) - ((pointC.y - bY) * (pointA.x - bX))
+}
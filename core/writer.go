@@ -0,0 +1,30 @@
+/*
+ * Copyright 2008 ZXing authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import "github.com/discesoft/zxing-go/core/common"
+
+// Writer is implemented by the barcode writers, which render a barcode
+// image of a given format.
+type Writer interface {
+	// Encode encodes contents as a barcode of the given format, rendered
+	// at the preferred width and height in pixels. hints may be nil, or
+	// may pass additional parameters as described by each EncodeHintType.
+	// It returns the barcode as a BitMatrix, where a set bit is black, or
+	// an error if contents cannot be encoded.
+	Encode(contents string, format BarcodeFormat, width, height int, hints map[EncodeHintType]interface{}) (*common.BitMatrix, error)
+}